@@ -61,6 +61,10 @@ type FieldMapping2 struct {
 
 	// FieldPosition 在 PO 结构体中的字段位置（用于排序）
 	FieldPosition int
+
+	// Guard 守卫条件，ToPO 中仅在条件成立时才赋值，如 "d.Meta != nil"
+	// 条件中的源参数使用 ParseResult2.SourceParam 表示
+	Guard string
 }
 
 // MappingGroup 映射组（表示一组相关的映射）
@@ -80,6 +84,9 @@ type MappingGroup struct {
 	// FieldPosition 在 PO 结构体中的字段位置（用于排序，确保生成代码顺序与 PO 定义一致）
 	FieldPosition int
 
+	// Guard 守卫条件（OneToOne 以外的组整体受该条件约束）
+	Guard string
+
 	// Mappings 具体的字段映射列表
 	Mappings []FieldMapping2
 }
@@ -95,6 +102,9 @@ type ParseResult2 struct {
 	// SourceType 源类型（Domain）
 	SourceType string
 
+	// SourceParam ToPO 的源参数名（如 "d"），Guard 中以该名称引用源对象
+	SourceParam string
+
 	// SourceTypePackage 源类型所在的包名（如果是外部包）
 	// 例如：源类型是 domain.ListingDomain，则 SourceTypePackage = "domain"
 	SourceTypePackage string
@@ -119,6 +129,42 @@ type ParseResult2 struct {
 
 	// AllMappings 所有映射的扁平列表（便于遍历）
 	AllMappings []FieldMapping2
+
+	// Skipped 分析时因无法识别而跳过的表达式
+	Skipped []SkippedExpr
+}
+
+// SkippedExpr 无法识别映射关系而被跳过的表达式
+type SkippedExpr struct {
+	// TargetPath PO字段路径，无法确定时为空
+	TargetPath string
+
+	// Expr 表达式源码
+	Expr string
+
+	// Line 表达式在源文件中的行号
+	Line int
+
+	// Reason 跳过原因
+	Reason string
+}
+
+// UncoveredColumns 返回没有任何映射的目标列，按 PO 字段顺序排列
+func (r *ParseResult2) UncoveredColumns() []string {
+	covered := make(map[string]bool)
+	for _, group := range r.Groups {
+		for _, mapping := range group.Mappings {
+			covered[mapping.ColumnName] = true
+		}
+	}
+
+	var columns []string
+	for _, column := range r.TargetColumns {
+		if !covered[column] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
 }
 
 // Parse 解析 ToPO 函数，返回映射关系
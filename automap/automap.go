@@ -1,11 +1,25 @@
 package automap
 
-// Option 解析选项
-type Option func(string) string
+// Option 生成选项
+type Option func(*generateOptions)
+
+// generateOptions 生成选项集合
+type generateOptions struct {
+	filePath      string // 文件上下文
+	versionColumn string // 乐观锁版本列名，非空时生成 version = version + 1
+}
 
 // WithFileContext 设置文件上下文
 func WithFileContext(filePath string) Option {
-	return func(current string) string {
-		return filePath
+	return func(o *generateOptions) {
+		o.filePath = filePath
+	}
+}
+
+// WithVersionColumn 设置乐观锁版本列
+// 该列不再从 ToPO 结果取值，而是在存在变更时生成 version = version + 1
+func WithVersionColumn(column string) Option {
+	return func(o *generateOptions) {
+		o.versionColumn = column
 	}
 }
@@ -38,6 +38,18 @@ func (m *Mapper) collectTypeSpecsFromFile(file *ast.File) {
 	for _, decl := range file.Decls {
 		switch d := decl.(type) {
 		case *ast.GenDecl:
+			// 收集包级常量、变量名
+			if d.Tok == token.CONST || d.Tok == token.VAR {
+				for _, spec := range d.Specs {
+					if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+						for _, name := range valueSpec.Names {
+							m.pkgValues[name.Name] = true
+						}
+					}
+				}
+				continue
+			}
+
 			// 收集类型定义
 			if d.Tok != token.TYPE {
 				continue
@@ -49,10 +61,13 @@ func (m *Mapper) collectTypeSpecsFromFile(file *ast.File) {
 			}
 
 		case *ast.FuncDecl:
-			// 收集方法定义
+			// 收集包级函数定义
 			if d.Recv == nil || len(d.Recv.List) == 0 {
+				m.funcDecls[d.Name.Name] = d
 				continue
 			}
+
+			// 收集方法定义
 			recvType := extractTypeName(d.Recv.List[0].Type)
 			if recvType == "" {
 				continue
@@ -99,9 +114,11 @@ func (m *Mapper) collectVariableAssignments(body *ast.BlockStmt) {
 					m.processAssignStmt(assignStmt)
 				}
 			}
+			m.collectLoopSlices(m.loopSourcePath(s), s.Body)
 
 		case *ast.RangeStmt:
-			// 忽略 range 语句的迭代变量
+			// 忽略 range 语句的迭代变量，只收集循环中构造的切片
+			m.collectLoopSlices(m.loopSourcePath(s), s.Body)
 		}
 	}
 }
@@ -122,6 +139,15 @@ func (m *Mapper) processAssignStmt(s *ast.AssignStmt) {
 		varName := ident.Name
 		rhs := s.Rhs[i]
 
+		// 纯字段访问记录为别名：meta := d.Meta
+		if sel, ok := rhs.(*ast.SelectorExpr); ok {
+			if path := m.buildSelectorPath(sel); path != "" {
+				if _, exists := m.aliasMap[varName]; !exists {
+					m.aliasMap[varName] = path
+				}
+			}
+		}
+
 		// 检查是否是方法调用：d.MethodName()
 		if methodInfo := m.extractMethodCallInfo(rhs); methodInfo != nil {
 			if _, exists := m.methodCallMap[varName]; !exists {
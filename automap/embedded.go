@@ -30,6 +30,7 @@ func (m *Mapper) analyzeEmbeddedCompositeLit(fieldName string, compLit *ast.Comp
 
 		sourcePath, convertExpr := m.extractSourcePath(kv.Value)
 		if sourcePath == "" {
+			m.skip(fieldName+"."+subFieldName, kv.Value, "no source field found in expression")
 			continue
 		}
 
@@ -66,7 +67,7 @@ func (m *Mapper) analyzeEmbeddedCompositeLit(fieldName string, compLit *ast.Comp
 			SourceField: commonParent,
 		}
 		group.Mappings = mappings
-		m.result.Groups = append(m.result.Groups, group)
+		m.appendGroup(group)
 	} else {
 		// 源字段来自不同父字段，使用 Embedded（每个字段单独检查）
 		group := MappingGroup{
@@ -74,7 +75,7 @@ func (m *Mapper) analyzeEmbeddedCompositeLit(fieldName string, compLit *ast.Comp
 			TargetField: fieldName,
 		}
 		group.Mappings = mappings
-		m.result.Groups = append(m.result.Groups, group)
+		m.appendGroup(group)
 	}
 
 	return nil
@@ -121,6 +122,7 @@ func (m *Mapper) analyzeEmbeddedOneToManyMapping(fieldName string, value ast.Exp
 		}
 	}
 	if sourcePath == "" {
+		m.skip(fieldName, value, "no source field found in embedded field value")
 		return nil
 	}
 
@@ -153,7 +155,7 @@ func (m *Mapper) analyzeEmbeddedOneToManyMapping(fieldName string, value ast.Exp
 	}
 
 	if len(group.Mappings) > 0 {
-		m.result.Groups = append(m.result.Groups, group)
+		m.appendGroup(group)
 	}
 	return nil
 }
@@ -0,0 +1,158 @@
+package automap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Explanation 映射分析结果的可序列化视图，用于 gogen explain mapping
+type Explanation struct {
+	Function         string           `json:"function"`
+	SourceType       string           `json:"source_type"`
+	TargetType       string           `json:"target_type"`
+	Groups           []ExplainGroup   `json:"groups"`
+	UncoveredColumns []string         `json:"uncovered_columns"`
+	Skipped          []ExplainSkipped `json:"skipped"`
+}
+
+// ExplainGroup 映射组
+type ExplainGroup struct {
+	Type          MappingType      `json:"type"`
+	SourceField   string           `json:"source_field,omitempty"`
+	TargetField   string           `json:"target_field,omitempty"`
+	MethodName    string           `json:"method_name,omitempty"`
+	Guard         string           `json:"guard,omitempty"`
+	FieldPosition int              `json:"field_position"`
+	Mappings      []ExplainMapping `json:"mappings"`
+}
+
+// ExplainMapping 单个字段映射
+type ExplainMapping struct {
+	SourcePath    string `json:"source_path"`
+	TargetPath    string `json:"target_path"`
+	ColumnName    string `json:"column"`
+	ConvertExpr   string `json:"convert_expr,omitempty"`
+	JSONPath      string `json:"json_path,omitempty"`
+	Guard         string `json:"guard,omitempty"`
+	FieldPosition int    `json:"field_position"`
+}
+
+// ExplainSkipped 被跳过的表达式
+type ExplainSkipped struct {
+	TargetPath string `json:"target_path,omitempty"`
+	Expr       string `json:"expr"`
+	Line       int    `json:"line"`
+	Reason     string `json:"reason"`
+}
+
+// Explain 将解析结果转换为 Explanation
+func Explain(r *ParseResult2) *Explanation {
+	sourceType := r.SourceType
+	if r.SourceTypePackage != "" {
+		sourceType = r.SourceTypePackage + "." + sourceType
+	}
+
+	e := &Explanation{
+		Function:         r.ReceiverType + "." + r.FuncName,
+		SourceType:       sourceType,
+		TargetType:       r.TargetType,
+		Groups:           []ExplainGroup{},
+		UncoveredColumns: r.UncoveredColumns(),
+		Skipped:          []ExplainSkipped{},
+	}
+	if e.UncoveredColumns == nil {
+		e.UncoveredColumns = []string{}
+	}
+
+	for _, group := range r.Groups {
+		g := ExplainGroup{
+			Type:          group.Type,
+			SourceField:   group.SourceField,
+			TargetField:   group.TargetField,
+			MethodName:    group.MethodName,
+			Guard:         group.Guard,
+			FieldPosition: group.FieldPosition,
+			Mappings:      make([]ExplainMapping, 0, len(group.Mappings)),
+		}
+		for _, m := range group.Mappings {
+			g.Mappings = append(g.Mappings, ExplainMapping{
+				SourcePath:    m.SourcePath,
+				TargetPath:    m.TargetPath,
+				ColumnName:    m.ColumnName,
+				ConvertExpr:   m.ConvertExpr,
+				JSONPath:      m.JSONPath,
+				Guard:         m.Guard,
+				FieldPosition: m.FieldPosition,
+			})
+		}
+		e.Groups = append(e.Groups, g)
+	}
+
+	for _, s := range r.Skipped {
+		e.Skipped = append(e.Skipped, ExplainSkipped(s))
+	}
+
+	return e
+}
+
+// WriteJSON 以缩进 JSON 格式输出，便于快照测试
+func (e *Explanation) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// WriteTable 以表格格式输出
+func (e *Explanation) WriteTable(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s (%s -> %s)\n\n", e.Function, e.SourceType, e.TargetType); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "GROUP\tSOURCE\tTARGET\tCOLUMN\tCONVERT\tJSON PATH\tPOS\tGUARD")
+	for _, g := range e.Groups {
+		groupName := string(g.Type)
+		if g.MethodName != "" {
+			groupName += "(" + g.MethodName + ")"
+		}
+		for _, m := range g.Mappings {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				groupName, m.SourcePath, m.TargetPath, m.ColumnName,
+				orDash(m.ConvertExpr), orDash(m.JSONPath), m.FieldPosition, orDash(m.Guard))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	uncovered := "(none)"
+	if len(e.UncoveredColumns) > 0 {
+		uncovered = strings.Join(e.UncoveredColumns, ", ")
+	}
+	if _, err := fmt.Fprintf(w, "\nUncovered columns: %s\n", uncovered); err != nil {
+		return err
+	}
+
+	if len(e.Skipped) == 0 {
+		_, err := fmt.Fprintln(w, "Skipped expressions: (none)")
+		return err
+	}
+
+	_, _ = fmt.Fprintln(w, "Skipped expressions:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range e.Skipped {
+		_, _ = fmt.Fprintf(tw, "  line %d\t%s\t%s\t%s\n", s.Line, orDash(s.TargetPath), strings.Join(strings.Fields(s.Expr), " "), s.Reason)
+	}
+	return tw.Flush()
+}
+
+// orDash 空字符串显示为 -
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
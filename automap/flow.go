@@ -0,0 +1,655 @@
+package automap
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ============================================================================
+// 控制流分析：条件赋值、循环构造切片、同包辅助函数内联
+// ============================================================================
+
+// returnedVarName 返回 return 语句中的局部变量名（return po 或 return &po）
+func returnedVarName(expr ast.Expr) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Name == "nil" {
+		return ""
+	}
+	return ident.Name
+}
+
+// analyzeReturnedVar 分析被返回的局部变量：先分析初始化的结构体字面量，再分析后续的字段赋值
+//
+//	po := &XxxPO{ID: d.ID}
+//	if d.Meta != nil {
+//	    po.Meta = d.Meta.Value
+//	}
+//	return po
+func (m *Mapper) analyzeReturnedVar(body *ast.BlockStmt, varName string) error {
+	for _, stmt := range body.List {
+		if compLit := varInitCompositeLit(stmt, varName); compLit != nil {
+			if err := m.analyzeCompositeLit(compLit, "", ""); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return m.analyzeFieldAssignments(body.List, varName, nil, "")
+}
+
+// varInitCompositeLit 查找局部变量初始化使用的结构体字面量
+func varInitCompositeLit(stmt ast.Stmt, varName string) *ast.CompositeLit {
+	var values []ast.Expr
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name == varName && i < len(s.Rhs) {
+				values = append(values, s.Rhs[i])
+			}
+		}
+	case *ast.DeclStmt:
+		genDecl, ok := s.Decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			return nil
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if name.Name == varName && i < len(valueSpec.Values) {
+					values = append(values, valueSpec.Values[i])
+				}
+			}
+		}
+	}
+
+	for _, value := range values {
+		if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			value = unary.X
+		}
+		if compLit, ok := value.(*ast.CompositeLit); ok {
+			return compLit
+		}
+	}
+	return nil
+}
+
+// analyzeFieldAssignments 分析对返回变量的字段赋值 po.Field = value
+// guard: 当前所在分支的守卫条件；loopSource: 当前所在循环遍历的源字段路径
+func (m *Mapper) analyzeFieldAssignments(stmts []ast.Stmt, varName string, guard ast.Expr, loopSource string) error {
+	for _, stmt := range stmts {
+		if err := m.analyzeFieldAssignStmt(stmt, varName, guard, loopSource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyzeFieldAssignStmt 分析单条语句中的字段赋值
+func (m *Mapper) analyzeFieldAssignStmt(stmt ast.Stmt, varName string, guard ast.Expr, loopSource string) error {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		return m.analyzeFieldAssign(s, varName, guard, loopSource)
+
+	case *ast.BlockStmt:
+		return m.analyzeFieldAssignments(s.List, varName, guard, loopSource)
+
+	case *ast.IfStmt:
+		if s.Init != nil {
+			if assignStmt, ok := s.Init.(*ast.AssignStmt); ok {
+				m.processAssignStmt(assignStmt)
+			}
+		}
+
+		// 循环内的条件针对单个元素，字段整体依赖循环遍历的源字段，不产生守卫条件
+		if loopSource != "" {
+			if err := m.analyzeFieldAssignments(s.Body.List, varName, guard, loopSource); err != nil {
+				return err
+			}
+			if s.Else != nil {
+				return m.analyzeFieldAssignStmt(s.Else, varName, guard, loopSource)
+			}
+			return nil
+		}
+
+		cond, ok := m.portableExpr(s.Cond)
+		if !ok {
+			// 条件引用了无法在 ToPatch 中还原的局部状态，分支内的赋值全部跳过
+			m.skipFieldAssignments(s, varName, "if condition references local state")
+			return nil
+		}
+
+		if err := m.analyzeFieldAssignments(s.Body.List, varName, andExpr(guard, cond), loopSource); err != nil {
+			return err
+		}
+		if s.Else != nil {
+			return m.analyzeFieldAssignStmt(s.Else, varName, andExpr(guard, notExpr(cond)), loopSource)
+		}
+
+	case *ast.RangeStmt:
+		return m.analyzeFieldAssignments(s.Body.List, varName, guard, m.loopSourcePath(s))
+
+	case *ast.ForStmt:
+		return m.analyzeFieldAssignments(s.Body.List, varName, guard, m.loopSourcePath(s))
+	}
+	return nil
+}
+
+// analyzeFieldAssign 分析 po.Field = value 赋值
+func (m *Mapper) analyzeFieldAssign(s *ast.AssignStmt, varName string, guard ast.Expr, loopSource string) error {
+	for i, lhs := range s.Lhs {
+		fieldName, ok := assignedField(lhs, varName)
+		if !ok {
+			if sel, isSel := lhs.(*ast.SelectorExpr); isSel && rootIdentName(sel) == varName {
+				m.skip("", lhs, "nested field assignment")
+			}
+			continue
+		}
+		if len(s.Lhs) != len(s.Rhs) {
+			m.skip(fieldName, s.Rhs[0], "multi-value assignment")
+			continue
+		}
+
+		m.guard = exprString(guard)
+		err := m.analyzeAssignedValue(fieldName, s.Tok, s.Rhs[i], loopSource)
+		m.guard = ""
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyzeAssignedValue 分析赋给字段的值
+func (m *Mapper) analyzeAssignedValue(fieldName string, tok token.Token, value ast.Expr, loopSource string) error {
+	typeSpec := m.typeSpecs[m.receiverType]
+	structType, _ := m.getStructType(typeSpec)
+	fieldInfo := m.getFieldInfo(structType, fieldName)
+
+	// 循环内的赋值（po.Items = append(po.Items, ...)）整体依赖循环遍历的源字段
+	if loopSource != "" {
+		if !m.hasMapping(fieldName, m.guard) {
+			m.addMapping(FieldMapping2{
+				SourcePath: loopSource,
+				TargetPath: fieldName,
+				ColumnName: fieldInfo.ColumnName,
+			}, fieldInfo, "")
+		}
+		return nil
+	}
+
+	if tok != token.ASSIGN {
+		m.skip(fieldName, value, "compound assignment outside of a loop")
+		return nil
+	}
+	return m.analyzeFieldValue(fieldName, value, "", fieldInfo, "")
+}
+
+// hasMapping 判断目标字段在相同守卫条件下是否已有映射
+func (m *Mapper) hasMapping(targetPath, guard string) bool {
+	for _, group := range m.result.Groups {
+		for _, mapping := range group.Mappings {
+			if mapping.TargetPath == targetPath && mapping.Guard == guard {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipFieldAssignments 将语句中对返回变量的所有字段赋值记录为跳过
+func (m *Mapper) skipFieldAssignments(node ast.Node, varName, reason string) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			if fieldName, ok := assignedField(lhs, varName); ok && i < len(assign.Rhs) {
+				m.skip(fieldName, assign.Rhs[i], reason)
+			}
+		}
+		return false
+	})
+}
+
+// assignedField 判断是否是 po.Field 形式的赋值目标
+func assignedField(lhs ast.Expr, varName string) (string, bool) {
+	sel, ok := lhs.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || ident.Name != varName {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// rootIdentName 返回选择器表达式最左侧的标识符名
+func rootIdentName(sel *ast.SelectorExpr) string {
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return rootIdentName(x)
+	}
+	return ""
+}
+
+// ============================================================================
+// 循环
+// ============================================================================
+
+// loopSourcePath 返回循环遍历的源字段路径
+//
+//	for _, item := range d.Items         -> Items
+//	for i := 0; i < len(d.Items); i++    -> Items
+func (m *Mapper) loopSourcePath(stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.RangeStmt:
+		path, _ := m.extractSourcePath(s.X)
+		return path
+	case *ast.ForStmt:
+		var path string
+		if s.Cond != nil {
+			ast.Inspect(s.Cond, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || path != "" {
+					return path == ""
+				}
+				if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "len" && len(call.Args) == 1 {
+					path, _ = m.extractSourcePath(call.Args[0])
+				}
+				return true
+			})
+		}
+		return path
+	}
+	return ""
+}
+
+// collectLoopSlices 收集循环中通过 append 构造的局部切片，整体映射到循环遍历的源字段
+//
+//	items := make([]ItemPO, 0, len(d.Items))
+//	for _, item := range d.Items {
+//	    items = append(items, toItemPO(item))
+//	}
+func (m *Mapper) collectLoopSlices(source string, body *ast.BlockStmt) {
+	if source == "" || body == nil {
+		return
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || !isAppendTo(assign.Rhs[0], ident.Name) {
+			return true
+		}
+		if _, exists := m.varMap[ident.Name]; !exists {
+			m.varMap[ident.Name] = source
+		}
+		return true
+	})
+}
+
+// isAppendTo 判断是否是 append(name, ...) 调用
+func isAppendTo(expr ast.Expr, name string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "append" {
+		return false
+	}
+	arg, ok := call.Args[0].(*ast.Ident)
+	return ok && arg.Name == name
+}
+
+// ============================================================================
+// 守卫条件
+// ============================================================================
+
+// portableExpr 将条件表达式改写为只引用源参数和包级标识符的副本
+// 字段别名（meta := d.Meta）被展开为 d.Meta；引用其他局部状态时返回 false
+func (m *Mapper) portableExpr(cond ast.Expr) (ast.Expr, bool) {
+	expr, err := parser.ParseExpr(m.exprSource(cond))
+	if err != nil {
+		return nil, false
+	}
+
+	portable := true
+	result := astutil.Apply(expr, func(c *astutil.Cursor) bool {
+		ident, ok := c.Node().(*ast.Ident)
+		if !ok {
+			return true
+		}
+		// 选择器的字段名、结构体字面量的键不是变量引用
+		if _, ok := c.Parent().(*ast.SelectorExpr); ok && c.Name() == "Sel" {
+			return false
+		}
+		if _, ok := c.Parent().(*ast.KeyValueExpr); ok && c.Name() == "Key" {
+			return false
+		}
+
+		switch {
+		case ident.Name == m.paramName, m.isPackageLevelIdent(ident.Name):
+		case m.aliasMap[ident.Name] != "":
+			c.Replace(selectorFromPath(m.paramName, m.aliasMap[ident.Name]))
+		default:
+			portable = false
+		}
+		return false
+	}, nil)
+
+	if !portable {
+		return nil, false
+	}
+	return result.(ast.Expr), true
+}
+
+// isPackageLevelIdent 判断标识符是否是内置标识符、导入的包名或包级声明
+func (m *Mapper) isPackageLevelIdent(name string) bool {
+	if types.Universe.Lookup(name) != nil {
+		return true
+	}
+	if m.resolveImportPath(name) != "" {
+		return true
+	}
+	_, isFunc := m.funcDecls[name]
+	_, isType := m.typeSpecs[name]
+	return isFunc || isType || m.pkgValues[name]
+}
+
+// selectorFromPath 由源参数名和字段路径构造选择器表达式，如 d + Meta.Value -> d.Meta.Value
+func selectorFromPath(root, path string) ast.Expr {
+	var expr ast.Expr = ast.NewIdent(root)
+	for part := range strings.SplitSeq(path, ".") {
+		expr = &ast.SelectorExpr{X: expr, Sel: ast.NewIdent(part)}
+	}
+	return expr
+}
+
+// andExpr 组合两个守卫条件
+func andExpr(a, b ast.Expr) ast.Expr {
+	if a == nil {
+		return b
+	}
+	return &ast.BinaryExpr{X: parenIfOr(a), Op: token.LAND, Y: parenIfOr(b)}
+}
+
+// notExpr 对守卫条件取反（else 分支），== 与 != 直接互换
+func notExpr(expr ast.Expr) ast.Expr {
+	if bin, ok := expr.(*ast.BinaryExpr); ok && (bin.Op == token.EQL || bin.Op == token.NEQ) {
+		op := token.EQL
+		if bin.Op == token.EQL {
+			op = token.NEQ
+		}
+		return &ast.BinaryExpr{X: bin.X, Op: op, Y: bin.Y}
+	}
+
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.CallExpr, *ast.ParenExpr:
+		return &ast.UnaryExpr{Op: token.NOT, X: expr}
+	}
+	return &ast.UnaryExpr{Op: token.NOT, X: &ast.ParenExpr{X: expr}}
+}
+
+// parenIfOr || 的优先级低于 &&，组合时需要加括号
+func parenIfOr(expr ast.Expr) ast.Expr {
+	if bin, ok := expr.(*ast.BinaryExpr); ok && bin.Op == token.LOR {
+		return &ast.ParenExpr{X: expr}
+	}
+	return expr
+}
+
+// exprString 打印改写后的表达式，nil 返回空字符串
+func exprString(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// exprSource 打印源文件中的表达式
+func (m *Mapper) exprSource(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, m.fset, expr); err != nil {
+		return getExprString(expr)
+	}
+	return buf.String()
+}
+
+// ============================================================================
+// 同包辅助函数内联
+// ============================================================================
+
+// resolveRootIdent 解析选择器最左侧的标识符，返回其对应的源字段路径前缀
+func (m *Mapper) resolveRootIdent(name string) (string, bool) {
+	if prefix, ok := m.inlineParams[name]; ok {
+		return prefix, true
+	}
+	if path, ok := m.aliasMap[name]; ok {
+		return path, true
+	}
+	return "", false
+}
+
+// lookupHelper 判断表达式是否是对同包辅助函数的调用：toAddressPO(d.Address)
+// 只内联有且仅有一个返回值的包级函数
+func (m *Mapper) lookupHelper(expr ast.Expr) (*ast.CallExpr, *ast.FuncDecl) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, nil
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	helper, ok := m.funcDecls[fun.Name]
+	if !ok || helper.Body == nil || m.inlining[fun.Name] {
+		return nil, nil
+	}
+	results := helper.Type.Results
+	if results == nil || results.NumFields() != 1 {
+		return nil, nil
+	}
+	return call, helper
+}
+
+// helperCompositeLit 返回辅助函数最后一条 return 语句中的结构体字面量
+func helperCompositeLit(helper *ast.FuncDecl) *ast.CompositeLit {
+	stmts := helper.Body.List
+	if len(stmts) == 0 {
+		return nil
+	}
+	ret, ok := stmts[len(stmts)-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	expr := ret.Results[0]
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	compLit, _ := expr.(*ast.CompositeLit)
+	return compLit
+}
+
+// enterHelper 进入辅助函数的分析上下文：将形参绑定到调用处实参的源路径
+// 返回恢复调用方上下文的函数
+func (m *Mapper) enterHelper(helper *ast.FuncDecl, args []ast.Expr) func() {
+	bindings := make(map[string]string)
+	var names []string
+	for _, field := range helper.Type.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "_")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	for i, name := range names {
+		if i >= len(args) || name == "_" {
+			continue
+		}
+		if path, ok := m.argSourcePath(args[i]); ok {
+			bindings[name] = path
+		}
+	}
+
+	paramName, varMap, aliasMap := m.paramName, m.varMap, m.aliasMap
+	methodCallMap, inlineParams := m.methodCallMap, m.inlineParams
+
+	m.paramName = ""
+	m.varMap = make(map[string]string)
+	m.aliasMap = make(map[string]string)
+	m.methodCallMap = make(map[string]methodCallInfo)
+	m.inlineParams = bindings
+	m.inlining[helper.Name.Name] = true
+
+	return func() {
+		delete(m.inlining, helper.Name.Name)
+		m.paramName, m.varMap, m.aliasMap = paramName, varMap, aliasMap
+		m.methodCallMap, m.inlineParams = methodCallMap, inlineParams
+	}
+}
+
+// argSourcePath 返回实参对应的源字段路径，空字符串表示源参数本身
+func (m *Mapper) argSourcePath(arg ast.Expr) (string, bool) {
+	switch e := arg.(type) {
+	case *ast.Ident:
+		if e.Name == m.paramName {
+			return "", true
+		}
+		if path, ok := m.resolveRootIdent(e.Name); ok {
+			return path, true
+		}
+		if path, ok := m.varMap[e.Name]; ok {
+			return path, true
+		}
+	case *ast.SelectorExpr:
+		if path := m.buildSelectorPath(e); path != "" {
+			return path, true
+		}
+	case *ast.UnaryExpr:
+		return m.argSourcePath(e.X)
+	case *ast.StarExpr:
+		return m.argSourcePath(e.X)
+	case *ast.ParenExpr:
+		return m.argSourcePath(e.X)
+	}
+	return "", false
+}
+
+// analyzeHelperCall 内联分析同包辅助函数，找出返回值依赖的源字段
+//   - 只依赖一个源字段：按普通映射处理，ConvertExpr 为 helper(...)
+//   - 依赖多个源字段：生成 MethodCall 组，任一源字段变更都会更新目标列
+func (m *Mapper) analyzeHelperCall(fieldName, targetPath string, call *ast.CallExpr, helper *ast.FuncDecl, fieldInfo *FieldAnalysisInfo, jsonColumn string) error {
+	restore := m.enterHelper(helper, call.Args)
+	paths := m.collectSourcePaths(helper.Body)
+	restore()
+
+	if len(paths) == 0 {
+		m.skip(targetPath, call, "helper function does not read any source field")
+		return nil
+	}
+
+	columnName := fieldInfo.ColumnName
+	if jsonColumn != "" {
+		columnName = jsonColumn
+	}
+
+	if len(paths) == 1 {
+		m.addMapping(FieldMapping2{
+			SourcePath:  paths[0],
+			TargetPath:  targetPath,
+			ColumnName:  columnName,
+			ConvertExpr: helper.Name.Name + "(...)",
+		}, fieldInfo, jsonColumn)
+		return nil
+	}
+
+	// 多个源字段：按顶层字段去重（与 MethodCall 一致，以顶层字段判断是否变更）
+	group := MappingGroup{
+		Type:        MethodCall,
+		TargetField: fieldName,
+		MethodName:  helper.Name.Name,
+	}
+	var fields []string
+	for _, path := range paths {
+		field, _, _ := strings.Cut(path, ".")
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	for _, field := range fields {
+		group.Mappings = append(group.Mappings, FieldMapping2{
+			SourcePath: field,
+			TargetPath: fieldName,
+			ColumnName: columnName,
+		})
+	}
+	m.appendGroup(group)
+	return nil
+}
+
+// collectSourcePaths 收集语法树中读取的所有源字段路径（已排序、去重）
+func (m *Mapper) collectSourcePaths(node ast.Node) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.CallExpr:
+			// 方法调用 a.Street.String()：方法名不属于字段路径
+			if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+				ast.Inspect(sel.X, visit)
+				for _, arg := range e.Args {
+					ast.Inspect(arg, visit)
+				}
+				return false
+			}
+		case *ast.SelectorExpr:
+			if path := m.buildSelectorPath(e); path != "" {
+				add(path)
+				return false
+			}
+		case *ast.Ident:
+			if path, ok := m.resolveRootIdent(e.Name); ok {
+				add(path)
+			}
+		}
+		return true
+	}
+	ast.Inspect(node, visit)
+
+	slices.Sort(paths)
+	return paths
+}
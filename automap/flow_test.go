@@ -0,0 +1,271 @@
+package automap_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/automap"
+)
+
+// findMappings 查找指定目标字段的所有映射
+func findMappings(result *automap.ParseResult2, targetPath string) []automap.FieldMapping2 {
+	var mappings []automap.FieldMapping2
+	for _, group := range result.Groups {
+		for _, mapping := range group.Mappings {
+			if mapping.TargetPath == targetPath {
+				mappings = append(mappings, mapping)
+			}
+		}
+	}
+	return mappings
+}
+
+// findGroup 查找指定类型和目标字段的映射组
+func findGroup(result *automap.ParseResult2, typ automap.MappingType, targetField string) *automap.MappingGroup {
+	for i := range result.Groups {
+		if result.Groups[i].Type == typ && result.Groups[i].TargetField == targetField {
+			return &result.Groups[i]
+		}
+	}
+	return nil
+}
+
+// TestParseGuardedAssignments 测试 if 分支中的字段赋值生成守卫条件
+func TestParseGuardedAssignments(t *testing.T) {
+	result, err := automap.Parse("testdata/guard_models.go", "GuardedPO", "ToPO")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.SourceParam != "d" {
+		t.Errorf("SourceParam = %q, want d", result.SourceParam)
+	}
+
+	nickname := findMappings(result, "Nickname")
+	if len(nickname) != 2 {
+		t.Fatalf("Nickname mappings = %+v, want 2", nickname)
+	}
+	if nickname[0].SourcePath != "Nickname" || nickname[0].Guard != `d.Nickname != ""` {
+		t.Errorf("if branch mapping = %+v", nickname[0])
+	}
+	if nickname[1].SourcePath != "Name" || nickname[1].Guard != `d.Nickname == ""` {
+		t.Errorf("else branch mapping = %+v", nickname[1])
+	}
+
+	// if 初始化语句中的别名被展开为源字段
+	var metaGroup *automap.MappingGroup
+	for i := range result.Groups {
+		if result.Groups[i].Type == automap.OneToMany && result.Groups[i].SourceField == "Meta" {
+			metaGroup = &result.Groups[i]
+		}
+	}
+	if metaGroup == nil {
+		t.Fatalf("OneToMany group for Meta not found: %+v", result.Groups)
+	}
+	if metaGroup.Guard != "d.Meta != nil" || len(metaGroup.Mappings) != 2 {
+		t.Errorf("Meta group = %+v", metaGroup)
+	}
+
+	// 引用局部计算结果的条件无法还原，记录为跳过
+	if mappings := findMappings(result, "Score"); len(mappings) != 0 {
+		t.Errorf("Score should be skipped, got %+v", mappings)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].TargetPath != "Score" {
+		t.Errorf("Skipped = %+v", result.Skipped)
+	}
+}
+
+// TestGenerate2GuardedAssignments 测试守卫条件生成到 ToPatch 中
+func TestGenerate2GuardedAssignments(t *testing.T) {
+	_, funcCode, _, err := automap.Generate2("testdata/guard_models.go", "GuardedPO", "ToPO", "ToPatch")
+	if err != nil {
+		t.Fatalf("Generate2 failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		// 两个分支合并为一项，依赖各分支的源字段与守卫条件引用的字段
+		"// Guarded: nickname\n\tif fields.Nickname.IsPresent() || fields.Name.IsPresent() {\n\t\tvalues[\"nickname\"] = b.Nickname\n\t}",
+		// 守卫条件 d.Meta != nil 只引用了 Meta
+		"if fields.Meta.IsPresent() {\n\t\tvalues[\"source\"] = b.Source",
+		"// Missing fields: score",
+	} {
+		if !strings.Contains(funcCode, expected) {
+			t.Errorf("Missing expected code: %s\ngot:\n%s", expected, funcCode)
+		}
+	}
+	if strings.Count(funcCode, `values["nickname"]`) != 1 {
+		t.Errorf("Guarded column should be written once, got:\n%s", funcCode)
+	}
+	if strings.Contains(funcCode, "d.") {
+		t.Errorf("Guard should reference input instead of ToPO parameter, got:\n%s", funcCode)
+	}
+}
+
+// guardRunSource 运行守卫条件映射生成的 ToPatch：翻转 if/else 分支、将守卫引用的指针置空
+const guardRunSource = `package main
+
+import "fmt"
+
+type PatchField struct{ present bool }
+
+func (f PatchField) IsPresent() bool { return f.present }
+
+type GuardedDomainPatch struct {
+	ID, Name, Nickname, Meta, Score PatchField
+}
+
+var patch GuardedDomainPatch
+
+func (d *GuardedDomain) ExportPatch() *GuardedDomainPatch { return &patch }
+
+func main() {
+	d := &GuardedDomain{ID: 1, Name: "alice", Nickname: "bob", Meta: &GuardedMeta{Source: "web", Region: "eu"}}
+	po := new(GuardedPO)
+
+	// 仅清空 Nickname：守卫条件翻转到 else 分支，nickname 取 Name
+	d.Nickname = ""
+	patch = GuardedDomainPatch{Nickname: PatchField{true}}
+	fmt.Println(po.ToPatch(d))
+
+	// 仅修改 Name：else 分支生效时 nickname 随之更新
+	d.Name = "carol"
+	patch = GuardedDomainPatch{Name: PatchField{true}}
+	fmt.Println(po.ToPatch(d))
+
+	// Meta 置空：守卫不成立，由 meta 派生的列写回初始值
+	d.Meta = nil
+	patch = GuardedDomainPatch{Meta: PatchField{true}}
+	fmt.Println(po.ToPatch(d))
+}
+`
+
+// TestGenerate2GuardedRuntime 编译运行带守卫条件的 ToPatch，验证分支翻转后依赖的列被写入
+func TestGenerate2GuardedRuntime(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+	_, funcCode, _, err := automap.Generate2("testdata/guard_models.go", "GuardedPO", "ToPO", "ToPatch")
+	if err != nil {
+		t.Fatalf("Generate2 failed: %v", err)
+	}
+	models, err := os.ReadFile("testdata/guard_models.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp(".", "_guardrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	for name, content := range map[string]string{
+		"main.go":   guardRunSource,
+		"models.go": strings.Replace(string(models), "package testdata", "package main", 1),
+		"patch.go":  "package main\n\n" + funcCode,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := exec.Command("go", "run", "-mod=readonly", "./"+dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s\n%s", err, out, funcCode)
+	}
+	want := "map[nickname:alice]\nmap[name:carol nickname:carol]\nmap[region: source:]"
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("ToPatch results:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestParseLoopSlices 测试循环构造的切片整体映射到源字段
+func TestParseLoopSlices(t *testing.T) {
+	result, err := automap.Parse("testdata/loop_models.go", "LoopOrderPO", "ToPO")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	for target, source := range map[string]string{
+		"Items":   "Items", // items = append(items, ...) 后传入 NewJSONSlice
+		"TagList": "Tags",  // for i := 0; i < len(d.Tags); i++ { po.TagList += ... }
+		"Notes":   "Notes", // range 循环中条件 append，条件不产生守卫
+	} {
+		mappings := findMappings(result, target)
+		if len(mappings) != 1 {
+			t.Errorf("%s mappings = %+v, want 1", target, mappings)
+			continue
+		}
+		if mappings[0].SourcePath != source || mappings[0].Guard != "" {
+			t.Errorf("%s mapping = %+v, want whole-field dependency on %s", target, mappings[0], source)
+		}
+	}
+	if uncovered := result.UncoveredColumns(); len(uncovered) != 0 {
+		t.Errorf("UncoveredColumns = %v", uncovered)
+	}
+}
+
+// TestParseHelperFunctions 测试内联同包辅助函数
+func TestParseHelperFunctions(t *testing.T) {
+	result, err := automap.Parse("testdata/helper_models.go", "HelperPO", "ToPO")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// 依赖单个源字段：普通映射
+	email := findMappings(result, "Email")
+	if len(email) != 1 || email[0].SourcePath != "Email" || email[0].ConvertExpr != "helperNormalizeEmail(...)" {
+		t.Errorf("Email mappings = %+v", email)
+	}
+
+	// 依赖多个源字段：MethodCall 组
+	fullName := findGroup(result, automap.MethodCall, "FullName")
+	if fullName == nil {
+		t.Fatalf("MethodCall group for FullName not found: %+v", result.Groups)
+	}
+	if fullName.MethodName != "helperFullName" || len(fullName.Mappings) != 2 ||
+		fullName.Mappings[0].SourcePath != "FirstName" || fullName.Mappings[1].SourcePath != "LastName" {
+		t.Errorf("FullName group = %+v", fullName)
+	}
+
+	// 返回结构体字面量：按字段展开到嵌入列
+	address := findGroup(result, automap.EmbeddedOneToMany, "Address")
+	if address == nil {
+		t.Fatalf("EmbeddedOneToMany group for Address not found: %+v", result.Groups)
+	}
+	columns := map[string]string{}
+	for _, mapping := range address.Mappings {
+		columns[mapping.ColumnName] = mapping.SourcePath
+	}
+	if columns["addr_street"] != "Address.Street" || columns["addr_city"] != "Address.City" {
+		t.Errorf("Address mappings = %+v", address.Mappings)
+	}
+
+	if len(result.Skipped) != 0 || len(result.UncoveredColumns()) != 0 {
+		t.Errorf("Skipped = %+v, Uncovered = %v", result.Skipped, result.UncoveredColumns())
+	}
+}
+
+// TestGenerate2HelperFunctions 测试辅助函数依赖多个源字段时任一字段变更都更新目标列
+func TestGenerate2HelperFunctions(t *testing.T) {
+	_, funcCode, _, err := automap.Generate2("testdata/helper_models.go", "HelperPO", "ToPO", "ToPatch")
+	if err != nil {
+		t.Fatalf("Generate2 failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"if fields.FirstName.IsPresent() || fields.LastName.IsPresent() {",
+		`values["full_name"] = b.FullName`,
+		`values["email"] = b.Email`,
+		`values["addr_street"] = b.Address.Street`,
+	} {
+		if !strings.Contains(funcCode, expected) {
+			t.Errorf("Missing expected code: %s\ngot:\n%s", expected, funcCode)
+		}
+	}
+	if strings.Contains(funcCode, "Missing fields") {
+		t.Errorf("All columns should be covered, got:\n%s", funcCode)
+	}
+}
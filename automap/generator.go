@@ -2,6 +2,9 @@ package automap
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -19,6 +22,9 @@ type Generator2 struct {
 	// 接收者信息
 	receiverType string
 	receiverVar  string
+
+	// 乐观锁版本列名（为空表示不处理版本）
+	versionColumn string
 }
 
 // NewGenerator2 创建新的代码生成器
@@ -140,26 +146,156 @@ func (g *Generator2) generateFunctionBody(builder *strings.Builder) {
 	// 创建生成项列表，并按字段位置排序
 	items := g.createSortedGenerationItems()
 
+	// 同一列的带守卫条件映射合并生成，依赖所有分支的源字段与守卫条件引用的字段
+	guarded := g.guardedColumnSources()
+	emitted := make(map[string]bool)
+
 	// 按顺序生成代码
 	for _, item := range items {
-		if item.isGroup {
-			switch item.group.Type {
-			case Embedded:
-				g.generateEmbeddedMappings(builder, item.group)
-			case ManyToOne:
-				g.generateManyToOneMappings(builder, item.group)
-			case OneToMany:
-				g.generateOneToManyMappings(builder, item.group)
-			case MethodCall:
-				g.generateMethodCallMappings(builder, item.group)
-			case EmbeddedOneToMany:
-				g.generateEmbeddedOneToManyMappings(builder, item.group)
-			}
-		} else {
+		switch {
+		case item.isGroup:
+			g.generateGroup(builder, item.group)
+		case item.mapping.Guard == "":
 			// 单个 OneToOne 映射
 			g.writeFieldMapping(builder, item.mapping.SourcePath, item.mapping.TargetPath, item.mapping.ColumnName)
+		case !emitted[item.mapping.ColumnName]:
+			emitted[item.mapping.ColumnName] = true
+			g.writeGuardedFieldMapping(builder, item.mapping, guarded[item.mapping.ColumnName])
+		}
+	}
+
+	g.generateVersionBump(builder)
+}
+
+// generateGroup 按组类型生成映射代码
+func (g *Generator2) generateGroup(builder *strings.Builder, group MappingGroup) {
+	switch group.Type {
+	case Embedded:
+		g.generateEmbeddedMappings(builder, group)
+	case ManyToOne:
+		g.generateManyToOneMappings(builder, group)
+	case OneToMany:
+		g.generateOneToManyMappings(builder, group)
+	case MethodCall:
+		g.generateMethodCallMappings(builder, group)
+	case EmbeddedOneToMany:
+		g.generateEmbeddedOneToManyMappings(builder, group)
+	}
+}
+
+// guardFields 返回守卫条件引用的源字段（d.Meta.Source -> Meta）
+// 条件中的方法调用（d.IsVIP()）或整体引用源对象无法确定依赖的字段，不计入
+func (g *Generator2) guardFields(guard string) []string {
+	if guard == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(g.guardExpr(guard))
+	if err != nil {
+		return nil
+	}
+	methods := make(map[ast.Expr]bool)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			methods[call.Fun] = true
+		}
+		return true
+	})
+
+	var fields []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "input" {
+			if !methods[sel] && !slices.Contains(fields, sel.Sel.Name) {
+				fields = append(fields, sel.Sel.Name)
+			}
+			return false
+		}
+		return true
+	})
+	return fields
+}
+
+// presentExpr 生成任一源字段存在的条件，如 fields.Name.IsPresent() || fields.Nickname.IsPresent()
+func presentExpr(sources ...string) string {
+	var conditions []string
+	for _, source := range sources {
+		cond := fmt.Sprintf("fields.%s.IsPresent()", source)
+		if !slices.Contains(conditions, cond) {
+			conditions = append(conditions, cond)
+		}
+	}
+	return strings.Join(conditions, " || ")
+}
+
+// guardedColumnSources 按列收集带守卫条件的 OneToOne 映射依赖的源字段：各分支的源字段及守卫条件引用的字段
+func (g *Generator2) guardedColumnSources() map[string][]string {
+	out := make(map[string][]string)
+	for _, group := range g.result.Groups {
+		if group.Type != OneToOne {
+			continue
+		}
+		for _, mapping := range group.Mappings {
+			if mapping.Guard == "" {
+				continue
+			}
+			out[mapping.ColumnName] = append(out[mapping.ColumnName], mapping.SourcePath)
+			out[mapping.ColumnName] = append(out[mapping.ColumnName], g.guardFields(mapping.Guard)...)
 		}
 	}
+	return out
+}
+
+// guardExpr 将守卫条件中的源参数改写为 ToPatch 的参数 input
+// 例如 ToPO(d *UserDomain) 中的 "d.Meta != nil" -> "input.Meta != nil"
+func (g *Generator2) guardExpr(guard string) string {
+	param := g.result.SourceParam
+	if param == "" || param == "input" {
+		return guard
+	}
+	expr, err := parser.ParseExpr(guard)
+	if err != nil {
+		return guard
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			// 只改写选择器左侧，字段名可能与参数同名
+			ast.Inspect(e.X, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && ident.Name == param {
+					ident.Name = "input"
+				}
+				return true
+			})
+			return false
+		case *ast.Ident:
+			if e.Name == param {
+				e.Name = "input"
+			}
+		}
+		return true
+	})
+	return exprString(expr)
+}
+
+// generateVersionBump 生成乐观锁版本自增代码，仅在存在变更时追加
+func (g *Generator2) generateVersionBump(builder *strings.Builder) {
+	if g.versionColumn == "" {
+		return
+	}
+	g.imports["github.com/donutnomad/gsql"] = ""
+
+	builder.WriteString(fmt.Sprintf("\t// Version: %s\n", g.versionColumn))
+	builder.WriteString("\tif len(values) > 0 {\n")
+	builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = gsql.Expr(\"%s + 1\")\n", g.versionColumn, g.versionColumn))
+	builder.WriteString("\t}\n")
+}
+
+// isVersionColumn 判断列是否为乐观锁版本列（版本列由 generateVersionBump 统一处理）
+func (g *Generator2) isVersionColumn(columnName string) bool {
+	return g.versionColumn != "" && columnName == g.versionColumn
 }
 
 // generationItem 生成项
@@ -206,8 +342,9 @@ func (g *Generator2) createSortedGenerationItems() []generationItem {
 // generateEmbeddedMappings 生成嵌入字段映射代码
 func (g *Generator2) generateEmbeddedMappings(builder *strings.Builder, group MappingGroup) {
 	builder.WriteString(fmt.Sprintf("\t// Embedded: %s\n", group.TargetField))
+	deps := g.guardFields(group.Guard)
 	for _, mapping := range group.Mappings {
-		g.writeFieldMapping(builder, mapping.SourcePath, mapping.TargetPath, mapping.ColumnName)
+		g.writeFieldMapping(builder, mapping.SourcePath, mapping.TargetPath, mapping.ColumnName, deps...)
 	}
 }
 
@@ -227,6 +364,8 @@ func (g *Generator2) generateManyToOneMappings(builder *strings.Builder, group M
 	builder.WriteString(fmt.Sprintf("\t\tset := gsql.JSONSet(\"%s\")\n", columnName))
 	builder.WriteString(fmt.Sprintf("\t\tfield := b.%s.Data()\n", group.TargetField))
 
+	deps := g.guardFields(group.Guard)
+
 	// 按 JSONPath 的前缀分组（用于嵌套结构的注释）
 	prefixGroups := g.groupByJSONPathPrefix(group.Mappings)
 
@@ -250,7 +389,7 @@ func (g *Generator2) generateManyToOneMappings(builder *strings.Builder, group M
 		for _, mapping := range mappings {
 			// 从 TargetPath 获取字段路径 (去掉 JSON 字段名前缀)
 			fieldPath := mapping.GoFieldPath
-			g.writeJSONFieldMapping(builder, mapping.SourcePath, mapping.JSONPath, fieldPath)
+			g.writeJSONFieldMapping(builder, mapping.SourcePath, mapping.JSONPath, fieldPath, deps...)
 		}
 	}
 
@@ -265,10 +404,13 @@ func (g *Generator2) generateOneToManyMappings(builder *strings.Builder, group M
 	builder.WriteString(fmt.Sprintf("\t// OneToMany: %s\n", group.SourceField))
 
 	// 一对多：一个源字段展开为多个目标字段
-	// 使用源字段的第一部分作为条件检查
+	// 使用源字段的第一部分（及守卫条件引用的字段）作为条件检查
 	if len(group.Mappings) > 0 {
-		builder.WriteString(fmt.Sprintf("\tif fields.%s.IsPresent() {\n", group.SourceField))
+		builder.WriteString(fmt.Sprintf("\tif %s {\n", presentExpr(append([]string{group.SourceField}, g.guardFields(group.Guard)...)...)))
 		for _, mapping := range group.Mappings {
+			if g.isVersionColumn(mapping.ColumnName) {
+				continue
+			}
 			builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = b.%s\n", mapping.ColumnName, mapping.TargetPath))
 		}
 		builder.WriteString("\t}\n")
@@ -283,13 +425,14 @@ func (g *Generator2) generateMethodCallMappings(builder *strings.Builder, group
 	// 如果任一源字段被修改，则更新目标字段
 	if len(group.Mappings) > 0 {
 		// 生成条件检查：任一字段 IsPresent
-		var conditions []string
+		var sources []string
 		for _, mapping := range group.Mappings {
-			conditions = append(conditions, fmt.Sprintf("fields.%s.IsPresent()", mapping.SourcePath))
+			sources = append(sources, mapping.SourcePath)
 		}
+		sources = append(sources, g.guardFields(group.Guard)...)
 
 		columnName := group.Mappings[0].ColumnName
-		builder.WriteString(fmt.Sprintf("\tif %s {\n", strings.Join(conditions, " || ")))
+		builder.WriteString(fmt.Sprintf("\tif %s {\n", presentExpr(sources...)))
 		builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = b.%s\n", columnName, group.TargetField))
 		builder.WriteString("\t}\n")
 	}
@@ -301,25 +444,43 @@ func (g *Generator2) generateEmbeddedOneToManyMappings(builder *strings.Builder,
 	builder.WriteString(fmt.Sprintf("\t// EmbeddedOneToMany: %s -> %s\n", group.SourceField, group.TargetField))
 
 	if len(group.Mappings) > 0 {
-		// 使用源字段作为条件检查
-		builder.WriteString(fmt.Sprintf("\tif fields.%s.IsPresent() {\n", group.SourceField))
+		// 使用源字段（及守卫条件引用的字段）作为条件检查
+		builder.WriteString(fmt.Sprintf("\tif %s {\n", presentExpr(append([]string{group.SourceField}, g.guardFields(group.Guard)...)...)))
 		for _, mapping := range group.Mappings {
+			if g.isVersionColumn(mapping.ColumnName) {
+				continue
+			}
 			builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = b.%s\n", mapping.ColumnName, mapping.TargetPath))
 		}
 		builder.WriteString("\t}\n")
 	}
 }
 
-// writeFieldMapping 写入字段映射
-func (g *Generator2) writeFieldMapping(builder *strings.Builder, sourcePath, targetPath, columnName string) {
-	builder.WriteString(fmt.Sprintf("\tif fields.%s.IsPresent() {\n", sourcePath))
+// writeGuardedFieldMapping 写入带守卫条件的列：sources 为该列所有分支的源字段及守卫条件引用的字段，任一存在时写入
+// b 由 ToPO 计算，取值即为守卫条件成立的分支（均不成立时为初始值），因此翻转分支或清空守卫引用的字段也会更新该列
+func (g *Generator2) writeGuardedFieldMapping(builder *strings.Builder, mapping FieldMapping2, sources []string) {
+	if g.isVersionColumn(mapping.ColumnName) {
+		return
+	}
+	builder.WriteString(fmt.Sprintf("\t// Guarded: %s\n", mapping.ColumnName))
+	builder.WriteString(fmt.Sprintf("\tif %s {\n", presentExpr(sources...)))
+	builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = b.%s\n", mapping.ColumnName, mapping.TargetPath))
+	builder.WriteString("\t}\n")
+}
+
+// writeFieldMapping 写入字段映射，deps 为额外依赖的源字段（所在分支守卫条件引用的字段）
+func (g *Generator2) writeFieldMapping(builder *strings.Builder, sourcePath, targetPath, columnName string, deps ...string) {
+	if g.isVersionColumn(columnName) {
+		return
+	}
+	builder.WriteString(fmt.Sprintf("\tif %s {\n", presentExpr(append([]string{sourcePath}, deps...)...)))
 	builder.WriteString(fmt.Sprintf("\t\tvalues[\"%s\"] = b.%s\n", columnName, targetPath))
 	builder.WriteString("\t}\n")
 }
 
 // writeJSONFieldMapping 写入 JSON 字段映射
-func (g *Generator2) writeJSONFieldMapping(builder *strings.Builder, sourcePath, jsonPath, fieldPath string) {
-	builder.WriteString(fmt.Sprintf("\t\tif fields.%s.IsPresent() {\n", sourcePath))
+func (g *Generator2) writeJSONFieldMapping(builder *strings.Builder, sourcePath, jsonPath, fieldPath string, deps ...string) {
+	builder.WriteString(fmt.Sprintf("\t\tif %s {\n", presentExpr(append([]string{sourcePath}, deps...)...)))
 	builder.WriteString(fmt.Sprintf("\t\t\tset.Set(\"%s\", field.%s)\n", jsonPath, fieldPath))
 	builder.WriteString("\t\t}\n")
 }
@@ -422,13 +583,11 @@ func generate2WithOptionsInternal(funcNameWithReceiver, genFuncName string, ctx
 	receiverType := parts[0]
 	funcName := parts[1]
 
-	// 获取文件路径
-	var filePath string
+	var opts generateOptions
 	for _, opt := range options {
-		if fp := opt(""); fp != "" {
-			filePath = fp
-		}
+		opt(&opts)
 	}
+	filePath := opts.filePath
 	if filePath == "" {
 		return "", "", nil, fmt.Errorf("需要通过 WithFileContext 指定文件路径")
 	}
@@ -447,6 +606,7 @@ func generate2WithOptionsInternal(funcNameWithReceiver, genFuncName string, ctx
 
 	// 生成代码
 	generator := NewGenerator2(result, genFuncName)
+	generator.versionColumn = opts.versionColumn
 	fullCode, funcCode, imports := generator.Generate()
 
 	return fullCode, funcCode, imports, nil
@@ -1072,3 +1072,92 @@ func TestGenerate2StructLiteralMixedSource(t *testing.T) {
 
 	t.Logf("Generated full code:\n%s", fullCode)
 }
+
+// TestGenerate2VersionColumn 测试乐观锁版本列：不再取 ToPO 的值，而是在有变更时自增
+func TestGenerate2VersionColumn(t *testing.T) {
+	_, funcCode, imports, err := automap.Generate2WithOptions("VersionedPO.ToPO", "ToPatch",
+		automap.WithFileContext("testdata/version_models.go"),
+		automap.WithVersionColumn("version"),
+	)
+	if err != nil {
+		t.Fatalf("Generate2WithOptions failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		`values["title"] = b.Title`,
+		"if len(values) > 0 {",
+		`values["version"] = gsql.Expr("version + 1")`,
+	} {
+		if !strings.Contains(funcCode, expected) {
+			t.Errorf("Missing expected code: %s\ngot:\n%s", expected, funcCode)
+		}
+	}
+	if strings.Contains(funcCode, `values["version"] = b.Version`) {
+		t.Errorf("Version column should not be copied from ToPO, got:\n%s", funcCode)
+	}
+	if strings.Contains(funcCode, "Missing fields") {
+		t.Errorf("Version column should count as covered, got:\n%s", funcCode)
+	}
+
+	hasGsql := false
+	for _, imp := range imports {
+		if imp.Path == "github.com/donutnomad/gsql" {
+			hasGsql = true
+		}
+	}
+	if !hasGsql {
+		t.Errorf("Missing gsql import, got: %v", imports)
+	}
+}
+
+// TestExplainMapping 测试映射解释输出：未覆盖列与跳过的表达式
+func TestExplainMapping(t *testing.T) {
+	result, err := automap.Parse("testdata/explain_models.go", "ExplainPO", "ToPO")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	explanation := automap.Explain(result)
+	if explanation.Function != "ExplainPO.ToPO" {
+		t.Errorf("Function = %q, want ExplainPO.ToPO", explanation.Function)
+	}
+	if got := strings.Join(explanation.UncoveredColumns, ","); got != "status,remark" {
+		t.Errorf("UncoveredColumns = %q, want status,remark", got)
+	}
+	if len(explanation.Skipped) != 1 {
+		t.Fatalf("Skipped = %+v, want 1 entry", explanation.Skipped)
+	}
+	if skipped := explanation.Skipped[0]; skipped.TargetPath != "Status" || skipped.Expr != `"active"` || skipped.Line != 29 {
+		t.Errorf("Skipped[0] = %+v", skipped)
+	}
+
+	var table strings.Builder
+	if err := explanation.WriteTable(&table); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	for _, expected := range []string{
+		"ExplainPO.ToPO (ExplainDomain -> ExplainPO)",
+		"GROUP",
+		".Unix()",
+		"Uncovered columns: status, remark",
+		`line 29  Status  "active"`,
+	} {
+		if !strings.Contains(table.String(), expected) {
+			t.Errorf("Missing %q in table:\n%s", expected, table.String())
+		}
+	}
+
+	var out strings.Builder
+	if err := explanation.WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	for _, expected := range []string{
+		`"function": "ExplainPO.ToPO"`,
+		`"convert_expr": ".Unix()"`,
+		`"uncovered_columns": [`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Missing %q in JSON:\n%s", expected, out.String())
+		}
+	}
+}
@@ -16,7 +16,7 @@ func (m *Mapper) analyzeJSONCompositeLit(fieldName string, compLit *ast.Composit
 	m.extractJSONMappings(&group, compLit, "", "", fieldInfo.ColumnName)
 
 	if len(group.Mappings) > 0 {
-		m.result.Groups = append(m.result.Groups, group)
+		m.appendGroup(group)
 	}
 	return nil
 }
@@ -62,6 +62,7 @@ func (m *Mapper) extractJSONMappings(group *MappingGroup, compLit *ast.Composite
 		// 提取源路径
 		sourcePath, convertExpr := m.extractSourcePath(kv.Value)
 		if sourcePath == "" {
+			m.skip(group.TargetField+"."+goFieldPath, kv.Value, "no source field found in JSON field value")
 			continue
 		}
 
@@ -1,8 +1,10 @@
 package automap
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
+	"go/printer"
 	"go/token"
 	"os"
 	"reflect"
@@ -28,6 +30,12 @@ type Mapper struct {
 	// 方法定义缓存：receiverType -> methodName -> funcDecl
 	methodDecls map[string]map[string]*ast.FuncDecl
 
+	// 包级函数定义缓存（不含方法）：funcName -> funcDecl，用于内联同包辅助函数
+	funcDecls map[string]*ast.FuncDecl
+
+	// 包级常量、变量名，守卫条件可以直接引用
+	pkgValues map[string]bool
+
 	// 当前分析的上下文
 	receiverType string
 	funcName     string
@@ -40,6 +48,19 @@ type Mapper struct {
 	// 方法调用映射：变量名 -> (methodName, receiverType)
 	methodCallMap map[string]methodCallInfo
 
+	// 字段别名：变量名 -> 源字段路径，仅记录纯字段访问（如 meta := d.Meta）
+	// 用于解析 meta.Value 这类选择器以及改写守卫条件
+	aliasMap map[string]string
+
+	// 内联辅助函数时的参数绑定：辅助函数参数名 -> 调用处实参的源路径（空字符串表示源参数本身）
+	inlineParams map[string]string
+
+	// 正在内联的辅助函数，防止递归
+	inlining map[string]bool
+
+	// 当前赋值所处的守卫条件（if 分支），为空表示无条件赋值
+	guard string
+
 	// 解析结果
 	result *ParseResult2
 }
@@ -57,6 +78,9 @@ func NewMapper(filePath string) *Mapper {
 		parseCtx:    NewParseContext2(),
 		typeSpecs:   make(map[string]*ast.TypeSpec),
 		methodDecls: make(map[string]map[string]*ast.FuncDecl),
+		funcDecls:   make(map[string]*ast.FuncDecl),
+		pkgValues:   make(map[string]bool),
+		inlining:    make(map[string]bool),
 	}
 }
 
@@ -70,6 +94,9 @@ func NewMapperWithCache(filePath string, ctx *ParseContext2) *Mapper {
 		parseCtx:    ctx,
 		typeSpecs:   make(map[string]*ast.TypeSpec),
 		methodDecls: make(map[string]map[string]*ast.FuncDecl),
+		funcDecls:   make(map[string]*ast.FuncDecl),
+		pkgValues:   make(map[string]bool),
+		inlining:    make(map[string]bool),
 	}
 }
 
@@ -180,6 +207,7 @@ func (m *Mapper) extractFuncParams(funcDecl *ast.FuncDecl) {
 	param := funcDecl.Type.Params.List[0]
 	if len(param.Names) > 0 {
 		m.paramName = param.Names[0].Name
+		m.result.SourceParam = m.paramName
 	}
 
 	// 提取源类型和包信息
@@ -225,6 +253,7 @@ func (m *Mapper) analyzeFuncBody(body *ast.BlockStmt) error {
 	// 初始化变量映射表
 	m.varMap = make(map[string]string)
 	m.methodCallMap = make(map[string]methodCallInfo)
+	m.aliasMap = make(map[string]string)
 
 	// 第一遍：收集所有局部变量的赋值
 	m.collectVariableAssignments(body)
@@ -239,6 +268,13 @@ func (m *Mapper) analyzeFuncBody(body *ast.BlockStmt) error {
 
 		// 分析返回的结构体字面量
 		for _, result := range retStmt.Results {
+			// 返回局部变量：po := &XxxPO{...}; po.Field = ...; return po
+			if varName := returnedVarName(result); varName != "" {
+				if err := m.analyzeReturnedVar(body, varName); err != nil {
+					return err
+				}
+				continue
+			}
 			if err := m.analyzeReturnExpr(result); err != nil {
 				return err
 			}
@@ -304,6 +340,7 @@ func (m *Mapper) analyzeReturnExpr(expr ast.Expr) error {
 	// 获取结构体字面量
 	compLit, ok := expr.(*ast.CompositeLit)
 	if !ok {
+		m.skip("", expr, "return value is not a struct literal")
 		return nil
 	}
 
@@ -326,12 +363,14 @@ func (m *Mapper) analyzeCompositeLit(compLit *ast.CompositeLit, targetPrefix, js
 	for _, elt := range compLit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
 		if !ok {
+			m.skip(targetPrefix, elt, "positional struct literal element")
 			continue
 		}
 
 		// 获取字段名
 		fieldName := m.getKeyName(kv.Key)
 		if fieldName == "" {
+			m.skip(targetPrefix, elt, "unsupported struct literal key")
 			continue
 		}
 
@@ -460,6 +499,14 @@ func (m *Mapper) analyzeFieldValue(fieldName string, value ast.Expr, targetPrefi
 		if compLit, ok := value.(*ast.CompositeLit); ok {
 			return m.analyzeEmbeddedCompositeLit(fieldName, compLit, fieldInfo)
 		}
+		// 同包辅助函数返回结构体字面量：Address: toAddressPO(d.Address)
+		if call, helper := m.lookupHelper(value); helper != nil {
+			if compLit := helperCompositeLit(helper); compLit != nil {
+				restore := m.enterHelper(helper, call.Args)
+				defer restore()
+				return m.analyzeEmbeddedCompositeLit(fieldName, compLit, fieldInfo)
+			}
+		}
 		// 处理嵌入字段的直接赋值（非结构体字面量）
 		// 例如: Account: d.Account 或 Account: d.Account.ToColumns()
 		// 这是 EmbeddedOneToMany 映射
@@ -472,6 +519,14 @@ func (m *Mapper) analyzeFieldValue(fieldName string, value ast.Expr, targetPrefi
 			if compLit, ok := callExpr.Args[0].(*ast.CompositeLit); ok {
 				return m.analyzeJSONCompositeLit(fieldName, compLit, fieldInfo)
 			}
+			// 同包辅助函数构造 JSON 结构体：datatypes.NewJSONType(toMetaJSON(d))
+			if call, helper := m.lookupHelper(callExpr.Args[0]); helper != nil {
+				if compLit := helperCompositeLit(helper); compLit != nil {
+					restore := m.enterHelper(helper, call.Args)
+					defer restore()
+					return m.analyzeJSONCompositeLit(fieldName, compLit, fieldInfo)
+				}
+			}
 		}
 
 		// 处理 JSONSlice - datatypes.NewJSONSlice(...)
@@ -502,8 +557,10 @@ func (m *Mapper) analyzeFieldValue(fieldName string, value ast.Expr, targetPrefi
 			}
 
 			// 情况3: 直接传入字段 datatypes.NewJSONSlice(entity.Field)
-			if argSelector, ok := arg.(*ast.SelectorExpr); ok {
-				sourcePath := m.buildSelectorPath(argSelector)
+			// 或循环构造的局部切片 datatypes.NewJSONSlice(items)
+			switch arg.(type) {
+			case *ast.SelectorExpr, *ast.Ident:
+				sourcePath, _ := m.extractSourcePath(arg)
 				if sourcePath != "" {
 					mapping := FieldMapping2{
 						SourcePath: sourcePath,
@@ -517,6 +574,11 @@ func (m *Mapper) analyzeFieldValue(fieldName string, value ast.Expr, targetPrefi
 		}
 	}
 
+	// 同包辅助函数：内联分析函数体
+	if call, helper := m.lookupHelper(value); helper != nil {
+		return m.analyzeHelperCall(fieldName, targetPath, call, helper, fieldInfo, jsonColumn)
+	}
+
 	// 检查是否是直接的方法调用 d.MethodName()
 	if methodInfo := m.extractMethodCallInfo(value); methodInfo != nil {
 		return m.analyzeMethodCallMapping(fieldName, methodInfo, fieldInfo)
@@ -532,6 +594,7 @@ func (m *Mapper) analyzeFieldValue(fieldName string, value ast.Expr, targetPrefi
 	// 提取源路径和转换表达式
 	sourcePath, convertExpr := m.extractSourcePath(value)
 	if sourcePath == "" {
+		m.skip(targetPath, value, "no source field found in expression")
 		return nil
 	}
 
@@ -581,7 +644,7 @@ func (m *Mapper) analyzeMethodCallMapping(fieldName string, methodInfo *methodCa
 					group.Mappings = append(group.Mappings, mapping)
 				}
 
-				m.result.Groups = append(m.result.Groups, group)
+				m.appendGroup(group)
 				return nil
 			}
 		}
@@ -602,6 +665,22 @@ func (m *Mapper) analyzeMethodCallMapping(fieldName string, methodInfo *methodCa
 	return nil
 }
 
+// skip 记录无法识别而被跳过的表达式
+func (m *Mapper) skip(targetPath string, expr ast.Expr, reason string) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, m.fset, expr); err != nil {
+		buf.Reset()
+		buf.WriteString(getExprString(expr))
+	}
+
+	m.result.Skipped = append(m.result.Skipped, SkippedExpr{
+		TargetPath: targetPath,
+		Expr:       buf.String(),
+		Line:       m.fset.Position(expr.Pos()).Line,
+		Reason:     reason,
+	})
+}
+
 // inferFieldNameFromMethod 从方法名推断字段名
 // GetExchangeRules -> ExchangeRules
 // GetFoo -> Foo
@@ -628,6 +707,10 @@ func (m *Mapper) extractSourcePath(expr ast.Expr) (sourcePath, convertExpr strin
 		if path, exists := m.varMap[e.Name]; exists {
 			return path, ""
 		}
+		// 内联辅助函数的参数
+		if path, exists := m.inlineParams[e.Name]; exists && path != "" {
+			return path, ""
+		}
 		return "", ""
 
 	case *ast.StarExpr:
@@ -657,6 +740,13 @@ func (m *Mapper) buildSelectorPath(expr *ast.SelectorExpr) string {
 			if x.Name == m.paramName {
 				return strings.Join(parts, ".")
 			}
+			// 字段别名或内联辅助函数的参数
+			if prefix, ok := m.resolveRootIdent(x.Name); ok {
+				if prefix != "" {
+					parts = append([]string{prefix}, parts...)
+				}
+				return strings.Join(parts, ".")
+			}
 			// 其他标识符，可能是包名
 			return ""
 		default:
@@ -710,31 +800,33 @@ func (m *Mapper) extractFromCallExpr(callExpr *ast.CallExpr) (sourcePath, conver
 
 // addMapping 添加映射到结果
 func (m *Mapper) addMapping(mapping FieldMapping2, fieldInfo *FieldAnalysisInfo, jsonColumn string) {
+	mapping.Guard = m.guard
+
 	// 检查是否是一对多映射（源路径包含点）
 	if strings.Contains(mapping.SourcePath, ".") && jsonColumn == "" && !fieldInfo.IsEmbedded && !fieldInfo.IsJSONType {
 		// 一对多映射
 		parts := strings.SplitN(mapping.SourcePath, ".", 2)
 		sourceField := parts[0]
 
-		// 查找或创建组
+		// 查找或创建组（守卫条件不同的映射不能合并到同一组）
 		for i := range m.result.Groups {
-			if m.result.Groups[i].Type == OneToMany && m.result.Groups[i].SourceField == sourceField {
-				m.result.Groups[i].Mappings = append(m.result.Groups[i].Mappings, mapping)
+			group := &m.result.Groups[i]
+			if group.Type == OneToMany && group.SourceField == sourceField && group.Guard == m.guard {
+				group.Mappings = append(group.Mappings, mapping)
 				return
 			}
 		}
 
 		// 创建新组
-		group := MappingGroup{
+		m.appendGroup(MappingGroup{
 			Type:        OneToMany,
 			SourceField: sourceField,
 			Mappings:    []FieldMapping2{mapping},
-		}
-		m.result.Groups = append(m.result.Groups, group)
+		})
 		return
 	}
 
-	// 一对一映射
+	// 一对一映射（每个映射单独生成，守卫条件记录在映射上）
 	for i := range m.result.Groups {
 		if m.result.Groups[i].Type == OneToOne {
 			m.result.Groups[i].Mappings = append(m.result.Groups[i].Mappings, mapping)
@@ -743,9 +835,19 @@ func (m *Mapper) addMapping(mapping FieldMapping2, fieldInfo *FieldAnalysisInfo,
 	}
 
 	// 创建一对一组
-	group := MappingGroup{
+	m.result.Groups = append(m.result.Groups, MappingGroup{
 		Type:     OneToOne,
 		Mappings: []FieldMapping2{mapping},
+	})
+}
+
+// appendGroup 添加映射组，组及其映射继承当前的守卫条件
+func (m *Mapper) appendGroup(group MappingGroup) {
+	if m.guard != "" {
+		group.Guard = m.guard
+		for i := range group.Mappings {
+			group.Mappings[i].Guard = m.guard
+		}
 	}
 	m.result.Groups = append(m.result.Groups, group)
 }
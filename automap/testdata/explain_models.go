@@ -0,0 +1,32 @@
+package testdata
+
+import "time"
+
+// ============================================================================
+// gogen explain mapping
+// ============================================================================
+
+// ExplainDomain 用于验证映射解释输出的领域模型
+type ExplainDomain struct {
+	ID        uint64
+	Name      string
+	CreatedAt time.Time
+}
+
+// ExplainPO 包含未覆盖列和无法识别表达式的 PO
+type ExplainPO struct {
+	ID        uint64 `gorm:"column:id;primaryKey"`
+	Name      string `gorm:"column:name"`
+	Status    string `gorm:"column:status"`
+	CreatedAt int64  `gorm:"column:created_at"`
+	Remark    string `gorm:"column:remark"`
+}
+
+func (p *ExplainPO) ToPO(d *ExplainDomain) *ExplainPO {
+	return &ExplainPO{
+		ID:        d.ID,
+		Name:      d.Name,
+		Status:    "active",
+		CreatedAt: d.CreatedAt.Unix(),
+	}
+}
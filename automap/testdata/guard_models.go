@@ -0,0 +1,62 @@
+package testdata
+
+// ============================================================================
+// 条件赋值：if 分支中的字段赋值生成带守卫条件的映射
+// ============================================================================
+
+// GuardedMeta 可选的元信息
+type GuardedMeta struct {
+	Source string
+	Region string
+}
+
+// GuardedDomain 带可选字段的领域模型
+type GuardedDomain struct {
+	ID       uint64
+	Name     string
+	Nickname string
+	Meta     *GuardedMeta
+	Score    int
+}
+
+// GuardedPO 字段在 ToPO 中按条件赋值的 PO
+type GuardedPO struct {
+	ID       uint64 `gorm:"column:id;primaryKey"`
+	Name     string `gorm:"column:name"`
+	Nickname string `gorm:"column:nickname"`
+	Source   string `gorm:"column:source"`
+	Region   string `gorm:"column:region"`
+	Score    int    `gorm:"column:score"`
+}
+
+func (p *GuardedPO) ToPO(d *GuardedDomain) *GuardedPO {
+	if d == nil {
+		return nil
+	}
+
+	po := &GuardedPO{
+		ID:   d.ID,
+		Name: d.Name,
+	}
+
+	// if/else：两个分支分别依赖不同的源字段
+	if d.Nickname != "" {
+		po.Nickname = d.Nickname
+	} else {
+		po.Nickname = d.Name
+	}
+
+	// 通过 if 初始化语句引入的别名
+	if meta := d.Meta; meta != nil {
+		po.Source = meta.Source
+		po.Region = meta.Region
+	}
+
+	// 条件引用了局部计算结果，无法在 ToPatch 中还原
+	bonus := d.Score * 2
+	if bonus > 100 {
+		po.Score = d.Score
+	}
+
+	return po
+}
@@ -0,0 +1,63 @@
+package testdata
+
+import "strings"
+
+// ============================================================================
+// 同包辅助函数：内联分析函数体，找出实际依赖的源字段
+// ============================================================================
+
+// HelperAddress 地址
+type HelperAddress struct {
+	Street string
+	City   string
+}
+
+// HelperDomain 领域模型
+type HelperDomain struct {
+	ID        uint64
+	FirstName string
+	LastName  string
+	Email     string
+	Address   HelperAddress
+}
+
+// HelperAddressPO 嵌入的地址列
+type HelperAddressPO struct {
+	Street string `gorm:"column:street"`
+	City   string `gorm:"column:city"`
+}
+
+// HelperPO 通过辅助函数构造字段的 PO
+type HelperPO struct {
+	ID       uint64          `gorm:"column:id;primaryKey"`
+	FullName string          `gorm:"column:full_name"`
+	Email    string          `gorm:"column:email"`
+	Address  HelperAddressPO `gorm:"embedded;embeddedPrefix:addr_"`
+}
+
+func (p *HelperPO) ToPO(d *HelperDomain) *HelperPO {
+	return &HelperPO{
+		ID:       d.ID,
+		FullName: helperFullName(d),
+		Email:    helperNormalizeEmail(d.Email),
+		Address:  toHelperAddressPO(d.Address),
+	}
+}
+
+// helperFullName 依赖多个源字段
+func helperFullName(d *HelperDomain) string {
+	return strings.TrimSpace(d.FirstName + " " + d.LastName)
+}
+
+// helperNormalizeEmail 依赖单个源字段
+func helperNormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// toHelperAddressPO 返回嵌入结构体字面量
+func toHelperAddressPO(a HelperAddress) HelperAddressPO {
+	return HelperAddressPO{
+		Street: a.Street,
+		City:   a.City,
+	}
+}
@@ -0,0 +1,62 @@
+package testdata
+
+import "gorm.io/datatypes"
+
+// ============================================================================
+// 循环构造切片：整体映射到循环遍历的源字段
+// ============================================================================
+
+// LoopItem 订单项
+type LoopItem struct {
+	SKU string
+	Qty int
+}
+
+// LoopItemJSON 订单项 JSON
+type LoopItemJSON struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+// LoopOrderDomain 订单领域模型
+type LoopOrderDomain struct {
+	ID    uint64
+	Items []LoopItem
+	Tags  []string
+	Notes []string
+}
+
+// LoopOrderPO 订单 PO
+type LoopOrderPO struct {
+	ID      uint64                            `gorm:"column:id;primaryKey"`
+	Items   datatypes.JSONSlice[LoopItemJSON] `gorm:"column:items"`
+	TagList string                            `gorm:"column:tag_list"`
+	Notes   datatypes.JSONSlice[string]       `gorm:"column:notes"`
+}
+
+func (p *LoopOrderPO) ToPO(d *LoopOrderDomain) *LoopOrderPO {
+	// range 循环构造局部切片
+	items := make([]LoopItemJSON, 0, len(d.Items))
+	for _, item := range d.Items {
+		items = append(items, LoopItemJSON{SKU: item.SKU, Qty: item.Qty})
+	}
+
+	po := &LoopOrderPO{
+		ID:    d.ID,
+		Items: datatypes.NewJSONSlice(items),
+	}
+
+	// 三段式 for 循环直接累加到字段
+	for i := 0; i < len(d.Tags); i++ {
+		po.TagList += d.Tags[i] + ","
+	}
+
+	// range 循环直接 append 到字段
+	for _, note := range d.Notes {
+		if note != "" {
+			po.Notes = append(po.Notes, note)
+		}
+	}
+
+	return po
+}
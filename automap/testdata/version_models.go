@@ -0,0 +1,27 @@
+package testdata
+
+// ============================================================================
+// 乐观锁版本列
+// ============================================================================
+
+// VersionedDomain 带版本号的领域模型
+type VersionedDomain struct {
+	ID      uint64
+	Title   string
+	Version int64
+}
+
+// VersionedPO 带版本列的 PO
+type VersionedPO struct {
+	ID      uint64 `gorm:"column:id;primaryKey"`
+	Title   string `gorm:"column:title"`
+	Version int64  `gorm:"column:version;version"`
+}
+
+func (p *VersionedPO) ToPO(d *VersionedDomain) *VersionedPO {
+	return &VersionedPO{
+		ID:      d.ID,
+		Title:   d.Title,
+		Version: d.Version,
+	}
+}
@@ -0,0 +1,149 @@
+package buildergen
+
+import (
+	"strings"
+
+	"github.com/donutnomad/gg"
+)
+
+// buildBuilder 生成 Builder 类型、构造函数、WithX 方法和 Build 方法
+func buildBuilder(gen *gg.Generator, t *targetInfo) {
+	group := gen.Body()
+	builderName := t.builderName
+
+	// 只有必填或带默认值的字段需要记录是否已设置
+	tracked := func(f fieldInfo) bool {
+		return f.Required || f.Default != ""
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s %s 的构建器", builderName, t.structName))
+	st := gg.Struct(builderName)
+	st.AddField("target", t.structName)
+	for _, f := range t.fields {
+		if tracked(f) {
+			st.AddField("has"+f.MethodName, "bool")
+		}
+	}
+	group.Append(st)
+
+	group.AddLine()
+	group.Append(gg.LineComment("New%s 创建 %s 的构建器", builderName, t.structName))
+	group.NewFunction("New"+builderName).
+		AddResult("", "*"+builderName).
+		AddBody(gg.Return(gg.S("&%s{}", builderName)))
+
+	for _, f := range t.fields {
+		paramName := avoidName(f.ParamName, "b")
+		body := []any{gg.S("b.target.%s = %s", f.AccessPath, paramName)}
+		if tracked(f) {
+			body = append(body, gg.S("b.has%s = true", f.MethodName))
+		}
+		body = append(body, gg.Return(gg.S("b")))
+
+		group.AddLine()
+		group.Append(gg.LineComment("With%s 设置 %s", f.MethodName, f.AccessPath))
+		group.NewFunction("With"+f.MethodName).
+			WithReceiver("b", "*"+builderName).
+			AddParameter(paramName, f.Type).
+			AddResult("", "*"+builderName).
+			AddBody(body...)
+	}
+
+	var required []string
+	for _, f := range t.fields {
+		if f.Required {
+			required = append(required, f.MethodName)
+		}
+	}
+
+	var body []any
+	if len(required) > 0 {
+		fmtPkg := gen.P("fmt")
+		stringsPkg := gen.P("strings")
+		body = append(body, gg.S("var missing []string"))
+		for _, f := range t.fields {
+			if f.Required {
+				body = append(body, gg.If(gg.S("!b.has%s", f.MethodName)).AddBody(
+					gg.S("missing = append(missing, %s)", gg.Lit(f.AccessPath)),
+				))
+			}
+		}
+		body = append(body, gg.If(gg.S("len(missing) > 0")).AddBody(
+			gg.Return(gg.S("%s{}", t.structName), fmtPkg.Call("Errorf",
+				gg.Lit("build "+t.structName+": missing required fields: %s"),
+				stringsPkg.Call("Join", gg.S("missing"), gg.Lit(", ")),
+			)),
+		))
+	}
+	body = append(body, gg.S("result := b.target"))
+	for _, f := range t.fields {
+		if f.Default != "" {
+			body = append(body, gg.If(gg.S("!b.has%s", f.MethodName)).AddBody(
+				gg.S("result.%s = %s", f.AccessPath, f.Default),
+			))
+		}
+	}
+	body = append(body, gg.Return(gg.S("result"), gg.S("nil")))
+
+	group.AddLine()
+	group.Append(gg.LineComment("Build 校验必填字段、填充未设置字段的默认值，返回 %s", t.structName))
+	group.NewFunction("Build").
+		WithReceiver("b", "*"+builderName).
+		AddResult("", t.structName).
+		AddResult("", "error").
+		AddBody(body...)
+}
+
+// buildOptions 生成函数式选项类型、WithX 构造函数和 NewXxx 构造函数
+func buildOptions(gen *gg.Generator, t *targetInfo) {
+	group := gen.Body()
+	optionName := t.structName + "Option"
+	recv := strings.ToLower(t.structName[:1])
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s %s 的函数式选项", optionName, t.structName))
+	group.AddType(optionName, gg.S("func(*%s)", t.structName))
+
+	for _, f := range t.fields {
+		paramName := avoidName(f.ParamName, recv)
+		funcName := t.optionPrefix + f.MethodName
+
+		group.AddLine()
+		group.Append(gg.LineComment("%s 设置 %s", funcName, f.AccessPath))
+		group.NewFunction(funcName).
+			AddParameter(paramName, f.Type).
+			AddResult("", optionName).
+			AddBody(
+				gg.Return(gg.S("func(%s *%s) {\n%s.%s = %s\n}", recv, t.structName, recv, f.AccessPath, paramName)),
+			)
+	}
+
+	body := []any{gg.S("%s := &%s{}", recv, t.structName)}
+	for _, f := range t.fields {
+		if f.Default != "" {
+			body = append(body, gg.S("%s.%s = %s", recv, f.AccessPath, f.Default))
+		}
+	}
+	body = append(body,
+		gg.For(gg.S("_, opt := range opts")).AddBody(gg.S("opt(%s)", recv)),
+		gg.Return(gg.S(recv)),
+	)
+
+	group.AddLine()
+	group.Append(gg.LineComment("New%s 创建 %s，先填充 default 标签的默认值，再依次应用 opts", t.structName, t.structName))
+	group.NewFunction("New"+t.structName).
+		AddParameter("opts", "..."+optionName).
+		AddResult("", "*"+t.structName).
+		AddBody(body...)
+}
+
+// avoidName 参数名与已使用的变量名冲突时追加 Val 后缀
+func avoidName(name string, used ...string) string {
+	for _, u := range used {
+		if name == u {
+			return name + "Val"
+		}
+	}
+	return name
+}
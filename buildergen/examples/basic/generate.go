@@ -0,0 +1,135 @@
+// Code generated by gogen. DO NOT EDIT.
+package basic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ================ buildergen ================
+
+// ServerConfigBuilder ServerConfig 的构建器
+type ServerConfigBuilder struct {
+	target          ServerConfig
+	hasReadTimeout  bool
+	hasWriteTimeout bool
+	hasHost         bool
+	hasPort         bool
+	hasTags         bool
+}
+
+// NewServerConfigBuilder 创建 ServerConfig 的构建器
+func NewServerConfigBuilder() *ServerConfigBuilder {
+	return &ServerConfigBuilder{}
+}
+
+// WithReadTimeout 设置 ReadTimeout
+func (b *ServerConfigBuilder) WithReadTimeout(readTimeout time.Duration) *ServerConfigBuilder {
+	b.target.ReadTimeout = readTimeout
+	b.hasReadTimeout = true
+	return b
+}
+
+// WithWriteTimeout 设置 WriteTimeout
+func (b *ServerConfigBuilder) WithWriteTimeout(writeTimeout time.Duration) *ServerConfigBuilder {
+	b.target.WriteTimeout = writeTimeout
+	b.hasWriteTimeout = true
+	return b
+}
+
+// WithHost 设置 Host
+func (b *ServerConfigBuilder) WithHost(host string) *ServerConfigBuilder {
+	b.target.Host = host
+	b.hasHost = true
+	return b
+}
+
+// WithPort 设置 Port
+func (b *ServerConfigBuilder) WithPort(port int) *ServerConfigBuilder {
+	b.target.Port = port
+	b.hasPort = true
+	return b
+}
+
+// WithTags 设置 Tags
+func (b *ServerConfigBuilder) WithTags(tags []string) *ServerConfigBuilder {
+	b.target.Tags = tags
+	b.hasTags = true
+	return b
+}
+
+// WithDebug 设置 Debug
+func (b *ServerConfigBuilder) WithDebug(debug bool) *ServerConfigBuilder {
+	b.target.Debug = debug
+	return b
+}
+
+// Build 校验必填字段、填充未设置字段的默认值，返回 ServerConfig
+func (b *ServerConfigBuilder) Build() (ServerConfig, error) {
+	var missing []string
+	if !b.hasHost {
+		missing = append(missing, "Host")
+	}
+	if len(missing) > 0 {
+		return ServerConfig{}, fmt.Errorf("build ServerConfig: missing required fields: %s", strings.Join(missing, ", "))
+	}
+	result := b.target
+	if !b.hasReadTimeout {
+		result.ReadTimeout = 5 * time.Second
+	}
+	if !b.hasWriteTimeout {
+		result.WriteTimeout = 10 * time.Second
+	}
+	if !b.hasPort {
+		result.Port = 8080
+	}
+	if !b.hasTags {
+		result.Tags = []string{"api", "internal"}
+	}
+	return result, nil
+}
+
+// ================ optionsgen ================
+
+// ClientOption Client 的函数式选项
+type ClientOption func(*Client)
+
+// WithBaseURL 设置 BaseURL
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithRetries 设置 Retries
+func WithRetries(retries int) ClientOption {
+	return func(c *Client) {
+		c.Retries = retries
+	}
+}
+
+// WithTimeout 设置 Timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithHeaders 设置 headers
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// NewClient 创建 Client，先填充 default 标签的默认值，再依次应用 opts
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{}
+	c.Retries = 3
+	c.Timeout = 30 * time.Second
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,30 @@
+package basic
+
+import "time"
+
+// Timeouts 超时配置，作为匿名嵌入字段展开
+type Timeouts struct {
+	ReadTimeout  time.Duration `default:"5s"`
+	WriteTimeout time.Duration `default:"10s"`
+}
+
+// ServerConfig 服务配置
+// Build 时 Host 必填，Port/Tags 未设置时使用默认值
+// @Builder
+type ServerConfig struct {
+	Timeouts
+	Host    string   `builder:"required"`
+	Port    int      `default:"8080"`
+	Tags    []string `default:"api,internal"`
+	Debug   bool
+	secrets map[string]string `builder:"-"`
+}
+
+// Client HTTP 客户端
+// @Options
+type Client struct {
+	BaseURL string
+	Retries int           `default:"3"`
+	Timeout time.Duration `default:"30s"`
+	headers map[string]string
+}
@@ -0,0 +1,294 @@
+package buildergen
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/donutnomad/gogen/internal/structparse"
+	"golang.org/x/tools/go/packages"
+)
+
+// builderTagKey 字段级配置使用的 struct tag 键
+//
+//	`builder:"required"` Build 时必须设置该字段
+//	`builder:"-"`        不为该字段生成 WithX
+const builderTagKey = "builder"
+
+// defaultTagKey 默认值 tag，如 `default:"8080"`
+const defaultTagKey = "default"
+
+// fieldInfo 生成代码所需的字段信息
+type fieldInfo struct {
+	Name       string // 字段名
+	Type       string // 带包前缀的字段类型
+	AccessPath string // 相对结构体的访问路径，gorm embedded 字段为 SourceField.Name
+	MethodName string // WithX 中的 X
+	ParamName  string // WithX 的参数名
+	Required   bool   // 是否必填（仅 Builder 使用）
+	Default    string // 默认值的 Go 表达式，为空表示没有默认值
+}
+
+// collectFields 从 structparse 结果中收集字段及其需要的导入；
+// resolveType 按访问路径返回字段的 go/types 类型，仅用于生成 default 标签的字面量
+func collectFields(info *structparse.StructInfo, resolveType func(accessPath string) (types.Type, error)) ([]fieldInfo, map[string]string, error) {
+	var fields []fieldInfo
+	imports := make(map[string]string)
+	methodOwners := make(map[string]string)
+
+	for _, f := range info.Fields {
+		// 未展开的匿名嵌入字段（如 sync.Mutex）没有可用的字段名
+		if !token.IsIdentifier(f.Name) {
+			continue
+		}
+
+		tag := reflect.StructTag(strings.Trim(f.Tag, "`"))
+		builderTag := strings.TrimSpace(tag.Get(builderTagKey))
+		if builderTag == "-" {
+			continue
+		}
+
+		fieldType := f.Type
+		if f.PkgAlias != "" && !strings.Contains(fieldType, ".") {
+			fieldType = f.PkgAlias + "." + fieldType
+		}
+
+		accessPath := f.Name
+		if f.SourceField != "" {
+			accessPath = f.SourceField + "." + f.Name
+		}
+
+		methodName := upperFirst(f.Name)
+		if owner, exists := methodOwners[methodName]; exists {
+			return nil, nil, fmt.Errorf("字段 %s 与 %s 生成的方法名 %s 冲突，请使用 builder:\"-\" 排除其中一个", accessPath, owner, methodName)
+		}
+		methodOwners[methodName] = accessPath
+
+		field := fieldInfo{
+			Name:       f.Name,
+			Type:       fieldType,
+			AccessPath: accessPath,
+			MethodName: methodName,
+			ParamName:  safeParamName(f.Name),
+			Required:   hasTagOption(builderTag, "required"),
+		}
+
+		if value, ok := tag.Lookup(defaultTagKey); ok {
+			typ, err := resolveType(accessPath)
+			if err != nil {
+				return nil, nil, fmt.Errorf("字段 %s: 解析类型失败: %w", accessPath, err)
+			}
+			expr, err := defaultLiteral(fieldType, typ, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("字段 %s 的 default 标签无效: %w", accessPath, err)
+			}
+			field.Default = expr
+		}
+
+		if f.PkgPath != "" {
+			imports[f.PkgPath] = f.PkgAlias
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, imports, nil
+}
+
+// defaultLiteral 将 default 标签的值转换为字段类型的 Go 表达式；typ 为字段类型的写法，t 为其 go/types 类型。
+// 命名类型与类型别名按底层类型生成无类型常量，值不符合底层类型时返回错误
+func defaultLiteral(typ string, t types.Type, value string) (string, error) {
+	t = types.Unalias(t)
+	if isDuration(t) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", err
+		}
+		return durationLiteral(d), nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return basicLiteral(typ, u, value)
+	case *types.Slice:
+		elem := types.Unalias(u.Elem())
+		if _, ok := elem.Underlying().(*types.Basic); !ok {
+			break
+		}
+		elemType, ok := strings.CutPrefix(typ, "[]")
+		if !ok {
+			elemType = types.TypeString(elem, (*types.Package).Name)
+		}
+		var elems []string
+		if value != "" {
+			for part := range strings.SplitSeq(value, ",") {
+				expr, err := defaultLiteral(elemType, elem, strings.TrimSpace(part))
+				if err != nil {
+					return "", err
+				}
+				elems = append(elems, expr)
+			}
+		}
+		return fmt.Sprintf("%s{%s}", typ, strings.Join(elems, ", ")), nil
+	}
+	return "", fmt.Errorf("不支持为类型 %s 设置默认值", typ)
+}
+
+// basicLiteral 按底层基础类型 b 校验并生成字面量，整数与浮点数按其位数检查范围
+func basicLiteral(typ string, b *types.Basic, value string) (string, error) {
+	if b.Kind() == types.Invalid {
+		return "", fmt.Errorf("无法解析类型 %s", typ)
+	}
+	bits := int(gcSizes.Sizeof(b) * 8)
+	info := b.Info()
+	var err error
+	switch {
+	case info&types.IsBoolean != 0:
+		var v bool
+		if v, err = strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(v), nil
+		}
+	case info&types.IsString != 0:
+		return strconv.Quote(value), nil
+	case info&types.IsUnsigned != 0:
+		if _, err = strconv.ParseUint(value, 0, bits); err == nil {
+			return value, nil
+		}
+	case info&types.IsInteger != 0:
+		if _, err = strconv.ParseInt(value, 0, bits); err == nil {
+			return value, nil
+		}
+	case info&types.IsFloat != 0:
+		if _, err = strconv.ParseFloat(value, bits); err == nil {
+			return value, nil
+		}
+	default:
+		return "", fmt.Errorf("不支持为类型 %s 设置默认值", typ)
+	}
+	if typ != b.Name() {
+		return "", fmt.Errorf("%q 不是 %s（底层类型 %s）的有效值: %w", value, typ, b.Name(), err)
+	}
+	return "", fmt.Errorf("%q 不是 %s 的有效值: %w", value, typ, err)
+}
+
+// gcSizes 计算基础类型的位数，int / uint / uintptr 按 64 位处理
+var gcSizes = types.SizesFor("gc", "amd64")
+
+// isDuration 判断类型是否为 time.Duration
+func isDuration(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+}
+
+// typeResolver 以 go/types 解析结构体字段的类型，按目录缓存已加载的包
+type typeResolver struct {
+	pkgs map[string]*packages.Package
+}
+
+func newTypeResolver() *typeResolver {
+	return &typeResolver{pkgs: make(map[string]*packages.Package)}
+}
+
+// fieldType 返回 filePath 中结构体 structName 访问路径为 accessPath（如 Account.ChainID）的字段类型
+func (r *typeResolver) fieldType(filePath, structName, accessPath string) (types.Type, error) {
+	pkg, err := r.load(filePath)
+	if err != nil {
+		return nil, err
+	}
+	obj := pkg.Types.Scope().Lookup(structName)
+	if obj == nil {
+		return nil, fmt.Errorf("包 %s 中未找到结构体 %s", pkg.Types.Name(), structName)
+	}
+	typ := obj.Type()
+	for name := range strings.SplitSeq(accessPath, ".") {
+		field, _, _ := types.LookupFieldOrMethod(typ, true, pkg.Types, name)
+		v, ok := field.(*types.Var)
+		if !ok {
+			return nil, fmt.Errorf("结构体 %s 中未找到字段 %s", structName, accessPath)
+		}
+		typ = v.Type()
+	}
+	return typ, nil
+}
+
+// load 加载源文件所在的包；包内存在类型错误时仍返回已检查出的类型信息
+func (r *typeResolver) load(filePath string) (*packages.Package, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(absPath)
+	if pkg, ok := r.pkgs[dir]; ok {
+		return pkg, nil
+	}
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes, Dir: dir}
+	pkgs, err := packages.Load(cfg, "file="+absPath)
+	if err != nil {
+		return nil, fmt.Errorf("加载包 %s 失败: %w", dir, err)
+	}
+	if len(pkgs) == 0 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("加载包 %s 失败: 未找到包", dir)
+	}
+	r.pkgs[dir] = pkgs[0]
+	return pkgs[0], nil
+}
+
+// durationLiteral 将 time.Duration 转换为可读的 Go 表达式，如 30 * time.Second
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d != 0 && d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
+// upperFirst 将首字母转换为大写
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// safeParamName 生成安全的参数名（避免 Go 关键词）
+func safeParamName(fieldName string) string {
+	r := []rune(fieldName)
+	r[0] = unicode.ToLower(r[0])
+	paramName := string(r)
+	if token.IsKeyword(paramName) {
+		return paramName + "Val"
+	}
+	return paramName
+}
+
+// hasTagOption 判断逗号分隔的 tag 值中是否包含指定选项
+func hasTagOption(tagValue, option string) bool {
+	for part := range strings.SplitSeq(tagValue, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), option) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package buildergen
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/structparse"
+	"github.com/donutnomad/gogen/plugin"
+)
+
+const generatorName = "buildergen"
+
+// GenMode 生成模式
+type GenMode int
+
+const (
+	ModeBuilder GenMode = iota // 生成 Builder
+	ModeOptions                // 生成函数式选项
+)
+
+// BuilderParams Builder 注解参数
+type BuilderParams struct {
+	Name string `param:"name=name,required=false,default=,description=构建器类型名，默认 <Type>Builder"`
+}
+
+// OptionsParams Options 注解参数
+type OptionsParams struct {
+	Prefix string `param:"name=prefix,required=false,default=With,description=选项构造函数前缀，同包多个 @Options 时用于避免重名"`
+}
+
+// BuilderGenerator 实现 plugin.Generator 接口，处理 @Builder
+type BuilderGenerator struct {
+	plugin.BaseGenerator
+}
+
+// NewBuilderGenerator 创建 Builder 生成器
+func NewBuilderGenerator() *BuilderGenerator {
+	gen := &BuilderGenerator{
+		BaseGenerator: *plugin.NewBaseGeneratorWithParamsStruct(
+			generatorName,
+			[]string{"Builder"},
+			[]plugin.TargetKind{plugin.TargetStruct},
+			BuilderParams{},
+		),
+	}
+	gen.SetPriority(45)
+	return gen
+}
+
+// OptionsGenerator 实现 plugin.Generator 接口，处理 @Options
+type OptionsGenerator struct {
+	plugin.BaseGenerator
+}
+
+// NewOptionsGenerator 创建 Options 生成器
+func NewOptionsGenerator() *OptionsGenerator {
+	gen := &OptionsGenerator{
+		BaseGenerator: *plugin.NewBaseGeneratorWithParamsStruct(
+			"optionsgen",
+			[]string{"Options"},
+			[]plugin.TargetKind{plugin.TargetStruct},
+			OptionsParams{},
+		),
+	}
+	gen.SetPriority(45)
+	return gen
+}
+
+// Generate 执行代码生成
+func (g *BuilderGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateResult, error) {
+	return generate(ctx, ModeBuilder, g.Name())
+}
+
+// Generate 执行代码生成
+func (g *OptionsGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateResult, error) {
+	return generate(ctx, ModeOptions, g.Name())
+}
+
+// targetInfo 存储单个目标的处理信息
+type targetInfo struct {
+	structName  string
+	packageName string
+	fields      []fieldInfo
+	imports     map[string]string // path -> alias
+
+	builderName  string // 仅 Builder 模式
+	optionPrefix string // 仅 Options 模式
+}
+
+// generate 通用生成逻辑，name 为当前生成器名称，用于查找插件特定的输出配置
+func generate(ctx *plugin.GenerateContext, mode GenMode, name string) (*plugin.GenerateResult, error) {
+	result := plugin.NewGenerateResult()
+
+	if len(ctx.Targets) == 0 {
+		return result, nil
+	}
+
+	annName, defaultFile := "Builder", "$FILE_builder.go"
+	if mode == ModeOptions {
+		annName, defaultFile = "Options", "$FILE_options.go"
+	}
+
+	parseCtx := structparse.NewParseContext()
+	resolver := newTypeResolver()
+
+	// 按输出文件分组处理
+	fileTargets := make(map[string][]*targetInfo)
+
+	for _, at := range ctx.Targets {
+		ann := plugin.GetAnnotation(at.Annotations, annName)
+		if ann == nil {
+			continue
+		}
+
+		t := &targetInfo{
+			structName:  at.Target.Name,
+			packageName: at.Target.PackageName,
+		}
+		switch mode {
+		case ModeBuilder:
+			params, _ := at.ParsedParams.(BuilderParams)
+			t.builderName = strings.TrimSpace(params.Name)
+			if t.builderName == "" {
+				t.builderName = at.Target.Name + "Builder"
+			}
+		case ModeOptions:
+			params, _ := at.ParsedParams.(OptionsParams)
+			t.optionPrefix = strings.TrimSpace(params.Prefix)
+			if t.optionPrefix == "" {
+				t.optionPrefix = "With"
+			}
+		}
+
+		structInfo, err := parseCtx.ParseStruct(at.Target.FilePath, at.Target.Name)
+		if err != nil {
+			result.AddError(fmt.Errorf("[%s] 解析结构体 %s 失败: %w", annName, at.Target.Name, err))
+			continue
+		}
+
+		t.fields, t.imports, err = collectFields(structInfo, func(accessPath string) (types.Type, error) {
+			return resolver.fieldType(at.Target.FilePath, at.Target.Name, accessPath)
+		})
+		if err != nil {
+			result.AddError(fmt.Errorf("[%s] 结构体 %s: %w", annName, at.Target.Name, err))
+			continue
+		}
+
+		fileConfig := ctx.GetFileConfig(at.Target.FilePath)
+		outputPath := plugin.GetOutputPath(at.Target, ann, defaultFile, fileConfig, name, ctx.DefaultOutput)
+		fileTargets[outputPath] = append(fileTargets[outputPath], t)
+
+		if ctx.Verbose {
+			fmt.Printf("[%s] 处理结构体 %s -> %s\n", annName, at.Target.Name, outputPath)
+		}
+	}
+
+	// 为每个输出文件生成 gg 定义
+	outputPaths := make([]string, 0, len(fileTargets))
+	for outputPath := range fileTargets {
+		outputPaths = append(outputPaths, outputPath)
+	}
+	slices.Sort(outputPaths)
+
+	for _, outputPath := range outputPaths {
+		targets := fileTargets[outputPath]
+		slices.SortFunc(targets, func(a, b *targetInfo) int {
+			return strings.Compare(a.structName, b.structName)
+		})
+		result.AddDefinition(outputPath, generateDefinition(targets, mode))
+	}
+
+	return result, nil
+}
+
+// generateDefinition 为一组目标生成 gg 定义
+func generateDefinition(targets []*targetInfo, mode GenMode) *gg.Generator {
+	gen := gg.New()
+	gen.SetPackage(targets[0].packageName)
+
+	for _, t := range targets {
+		for path, alias := range t.imports {
+			if alias != "" {
+				gen.PAlias(path, alias)
+			} else {
+				gen.P(path)
+			}
+		}
+
+		switch mode {
+		case ModeBuilder:
+			buildBuilder(gen, t)
+		case ModeOptions:
+			buildOptions(gen, t)
+		}
+	}
+
+	return gen
+}
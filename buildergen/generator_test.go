@@ -0,0 +1,288 @@
+package buildergen
+
+import (
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/donutnomad/gogen/internal/structparse"
+	"github.com/donutnomad/gogen/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedType 构造 pkg 包中底层类型为 underlying 的命名类型
+func namedType(pkg, name string, underlying types.Type) types.Type {
+	p := types.NewPackage(pkg, filepath.Base(pkg))
+	return types.NewNamed(types.NewTypeName(0, p, name, nil), underlying, nil)
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	duration := namedType("time", "Duration", types.Typ[types.Int64])
+	tests := []struct {
+		typ   string
+		t     types.Type
+		value string
+		want  string
+	}{
+		{"string", types.Typ[types.String], "localhost", `"localhost"`},
+		{"int", types.Typ[types.Int], "8080", "8080"},
+		{"uint8", types.Typ[types.Uint8], "0x10", "0x10"},
+		{"float64", types.Typ[types.Float64], "0.5", "0.5"},
+		{"bool", types.Typ[types.Bool], "true", "true"},
+		{"time.Duration", duration, "30s", "30 * time.Second"},
+		{"time.Duration", duration, "1500ms", "1500 * time.Millisecond"},
+		{"[]string", types.NewSlice(types.Typ[types.String]), "a, b", `[]string{"a", "b"}`},
+		{"[]int", types.NewSlice(types.Typ[types.Int]), "", "[]int{}"},
+		// 命名类型与别名按底层类型生成
+		{"LogLevel", namedType("app", "LogLevel", types.Typ[types.String]), "info", `"info"`},
+		{"Priority", namedType("app", "Priority", types.Typ[types.Int]), "3", "3"},
+		{"Ratio", namedType("app", "Ratio", types.Typ[types.Float32]), "1", "1"},
+		{"Level", types.NewAlias(types.NewTypeName(0, nil, "Level", nil), types.Typ[types.String]), "10", `"10"`},
+		{"Tags", namedType("app", "Tags", types.NewSlice(types.Typ[types.String])), "a,b", `Tags{"a", "b"}`},
+		{"[]time.Duration", types.NewSlice(duration), "1s", "[]time.Duration{1 * time.Second}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+"="+tt.value, func(t *testing.T) {
+			got, err := defaultLiteral(tt.typ, tt.t, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDefaultLiteral_Invalid(t *testing.T) {
+	duration := namedType("time", "Duration", types.Typ[types.Int64])
+	for _, tt := range []struct {
+		typ   string
+		t     types.Type
+		value string
+	}{
+		{"int", types.Typ[types.Int], "abc"},
+		{"int8", types.Typ[types.Int8], "300"},
+		{"uint", types.Typ[types.Uint], "-1"},
+		{"bool", types.Typ[types.Bool], "maybe"},
+		{"time.Duration", duration, "10"},
+		{"*string", types.NewPointer(types.Typ[types.String]), "x"},
+		{"map[string]int", types.NewMap(types.Typ[types.String], types.Typ[types.Int]), "x"},
+		{"Mode", namedType("app", "Mode", types.Typ[types.Int]), "fast"},
+		{"Point", namedType("app", "Point", types.NewStruct(nil, nil)), "1"},
+		{"[][]int", types.NewSlice(types.NewSlice(types.Typ[types.Int])), "1"},
+		{"Unknown", types.Typ[types.Invalid], "1"},
+	} {
+		_, err := defaultLiteral(tt.typ, tt.t, tt.value)
+		assert.Error(t, err, "%s=%s", tt.typ, tt.value)
+	}
+}
+
+func TestCollectFields(t *testing.T) {
+	info := &structparse.StructInfo{
+		Name: "Config",
+		Fields: []structparse.FieldInfo{
+			{Name: "Host", Type: "string", Tag: "`builder:\"required\"`"},
+			{Name: "Port", Type: "int", Tag: "`default:\"8080\"`"},
+			{Name: "Status", Type: "Status", PkgPath: "example.com/app/domain", PkgAlias: "domain"},
+			{Name: "ChainID", Type: "string", SourceField: "Account"},
+			{Name: "Secret", Type: "string", Tag: "`builder:\"-\"`"},
+			{Name: "sync.Mutex", Type: "sync.Mutex", PkgPath: "sync"},
+			{Name: "Type", Type: "string"},
+		},
+	}
+
+	fields, imports, err := collectFields(info, func(accessPath string) (types.Type, error) {
+		require.Equal(t, "Port", accessPath)
+		return types.Typ[types.Int], nil
+	})
+	require.NoError(t, err)
+	require.Len(t, fields, 5)
+
+	assert.Equal(t, "Host", fields[0].MethodName)
+	assert.True(t, fields[0].Required)
+	assert.Equal(t, "8080", fields[1].Default)
+	assert.Equal(t, "domain.Status", fields[2].Type)
+	assert.Equal(t, "Account.ChainID", fields[3].AccessPath)
+	assert.Equal(t, "Type", fields[4].MethodName)
+	assert.Equal(t, "typeVal", fields[4].ParamName)
+	assert.Equal(t, map[string]string{"example.com/app/domain": "domain"}, imports)
+}
+
+func TestCollectFields_MethodConflict(t *testing.T) {
+	info := &structparse.StructInfo{
+		Name: "Config",
+		Fields: []structparse.FieldInfo{
+			{Name: "name", Type: "string"},
+			{Name: "Name", Type: "string"},
+		},
+	}
+
+	_, _, err := collectFields(info, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "冲突")
+}
+
+func writeTestModel(t *testing.T) string {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "model.go")
+	err := os.WriteFile(testFile, []byte(`package testpkg
+
+import "time"
+
+type Base struct {
+	ID int64
+}
+
+type Server struct {
+	Base
+	Host    string        `+"`builder:\"required\"`"+`
+	Port    int           `+"`default:\"8080\"`"+`
+	Timeout time.Duration `+"`default:\"1m\"`"+`
+}
+`), 0644)
+	require.NoError(t, err)
+	return testFile
+}
+
+func newTestContext(testFile, annName string, params any) *plugin.GenerateContext {
+	return &plugin.GenerateContext{
+		Targets: []*plugin.AnnotatedTarget{
+			{
+				Target: &plugin.Target{
+					Kind:        plugin.TargetStruct,
+					Name:        "Server",
+					PackageName: "testpkg",
+					FilePath:    testFile,
+				},
+				Annotations:  []*plugin.Annotation{{Name: annName, Params: map[string]string{}}},
+				ParsedParams: params,
+			},
+		},
+	}
+}
+
+func generatedCode(t *testing.T, result *plugin.GenerateResult) string {
+	require.Empty(t, result.Errors)
+	require.Len(t, result.Definitions, 1)
+	for _, def := range result.Definitions {
+		formatted, err := format.Source(def.Bytes())
+		require.NoError(t, err, string(def.Bytes()))
+		return string(formatted)
+	}
+	return ""
+}
+
+func TestBuilderGenerator_Generate(t *testing.T) {
+	testFile := writeTestModel(t)
+
+	result, err := NewBuilderGenerator().Generate(newTestContext(testFile, "Builder", BuilderParams{}))
+	require.NoError(t, err)
+	code := generatedCode(t, result)
+
+	assert.Contains(t, code, "type ServerBuilder struct")
+	assert.Contains(t, code, "func NewServerBuilder() *ServerBuilder")
+	// 匿名嵌入字段被展开
+	assert.Contains(t, code, "func (b *ServerBuilder) WithID(iD int64) *ServerBuilder")
+	assert.Contains(t, code, "func (b *ServerBuilder) WithHost(host string) *ServerBuilder")
+	assert.Contains(t, code, "func (b *ServerBuilder) Build() (Server, error)")
+	assert.Contains(t, code, `missing = append(missing, "Host")`)
+	assert.Contains(t, code, "result.Port = 8080")
+	assert.Contains(t, code, "result.Timeout = 1 * time.Minute")
+	assert.Contains(t, code, `"time"`)
+}
+
+func TestBuilderGenerator_DefaultUnderlyingType(t *testing.T) {
+	write := func(t *testing.T, fields string) string {
+		testFile := filepath.Join(t.TempDir(), "model.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(`package testpkg
+
+type Mode int
+
+type Level = string
+
+type Point struct{ X, Y int }
+
+type Base struct {
+	Mode Mode `+"`default:\"2\"`"+`
+}
+
+type Server struct {
+	Base
+`+fields+`
+}
+`), 0644))
+		return testFile
+	}
+
+	testFile := write(t, "	Level Level `default:\"info\"`")
+	result, err := NewBuilderGenerator().Generate(newTestContext(testFile, "Builder", BuilderParams{}))
+	require.NoError(t, err)
+	code := generatedCode(t, result)
+	assert.Contains(t, code, "result.Mode = 2")
+	assert.Contains(t, code, `result.Level = "info"`)
+
+	for fields, want := range map[string]string{
+		"	Speed Mode `default:\"fast\"`": `"fast" 不是 Mode（底层类型 int）的有效值`,
+		"	Origin Point `default:\"0\"`":  "不支持为类型 Point 设置默认值",
+	} {
+		result, err := NewBuilderGenerator().Generate(newTestContext(write(t, fields), "Builder", BuilderParams{}))
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0].Error(), want)
+	}
+}
+
+func TestBuilderGenerator_CustomName(t *testing.T) {
+	testFile := writeTestModel(t)
+
+	result, err := NewBuilderGenerator().Generate(newTestContext(testFile, "Builder", BuilderParams{Name: "ServerFactory"}))
+	require.NoError(t, err)
+	code := generatedCode(t, result)
+
+	assert.Contains(t, code, "type ServerFactory struct")
+	assert.Contains(t, code, "func NewServerFactory() *ServerFactory")
+}
+
+func TestOptionsGenerator_Generate(t *testing.T) {
+	testFile := writeTestModel(t)
+
+	result, err := NewOptionsGenerator().Generate(newTestContext(testFile, "Options", OptionsParams{Prefix: "WithServer"}))
+	require.NoError(t, err)
+	code := generatedCode(t, result)
+
+	assert.Contains(t, code, "type ServerOption func(*Server)")
+	assert.Contains(t, code, "func WithServerHost(host string) ServerOption")
+	assert.Contains(t, code, "s.Host = host")
+	assert.Contains(t, code, "func NewServer(opts ...ServerOption) *Server")
+	assert.Contains(t, code, "s.Port = 8080")
+	assert.Contains(t, code, "opt(s)")
+}
+
+func TestGenerate_PluginOutput(t *testing.T) {
+	testFile := writeTestModel(t)
+	dir := filepath.Dir(testFile)
+	pkgConfigs := map[string]*plugin.PackageConfig{
+		dir: {
+			PackageDir: dir,
+			PluginOutputs: map[string]string{
+				"buildergen": "builder_out",
+				"optionsgen": "options_out",
+			},
+		},
+	}
+
+	ctx := newTestContext(testFile, "Options", OptionsParams{})
+	ctx.PackageConfigs = pkgConfigs
+	result, err := NewOptionsGenerator().Generate(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	assert.Contains(t, result.Definitions, filepath.Join(dir, "options_out.go"))
+
+	ctx = newTestContext(testFile, "Builder", BuilderParams{})
+	ctx.PackageConfigs = pkgConfigs
+	result, err = NewBuilderGenerator().Generate(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	assert.Contains(t, result.Definitions, filepath.Join(dir, "builder_out.go"))
+}
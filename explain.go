@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/donutnomad/gogen/automap"
+)
+
+// runExplain 处理 explain 子命令
+func runExplain(args []string) {
+	if len(args) == 0 {
+		explainUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "mapping":
+		if err := explainMapping(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "错误: 未知的 explain 目标 %q\n", args[0])
+		explainUsage()
+		os.Exit(1)
+	}
+}
+
+// explainMapping 打印 automap 对 ToPO 函数的映射分析结果
+// 用法: gogen explain mapping [-json] path/to/file.go Type.ToPO
+func explainMapping(args []string) error {
+	fs := flag.NewFlagSet("explain mapping", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "以 JSON 格式输出")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// 允许 -json 出现在位置参数之后
+	positional := fs.Args()
+	if len(positional) > 2 {
+		if err := fs.Parse(positional[2:]); err != nil {
+			return err
+		}
+		positional = positional[:2]
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("用法: gogen explain mapping [-json] <file.go> <Type.Method>")
+	}
+
+	filePath, funcName := positional[0], positional[1]
+	receiverType, methodName, ok := strings.Cut(funcName, ".")
+	if !ok || receiverType == "" || methodName == "" {
+		return fmt.Errorf("函数名格式错误，应为 Type.Method，实际为 %q", funcName)
+	}
+
+	result, err := automap.Parse(filePath, receiverType, methodName)
+	if err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", funcName, err)
+	}
+
+	explanation := automap.Explain(result)
+	if *jsonOutput {
+		return explanation.WriteJSON(os.Stdout)
+	}
+	return explanation.WriteTable(os.Stdout)
+}
+
+func explainUsage() {
+	_, _ = fmt.Fprintf(os.Stderr, `用法:
+  gogen explain mapping [-json] <file.go> <Type.Method>
+
+目标:
+  mapping   打印 automap 对 ToPO 函数推断出的映射关系、未覆盖的列和跳过的表达式
+
+示例:
+  gogen explain mapping models/user.go UserPO.ToPO
+  gogen explain mapping -json models/user.go UserPO.ToPO > testdata/user_mapping.json
+`)
+}
@@ -0,0 +1,56 @@
+package xast
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PackageDecls 返回目录中 Go 源文件声明的包级标识符（函数、类型、变量、常量），
+// 跳过测试文件与 skip 中的文件（通常为本次将要覆盖的生成文件），无法解析的文件忽略
+func PackageDecls(dir string, skip ...string) map[string]bool {
+	out := make(map[string]bool)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return out
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if slices.ContainsFunc(skip, func(s string) bool { return filepath.Clean(s) == path }) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					out[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						out[s.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, ident := range s.Names {
+							out[ident.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return out
+}
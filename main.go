@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/donutnomad/gogen/abigengen"
+	"github.com/donutnomad/gogen/buildergen"
 	"github.com/donutnomad/gogen/codegen"
 	"github.com/donutnomad/gogen/gormgen"
 	"github.com/donutnomad/gogen/mockgen"
@@ -34,6 +35,8 @@ func init() {
 	plugin.MustRegister(templategen.NewTemplateGenerator())
 	plugin.MustRegister(pickgen.NewPickGenerator())
 	plugin.MustRegister(pickgen.NewOmitGenerator())
+	plugin.MustRegister(buildergen.NewBuilderGenerator())
+	plugin.MustRegister(buildergen.NewOptionsGenerator())
 	plugin.MustRegister(abigengen.NewAbigenGenerator())
 }
 
@@ -69,6 +72,12 @@ func main() {
 		runGen(args[1:])
 	case "dev":
 		runDev(args[1:])
+	case "explain":
+		runExplain(args[1:])
+	case "stateflow":
+		runStateFlow(args[1:])
+	case "swag":
+		runSwag(args[1:])
 	default:
 		// 不是子命令，当作路径参数处理，执行 gen
 		runGen(args)
@@ -137,10 +146,17 @@ func usage() {
   gogen [选项] [路径...]
   gogen gen [选项] [路径...]
   gogen dev [选项] [路径...]
+  gogen explain mapping [-json] <file.go> <Type.Method>
+  gogen stateflow diagram [-format mermaid] [-name Order] [-o out] <file.go>
+  gogen stateflow lint [-name Order] [-strict] <file.go|dir>...
+  gogen swag diff --base <git-ref> [路径...]
 
 命令:
   gen     执行代码生成（默认）
   dev     启动开发模式，监听文件变动自动生成
+  explain 打印 automap 的映射分析结果，用于排查 ToPatch 生成问题
+  stateflow 状态流转工具（diagram: 导出 mermaid/dot/plantuml/ascii 流程图；lint: 静态分析）
+  swag    swaggen 工具（diff: 与 git 版本比较接口，检测破坏性变更）
 
 路径:
   支持 Go 包路径模式，如:
@@ -171,5 +187,9 @@ func usage() {
   gogen -no-output ./...                    每个生成器输出到独立文件
   gogen dev ./...                           开发模式，监听文件变动
   gogen -v dev ./models/...                 开发模式，详细输出
+  gogen explain mapping user.go UserPO.ToPO 查看 ToPO 的映射分析结果
+  gogen stateflow diagram order.go          输出 order.go 中状态流转的 mermaid 流程图
+  gogen stateflow lint ./models             检查 models 目录中的状态流转定义
+  gogen swag diff --base main ./api/...     检查 api 目录中相对 main 分支的接口破坏性变更
 `)
 }
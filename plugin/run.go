@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -279,6 +280,14 @@ func RunWithOptionsAndStats(ctx context.Context, opts *RunOptions) (*RunStats, e
 		allErrors = append(allErrors, genResult.Errors...)
 	}
 
+	// 非 Go 文件按原样写入
+	fileOutputs := make(map[string][]byte)
+	for _, genName := range genNames {
+		if genResult, ok := genResults[genName]; ok {
+			maps.Copy(fileOutputs, genResult.FileOutputs)
+		}
+	}
+
 	// 合并同一文件的定义并写入
 	writeStart := time.Now()
 	var totalMergeDuration, totalFormatDuration time.Duration
@@ -308,6 +317,14 @@ func RunWithOptionsAndStats(ctx context.Context, opts *RunOptions) (*RunStats, e
 			}
 		}
 	}
+	for _, path := range slices.Sorted(maps.Keys(fileOutputs)) {
+		if err := writeRawFile(path, fileOutputs[path]); err != nil {
+			allErrors = append(allErrors, fmt.Errorf("写入文件 %s 失败: %w", path, err))
+			continue
+		}
+		stats.FileCount++
+		fmt.Printf("生成文件: %s\n", path)
+	}
 	if opts.Verbose {
 		fmt.Printf("\n文件写入统计: %d 个文件, 合并总耗时: %v, 格式化+写入总耗时: %v, 总耗时: %v\n",
 			stats.FileCount, totalMergeDuration, totalFormatDuration, time.Since(writeStart))
@@ -419,6 +436,14 @@ func writeGGFile(path string, gen *gg.Generator) error {
 	return utils.WriteFormat(path, gen.Bytes())
 }
 
+// writeRawFile 写入非 Go 文件（不格式化）
+func writeRawFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
 // GetOutputPath 根据注解参数和默认规则计算输出路径
 // 优先级：注解参数 > 包级插件配置 > 包级默认配置 > 命令行参数 > 默认文件名
 // 模板变量：
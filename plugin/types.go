@@ -159,6 +159,11 @@ type GenerateResult struct {
 	// 注意: RawOutputs 中的文件不会与其他生成器的输出合并
 	RawOutputs map[string][]byte
 
+	// FileOutputs 是非 Go 文件输出（如流程图、JSON 文档）
+	// key: 输出文件路径, value: 文件内容
+	// 注意: FileOutputs 按原样写入，不做解析、格式化或合并
+	FileOutputs map[string][]byte
+
 	// Errors 错误列表
 	Errors []error
 
@@ -206,6 +211,7 @@ func NewGenerateResult() *GenerateResult {
 	return &GenerateResult{
 		Definitions: make(map[string]*gg.Generator),
 		RawOutputs:  make(map[string][]byte),
+		FileOutputs: make(map[string][]byte),
 	}
 }
 
@@ -235,6 +241,15 @@ func (r *GenerateResult) AddRawOutput(path string, data []byte) {
 	r.RawOutputs[path] = data
 }
 
+// AddFileOutput 添加非 Go 文件输出
+// 内容按原样写入目标路径
+func (r *GenerateResult) AddFileOutput(path string, data []byte) {
+	if r.FileOutputs == nil {
+		r.FileOutputs = make(map[string][]byte)
+	}
+	r.FileOutputs[path] = data
+}
+
 // AddError 添加错误
 func (r *GenerateResult) AddError(err error) {
 	r.Errors = append(r.Errors, err)
@@ -83,3 +83,20 @@ type Order struct {
 	TotalPrice float64
 	Status     string
 }
+
+// 示例 5: 生成 ApplyMergePatch 方法，将 JSON Merge Patch 通过 setter 记录到 ProfilePatch
+// @Setter(merge_patch="strict")
+type Profile struct {
+	ID       int64             `json:"id" setter:"readonly"`
+	Nickname string            `json:"nickname"`
+	Avatar   *string           `json:"avatar"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// 示例 6: 乐观锁 - ToMap 中版本列写入 version = version + 1，并生成 WhereVersion 条件
+// @Setter(patch="full", setter=false)
+type Inventory struct {
+	ID      int64 `gorm:"column:id;primaryKey"`
+	Stock   int   `gorm:"column:stock"`
+	Version int64 `gorm:"column:version;version"`
+}
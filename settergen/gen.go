@@ -10,7 +10,8 @@ import (
 )
 
 // generateToMapMethod 生成 ToMap 方法（full 模式）
-func generateToMapMethod(gen *gg.Generator, model *gormparse.GormModelInfo) {
+// versionColumn 非空时，该列写入 version = version + 1 而不是字段值
+func generateToMapMethod(gen *gg.Generator, model *gormparse.GormModelInfo, versionColumn string) {
 	rawModelName := model.Name
 	receiverVar := strings.ToLower(rawModelName[:1])
 
@@ -31,6 +32,13 @@ func generateToMapMethod(gen *gg.Generator, model *gormparse.GormModelInfo) {
 		if f.ColumnName == "" {
 			continue
 		}
+		if versionColumn != "" && f.ColumnName == versionColumn {
+			body = append(body, gg.NewInlineGroup().Append(
+				gg.S("values[%s] = ", gg.Lit(f.ColumnName)),
+				gen.P(gsqlImportPath).Call("Expr", gg.Lit(versionColumn+" + 1")),
+			))
+			continue
+		}
 		// 构建访问路径：如果是嵌入字段，使用 receiver.SourceField.Name
 		var accessPath string
 		if f.SourceField != "" {
@@ -13,6 +13,7 @@ import (
 	"github.com/donutnomad/gg"
 	"github.com/donutnomad/gogen/automap"
 	"github.com/donutnomad/gogen/internal/gormparse"
+	"github.com/donutnomad/gogen/internal/xast"
 	"github.com/donutnomad/gogen/plugin"
 )
 
@@ -23,6 +24,8 @@ type SetterParams struct {
 	Patch       string `param:"name=patch,required=false,default=none,description=Patch 模式: none|v2|full，支持组合如 v2|full"`
 	PatchMapper string `param:"name=patch_mapper,required=false,default=ToPO,description=Patch mapper 方法名"`
 	Setter      string `param:"name=setter,required=false,default=true,description=是否生成 setter 方法: true|false"`
+	Version     string `param:"name=version,required=false,default=,description=乐观锁版本字段名，未指定时识别 gorm:\"version\" 标签"`
+	MergePatch  string `param:"name=merge_patch,required=false,default=none,description=生成 ApplyMergePatch 方法(需 setter=true): none|strict|lenient，strict 拒绝未知字段"`
 }
 
 // SetterGenerator 实现 plugin.Generator 接口
@@ -81,6 +84,26 @@ func (c *generateCache) parseGormModel(filePath, structName string) (*gormparse.
 	return model, err
 }
 
+// parseMapperModel 解析 v2 mapper 的接收者类型（PO），mapperMethod 为 [Type.Method, 文件路径]
+// 优先在 mapper 所在文件中查找，找不到时查找同目录的其他文件
+func (c *generateCache) parseMapperModel(mapperMethod [2]string) (*gormparse.GormModelInfo, error) {
+	typeName, _, _ := strings.Cut(mapperMethod[0], ".")
+	model, err := c.parseGormModel(mapperMethod[1], typeName)
+	if err == nil {
+		return model, nil
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(mapperMethod[1]), "*.go"))
+	for _, file := range files {
+		if file == mapperMethod[1] || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if model, e := c.parseGormModel(file, typeName); e == nil {
+			return model, nil
+		}
+	}
+	return nil, err
+}
+
 // getDirMethods 带缓存的目录方法解析
 func (c *generateCache) getDirMethods(dir string) []methodInfo {
 	if methods, ok := c.dirMethodCache[dir]; ok {
@@ -154,18 +177,46 @@ func (g *SetterGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.Generat
 		fileConfig := ctx.GetFileConfig(at.Target.FilePath)
 		outputPath := plugin.GetOutputPath(at.Target, ann, "$FILE_setter.go", fileConfig, g.Name(), ctx.DefaultOutput)
 
+		// 识别乐观锁版本字段：full 模式的 ToMap 写入 @Setter 结构体自身的列
+		patchModes := parsePatchModes(params.Patch)
+		var versionField *gormparse.GormFieldInfo
+		if slices.Contains(patchModes, "full") {
+			versionField, err = findVersionField(gormModel, params.Version)
+			if err != nil {
+				result.AddError(err)
+				continue
+			}
+		}
+
 		// 收集 mapper 方法信息（使用缓存）
 		var mapperMethod *[2]string
-		patchModeForMapper := strings.ToLower(params.Patch)
-		if patchModeForMapper == "v2" {
+		var mapperModel *gormparse.GormModelInfo
+		var mapperVersionField *gormparse.GormFieldInfo
+		if slices.Contains(patchModes, "v2") {
 			dir := filepath.Dir(at.Target.FilePath)
 			mapperMethod = g.processPatchMapperCached(cache, dir, at.Target.Name, &params)
+			// v2 的 ToPatch 写入 mapper 接收者（PO）的列，版本字段从 PO 识别
+			if mapperMethod != nil {
+				mapperModel, err = cache.parseMapperModel(*mapperMethod)
+				if err != nil {
+					result.AddError(fmt.Errorf("解析 %s 的 mapper 目标失败: %w", at.Target.Name, err))
+					continue
+				}
+				mapperVersionField, err = findVersionField(mapperModel, params.Version)
+				if err != nil {
+					result.AddError(err)
+					continue
+				}
+			}
 		}
 
 		fileTargets[outputPath] = append(fileTargets[outputPath], &targetInfo{
-			model:        gormModel,
-			params:       &SetterParams{Patch: params.Patch, PatchMapper: params.PatchMapper, Setter: params.Setter},
-			mapperMethod: mapperMethod,
+			model:              gormModel,
+			params:             &SetterParams{Patch: params.Patch, PatchMapper: params.PatchMapper, Setter: params.Setter, Version: params.Version, MergePatch: params.MergePatch},
+			mapperMethod:       mapperMethod,
+			versionField:       versionField,
+			mapperModel:        mapperModel,
+			mapperVersionField: mapperVersionField,
 		})
 
 		if ctx.Verbose {
@@ -184,6 +235,18 @@ func (g *SetterGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.Generat
 	}
 	slices.Sort(outputPaths)
 
+	// ErrStaleVersion/CheckVersion 与 merge patch 的合并函数是包级声明，
+	// 每个包只生成一次，包内其他文件（如另一次运行的输出）已声明时不再生成
+	staleVersionDirs := make(map[string]bool)
+	mergePatchDirs := make(map[string]bool)
+	packageDecls := make(map[string]map[string]bool)
+	declared := func(dir, name string) bool {
+		if _, ok := packageDecls[dir]; !ok {
+			packageDecls[dir] = xast.PackageDecls(dir, outputPaths...)
+		}
+		return packageDecls[dir][name]
+	}
+
 	for _, outputPath := range outputPaths {
 		targets := fileTargets[outputPath]
 		// 按结构体名称排序，确保同一文件中不同结构体的顺序一致
@@ -201,6 +264,22 @@ func (g *SetterGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.Generat
 			result.AddError(fmt.Errorf("生成 %s 失败: %w", outputPath, err))
 			continue
 		}
+		if dir := filepath.Dir(outputPath); !staleVersionDirs[dir] && slices.ContainsFunc(targets, func(t *targetInfo) bool {
+			return t.versionField != nil || t.mapperVersionField != nil
+		}) {
+			if !declared(dir, staleVersionHelperName) {
+				generateStaleVersionHelpers(gen)
+			}
+			staleVersionDirs[dir] = true
+		}
+		if dir := filepath.Dir(outputPath); !mergePatchDirs[dir] && slices.ContainsFunc(targets, func(t *targetInfo) bool {
+			return parseBoolParam(t.params.Setter) && parseMergePatchMode(t.params.MergePatch) != mergePatchNone
+		}) {
+			if !declared(dir, mergePatchHelperName) {
+				generateMergePatchHelpers(gen)
+			}
+			mergePatchDirs[dir] = true
+		}
 		result.AddDefinition(outputPath, gen)
 	}
 
@@ -212,6 +291,10 @@ type targetInfo struct {
 	model        *gormparse.GormModelInfo
 	params       *SetterParams
 	mapperMethod *[2]string
+	versionField *gormparse.GormFieldInfo // @Setter 结构体的乐观锁版本字段（full 模式），nil 表示无
+
+	mapperModel        *gormparse.GormModelInfo // v2 mapper 的接收者（PO），ToPatch 生成在该类型上
+	mapperVersionField *gormparse.GormFieldInfo // PO 的乐观锁版本字段（v2 模式），nil 表示无
 }
 
 // generateDefinitionCached 为一组目标生成 gg 定义（使用缓存）
@@ -247,12 +330,15 @@ func (g *SetterGenerator) generateDefinitionCached(cache *generateCache, targets
 		if parseBoolParam(t.params.Setter) {
 			// 生成 Patch 结构体和 setter 方法
 			generateSetterV1(gen, t.model)
+
+			// 生成 ApplyMergePatch 方法
+			if mode := parseMergePatchMode(t.params.MergePatch); mode != mergePatchNone {
+				generateApplyMergePatchMethod(gen, t.model, mode)
+			}
 		}
 
 		// 处理 patch 模式（支持 v2|full 多值输入）
-		patchModes := strings.Split(strings.ToLower(t.params.Patch), "|")
-		for _, patchMode := range patchModes {
-			patchMode = strings.TrimSpace(patchMode)
+		for _, patchMode := range parsePatchModes(t.params.Patch) {
 			switch patchMode {
 			case "v2":
 				// 使用 automap 生成 ToPatch 方法（使用共享缓存）
@@ -260,7 +346,8 @@ func (g *SetterGenerator) generateDefinitionCached(cache *generateCache, targets
 					fileCtx := (*t.mapperMethod)[1]
 					dir := filepath.Dir(fileCtx)
 					automapCtx := cache.getAutomapCtx(dir)
-					_, code, imports, err := automap.Generate2WithCache((*t.mapperMethod)[0], "ToPatch", automapCtx, automap.WithFileContext(fileCtx))
+					_, code, imports, err := automap.Generate2WithCache((*t.mapperMethod)[0], "ToPatch", automapCtx,
+						automap.WithFileContext(fileCtx), automap.WithVersionColumn(versionColumnName(t.mapperVersionField)))
 					if err != nil {
 						return nil, fmt.Errorf("生成 ToPatch 代码失败: %w", err)
 					}
@@ -278,18 +365,35 @@ func (g *SetterGenerator) generateDefinitionCached(cache *generateCache, targets
 				}
 			case "full":
 				// 生成 ToMap 方法
-				generateToMapMethod(gen, t.model)
+				generateToMapMethod(gen, t.model, versionColumnName(t.versionField))
 			case "", "none":
 				// 不生成
 			default:
 				fmt.Printf("[settergen] 警告: 结构体 %s 的 patch=%s 不支持，可选值: none|v2|full\n", t.model.Name, patchMode)
 			}
 		}
+
+		// 生成乐观锁条件方法，@Setter 结构体与 PO 相同时只生成一次
+		if t.versionField != nil {
+			generateWhereVersionMethod(gen, t.model, t.versionField)
+		}
+		if t.mapperVersionField != nil && (t.versionField == nil || t.mapperModel.Name != t.model.Name) {
+			generateWhereVersionMethod(gen, t.mapperModel, t.mapperVersionField)
+		}
 	}
 
 	return gen, nil
 }
 
+// parsePatchModes 解析 patch 参数（支持 v2|full 多值输入），返回小写的模式列表
+func parsePatchModes(s string) []string {
+	var modes []string
+	for mode := range strings.SplitSeq(strings.ToLower(s), "|") {
+		modes = append(modes, strings.TrimSpace(mode))
+	}
+	return modes
+}
+
 // processPatchMapperCached 使用缓存处理 patch_mapper 参数
 func (g *SetterGenerator) processPatchMapperCached(cache *generateCache, fileDir string, structName string, params *SetterParams) *[2]string {
 	patchMapper := params.PatchMapper
@@ -241,7 +241,7 @@ func TestGenerateToMapMethod_DirectFields(t *testing.T) {
 
 	gen := gg.New()
 	gen.SetPackage("models")
-	generateToMapMethod(gen, model)
+	generateToMapMethod(gen, model, "")
 
 	code := gen.String()
 
@@ -274,7 +274,7 @@ func TestGenerateToMapMethod_EmbeddedFields(t *testing.T) {
 
 	gen := gg.New()
 	gen.SetPackage("models")
-	generateToMapMethod(gen, model)
+	generateToMapMethod(gen, model, "")
 
 	code := gen.String()
 
@@ -313,7 +313,7 @@ func TestGenerateToMapMethod_NestedEmbeddedFields(t *testing.T) {
 
 	gen := gg.New()
 	gen.SetPackage("models")
-	generateToMapMethod(gen, model)
+	generateToMapMethod(gen, model, "")
 
 	code := gen.String()
 
@@ -342,7 +342,7 @@ func TestGenerateToMapMethod_MixedEmbedded(t *testing.T) {
 
 	gen := gg.New()
 	gen.SetPackage("models")
-	generateToMapMethod(gen, model)
+	generateToMapMethod(gen, model, "")
 
 	code := gen.String()
 
@@ -0,0 +1,276 @@
+package settergen
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/gormparse"
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// merge_patch 参数的取值
+const (
+	mergePatchNone    = "none"    // 不生成 ApplyMergePatch
+	mergePatchStrict  = "strict"  // 未知字段返回错误
+	mergePatchLenient = "lenient" // 忽略未知字段
+)
+
+// setterTagKey 字段级配置使用的 struct tag 键
+//
+//	`setter:"readonly"` 字段只读，merge patch 中出现该字段时返回错误
+//	`setter:"-"`        字段不参与 merge patch，视为未知字段
+const setterTagKey = "setter"
+
+// mergePatchHelperName 生成到包中的合并函数名，用于判断包内是否已经声明
+const mergePatchHelperName = "mergePatchApply"
+
+// mergePatchField ApplyMergePatch 中单个字段的信息
+type mergePatchField struct {
+	Name     string // Go 字段名
+	Type     string // Go 字段类型
+	JSONKey  string // JSON 键名
+	ReadOnly bool   // 是否只读
+}
+
+// parseMergePatchMode 解析 merge_patch 参数，无法识别时返回 none
+func parseMergePatchMode(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case mergePatchStrict, "true":
+		return mergePatchStrict
+	case mergePatchLenient:
+		return mergePatchLenient
+	default:
+		return mergePatchNone
+	}
+}
+
+// collectMergePatchFields 收集可以出现在 merge patch 中的字段
+// 与 setter 方法使用相同的字段集合，额外跳过未导出字段以及 json:"-"/setter:"-" 的字段
+func collectMergePatchFields(model *gormparse.GormModelInfo) []mergePatchField {
+	var fields []mergePatchField
+	for _, f := range model.Fields {
+		if strings.ToLower(f.Name) == "patch" {
+			continue
+		}
+		if !isExportedName(f.Name) {
+			continue
+		}
+
+		tag := reflect.StructTag(strings.Trim(f.Tag, "`"))
+		setterTag := strings.TrimSpace(tag.Get(setterTagKey))
+		if setterTag == "-" {
+			continue
+		}
+
+		jsonKey := f.Name
+		if jsonTag, ok := tag.Lookup("json"); ok {
+			name, _, _ := strings.Cut(jsonTag, ",")
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				jsonKey = name
+			}
+		}
+
+		fields = append(fields, mergePatchField{
+			Name:     f.Name,
+			Type:     f.Type,
+			JSONKey:  jsonKey,
+			ReadOnly: hasTagOption(setterTag, "readonly"),
+		})
+	}
+	return fields
+}
+
+// generateApplyMergePatchMethod 生成 ApplyMergePatch 方法（RFC 7386 JSON Merge Patch）
+//
+// 生成的方法逐个字段调用 setX，因此所有变更都会记录到 XxxPatch 中，
+// 后续 ExportPatch()/ToPatch 可以直接得到数据库更新内容：
+//   - 缺失的字段不做任何修改
+//   - null 将字段重置为零值
+//   - 对象与字段当前值递归合并（map、结构体及其指针），成员为 null 时删除或重置为零值，见 mergePatchApply
+//   - 其他值整体替换
+//   - 键名与 encoding/json 一致：优先精确匹配，否则忽略大小写匹配；同一字段出现多次时返回错误
+//   - 任一字段校验失败时返回错误，且不修改原对象
+//
+// 依赖的 mergePatchApply 等函数由 generateMergePatchHelpers 在每个包中生成一次
+func generateApplyMergePatchMethod(gen *gg.Generator, model *gormparse.GormModelInfo, mode string) {
+	rawModelName := model.Name
+	receiverVar := strings.ToLower(rawModelName[:1])
+	jsonPkg := gen.P("encoding/json")
+	fmtPkg := gen.P("fmt")
+
+	errPrefix := "merge patch " + rawModelName
+
+	fields := collectMergePatchFields(model)
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, strconv.Quote(f.JSONKey))
+	}
+
+	sw := gg.Switch("name")
+	for _, f := range fields {
+		if f.ReadOnly {
+			sw.NewCase(gg.Lit(f.JSONKey)).AddBody(
+				gg.Return(fmtPkg.Call("Errorf", gg.Lit(errPrefix+": field %q is read-only"), gg.S("key"))),
+			)
+			continue
+		}
+
+		sw.NewCase(gg.Lit(f.JSONKey)).AddBody(
+			gg.NewInlineGroup().Append(
+				gg.S("value, err := %s(next.%s, raw)", mergePatchHelperName, f.Name),
+			),
+			gg.If(gg.S("err != nil")).AddBody(
+				gg.Return(fmtPkg.Call("Errorf", gg.Lit(errPrefix+": field %q: %w"), gg.S("key"), gg.S("err"))),
+			),
+			gg.S("next.set%s(value)", f.Name),
+		)
+	}
+	if mode == mergePatchStrict {
+		sw.NewDefault().AddBody(
+			gg.Return(fmtPkg.Call("Errorf", gg.Lit(errPrefix+": unknown field %q"), gg.S("key"))),
+		)
+	}
+
+	gen.Body().AddLine()
+	gen.Body().AddLineComment("ApplyMergePatch 按 RFC 7386 应用 JSON Merge Patch，变更记录到 %sPatch", rawModelName)
+	gen.Body().NewFunction("ApplyMergePatch").
+		WithReceiver(receiverVar, "*"+rawModelName).
+		AddParameter("data", "[]byte").
+		AddResult("", "error").
+		AddBody(
+			gg.NewInlineGroup().Append(gg.S("var fields map[string]"), jsonPkg.Type("RawMessage")),
+			gg.If(gg.NewInlineGroup().Append(
+				gg.S("err := "),
+				jsonPkg.Call("Unmarshal", gg.S("data"), gg.S("&fields")),
+				gg.S("; err != nil"),
+			)).AddBody(
+				gg.Return(fmtPkg.Call("Errorf", gg.Lit(errPrefix+": %w"), gg.S("err"))),
+			),
+			// 在副本上应用，任一字段出错时保持原对象不变
+			gg.S("next := *%s", receiverVar),
+			gg.S("seen := make(map[string]string, len(fields))"),
+			gg.For(gg.S("key, raw := range fields")).AddBody(
+				gg.S("name := mergePatchKey(key, %s)", strings.Join(keys, ", ")),
+				gg.If(gg.S("prev, ok := seen[name]; ok")).AddBody(
+					gg.Return(fmtPkg.Call("Errorf", gg.Lit(errPrefix+": duplicate field %q and %q"), gg.S("prev"), gg.S("key"))),
+				),
+				gg.S("seen[name] = key"),
+				sw,
+			),
+			gg.S("*%s = next", receiverVar),
+			gg.Return(gg.S("nil")),
+		)
+}
+
+// mergePatchHelpersTemplate 生成到包中的 RFC 7386 合并函数
+const mergePatchHelpersTemplate = `
+// mergePatchApply 按 RFC 7386 将 patch 合并到 current，返回合并后的值，current 本身不被修改：
+//   - null 返回零值（指针、切片、map 为 nil）
+//   - 对象与 current 的 JSON 表示递归合并：成员为 null 时删除（结构体字段重置为零值），
+//     成员为对象时继续合并，其余成员直接替换；current 不是对象（如 nil 指针）时从空对象开始
+//   - 其他值（数字、字符串、数组等）整体替换
+func mergePatchApply[T any](current T, patch []byte) (T, error) {
+	var out T
+	patch = {{.Bytes}}.TrimSpace(patch)
+	switch {
+	case string(patch) == "null":
+		return out, nil
+	case len(patch) > 0 && patch[0] == '{':
+		base, err := {{.JSON}}.Marshal(current)
+		if err != nil {
+			return out, err
+		}
+		var target, changes any
+		if err := mergePatchDecode(base, &target); err != nil {
+			return out, err
+		}
+		if err := mergePatchDecode(patch, &changes); err != nil {
+			return out, err
+		}
+		merged, err := {{.JSON}}.Marshal(mergePatchMerge(target, changes))
+		if err != nil {
+			return out, err
+		}
+		patch = merged
+	}
+	if err := {{.JSON}}.Unmarshal(patch, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// mergePatchMerge 对解码后的 JSON 值执行 RFC 7386 的 MergePatch(target, patch)，可能修改 target 中的 map
+func mergePatchMerge(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = make(map[string]any, len(changes))
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+		} else {
+			object[key] = mergePatchMerge(object[key], value)
+		}
+	}
+	return object
+}
+
+// mergePatchDecode 解码 JSON，数字保留为 json.Number 以免大整数丢失精度
+func mergePatchDecode(data []byte, v any) error {
+	dec := {{.JSON}}.NewDecoder({{.Bytes}}.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// mergePatchKey 按 encoding/json 的规则将 patch 中的键映射到字段键名：优先精确匹配，否则忽略大小写匹配，都不匹配时原样返回
+func mergePatchKey(key string, known ...string) string {
+	if {{.Slices}}.Contains(known, key) {
+		return key
+	}
+	for _, k := range known {
+		if {{.Strings}}.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+`
+
+// generateMergePatchHelpers 生成 ApplyMergePatch 依赖的包级合并函数，每个包只需生成一次
+func generateMergePatchHelpers(gen *gg.Generator) {
+	gen.Body().AddLine()
+	gen.Body().AddString(strings.TrimSpace(utils.MustExecuteTemplate(map[string]any{
+		"Bytes":   gen.P("bytes").Alias(),
+		"JSON":    gen.P("encoding/json").Alias(),
+		"Slices":  gen.P("slices").Alias(),
+		"Strings": gen.P("strings").Alias(),
+	}, mergePatchHelpersTemplate)))
+}
+
+// hasTagOption 判断逗号分隔的 tag 值中是否包含指定选项
+func hasTagOption(tagValue, option string) bool {
+	for part := range strings.SplitSeq(tagValue, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), option) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExportedName 判断字段名是否导出
+func isExportedName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package settergen
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/gormparse"
+)
+
+func mergePatchTestModel() *gormparse.GormModelInfo {
+	return &gormparse.GormModelInfo{
+		Name:        "User",
+		PackageName: "models",
+		Fields: []gormparse.GormFieldInfo{
+			{Name: "ID", Type: "int64", Tag: "`json:\"id\" setter:\"readonly\"`"},
+			{Name: "Name", Type: "string", Tag: "`json:\"name,omitempty\"`"},
+			{Name: "Meta", Type: "*Meta"},
+			{Name: "Tags", Type: "[]string", Tag: "`json:\"tags\"`"},
+			{Name: "Password", Type: "string", Tag: "`json:\"-\"`"},
+			{Name: "Internal", Type: "string", Tag: "`setter:\"-\"`"},
+			{Name: "patch", Type: "UserPatch"},
+		},
+	}
+}
+
+func TestParseMergePatchMode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", mergePatchNone},
+		{"none", mergePatchNone},
+		{"strict", mergePatchStrict},
+		{"TRUE", mergePatchStrict},
+		{"lenient", mergePatchLenient},
+		{"unknown", mergePatchNone},
+	}
+	for _, tt := range tests {
+		if got := parseMergePatchMode(tt.input); got != tt.want {
+			t.Errorf("parseMergePatchMode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCollectMergePatchFields(t *testing.T) {
+	fields := collectMergePatchFields(mergePatchTestModel())
+
+	got := make(map[string]mergePatchField)
+	for _, f := range fields {
+		got[f.Name] = f
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("collectMergePatchFields() got %d fields, want 4: %+v", len(got), fields)
+	}
+	if f := got["ID"]; f.JSONKey != "id" || !f.ReadOnly {
+		t.Errorf("ID 应为只读且键名为 id，实际 %+v", f)
+	}
+	if f := got["Name"]; f.JSONKey != "name" || f.ReadOnly {
+		t.Errorf("Name 键名应为 name，实际 %+v", f)
+	}
+	if f := got["Meta"]; f.JSONKey != "Meta" {
+		t.Errorf("Meta 无 json tag 时应使用字段名，实际 %+v", f)
+	}
+	for _, name := range []string{"Password", "Internal", "patch"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("字段 %s 不应出现在 merge patch 中", name)
+		}
+	}
+}
+
+func TestGenerateApplyMergePatchMethod(t *testing.T) {
+	tests := []struct {
+		mode          string
+		expectUnknown bool
+	}{
+		{mergePatchStrict, true},
+		{mergePatchLenient, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			model := mergePatchTestModel()
+			gen := gg.New()
+			gen.SetPackage("models")
+			generateSetterV1(gen, model)
+			generateApplyMergePatchMethod(gen, model, tt.mode)
+
+			formatted, err := format.Source([]byte(gen.String()))
+			if err != nil {
+				t.Fatalf("生成的代码无法格式化: %v\n%s", err, gen.String())
+			}
+			code := string(formatted)
+
+			for _, want := range []string{
+				"func (u *User) ApplyMergePatch(data []byte) error",
+				`"encoding/json"`,
+				`case "id":`,
+				`"merge patch User: field %q is read-only"`,
+				`case "name":`,
+				`name := mergePatchKey(key, "id", "name", "Meta", "tags")`,
+				`"merge patch User: duplicate field %q and %q"`,
+				"value, err := mergePatchApply(next.Name, raw)",
+				"next.setName(value)",
+				`case "Meta":`,
+				"value, err := mergePatchApply(next.Meta, raw)",
+				"next.setMeta(value)",
+				"next.setTags(value)",
+				"*u = next",
+			} {
+				if !strings.Contains(code, want) {
+					t.Errorf("生成的代码应包含 %q，实际代码:\n%s", want, code)
+				}
+			}
+
+			for _, unwanted := range []string{`case "Password":`, `case "Internal":`, "next.setID(", "github.com/donutnomad/gogen"} {
+				if strings.Contains(code, unwanted) {
+					t.Errorf("生成的代码不应包含 %q，实际代码:\n%s", unwanted, code)
+				}
+			}
+
+			hasUnknown := strings.Contains(code, `"merge patch User: unknown field %q"`)
+			if hasUnknown != tt.expectUnknown {
+				t.Errorf("unknown field 检查 = %v, want %v\n%s", hasUnknown, tt.expectUnknown, code)
+			}
+		})
+	}
+}
+
+// mergePatchRunModel 与 mergePatchRunSource 中的 User 结构体对应
+func mergePatchRunModel() *gormparse.GormModelInfo {
+	return &gormparse.GormModelInfo{
+		Name:        "User",
+		PackageName: "main",
+		Fields: []gormparse.GormFieldInfo{
+			{Name: "ID", Type: "int64", Tag: "`json:\"id\" setter:\"readonly\"`"},
+			{Name: "Name", Type: "string", Tag: "`json:\"name\"`"},
+			{Name: "Age", Type: "int", Tag: "`json:\"age\"`"},
+			{Name: "Address", Type: "Address", Tag: "`json:\"address\"`"},
+			{Name: "Meta", Type: "*Meta", Tag: "`json:\"meta\"`"},
+			{Name: "Labels", Type: "map[string]string", Tag: "`json:\"labels\"`"},
+			{Name: "Tags", Type: "[]string", Tag: "`json:\"tags\"`"},
+			{Name: "patch", Type: "UserPatch"},
+		},
+	}
+}
+
+const mergePatchRunSource = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type Address struct {
+	City string ` + "`json:\"city\"`" + `
+	Zip  string ` + "`json:\"zip\"`" + `
+}
+
+type Meta struct {
+	Source string ` + "`json:\"source\"`" + `
+	Score  int    ` + "`json:\"score\"`" + `
+}
+
+type User struct {
+	ID      int64             ` + "`json:\"id\" setter:\"readonly\"`" + `
+	Name    string            ` + "`json:\"name\"`" + `
+	Age     int               ` + "`json:\"age\"`" + `
+	Address Address           ` + "`json:\"address\"`" + `
+	Meta    *Meta             ` + "`json:\"meta\"`" + `
+	Labels  map[string]string ` + "`json:\"labels\"`" + `
+	Tags    []string          ` + "`json:\"tags\"`" + `
+
+	patch UserPatch
+}
+
+// 每行输入一个 patch，输出应用后的对象、错误以及记录到 UserPatch 的字段
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		u := User{
+			ID:      1,
+			Name:    "alice",
+			Age:     30,
+			Address: Address{City: "paris", Zip: "75001"},
+			Meta:    &Meta{Source: "web", Score: 5},
+			Labels:  map[string]string{"a": "1", "b": "2"},
+			Tags:    []string{"x"},
+		}
+		err := u.ApplyMergePatch(scanner.Bytes())
+		data, _ := json.Marshal(u)
+		var patched []string
+		for name, present := range map[string]bool{
+			"Name": u.patch.Name.IsPresent(), "Age": u.patch.Age.IsPresent(), "Address": u.patch.Address.IsPresent(),
+			"Meta": u.patch.Meta.IsPresent(), "Labels": u.patch.Labels.IsPresent(), "Tags": u.patch.Tags.IsPresent(),
+		} {
+			if present {
+				patched = append(patched, name)
+			}
+		}
+		fmt.Printf("%s | %v | %d\n", data, err, len(patched))
+	}
+}
+`
+
+// TestApplyMergePatchRuntime 编译生成的 ApplyMergePatch 并应用真实的 patch
+func TestApplyMergePatchRuntime(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+	model := mergePatchRunModel()
+	gen := gg.New()
+	gen.SetPackage("main")
+	generateSetterV1(gen, model)
+	generateApplyMergePatchMethod(gen, model, mergePatchStrict)
+	generateMergePatchHelpers(gen)
+
+	dir, err := os.MkdirTemp(".", "_mergepatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	for name, content := range map[string]string{"main.go": mergePatchRunSource, "user_setter.go": gen.String()} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const base = `{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":"75001"},"meta":{"source":"web","score":5},"labels":{"a":"1","b":"2"},"tags":["x"]}`
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"empty", `{}`, base + ` | <nil> | 0`},
+		{"absent vs null", `{"name":"bob","age":null}`,
+			`{"id":1,"name":"bob","age":0,"address":{"city":"paris","zip":"75001"},"meta":{"source":"web","score":5},"labels":{"a":"1","b":"2"},"tags":["x"]} | <nil> | 2`},
+		{"nested struct", `{"address":{"zip":null}}`,
+			`{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":""},"meta":{"source":"web","score":5},"labels":{"a":"1","b":"2"},"tags":["x"]} | <nil> | 1`},
+		{"nested pointer", `{"meta":{"score":9}}`,
+			`{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":"75001"},"meta":{"source":"web","score":9},"labels":{"a":"1","b":"2"},"tags":["x"]} | <nil> | 1`},
+		{"null pointer", `{"meta":null}`,
+			`{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":"75001"},"meta":null,"labels":{"a":"1","b":"2"},"tags":["x"]} | <nil> | 1`},
+		{"map", `{"labels":{"a":null,"c":"3"}}`,
+			`{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":"75001"},"meta":{"source":"web","score":5},"labels":{"b":"2","c":"3"},"tags":["x"]} | <nil> | 1`},
+		{"array replaced", `{"tags":["y","z"]}`,
+			`{"id":1,"name":"alice","age":30,"address":{"city":"paris","zip":"75001"},"meta":{"source":"web","score":5},"labels":{"a":"1","b":"2"},"tags":["y","z"]} | <nil> | 1`},
+		{"read-only", `{"id":2}`, base + ` | merge patch User: field "id" is read-only | 0`},
+		{"unknown", `{"nickname":"x"}`, base + ` | merge patch User: unknown field "nickname" | 0`},
+		{"case-insensitive", `{"Name":"bob","ADDRESS":{"city":"rome"}}`,
+			`{"id":1,"name":"bob","age":30,"address":{"city":"rome","zip":"75001"},"meta":{"source":"web","score":5},"labels":{"a":"1","b":"2"},"tags":["x"]} | <nil> | 2`},
+		{"case-insensitive read-only", `{"ID":2}`, base + ` | merge patch User: field "ID" is read-only | 0`},
+		{"duplicate", `{"NAME":"bob","Name":"bob"}`, "duplicate"},
+		{"rollback", `{"address":{"city":"rome"},"age":"old"}`,
+			base + ` | merge patch User: field "age": json: cannot unmarshal string into Go value of type int | 0`},
+	}
+
+	var input strings.Builder
+	for _, tt := range tests {
+		input.WriteString(tt.patch + "\n")
+	}
+	cmd := exec.Command("go", "run", "-mod=readonly", "./"+dir)
+	cmd.Stdin = strings.NewReader(input.String())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s\n%s", err, out, gen.String())
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != len(tests) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tests), out)
+	}
+	for i, tt := range tests {
+		// 重复字段的报错顺序取决于 map 遍历顺序，只检查错误类型
+		if tt.want == "duplicate" {
+			if !strings.HasPrefix(lines[i], base+` | merge patch User: duplicate field `) {
+				t.Errorf("%s: patch %s\ngot:  %s", tt.name, tt.patch, lines[i])
+			}
+			continue
+		}
+		if lines[i] != tt.want {
+			t.Errorf("%s: patch %s\ngot:  %s\nwant: %s", tt.name, tt.patch, lines[i], tt.want)
+		}
+	}
+}
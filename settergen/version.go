@@ -0,0 +1,106 @@
+package settergen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/gormparse"
+)
+
+const gsqlImportPath = "github.com/donutnomad/gsql"
+
+// staleVersionHelperName 包级乐观锁错误变量名，用于判断包内是否已经声明
+const staleVersionHelperName = "ErrStaleVersion"
+
+// findVersionField 查找乐观锁版本字段
+// 优先使用 version 参数指定的字段名，否则识别 gorm:"version" 标签或 optimisticlock.Version 类型
+func findVersionField(model *gormparse.GormModelInfo, fieldName string) (*gormparse.GormFieldInfo, error) {
+	fieldName = strings.TrimSpace(fieldName)
+	if fieldName != "" {
+		for i := range model.Fields {
+			if model.Fields[i].Name == fieldName {
+				if model.Fields[i].ColumnName == "" {
+					return nil, fmt.Errorf("版本字段 %s.%s 没有对应的数据库列", model.Name, fieldName)
+				}
+				return &model.Fields[i], nil
+			}
+		}
+		return nil, fmt.Errorf("结构体 %s 中未找到版本字段 %s", model.Name, fieldName)
+	}
+
+	for i := range model.Fields {
+		f := &model.Fields[i]
+		if f.ColumnName == "" {
+			continue
+		}
+		if hasGormVersionTag(f.Tag) || strings.HasSuffix(f.Type, "optimisticlock.Version") {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// versionColumnName 返回版本字段的列名，字段为 nil 时返回空字符串
+func versionColumnName(f *gormparse.GormFieldInfo) string {
+	if f == nil {
+		return ""
+	}
+	return f.ColumnName
+}
+
+// hasGormVersionTag 判断 gorm 标签中是否包含 version 选项
+func hasGormVersionTag(tag string) bool {
+	gormTag := reflect.StructTag(strings.Trim(tag, "`")).Get("gorm")
+	for part := range strings.SplitSeq(gormTag, ";") {
+		if strings.EqualFold(strings.TrimSpace(part), "version") {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldAccessPath 构建字段访问路径：如果是嵌入字段，使用 receiver.SourceField.Name
+func fieldAccessPath(receiverVar string, f *gormparse.GormFieldInfo) string {
+	if f.SourceField != "" {
+		return receiverVar + "." + f.SourceField + "." + f.Name
+	}
+	return receiverVar + "." + f.Name
+}
+
+// generateWhereVersionMethod 生成 WhereVersion 方法，返回与当前版本匹配的更新条件
+func generateWhereVersionMethod(gen *gg.Generator, model *gormparse.GormModelInfo, versionField *gormparse.GormFieldInfo) {
+	rawModelName := model.Name
+	receiverVar := strings.ToLower(rawModelName[:1])
+	gsqlPkg := gen.P(gsqlImportPath)
+
+	gen.Body().AddLine()
+	gen.Body().AddLineComment("WhereVersion 返回乐观锁条件 %s = 当前版本，配合 ToPatch/ToMap 的版本自增使用", versionField.ColumnName)
+	gen.Body().NewFunction("WhereVersion").
+		WithReceiver(receiverVar, "*"+rawModelName).
+		AddResult("", gsqlPkg.Type("Expression")).
+		AddBody(
+			gg.Return(gsqlPkg.Call("Expr", gg.Lit(versionField.ColumnName+" = ?"), gg.S(fieldAccessPath(receiverVar, versionField)))),
+		)
+}
+
+// generateStaleVersionHelpers 生成包级 ErrStaleVersion 和 CheckVersion，每个包只生成一次
+func generateStaleVersionHelpers(gen *gg.Generator) {
+	errorsPkg := gen.P("errors")
+	gsqlPkg := gen.P(gsqlImportPath)
+
+	gen.Body().AddLine()
+	gen.Body().AddLineComment("ErrStaleVersion 带版本条件的更新未影响任何行，说明数据已被其他请求修改")
+	gen.Body().NewVar().AddField("ErrStaleVersion", errorsPkg.Call("New", gg.Lit("stale version")))
+	gen.Body().AddLine()
+	gen.Body().AddLineComment("CheckVersion 检查 gsql 更新结果，影响行数为 0 时返回 ErrStaleVersion")
+	gen.Body().NewFunction("CheckVersion").
+		AddParameter("ret", gsqlPkg.Type("DBResult")).
+		AddResult("", "error").
+		AddBody(
+			gg.If(gg.S("ret.Error != nil")).AddBody(gg.Return(gg.S("ret.Error"))),
+			gg.If(gg.S("ret.RowsAffected == 0")).AddBody(gg.Return(gg.S("ErrStaleVersion"))),
+			gg.Return(gg.S("nil")),
+		)
+}
@@ -0,0 +1,173 @@
+package settergen
+
+import (
+	"context"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/gormparse"
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func versionTestModel() *gormparse.GormModelInfo {
+	return &gormparse.GormModelInfo{
+		Name:        "OrderPO",
+		PackageName: "models",
+		Fields: []gormparse.GormFieldInfo{
+			{Name: "ID", Type: "int64", ColumnName: "id", Tag: "`gorm:\"column:id;primaryKey\"`"},
+			{Name: "Amount", Type: "int64", ColumnName: "amount", Tag: "`gorm:\"column:amount\"`"},
+			{Name: "Revision", Type: "int64", ColumnName: "revision", Tag: "`gorm:\"column:revision;version\"`"},
+		},
+	}
+}
+
+func TestFindVersionField(t *testing.T) {
+	model := versionTestModel()
+
+	f, err := findVersionField(model, "")
+	if err != nil || f == nil || f.Name != "Revision" {
+		t.Fatalf("findVersionField() 应通过 gorm:\"version\" 识别 Revision，got %+v, %v", f, err)
+	}
+
+	f, err = findVersionField(model, "Amount")
+	if err != nil || f == nil || f.ColumnName != "amount" {
+		t.Fatalf("findVersionField() 应使用 version 参数指定的字段，got %+v, %v", f, err)
+	}
+
+	if _, err = findVersionField(model, "Missing"); err == nil {
+		t.Error("findVersionField() 指定不存在的字段时应返回错误")
+	}
+
+	model.Fields[2].Tag = "`gorm:\"column:revision\"`"
+	if f, err = findVersionField(model, ""); err != nil || f != nil {
+		t.Errorf("findVersionField() 没有版本字段时应返回 nil，got %+v, %v", f, err)
+	}
+}
+
+func TestGenerateToMapMethod_VersionColumn(t *testing.T) {
+	gen := gg.New()
+	gen.SetPackage("models")
+	generateToMapMethod(gen, versionTestModel(), "revision")
+
+	code := gen.String()
+	if !strings.Contains(code, `values["revision"] = gsql.Expr("revision + 1")`) {
+		t.Errorf("ToMap 应对版本列自增，实际代码:\n%s", code)
+	}
+	if strings.Contains(code, `values["revision"] = o.Revision`) {
+		t.Errorf("ToMap 不应直接写入版本字段，实际代码:\n%s", code)
+	}
+	if !strings.Contains(code, `values["amount"] = o.Amount`) {
+		t.Errorf("ToMap 应保留普通字段，实际代码:\n%s", code)
+	}
+}
+
+func TestGenerateVersionHelpers(t *testing.T) {
+	model := versionTestModel()
+	gen := gg.New()
+	gen.SetPackage("models")
+	generateWhereVersionMethod(gen, model, &model.Fields[2])
+	generateStaleVersionHelpers(gen)
+
+	formatted, err := format.Source([]byte(gen.String()))
+	if err != nil {
+		t.Fatalf("生成的代码无法格式化: %v\n%s", err, gen.String())
+	}
+	code := string(formatted)
+
+	for _, want := range []string{
+		`"github.com/donutnomad/gsql"`,
+		"func (o *OrderPO) WhereVersion() gsql.Expression",
+		`return gsql.Expr("revision = ?", o.Revision)`,
+		`var ErrStaleVersion = errors.New("stale version")`,
+		"func CheckVersion(ret gsql.DBResult) error",
+		"return ErrStaleVersion",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("生成的代码应包含 %q，实际代码:\n%s", want, code)
+		}
+	}
+}
+
+// TestRunPatchV2VersionFromMapperTarget v2 模式从 mapper 的 PO 识别版本列，包内已声明 ErrStaleVersion 时不再生成
+func TestRunPatchV2VersionFromMapperTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.25\n",
+		"order/order.go": `package order
+
+// @Setter(patch="v2", patch_mapper="OrderPO.FromDomain")
+type Order struct {
+	ID       int64
+	Amount   int64
+	Revision int64
+}
+
+type OrderPO struct {
+	ID       int64 ` + "`gorm:\"column:id;primaryKey\"`" + `
+	Amount   int64 ` + "`gorm:\"column:amount\"`" + `
+	Revision int64 ` + "`gorm:\"column:revision;version\"`" + `
+}
+
+func (p *OrderPO) FromDomain(d *Order) *OrderPO {
+	return &OrderPO{
+		ID:       d.ID,
+		Amount:   d.Amount,
+		Revision: d.Revision,
+	}
+}
+`,
+		"order/errors.go": `package order
+
+import "errors"
+
+var ErrStaleVersion = errors.New("order: stale version")
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := plugin.NewRegistry()
+	if err := registry.Register(NewSetterGenerator()); err != nil {
+		t.Fatalf("failed to register settergen: %v", err)
+	}
+	err := plugin.RunWithOptions(context.Background(), &plugin.RunOptions{
+		Registry: registry,
+		Patterns: []string{filepath.Join(tmpDir, "order")},
+		Output:   "generate.go",
+		Async:    false,
+	})
+	if err != nil {
+		t.Fatalf("RunWithOptions failed: %v", err)
+	}
+	generated, err := os.ReadFile(filepath.Join(tmpDir, "order", "generate.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	code := string(generated)
+
+	for _, want := range []string{
+		`values["revision"] = gsql.Expr("revision + 1")`,
+		"func (o *OrderPO) WhereVersion() gsql.Expression",
+		`return gsql.Expr("revision = ?", o.Revision)`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("生成的代码应包含 %q，实际代码:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"func (o *Order) WhereVersion()", "ErrStaleVersion =", "func CheckVersion("} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("生成的代码不应包含 %q，实际代码:\n%s", unwanted, code)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/donutnomad/gogen/stateflowgen"
+)
+
+// runStateFlow 处理 stateflow 子命令
+func runStateFlow(args []string) {
+	if len(args) == 0 {
+		stateFlowUsage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch args[0] {
+	case "diagram":
+		err = stateFlowDiagram(args[1:], os.Stdout)
+	case "lint":
+		err = stateFlowLint(args[1:], os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "错误: 未知的 stateflow 命令 %q\n", args[0])
+		stateFlowUsage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// stateFlowDiagram 将源文件中的状态流转定义导出为流程图
+// 用法: gogen stateflow diagram [-format mermaid] [-name Order] [-o out.mmd] path/to/file.go
+func stateFlowDiagram(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stateflow diagram", flag.ContinueOnError)
+	formatFlag := fs.String("format", "", "输出格式: mermaid, dot, plantuml, ascii（默认取注解的 diagram 参数，否则 mermaid）")
+	name := fs.String("name", "", "文件中有多个定义时，按 name 选择")
+	output := fs.String("o", "", "输出文件路径（默认输出到标准输出）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// 允许选项出现在文件路径之后
+	positional := fs.Args()
+	if len(positional) > 1 {
+		if err := fs.Parse(positional[1:]); err != nil {
+			return err
+		}
+		positional = positional[:1]
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("用法: gogen stateflow diagram [-format mermaid] [-name Order] [-o out] <file.go>")
+	}
+
+	defs, err := stateflowgen.LoadFlowDefinitions(positional[0])
+	if err != nil {
+		return err
+	}
+	def, err := selectFlowDefinition(defs, *name, positional[0])
+	if err != nil {
+		return err
+	}
+
+	format, err := stateflowgen.ParseDiagramFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = def.Diagram
+	}
+	if format == "" {
+		format = stateflowgen.DiagramMermaid
+	}
+
+	content, err := def.Graph().Render(format)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = io.WriteString(stdout, content)
+		return err
+	}
+	return os.WriteFile(*output, []byte(content), 0644)
+}
+
+// stateFlowLint 对源文件中的状态流转定义做静态分析，逐条输出问题所在的 @Flow 行
+// 用法: gogen stateflow lint [-name Order] [-strict] <file.go|dir>...
+func stateFlowLint(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stateflow lint", flag.ContinueOnError)
+	name := fs.String("name", "", "只检查指定 name 的定义")
+	strict := fs.Bool("strict", false, "存在警告时也返回失败")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("用法: gogen stateflow lint [-name Order] [-strict] <file.go|dir>...")
+	}
+
+	files, err := stateFlowLintFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var errCount, warnCount int
+	for _, file := range files {
+		defs, err := stateflowgen.LoadFlowDefinitions(file)
+		if err != nil {
+			_, _ = fmt.Fprintf(stdout, "%s: error: %v\n", file, err)
+			errCount++
+			continue
+		}
+		for _, def := range defs {
+			if *name != "" && def.Name != *name {
+				continue
+			}
+			for _, issue := range def.Issues {
+				_, _ = fmt.Fprintf(stdout, "%s:%d: %s: %s (%s)\n", file, issue.Line, issue.Severity, issue.Message, issue.Check)
+				if issue.Severity == stateflowgen.LintError {
+					errCount++
+				} else {
+					warnCount++
+				}
+			}
+		}
+	}
+
+	if errCount > 0 || (*strict && warnCount > 0) {
+		return fmt.Errorf("发现 %d 个错误, %d 个警告", errCount, warnCount)
+	}
+	return nil
+}
+
+// stateFlowLintFiles 展开参数中的目录为其中的 .go 文件（不含测试文件，不递归）
+func stateFlowLintFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("没有找到 Go 源文件")
+	}
+	return files, nil
+}
+
+// selectFlowDefinition 按 name 选择定义；只有一个定义时可省略 name
+func selectFlowDefinition(defs []*stateflowgen.FlowDefinition, name, filePath string) (*stateflowgen.FlowDefinition, error) {
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("%s 中没有 @StateFlow / @StateFlowV2 定义", filePath)
+	}
+
+	if name == "" {
+		if len(defs) == 1 {
+			return defs[0], nil
+		}
+		return nil, fmt.Errorf("%s 中有 %d 个定义，请使用 -name 选择: %s", filePath, len(defs), flowDefinitionNames(defs))
+	}
+
+	for _, def := range defs {
+		if def.Name == name {
+			return def, nil
+		}
+	}
+	return nil, fmt.Errorf("%s 中没有 name=%q 的定义，可选: %s", filePath, name, flowDefinitionNames(defs))
+}
+
+func flowDefinitionNames(defs []*stateflowgen.FlowDefinition) string {
+	names := make([]string, 0, len(defs))
+	for _, def := range defs {
+		if def.Name == "" {
+			names = append(names, fmt.Sprintf("(未命名, 第 %d 行)", def.Line))
+		} else {
+			names = append(names, def.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func stateFlowUsage() {
+	_, _ = fmt.Fprintf(os.Stderr, `用法:
+  gogen stateflow diagram [-format mermaid|dot|plantuml|ascii] [-name Order] [-o out] <file.go>
+  gogen stateflow lint [-name Order] [-strict] <file.go|dir>...
+
+命令:
+  diagram   将 @StateFlow / @StateFlowV2 定义导出为流程图
+            审批边加粗、via 中间状态为虚线节点、else/拒绝为虚线边、通配符展开的边以 * 标注
+  lint      静态分析：不可达状态、非终态死路、有出边的 final 终态、审批 via 循环、
+            空通配符、重复或被遮蔽的规则；存在错误时返回非零退出码
+
+示例:
+  gogen stateflow diagram order.go > order.mmd
+  gogen stateflow diagram -format dot -name Order order.go | dot -Tsvg > order.svg
+  gogen stateflow diagram -format plantuml -o docs/order.puml order.go
+  gogen stateflow lint ./models
+`)
+}
@@ -0,0 +1,145 @@
+// Package clock 为 stateflowgen 生成的定时流转（after）提供可选的调度与测试工具
+//
+// 生成代码不依赖本包：<Name>Clock 变量只要求 Now() time.Time，默认使用系统时间；
+// 调度器可用 Run 周期性检查 DueTransitions，测试中可将 <Name>Clock 替换为 Fake 手动推进时间。
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 时间来源
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 周期触发器
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// System 使用系统时间的默认时钟
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// Run 每隔 interval 调用一次 fn，直到 ctx 取消
+// 调度器在 fn 中加载到期的记录并执行 ApplyDue
+func Run(ctx context.Context, c Clock, interval time.Duration, fn func(now time.Time)) {
+	ticker := c.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C():
+			fn(now)
+		}
+	}
+}
+
+// Fake 手动推进的时钟，用于测试
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFake 创建停在 now 的时钟
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now 返回当前时间
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance 将时间推进 d，并触发期间到期的 Ticker
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set 将时间设置为 t（不能早于当前时间），并触发期间到期的 Ticker
+// 与 time.Ticker 一致，接收方来不及读取时丢弃多余的触发
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.Before(f.now) {
+		panic("clock: Fake.Set cannot move time backwards")
+	}
+	f.now = t
+
+	active := f.tickers[:0]
+	for _, ticker := range f.tickers {
+		if ticker.stopped {
+			continue
+		}
+		for !ticker.next.After(t) {
+			select {
+			case ticker.c <- ticker.next:
+			default:
+			}
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+		active = append(active, ticker)
+	}
+	f.tickers = active
+}
+
+// NewTicker 创建随 Advance/Set 触发的 Ticker
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ticker := &fakeTicker{
+		clock:  f,
+		c:      make(chan time.Time, 1),
+		period: d,
+		next:   f.now.Add(d),
+	}
+	f.tickers = append(f.tickers, ticker)
+	return ticker
+}
+
+type fakeTicker struct {
+	clock   *Fake
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFake_AdvanceFiresTicker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Minute)
+
+	f.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired before its period")
+	default:
+	}
+
+	f.Advance(30 * time.Second)
+	select {
+	case got := <-ticker.C():
+		if want := start.Add(time.Minute); !got.Equal(want) {
+			t.Errorf("tick = %v, want %v", got, want)
+		}
+	default:
+		t.Fatal("ticker did not fire")
+	}
+
+	ticker.Stop()
+	f.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+	if got, want := f.Now(), start.Add(time.Hour+time.Minute); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	f := NewFake(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, f, time.Second, func(now time.Time) { calls <- now })
+		close(done)
+	}()
+
+	// 等待 Run 注册 Ticker
+	for {
+		f.mu.Lock()
+		n := len(f.tickers)
+		f.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	f.Advance(time.Second)
+	if got := <-calls; !got.Equal(time.Unix(1, 0)) {
+		t.Errorf("fn called with %v, want %v", got, time.Unix(1, 0))
+	}
+	cancel()
+	<-done
+}
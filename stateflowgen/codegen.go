@@ -42,10 +42,18 @@ func (c *CodeGenerator) Generate() (*gg.Generator, error) {
 	// 生成预定义阶段变量
 	c.generateStageVars(group)
 
+	// 生成区域子状态枚举（如果有复合状态）
+	if c.model.HasRegions() {
+		c.generateRegionEnums(group)
+	}
+
 	// 如果有流转，生成 State 和相关方法
-	if len(c.model.Transitions) > 0 {
+	if len(c.model.flowTransitions()) > 0 {
 		// 生成审批相关类型（如果有）
 		if c.model.HasApproval {
+			if c.model.HasApprovalPolicy() {
+				c.generateApprovalLedgerTypes(group)
+			}
 			c.generatePendingTransitionType(group)
 		}
 
@@ -69,8 +77,36 @@ func (c *CodeGenerator) Generate() (*gg.Generator, error) {
 			c.generateRejectMethod(group)
 			c.generateIsApprovalPendingMethod(group)
 		}
+		if c.model.HasApprovalPolicy() {
+			c.generateApprovalPolicyAPI(group)
+		}
 		c.generateValidTransitionsMethod(group)
 		c.generateNextMethod(group)
+
+		// 生成复合状态的区域流转与完成流转
+		if c.model.HasRegions() {
+			c.generateRegionAPI(group)
+		}
+
+		// 生成定时流转（如果有 after）
+		if c.model.HasTimers() {
+			c.generateTimerAPI(group)
+		}
+
+		// 生成事件、守卫与钩子（如果有 on/if）
+		if c.model.HasEvents() {
+			c.generateEventAPI(group)
+		}
+
+		// 生成流转记录、历史表与 Replay（history=true）
+		if c.model.History {
+			c.generateHistoryAPI(group)
+		}
+
+		// 生成数据库约束配套的更新校验与 GORM 钩子（sql=mysql|postgres）
+		if c.model.SQL != "" {
+			c.generateEnforceAPI(group)
+		}
 	}
 
 	return c.gen, nil
@@ -230,6 +266,9 @@ func (c *CodeGenerator) generatePendingTransitionType(group *gg.Group) {
 	st.AddField("From", fmt.Sprintf("%s `json:\"from\"`", stageType))
 	st.AddField("To", fmt.Sprintf("%s `json:\"to\"`", stageType))
 	st.AddField("Fallback", fmt.Sprintf("%s `json:\"fallback\"`", stageType))
+	if c.model.HasApprovalPolicy() {
+		st.AddField("Ledger", fmt.Sprintf("[]%sApprovalVote `json:\"ledger,omitempty\"` // 审批账本，按投票顺序", c.model.Name))
+	}
 	group.Append(st)
 }
 
@@ -248,6 +287,12 @@ func (c *CodeGenerator) generateStateType(group *gg.Group) {
 		pendingType := "*" + c.model.Name + "PendingTransition"
 		st.AddField("Pending", fmt.Sprintf("%s `json:\"pending,omitempty\"`", pendingType))
 	}
+	for _, region := range c.model.Regions {
+		st.AddField(c.regionField(region), fmt.Sprintf("%s `json:\"%s,omitempty\"`", c.regionType(region), region.Name))
+	}
+	if c.model.HasTimers() {
+		st.AddField("EnteredAt", gg.NewInlineGroup().Append(c.gen.P("time").Type("Time"), gg.S(" `json:\"entered_at\"`")))
+	}
 	group.Append(st)
 }
 
@@ -273,6 +318,28 @@ func (c *CodeGenerator) generateErrors(group *gg.Group) {
 			errorsP.Call("New", gg.Lit("not in approval")),
 		)
 	}
+	if c.model.HasApprovalPolicy() {
+		varGroup.AddField(
+			"Err"+c.model.Name+"ApproverRequired",
+			errorsP.Call("New", gg.Lit("approver required")),
+		)
+		varGroup.AddField(
+			"Err"+c.model.Name+"DuplicateVote",
+			errorsP.Call("New", gg.Lit("duplicate vote")),
+		)
+	}
+	if len(c.model.Guards) > 0 {
+		varGroup.AddField(
+			"Err"+c.model.Name+"GuardsNotConfigured",
+			errorsP.Call("New", gg.Lit("guards not configured")),
+		)
+	}
+	if c.model.HasRegions() {
+		varGroup.AddField(
+			"Err"+c.model.Name+"RegionInactive",
+			errorsP.Call("New", gg.Lit("region not active")),
+		)
+	}
 	group.Append(varGroup)
 }
 
@@ -321,6 +388,12 @@ func (c *CodeGenerator) generateTransitionRulesSwitch() []any {
 	stateType := c.model.Name + "State"
 	pendingType := c.model.Name + "PendingTransition"
 
+	// 有定时流转时，进入 via 状态同样记录进入时间（用于审批超时）
+	enteredAt := ""
+	if c.model.HasTimers() {
+		enteredAt = ", EnteredAt: " + c.enteredAtExpr()
+	}
+
 	// 构建外层 switch
 	outerSwitch := gg.Switch("s.Current")
 
@@ -356,22 +429,22 @@ func (c *CodeGenerator) generateTransitionRulesSwitch() []any {
 					// 必须审批
 					caseBody = append(caseBody,
 						pendingCheck,
-						gg.S("return %s{Current: %s, Pending: &%s{From: s.Current, To: to, Fallback: %s}}, nil",
-							stateType, viaVarName, pendingType, fallbackVarName))
+						gg.S("return %s{Current: %s, Pending: &%s{From: s.Current, To: to, Fallback: %s}%s}, nil",
+							stateType, viaVarName, pendingType, fallbackVarName, enteredAt))
 				} else {
 					// 可选审批
 					caseBody = append(caseBody,
 						gg.If("withApproval").AddBody(
 							pendingCheck,
-							gg.S("return %s{Current: %s, Pending: &%s{From: s.Current, To: to, Fallback: %s}}, nil",
-								stateType, viaVarName, pendingType, fallbackVarName),
+							gg.S("return %s{Current: %s, Pending: &%s{From: s.Current, To: to, Fallback: %s}%s}, nil",
+								stateType, viaVarName, pendingType, fallbackVarName, enteredAt),
 						),
-						gg.S("return %s{Current: to}, nil", stateType),
+						gg.S("return %s, nil", c.stateLit("to")),
 					)
 				}
 			} else {
 				// 直接流转：不需要检查 Pending，直接执行
-				caseBody = append(caseBody, gg.S("return %s{Current: to}, nil", stateType))
+				caseBody = append(caseBody, gg.S("return %s, nil", c.stateLit("to")))
 			}
 
 			innerSwitch.NewCase(gg.S(toVarName)).AddBody(caseBody...)
@@ -386,6 +459,19 @@ func (c *CodeGenerator) generateTransitionRulesSwitch() []any {
 	}
 }
 
+// stateLit 返回进入指定阶段后的 State 表达式
+// 有定时流转时记录进入时间，有复合状态时同时设置各区域的入口子状态
+func (c *CodeGenerator) stateLit(stage string) string {
+	lit := fmt.Sprintf("%sState{Current: %s}", c.model.Name, stage)
+	if c.model.HasTimers() {
+		lit = fmt.Sprintf("%sState{Current: %s, EnteredAt: %s}", c.model.Name, stage, c.enteredAtExpr())
+	}
+	if c.model.HasRegions() {
+		lit += ".enterRegions()"
+	}
+	return lit
+}
+
 // getStageVarName 获取阶段变量名
 func (c *CodeGenerator) getStageVarName(stage Stage) string {
 	varName := "Stage" + c.model.Name + utils.UpperCamelCase(stage.Phase)
@@ -409,7 +495,7 @@ func (c *CodeGenerator) generateCommitMethod(group *gg.Group) {
 			gg.If("s.Pending == nil").AddBody(
 				gg.S("return s, Err%sNotInApproval", c.model.Name),
 			),
-			gg.S("return %s{Current: s.Pending.To}, nil", stateType),
+			gg.S("return %s, nil", c.stateLit("s.Pending.To")),
 		)
 	group.Append(fn)
 }
@@ -428,7 +514,7 @@ func (c *CodeGenerator) generateRejectMethod(group *gg.Group) {
 			gg.If("s.Pending == nil").AddBody(
 				gg.S("return s, Err%sNotInApproval", c.model.Name),
 			),
-			gg.S("return %s{Current: s.Pending.Fallback}, nil", stateType),
+			gg.S("return %s, nil", c.stateLit("s.Pending.Fallback")),
 		)
 	group.Append(fn)
 }
@@ -514,7 +600,7 @@ func (c *CodeGenerator) generateNextMethod(group *gg.Group) {
 			gg.S("}"),
 			gg.S("var result []%s", stateType),
 			gg.S("for _, stage := range s.ValidTransitions() {"),
-			gg.S("	result = append(result, %s{Current: stage})", stateType),
+			gg.S("	result = append(result, %s)", c.stateLit("stage")),
 			gg.S("}"),
 			gg.S("return result"),
 		)
@@ -523,7 +609,7 @@ func (c *CodeGenerator) generateNextMethod(group *gg.Group) {
 		fn.AddBody(
 			gg.S("var result []%s", stateType),
 			gg.S("for _, stage := range s.ValidTransitions() {"),
-			gg.S("	result = append(result, %s{Current: stage})", stateType),
+			gg.S("	result = append(result, %s)", c.stateLit("stage")),
 			gg.S("}"),
 			gg.S("return result"),
 		)
@@ -557,6 +643,15 @@ func (c *CodeGenerator) generateStateColumnsType(group *gg.Group) {
 		))
 	}
 
+	for _, region := range c.model.Regions {
+		st.AddField(c.regionField(region), fmt.Sprintf("%s `gorm:\"column:%s\" json:\"%s\"`",
+			c.regionType(region), utils.ToSnakeCase(region.Name), region.Name))
+	}
+
+	if c.model.HasTimers() {
+		st.AddField("EnteredAt", gg.NewInlineGroup().Append(c.gen.P("time").Type("Time"), gg.S(" `gorm:\"column:entered_at\" json:\"entered_at\"`")))
+	}
+
 	group.Append(st)
 }
 
@@ -586,6 +681,15 @@ func (c *CodeGenerator) generateToColumnsMethod(group *gg.Group) {
 		bodyParts = append(bodyParts, "Pending: datatypes.NewJSONType(s.Pending),")
 	}
 
+	for _, region := range c.model.Regions {
+		field := c.regionField(region)
+		bodyParts = append(bodyParts, fmt.Sprintf("%s: s.%s,", field, field))
+	}
+
+	if c.model.HasTimers() {
+		bodyParts = append(bodyParts, "EnteredAt: s.EnteredAt,")
+	}
+
 	fn.AddBody(gg.S("return %s{\n\t%s\n}", columnsType, strings.Join(bodyParts, "\n\t")))
 	group.Append(fn)
 }
@@ -617,36 +721,53 @@ func (c *CodeGenerator) generateFromColumnsMethod(group *gg.Group) {
 		bodyParts = append(bodyParts, "Pending: c.Pending.Data(),")
 	}
 
+	for _, region := range c.model.Regions {
+		field := c.regionField(region)
+		bodyParts = append(bodyParts, fmt.Sprintf("%s: c.%s,", field, field))
+	}
+
+	if c.model.HasTimers() {
+		bodyParts = append(bodyParts, "EnteredAt: c.EnteredAt,")
+	}
+
 	fn.AddBody(gg.S("return %s{\n\t%s\n}", stateType, strings.Join(bodyParts, "\n\t")))
 	group.Append(fn)
 }
 
 // generateFlowDiagram 生成流程图注释
 func (c *CodeGenerator) generateFlowDiagram(group *gg.Group) {
-	if len(c.model.Transitions) == 0 {
+	if len(c.model.flowTransitions()) == 0 {
 		return
 	}
 
+	comment := newFlowRenderer(c.model.flowTransitions()).RenderAsComment()
+	if comment != "" {
+		group.Append(gg.S(comment))
+	}
+}
+
+// newFlowRenderer 根据展开后的流转构建 ASCII 流程图
+func newFlowRenderer(transitions []Transition) *DiagramRenderer {
 	renderer := NewDiagramRenderer()
 	renderer.ArrowSymbol = "──"
 
 	// 收集有出边的状态（用于判断 REJECT 叶子是否标记 🔁）
 	hasOutTransition := make(map[string]bool)
-	for _, trans := range c.model.Transitions {
-		hasOutTransition[c.formatStage(trans.From)] = true
+	for _, trans := range transitions {
+		hasOutTransition[formatStage(trans.From)] = true
 	}
 
-	for _, trans := range c.model.Transitions {
-		fromStr := c.formatStage(trans.From)
-		toStr := c.formatStage(trans.To)
+	for _, trans := range transitions {
+		fromStr := formatStage(trans.From)
+		toStr := formatStage(trans.To)
 
 		if trans.ApprovalOptional && trans.Via.Phase != "" {
 			// 可选审批：创建中间判别节点
 			// from ──▶ <?APPROVAL?> ──┬──▶ via (via) ──┬── <COMMIT> ──▶ to
 			//                         │                └── <REJECT> ──▶ fallback
 			//                         └──▶ to (直接)
-			viaStr := c.formatStage(trans.Via)
-			fallbackStr := c.formatStage(trans.Fallback)
+			viaStr := formatStage(trans.Via)
+			fallbackStr := formatStage(trans.Fallback)
 
 			// 同一 from 共用一个 decision 节点
 			decisionNode := fromStr + "_decision"
@@ -655,66 +776,82 @@ func (c *CodeGenerator) generateFlowDiagram(group *gg.Group) {
 
 			// 每个 (from, to) 独立的 via 节点，避免不同转换的边混在一起
 			viaNodeID := fmt.Sprintf("%s_%s_%s_via", fromStr, toStr, viaStr)
-			renderer.AddNode(viaNodeID, viaStr+" (via)")
+			renderer.AddNode(viaNodeID, viaLabel(trans, trans.Via))
 			renderer.AddEdge(decisionNode, viaNodeID, "──▶ ")
 
 			// COMMIT → 真实目标状态节点（由渲染器 expanded 控制是否展开）
-			if toStr != "" {
-				renderer.AddEdge(viaNodeID, toStr, "── <COMMIT> ──▶ ")
-			}
 			// REJECT → 独立叶子节点（fallback 通常回到源状态，用叶子避免回环）
-			if fallbackStr != "" {
-				rejectID := fmt.Sprintf("%s_%s_reject", fromStr, toStr)
-				rejectLabel := fallbackStr
-				if hasOutTransition[fallbackStr] {
-					rejectLabel += " 🔁"
-				}
-				renderer.AddNode(rejectID, rejectLabel)
-				renderer.AddEdge(viaNodeID, rejectID, "── <REJECT> ──▶ ")
-			}
+			addApprovalEdges(renderer, trans, viaNodeID, fallbackStr, hasOutTransition[fallbackStr])
 
 			// 直接路径：decision → 真实目标状态节点
 			renderer.AddEdge(decisionNode, toStr, "──▶ ")
 		} else if trans.Via.Phase != "" {
 			// 必须审批：from -> via -> (Commit/Reject)
-			viaStr := c.formatStage(trans.Via)
-			toStr := c.formatStage(trans.To)
-			fallbackStr := c.formatStage(trans.Fallback)
+			viaStr := formatStage(trans.Via)
+			toStr := formatStage(trans.To)
+			fallbackStr := formatStage(trans.Fallback)
 
 			// 每个 (from, to) 独立的 via 节点
 			viaNodeID := fmt.Sprintf("%s_%s_%s_via", fromStr, toStr, viaStr)
-			renderer.AddNode(viaNodeID, viaStr+" (via)")
+			renderer.AddNode(viaNodeID, viaLabel(trans, trans.Via))
 			renderer.AddEdge(fromStr, viaNodeID, "──▶ ")
 
-			// COMMIT → 真实目标状态节点
-			if toStr != "" {
-				renderer.AddEdge(viaNodeID, toStr, "── <COMMIT> ──▶ ")
-			}
-			// REJECT → 独立叶子节点
-			if fallbackStr != "" {
-				rejectID := fmt.Sprintf("%s_%s_reject", fromStr, toStr)
-				rejectLabel := fallbackStr
-				if hasOutTransition[fallbackStr] {
-					rejectLabel += " 🔁"
-				}
-				renderer.AddNode(rejectID, rejectLabel)
-				renderer.AddEdge(viaNodeID, rejectID, "── <REJECT> ──▶ ")
-			}
+			// COMMIT → 真实目标状态节点，REJECT → 独立叶子节点
+			addApprovalEdges(renderer, trans, viaNodeID, fallbackStr, hasOutTransition[fallbackStr])
+		} else if trans.Completion {
+			// 完成流转：复合状态所有区域到达终态
+			renderer.AddEdge(fromStr, toStr, "── <DONE> ──▶ ")
 		} else {
 			// 直接流转
 			renderer.AddEdge(fromStr, toStr, "──▶ ")
 		}
 	}
 
-	// 渲染并输出
-	comment := renderer.RenderAsComment()
-	if comment != "" {
-		group.Append(gg.S(comment))
+	return renderer
+}
+
+// viaLabel 返回 via 节点的显示文字，多人审批时附带 quorum
+func viaLabel(trans Transition, step Stage) string {
+	if trans.Quorum > 1 {
+		return fmt.Sprintf("%s (via, quorum %d)", formatStage(step), trans.Quorum)
+	}
+	return formatStage(step) + " (via)"
+}
+
+// addApprovalEdges 从第一级 via 节点出发添加 COMMIT/REJECT 边
+// 多级审批时每一级通过后经 <APPROVE> 进入下一级，每一级都可以拒绝
+func addApprovalEdges(renderer *DiagramRenderer, trans Transition, viaNodeID, fallbackStr string, loop bool) {
+	fromStr := formatStage(trans.From)
+	toStr := formatStage(trans.To)
+	steps := trans.ApprovalSteps()
+
+	for i := range steps {
+		nextID := ""
+		if i+1 < len(steps) {
+			nextID = fmt.Sprintf("%s_%s_%s_via", fromStr, toStr, formatStage(steps[i+1]))
+			renderer.AddNode(nextID, viaLabel(trans, steps[i+1]))
+			renderer.AddEdge(viaNodeID, nextID, "── <APPROVE> ──▶ ")
+		} else if toStr != "" {
+			renderer.AddEdge(viaNodeID, toStr, "── <COMMIT> ──▶ ")
+		}
+		if fallbackStr != "" {
+			rejectID := fmt.Sprintf("%s_%s_reject", fromStr, toStr)
+			if i > 0 {
+				rejectID = fmt.Sprintf("%s_%s_reject_%d", fromStr, toStr, i)
+			}
+			rejectLabel := fallbackStr
+			if loop {
+				rejectLabel += " 🔁"
+			}
+			renderer.AddNode(rejectID, rejectLabel)
+			renderer.AddEdge(viaNodeID, rejectID, "── <REJECT> ──▶ ")
+		}
+		viaNodeID = nextID
 	}
 }
 
 // formatStage 格式化阶段显示
-func (c *CodeGenerator) formatStage(stage Stage) string {
+func formatStage(stage Stage) string {
 	if stage.Status != "" {
 		return fmt.Sprintf("%s(%s)", stage.Phase, stage.Status)
 	}
@@ -0,0 +1,178 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gg"
+)
+
+// generateApprovalLedgerTypes 生成审批决定枚举与审批账本条目类型
+func (c *CodeGenerator) generateApprovalLedgerTypes(group *gg.Group) {
+	decisionType := c.model.Name + "Decision"
+	voteType := c.model.Name + "ApprovalVote"
+	stageType := c.model.Name + "Stage"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 审批人的决定", decisionType))
+	group.Append(gg.Type(decisionType, "string"))
+
+	group.AddLine()
+	constGroup := gg.Const()
+	constGroup.AddTypedField(decisionType+"Approved", decisionType, gg.Lit("approved"))
+	constGroup.AddTypedField(decisionType+"Rejected", decisionType, gg.Lit("rejected"))
+	group.Append(constGroup)
+
+	st := gg.Struct(voteType)
+	st.AddField("Approver", "string `json:\"approver\"`")
+	st.AddField("Decision", fmt.Sprintf("%s `json:\"decision\"`", decisionType))
+	st.AddField("Stage", fmt.Sprintf("%s `json:\"stage\"` // 投票时所在的审批阶段", stageType))
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 审批账本中的一票", voteType))
+	group.Append(st)
+}
+
+// generateApprovalPolicyAPI 生成审批策略查询、账本统计与 Approve/RejectBy 方法
+func (c *CodeGenerator) generateApprovalPolicyAPI(group *gg.Group) {
+	c.generatePolicyMethod(group)
+	c.generateLedgerMethods(group)
+	c.generateCheckVoteMethod(group)
+	c.generateApproveMethod(group)
+	c.generateRejectByMethod(group)
+}
+
+// generatePolicyMethod 生成 PendingTransition.Policy：按 From/To 返回审批阶段与 quorum
+func (c *CodeGenerator) generatePolicyMethod(group *gg.Group) {
+	pendingType := c.model.Name + "PendingTransition"
+	stageType := c.model.Name + "Stage"
+
+	sw := gg.Switch("")
+	seen := make(map[string]bool)
+	for _, trans := range c.model.Transitions {
+		key := trans.From.String() + "|" + trans.To.String()
+		if trans.Via.Phase == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var steps []string
+		for _, step := range trans.ApprovalSteps() {
+			steps = append(steps, c.getStageVarName(step))
+		}
+		sw.NewCase(gg.S("p.From == %s && p.To == %s", c.getStageVarName(trans.From), c.getStageVarName(trans.To))).AddBody(
+			gg.S("return []%s{%s}, %d", stageType, strings.Join(steps, ", "), max(trans.Quorum, 1)),
+		)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("Policy 返回审批策略：依次经过的审批阶段，以及每一级需要的不同审批人数"))
+	group.Append(gg.Function("Policy").
+		WithReceiver("p", pendingType).
+		AddResult("steps", "[]"+stageType).
+		AddResult("quorum", "int").
+		AddBody(sw, gg.S("return nil, 1")))
+}
+
+// generateLedgerMethods 生成 PendingTransition 的账本统计方法
+func (c *CodeGenerator) generateLedgerMethods(group *gg.Group) {
+	pendingType := c.model.Name + "PendingTransition"
+	stageType := c.model.Name + "Stage"
+
+	group.AddLine()
+	group.Append(gg.LineComment("Approvals 返回指定审批阶段已获得的赞成票数"))
+	group.Append(gg.Function("Approvals").
+		WithReceiver("p", pendingType).
+		AddParameter("stage", stageType).
+		AddResult("", "int").
+		AddBody(
+			gg.S("n := 0"),
+			gg.S("for _, vote := range p.Ledger {"),
+			gg.S("	if vote.Stage == stage && vote.Decision == %sDecisionApproved {", c.model.Name),
+			gg.S("		n++"),
+			gg.S("	}"),
+			gg.S("}"),
+			gg.S("return n"),
+		))
+
+	group.AddLine()
+	group.Append(gg.LineComment("HasVoted 审批人是否已在本次审批中投过票（任一级）"))
+	group.Append(gg.Function("HasVoted").
+		WithReceiver("p", pendingType).
+		AddParameter("approver", "string").
+		AddResult("", "bool").
+		AddBody(
+			gg.S("for _, vote := range p.Ledger {"),
+			gg.S("	if vote.Approver == approver {"),
+			gg.S("		return true"),
+			gg.S("	}"),
+			gg.S("}"),
+			gg.S("return false"),
+		))
+}
+
+// generateCheckVoteMethod 生成 checkVote：投票前的公共校验
+func (c *CodeGenerator) generateCheckVoteMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	group.AddLine()
+	group.Append(gg.Function("checkVote").
+		WithReceiver("s", stateType).
+		AddParameter("approver", "string").
+		AddResult("", "error").
+		AddBody(
+			gg.If("s.Pending == nil").AddBody(gg.S("return Err%sNotInApproval", c.model.Name)),
+			gg.If(`approver == ""`).AddBody(gg.S("return Err%sApproverRequired", c.model.Name)),
+			gg.If("s.Pending.HasVoted(approver)").AddBody(gg.S("return Err%sDuplicateVote", c.model.Name)),
+			gg.S("return nil"),
+		))
+}
+
+// generateApproveMethod 生成 Approve：记录赞成票，满足策略后进入下一级或提交
+func (c *CodeGenerator) generateApproveMethod(group *gg.Group) {
+	c.gen.P("slices")
+	stateType := c.model.Name + "State"
+
+	advance := []any{gg.S("next.Current = steps[i+1]")}
+	if c.model.HasTimers() {
+		advance = append(advance, gg.S("next.EnteredAt = %s", c.enteredAtExpr()))
+	}
+	advance = append(advance, gg.S("return next, nil"))
+
+	group.AddLine()
+	group.Append(gg.LineComment("Approve 以 approver 的身份投赞成票，本级票数达到 quorum 后进入下一级，最后一级满足时提交"))
+	group.Append(gg.LineComment("同一审批人在一次审批中只能投一票，重复投票返回 Err%sDuplicateVote", c.model.Name))
+	group.Append(gg.Function("Approve").
+		WithReceiver("s", stateType).
+		AddParameter("approver", "string").
+		AddResult("", stateType).
+		AddResult("", "error").
+		AddBody(
+			gg.If("err := s.checkVote(approver); err != nil").AddBody(gg.S("return s, err")),
+			gg.S("pending := *s.Pending"),
+			gg.S("pending.Ledger = append(slices.Clip(pending.Ledger), %sApprovalVote{Approver: approver, Decision: %sDecisionApproved, Stage: s.Current})", c.model.Name, c.model.Name),
+			gg.S("next := s"),
+			gg.S("next.Pending = &pending"),
+			gg.S("steps, quorum := pending.Policy()"),
+			gg.If("pending.Approvals(s.Current) < quorum").AddBody(gg.S("return next, nil")),
+			gg.If("i := slices.Index(steps, s.Current); i >= 0 && i+1 < len(steps)").AddBody(advance...),
+			gg.S("return s.Commit()"),
+		))
+}
+
+// generateRejectByMethod 生成 RejectBy：校验审批人后否决审批
+func (c *CodeGenerator) generateRejectByMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	group.AddLine()
+	group.Append(gg.LineComment("RejectBy 以 approver 的身份否决审批，任一级的任一审批人否决即回退"))
+	group.Append(gg.Function("RejectBy").
+		WithReceiver("s", stateType).
+		AddParameter("approver", "string").
+		AddResult("", stateType).
+		AddResult("", "error").
+		AddBody(
+			gg.If("err := s.checkVote(approver); err != nil").AddBody(gg.S("return s, err")),
+			gg.S("return s.Reject()"),
+		))
+}
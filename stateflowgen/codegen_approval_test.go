@@ -0,0 +1,62 @@
+package stateflowgen
+
+import (
+	"strings"
+	"testing"
+)
+
+const paymentPolicyFlow = `
+@StateFlow(name="Payment")
+@Flow: Submitted => [ Approved! via FinanceReview quorum 2, Escalated! via [ManagerReview, DirectorReview] else Submitted ]
+@Flow: Approved  => [ Paid ]
+@Flow: Escalated => [ Paid ]
+`
+
+func TestCodeGenerator_ApprovalPolicy(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, paymentPolicyFlow))
+
+	for _, fragment := range []string{
+		`PaymentDecisionApproved PaymentDecision = "approved"`,
+		"Ledger   []PaymentApprovalVote `json:\"ledger,omitempty\"`",
+		`ErrPaymentDuplicateVote      = errors.New("duplicate vote")`,
+		"return []PaymentStage{StagePaymentFinanceReview}, 2",
+		"return []PaymentStage{StagePaymentManagerReview, StagePaymentDirectorReview}, 1",
+		"func (s PaymentState) Approve(approver string) (PaymentState, error) {",
+		"func (s PaymentState) RejectBy(approver string) (PaymentState, error) {",
+		"return ErrPaymentDuplicateVote",
+		"next.Current = steps[i+1]",
+		"── <APPROVE> ──▶ DirectorReview (via)",
+		"FinanceReview (via, quorum 2)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated code missing %q", fragment)
+		}
+	}
+}
+
+func TestCodeGenerator_NoApprovalPolicy(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, `
+@StateFlow(name="Document")
+@Flow: Draft => [ Published! via Reviewing ]
+`))
+	for _, fragment := range []string{"Ledger", "Approve(", "RejectBy", "DuplicateVote", "Policy()"} {
+		if strings.Contains(output, fragment) {
+			t.Errorf("generated code unexpectedly contains %q", fragment)
+		}
+	}
+}
+
+func TestFlowGraph_ApprovalSteps(t *testing.T) {
+	mermaid := NewFlowGraph(buildTestModel(t, paymentPolicyFlow)).RenderMermaid()
+	for _, want := range []string{
+		`via_1 -->|"commit quorum 2"| s_Approved`,
+		`via_2 -->|"approve"| via_3`,
+		`via_3 -->|"commit"| s_Escalated`,
+		`via_2 -.->|"reject"| s_Submitted`,
+		`via_3 -.->|"reject"| s_Submitted`,
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gg"
+)
+
+// generateEnforceAPI 生成 sql=mysql|postgres 相关代码：允许流转表、更新校验与 GORM BeforeUpdate 钩子
+func (c *CodeGenerator) generateEnforceAPI(group *gg.Group) {
+	c.generateAllowedTransitionsVar(group)
+	c.generateValidateUpdateMethod(group)
+	c.generateBeforeUpdateFunc(group)
+}
+
+// generateAllowedTransitionsVar 生成持久化层允许的阶段变化表
+func (c *CodeGenerator) generateAllowedTransitionsVar(group *gg.Group) {
+	varName := c.model.Name + "AllowedTransitions"
+	stageType := c.model.Name + "Stage"
+
+	var order []Stage
+	targets := make(map[Stage][]string)
+	for _, pair := range c.model.StoredTransitions() {
+		if _, ok := targets[pair[0]]; !ok {
+			order = append(order, pair[0])
+		}
+		targets[pair[0]] = append(targets[pair[0]], c.getStageVarName(pair[1]))
+	}
+
+	var entries []string
+	for _, from := range order {
+		entries = append(entries, fmt.Sprintf("\t%s: {%s},", c.getStageVarName(from), strings.Join(targets[from], ", ")))
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 持久化层允许的阶段变化（含审批中间态）", varName))
+	group.Append(gg.LineComment("与生成的 .sql 文件中的允许流转表一致"))
+	group.Append(gg.S("var %s = map[%s][]%s{\n%s\n}", varName, stageType, stageType, strings.Join(entries, "\n")))
+}
+
+// generateValidateUpdateMethod 生成 StateColumns.ValidateUpdate：校验从旧状态列到新状态列的变化
+func (c *CodeGenerator) generateValidateUpdateMethod(group *gg.Group) {
+	c.gen.P("fmt")
+	c.gen.P("slices")
+	columnsType := c.model.Name + "StateColumns"
+
+	group.AddLine()
+	group.Append(gg.LineComment("ValidateUpdate 校验从 prev 更新为 c 是否合法：阶段未变或为允许的流转"))
+	group.Append(gg.S(`func (c %s) ValidateUpdate(prev %s) error {
+	from, to := prev.ToState().Current, c.ToState().Current
+	if from == to || slices.Contains(%sAllowedTransitions[from], to) {
+		return nil
+	}
+	return fmt.Errorf("%%v -> %%v: %%w", from, to, Err%sInvalidTransition)
+}`, columnsType, columnsType, c.model.Name, c.model.Name))
+}
+
+// generateBeforeUpdateFunc 生成 GORM BeforeUpdate 钩子辅助函数
+func (c *CodeGenerator) generateBeforeUpdateFunc(group *gg.Group) {
+	c.gen.P("errors")
+	c.gen.P("fmt")
+	c.gen.P("reflect")
+	c.gen.P("gorm.io/gorm")
+	c.gen.P("gorm.io/gorm/clause")
+	funcName := c.model.Name + "BeforeUpdate"
+	columnsType := c.model.Name + "StateColumns"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 供模型的 BeforeUpdate 钩子调用", funcName))
+	group.Append(gg.LineComment("按主键加载更新前的状态列，拒绝 ValidateUpdate 不允许的流转"))
+	group.Append(gg.S(`func %s(tx *gorm.DB, next %s) error {
+	stmt := tx.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("%%s: primary key required to load previous state", stmt.Table)
+	}
+	if stmt.ReflectValue.Kind() != reflect.Struct {
+		return nil
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	id, zero := field.ValueOf(stmt.Context, stmt.ReflectValue)
+	if zero {
+		return nil
+	}
+	var prev %s
+	err := tx.Session(&gorm.Session{NewDB: true}).
+		Table(stmt.Table).
+		Where(clause.Eq{Column: clause.Column{Name: field.DBName}, Value: id}).
+		Take(&prev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return next.ValidateUpdate(prev)
+}`, funcName, columnsType, columnsType))
+}
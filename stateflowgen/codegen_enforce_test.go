@@ -0,0 +1,129 @@
+package stateflowgen
+
+import (
+	"strings"
+	"testing"
+)
+
+const orderEnforceFlow = `
+@StateFlow(name="Order", sql=postgres, table=orders)
+@Flow: Created(Pending) => [ (Paid), Cancelled final ]
+@Flow: Created(Paid)    => [ Shipped? via [Review, Audit] else Created(Pending) ]
+@Flow: Shipped          => [ Delivered final ]
+`
+
+func TestParseSQLDialect(t *testing.T) {
+	for input, want := range map[string]SQLDialect{"": "", "MySQL": SQLMySQL, "postgres": SQLPostgres, "pg": SQLPostgres} {
+		if got, err := ParseSQLDialect(input); err != nil || got != want {
+			t.Errorf("ParseSQLDialect(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSQLDialect("sqlite"); err == nil {
+		t.Error("ParseSQLDialect(sqlite) expected error")
+	}
+}
+
+func TestStateModel_StoredTransitions(t *testing.T) {
+	var got []string
+	for _, pair := range buildTestModel(t, orderEnforceFlow).StoredTransitions() {
+		got = append(got, pair[0].String()+" -> "+pair[1].String())
+	}
+	want := []string{
+		"Created(Pending) -> Created(Paid)",
+		"Created(Pending) -> Cancelled",
+		"Created(Paid) -> Shipped",
+		"Created(Paid) -> Review",
+		"Review -> Audit",
+		"Review -> Created(Pending)",
+		"Audit -> Shipped",
+		"Audit -> Created(Pending)",
+		"Shipped -> Delivered",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("StoredTransitions() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStateModel_RenderSQL(t *testing.T) {
+	model := buildTestModel(t, orderEnforceFlow)
+	sql := model.RenderSQL()
+	for _, fragment := range []string{
+		`ALTER TABLE "orders" ADD CONSTRAINT "chk_orders_stage" CHECK (`,
+		`("phase" = 'Created' AND "status" IN ('Paid', 'Pending'))`,
+		`OR ("phase" IN ('Cancelled', 'Shipped', 'Review', 'Audit', 'Delivered') AND "status" = '')`,
+		`CREATE TABLE IF NOT EXISTS "order_transitions" (`,
+		`INSERT INTO "order_transitions" ("from_phase", "from_status", "to_phase", "to_status") VALUES`,
+		`('Review', '', 'Audit', '')`,
+		"ON CONFLICT DO NOTHING;",
+	} {
+		if !strings.Contains(sql, fragment) {
+			t.Errorf("postgres SQL missing %q:\n%s", fragment, sql)
+		}
+	}
+
+	model.SQL = SQLMySQL
+	model.Table = ""
+	sql = model.RenderSQL()
+	for _, fragment := range []string{
+		"ALTER TABLE `order` ADD CONSTRAINT `chk_order_stage` CHECK (",
+		"INSERT IGNORE INTO `order_transitions`",
+	} {
+		if !strings.Contains(sql, fragment) {
+			t.Errorf("mysql SQL missing %q:\n%s", fragment, sql)
+		}
+	}
+	if strings.Contains(sql, "ON CONFLICT") {
+		t.Errorf("mysql SQL should not use ON CONFLICT:\n%s", sql)
+	}
+}
+
+func TestStateModel_RenderSQL_Regions(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Shipment", sql=mysql)
+@Flow: Created    => [ Processing ]
+@Flow: Processing regions [ payment ]
+@Flow: payment.Unpaid => [ Paid final ]
+@Flow: Processing done => [ Completed final ]
+`)
+	sql := model.RenderSQL()
+	for _, fragment := range []string{
+		"CHECK (\n  `phase` IN ('Created', 'Processing', 'Completed')\n);",
+		"ADD CONSTRAINT `chk_shipment_payment` CHECK (`payment` IN ('', 'Unpaid', 'Paid'));",
+		"('Processing', 'Completed')",
+	} {
+		if !strings.Contains(sql, fragment) {
+			t.Errorf("SQL missing %q:\n%s", fragment, sql)
+		}
+	}
+}
+
+func TestCodeGenerator_Enforce(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, orderEnforceFlow))
+
+	for _, fragment := range []string{
+		`"gorm.io/gorm/clause"`,
+		"var OrderAllowedTransitions = map[OrderStage][]OrderStage{",
+		"StageOrderReview:         {StageOrderAudit, StageOrderCreatedPending},",
+		"func (c OrderStateColumns) ValidateUpdate(prev OrderStateColumns) error {",
+		"if from == to || slices.Contains(OrderAllowedTransitions[from], to) {",
+		"func OrderBeforeUpdate(tx *gorm.DB, next OrderStateColumns) error {",
+		"Take(&prev).Error",
+		"return next.ValidateUpdate(prev)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated code missing %q", fragment)
+		}
+	}
+}
+
+func TestCodeGenerator_NoEnforce(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Created => [ Paid ]
+`))
+	for _, fragment := range []string{"AllowedTransitions", "ValidateUpdate", "BeforeUpdate", "gorm.io/gorm"} {
+		if strings.Contains(output, fragment) {
+			t.Errorf("generated code unexpectedly contains %q", fragment)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// generateEventAPI 生成 on/if 相关的事件枚举、守卫、钩子与 Fire 方法
+func (c *CodeGenerator) generateEventAPI(group *gg.Group) {
+	c.generateEventEnum(group)
+	if len(c.model.Guards) > 0 {
+		c.generateGuardsInterface(group)
+	}
+	c.generateHooksInterface(group)
+	c.generateFireContextType(group)
+	if len(c.model.Guards) > 0 {
+		c.generateGuardErrorType(group)
+	}
+	c.generateFireMethod(group)
+	c.generateEventTargetMethod(group)
+	if len(c.model.Guards) > 0 {
+		c.generateCheckGuardMethod(group)
+	}
+	if c.model.HasApproval {
+		c.generateApprovalHookMethods(group)
+	}
+	c.generateHookDispatchMethods(group)
+}
+
+// generateEventEnum 生成事件枚举
+func (c *CodeGenerator) generateEventEnum(group *gg.Group) {
+	typeName := c.model.Name + "Event"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 事件枚举", typeName))
+	group.Append(gg.Type(typeName, "string"))
+
+	group.AddLine()
+
+	constGroup := gg.Const()
+	for _, event := range c.model.Events {
+		constGroup.AddTypedField(typeName+utils.UpperCamelCase(event), typeName, gg.Lit(event))
+	}
+	group.Append(constGroup)
+
+	c.generateEnumAggregateVar(group, typeName, c.model.Events)
+}
+
+// generateGuardsInterface 生成守卫接口，每个 if 守卫对应一个方法
+func (c *CodeGenerator) generateGuardsInterface(group *gg.Group) {
+	c.gen.P("context")
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+
+	var methods []string
+	for _, guard := range c.model.Guards {
+		methods = append(methods, fmt.Sprintf("\t%s(ctx context.Context, s %s, to %s) error", utils.UpperCamelCase(guard), stateType, stageType))
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%sGuards 流转守卫，返回非 nil 错误时拒绝流转", c.model.Name))
+	group.Append(gg.S("type %sGuards interface {\n%s\n}", c.model.Name, strings.Join(methods, "\n")))
+}
+
+// hookMethod 钩子方法签名
+type hookMethod struct {
+	name   string
+	params string // 带参数名，用于接口声明
+	types  string // 仅类型，用于空实现
+}
+
+// hookMethods 返回钩子接口的全部方法：每个事件 Before/After，每个阶段 OnEnter/OnExit
+func (c *CodeGenerator) hookMethods() []hookMethod {
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+
+	var methods []hookMethod
+	for _, event := range c.model.Events {
+		name := utils.UpperCamelCase(event)
+		methods = append(methods,
+			hookMethod{"Before" + name, fmt.Sprintf("ctx context.Context, s %s, to %s", stateType, stageType), fmt.Sprintf("context.Context, %s, %s", stateType, stageType)},
+			hookMethod{"After" + name, fmt.Sprintf("ctx context.Context, from %s, to %s", stateType, stateType), fmt.Sprintf("context.Context, %s, %s", stateType, stateType)},
+		)
+	}
+	for _, stage := range c.model.GetAllStages() {
+		name := c.stageHookSuffix(stage)
+		methods = append(methods,
+			hookMethod{"OnEnter" + name, fmt.Sprintf("ctx context.Context, s %s", stateType), "context.Context, " + stateType},
+			hookMethod{"OnExit" + name, fmt.Sprintf("ctx context.Context, s %s", stateType), "context.Context, " + stateType},
+		)
+	}
+	return methods
+}
+
+// generateHooksInterface 生成钩子接口及其空实现
+func (c *CodeGenerator) generateHooksInterface(group *gg.Group) {
+	c.gen.P("context")
+	hooksType := c.model.Name + "Hooks"
+	nopType := c.model.Name + "NopHooks"
+	methods := c.hookMethods()
+
+	var decls []string
+	for _, m := range methods {
+		decls = append(decls, fmt.Sprintf("\t%s(%s) error", m.name, m.params))
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 流转钩子，任一钩子返回错误时中止流转", hooksType))
+	group.Append(gg.LineComment("嵌入 %s 后只需实现关心的方法", nopType))
+	group.Append(gg.S("type %s interface {\n%s\n}", hooksType, strings.Join(decls, "\n")))
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s %s 的空实现", nopType, hooksType))
+	group.Append(gg.S("type %s struct{}", nopType))
+
+	for _, m := range methods {
+		group.AddLine()
+		group.Append(gg.S("func (%s) %s(%s) error { return nil }", nopType, m.name, m.types))
+	}
+}
+
+// generateFireContextType 生成 Fire 的执行上下文
+func (c *CodeGenerator) generateFireContextType(group *gg.Group) {
+	c.gen.P("context")
+	typeName := c.model.Name + "Context"
+
+	fields := []string{
+		"\tCtx context.Context // 为 nil 时使用 context.Background()",
+	}
+	if len(c.model.Guards) > 0 {
+		fields = append(fields, fmt.Sprintf("\tGuards %sGuards // 存在 if 守卫时必须设置", c.model.Name))
+	}
+	fields = append(fields, fmt.Sprintf("\tHooks %sHooks // 可为 nil", c.model.Name))
+	if c.model.HasOptionalApproval {
+		fields = append(fields, "\tWithApproval bool // 可选审批（?）时是否发起审批")
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s Fire 的执行上下文", typeName))
+	group.Append(gg.S("type %s struct {\n%s\n}", typeName, strings.Join(fields, "\n")))
+}
+
+// generateGuardErrorType 生成守卫拒绝时返回的错误类型
+func (c *CodeGenerator) generateGuardErrorType(group *gg.Group) {
+	c.gen.P("fmt")
+	typeName := c.model.Name + "GuardError"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 守卫拒绝流转时返回的错误", typeName))
+	group.Append(gg.S(`type %s struct {
+	Guard string
+	Event %sEvent
+	From  %sStage
+	To    %sStage
+	Err   error
+}`, typeName, c.model.Name, c.model.Name, c.model.Name))
+
+	group.AddLine()
+	group.Append(gg.S(`func (e *%s) Error() string {
+	return fmt.Sprintf("guard %%s rejected %%s (%%v -> %%v): %%v", e.Guard, e.Event, e.From, e.To, e.Err)
+}`, typeName))
+
+	group.AddLine()
+	group.Append(gg.S(`func (e *%s) Unwrap() error {
+	return e.Err
+}`, typeName))
+}
+
+// generateFireMethod 生成 Fire 方法
+func (c *CodeGenerator) generateFireMethod(group *gg.Group) {
+	c.gen.P("context")
+	stateType := c.model.Name + "State"
+	eventType := c.model.Name + "Event"
+
+	transitionCall := "s.TransitionTo(to)"
+	if c.model.HasOptionalApproval {
+		transitionCall = "s.TransitionTo(to, ctx.WithApproval)"
+	}
+
+	targetLHS, guardCheck := "to, _, ok", ""
+	if len(c.model.Guards) > 0 {
+		targetLHS = "to, guard, ok"
+		guardCheck = `
+	if guard != "" {
+		if err := s.checkGuard(ctx, guard, event, to); err != nil {
+			return s, err
+		}
+	}`
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("Fire 触发事件，依次执行守卫、Before、TransitionTo、OnExit、OnEnter、After"))
+	group.Append(gg.LineComment("OnExit/OnEnter 仅在当前阶段发生变化时调用；任一步骤失败时返回原状态"))
+	if c.model.HasApproval {
+		group.Append(gg.LineComment("发起审批时停在 via 阶段，之后以 CommitWith / RejectWith 完成审批，同样执行守卫与钩子"))
+	}
+	group.Append(gg.S(`func (s %s) Fire(event %s, ctx %sContext) (%s, error) {
+	if ctx.Ctx == nil {
+		ctx.Ctx = context.Background()
+	}
+	%s := s.eventTarget(event)
+	if !ok {
+		return s, Err%sInvalidTransition
+	}%s
+	if ctx.Hooks != nil {
+		if err := s.beforeEvent(ctx, event, to); err != nil {
+			return s, err
+		}
+	}
+	next, err := %s
+	if err != nil {
+		return s, err
+	}
+	if ctx.Hooks != nil {
+		if next.Current != s.Current {
+			if err := s.exitStage(ctx); err != nil {
+				return s, err
+			}
+			if err := next.enterStage(ctx); err != nil {
+				return s, err
+			}
+		}
+		if err := s.afterEvent(ctx, event, next); err != nil {
+			return s, err
+		}
+	}
+	return next, nil
+}`, stateType, eventType, c.model.Name, stateType, targetLHS, c.model.Name, guardCheck, transitionCall))
+}
+
+// generateEventTargetMethod 生成 eventTarget 方法：按当前阶段与事件查找目标阶段和守卫
+func (c *CodeGenerator) generateEventTargetMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+	eventType := c.model.Name + "Event"
+
+	byFrom := make(map[string][]Transition)
+	seen := make(map[string]bool)
+	for _, trans := range c.model.Transitions {
+		key := trans.From.String() + "|" + trans.Event
+		if trans.Event == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		byFrom[trans.From.String()] = append(byFrom[trans.From.String()], trans)
+	}
+
+	var lines []string
+	for _, stage := range c.model.GetAllStages() {
+		transitions := byFrom[stage.String()]
+		if len(transitions) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("\tcase %s:", c.getStageVarName(stage)), "\t\tswitch event {")
+		for _, trans := range transitions {
+			lines = append(lines,
+				fmt.Sprintf("\t\tcase %s%s:", eventType, utils.UpperCamelCase(trans.Event)),
+				fmt.Sprintf("\t\t\treturn %s, %q, true", c.getStageVarName(trans.To), trans.Guard),
+			)
+		}
+		lines = append(lines, "\t\t}")
+	}
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) eventTarget(event %s) (%s, string, bool) {
+	switch s.Current {
+%s
+	}
+	return s.Current, "", false
+}`, stateType, eventType, stageType, strings.Join(lines, "\n")))
+}
+
+// generateCheckGuardMethod 生成 checkGuard 方法
+func (c *CodeGenerator) generateCheckGuardMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+	eventType := c.model.Name + "Event"
+	errType := c.model.Name + "GuardError"
+
+	var cases []string
+	for _, guard := range c.model.Guards {
+		cases = append(cases,
+			fmt.Sprintf("\tcase %q:", guard),
+			fmt.Sprintf("\t\terr = ctx.Guards.%s(ctx.Ctx, s, to)", utils.UpperCamelCase(guard)),
+		)
+	}
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) checkGuard(ctx %sContext, guard string, event %s, to %s) error {
+	if ctx.Guards == nil {
+		return &%s{Guard: guard, Event: event, From: s.Current, To: to, Err: Err%sGuardsNotConfigured}
+	}
+	var err error
+	switch guard {
+%s
+	}
+	if err != nil {
+		return &%s{Guard: guard, Event: event, From: s.Current, To: to, Err: err}
+	}
+	return nil
+}`, stateType, c.model.Name, eventType, stageType, errType, c.model.Name, strings.Join(cases, "\n"), errType))
+}
+
+// approvalGuardTransitions 返回带守卫的审批流转，同一源/目标只取第一条（与 TransitionTo 一致）
+func (c *CodeGenerator) approvalGuardTransitions() []Transition {
+	var out []Transition
+	seen := make(map[string]bool)
+	for _, trans := range c.model.Transitions {
+		key := trans.From.String() + "|" + trans.To.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if trans.Via.Phase != "" && trans.Guard != "" {
+			out = append(out, trans)
+		}
+	}
+	return out
+}
+
+// generateApprovalHookMethods 生成经过守卫与钩子的审批处理方法：CommitWith、RejectWith（有审批策略时另有 ApproveWith、RejectByWith）
+// Fire 发起审批后停在 via 阶段，这些方法完成审批时同样调用 OnExit/OnEnter，提交时再次检查该流转的守卫
+func (c *CodeGenerator) generateApprovalHookMethods(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	ctxType := c.model.Name + "Context"
+
+	methods := []struct{ name, params, call, committed, doc string }{
+		{"CommitWith", "", "s.Commit()", "true", "CommitWith 与 Commit 相同，同时检查守卫并执行 OnExit（via 阶段）、OnEnter（目标阶段）钩子"},
+		{"RejectWith", "", "s.Reject()", "false", "RejectWith 与 Reject 相同，同时执行 OnExit（via 阶段）、OnEnter（回退阶段）钩子"},
+	}
+	if c.model.HasApprovalPolicy() {
+		methods = append(methods,
+			struct{ name, params, call, committed, doc string }{"ApproveWith", ", approver string", "s.Approve(approver)", "next.Pending == nil", "ApproveWith 与 Approve 相同，进入下一级或提交时执行钩子，提交时检查守卫"},
+			struct{ name, params, call, committed, doc string }{"RejectByWith", ", approver string", "s.RejectBy(approver)", "false", "RejectByWith 与 RejectBy 相同，同时执行 OnExit、OnEnter 钩子"},
+		)
+	}
+	for _, m := range methods {
+		group.AddLine()
+		group.Append(gg.LineComment("%s", m.doc))
+		group.Append(gg.S(`func (s %s) %s(ctx %s%s) (%s, error) {
+	next, err := %s
+	if err != nil {
+		return s, err
+	}
+	return s.resolveApproval(ctx, next, %s)
+}`, stateType, m.name, ctxType, m.params, stateType, m.call, m.committed))
+	}
+
+	guards := c.approvalGuardTransitions()
+	guardCheck := ""
+	if len(guards) > 0 {
+		guardCheck = `
+	if committed {
+		if event, guard := s.Pending.guard(); guard != "" {
+			if err := s.checkGuard(ctx, guard, event, next.Current); err != nil {
+				return s, err
+			}
+		}
+	}`
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("resolveApproval 审批状态变化后执行的守卫与钩子，committed 表示审批已提交到目标阶段"))
+	group.Append(gg.S(`func (s %s) resolveApproval(ctx %s, next %s, committed bool) (%s, error) {
+	if ctx.Ctx == nil {
+		ctx.Ctx = context.Background()
+	}%s
+	if ctx.Hooks != nil && next.Current != s.Current {
+		if err := s.exitStage(ctx); err != nil {
+			return s, err
+		}
+		if err := next.enterStage(ctx); err != nil {
+			return s, err
+		}
+	}
+	return next, nil
+}`, stateType, ctxType, stateType, stateType, guardCheck))
+
+	if len(guards) > 0 {
+		c.generatePendingGuardMethod(group, guards)
+	}
+}
+
+// generatePendingGuardMethod 生成 guard 方法：按审批的源/目标查找发起审批的事件与守卫
+func (c *CodeGenerator) generatePendingGuardMethod(group *gg.Group, guards []Transition) {
+	eventType := c.model.Name + "Event"
+
+	var lines []string
+	for _, trans := range guards {
+		lines = append(lines,
+			fmt.Sprintf("	case p.From == %s && p.To == %s:", c.getStageVarName(trans.From), c.getStageVarName(trans.To)),
+			fmt.Sprintf("		return %s%s, %q", eventType, utils.UpperCamelCase(trans.Event), trans.Guard),
+		)
+	}
+
+	group.AddLine()
+	group.Append(gg.S(`func (p *%sPendingTransition) guard() (%s, string) {
+	switch {
+%s
+	}
+	return "", ""
+}`, c.model.Name, eventType, strings.Join(lines, "\n")))
+}
+
+// generateHookDispatchMethods 生成 beforeEvent/afterEvent/enterStage/exitStage
+func (c *CodeGenerator) generateHookDispatchMethods(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+	eventType := c.model.Name + "Event"
+	ctxType := c.model.Name + "Context"
+
+	var beforeCases, afterCases []string
+	for _, event := range c.model.Events {
+		name := utils.UpperCamelCase(event)
+		beforeCases = append(beforeCases,
+			fmt.Sprintf("\tcase %s%s:", eventType, name),
+			fmt.Sprintf("\t\treturn ctx.Hooks.Before%s(ctx.Ctx, s, to)", name),
+		)
+		afterCases = append(afterCases,
+			fmt.Sprintf("\tcase %s%s:", eventType, name),
+			fmt.Sprintf("\t\treturn ctx.Hooks.After%s(ctx.Ctx, s, next)", name),
+		)
+	}
+
+	var enterCases, exitCases []string
+	for _, stage := range c.model.GetAllStages() {
+		varName := c.getStageVarName(stage)
+		name := c.stageHookSuffix(stage)
+		enterCases = append(enterCases,
+			fmt.Sprintf("\tcase %s:", varName),
+			fmt.Sprintf("\t\treturn ctx.Hooks.OnEnter%s(ctx.Ctx, s)", name),
+		)
+		exitCases = append(exitCases,
+			fmt.Sprintf("\tcase %s:", varName),
+			fmt.Sprintf("\t\treturn ctx.Hooks.OnExit%s(ctx.Ctx, s)", name),
+		)
+	}
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) beforeEvent(ctx %s, event %s, to %s) error {
+	switch event {
+%s
+	}
+	return nil
+}`, stateType, ctxType, eventType, stageType, strings.Join(beforeCases, "\n")))
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) afterEvent(ctx %s, event %s, next %s) error {
+	switch event {
+%s
+	}
+	return nil
+}`, stateType, ctxType, eventType, stateType, strings.Join(afterCases, "\n")))
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) enterStage(ctx %s) error {
+	switch s.Current {
+%s
+	}
+	return nil
+}`, stateType, ctxType, strings.Join(enterCases, "\n")))
+
+	group.AddLine()
+	group.Append(gg.S(`func (s %s) exitStage(ctx %s) error {
+	switch s.Current {
+%s
+	}
+	return nil
+}`, stateType, ctxType, strings.Join(exitCases, "\n")))
+}
+
+// stageHookSuffix 阶段钩子方法名后缀，如 ReadyRunning
+func (c *CodeGenerator) stageHookSuffix(stage Stage) string {
+	return strings.TrimPrefix(c.getStageVarName(stage), "Stage"+c.model.Name)
+}
@@ -0,0 +1,95 @@
+package stateflowgen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestCodeGenerator_EventAPI(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Created => [ Paid on Pay, Cancelled on Cancel ]
+@Flow: Paid    => [ Shipped ] on Ship if canShip
+@Flow: Paid    => [ Refunded? via Refunding ] on Refund
+`)
+
+	gen, err := NewCodeGenerator(model, "order").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	src, err := format.Source(gen.Bytes())
+	if err != nil {
+		t.Fatalf("format.Source() error = %v\n%s", err, gen.Bytes())
+	}
+	output := string(src)
+
+	for _, fragment := range []string{
+		"type OrderEvent string",
+		`OrderEvent = "Ship"`,
+		"CanShip(ctx context.Context, s OrderState, to OrderStage) error",
+		"BeforeShip(ctx context.Context, s OrderState, to OrderStage) error",
+		"AfterShip(ctx context.Context, from OrderState, to OrderState) error",
+		"OnEnterRefunding(ctx context.Context, s OrderState) error",
+		"OnExitCreated(ctx context.Context, s OrderState) error",
+		"func (OrderNopHooks) OnExitPaid(context.Context, OrderState) error { return nil }",
+		"WithApproval bool",
+		"type OrderGuardError struct",
+		"func (s OrderState) Fire(event OrderEvent, ctx OrderContext) (OrderState, error)",
+		"next, err := s.TransitionTo(to, ctx.WithApproval)",
+		`return StageOrderShipped, "canShip", true`,
+		`return StageOrderRefunded, "", true`,
+		"err = ctx.Guards.CanShip(ctx.Ctx, s, to)",
+		`ErrOrderGuardsNotConfigured = errors.New("guards not configured")`,
+		// 审批的提交与否决同样执行钩子
+		"func (s OrderState) CommitWith(ctx OrderContext) (OrderState, error) {",
+		"return s.resolveApproval(ctx, next, true)",
+		"func (s OrderState) RejectWith(ctx OrderContext) (OrderState, error) {",
+		"func (s OrderState) resolveApproval(ctx OrderContext, next OrderState, committed bool) (OrderState, error) {",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated output missing %q", fragment)
+		}
+	}
+	if t.Failed() {
+		t.Log(output)
+	}
+}
+
+func TestCodeGenerator_EventsWithoutGuards(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Door")
+@Flow: Closed => [ Open on Push ]
+@Flow: Open   => [ Closed on Pull ]
+`)
+
+	gen, err := NewCodeGenerator(model, "door").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := gen.String()
+
+	if !strings.Contains(output, "to, _, ok := s.eventTarget(event)") {
+		t.Errorf("Fire should ignore guard name when no guards are defined:\n%s", output)
+	}
+	for _, unexpected := range []string{"DoorGuards", "DoorGuardError", "checkGuard", "GuardsNotConfigured", "WithApproval"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("generated output contains unexpected %q", unexpected)
+		}
+	}
+}
+
+func TestCodeGenerator_NoEvents(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Plain")
+@Flow: A => [ B ]
+`)
+
+	gen, err := NewCodeGenerator(model, "plain").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if output := gen.String(); strings.Contains(output, "Fire(") || strings.Contains(output, "PlainEvent") {
+		t.Errorf("event API should not be generated without on clauses:\n%s", output)
+	}
+}
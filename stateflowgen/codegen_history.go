@@ -0,0 +1,330 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gg"
+)
+
+// generateHistoryAPI 生成 history=true 相关代码：流转记录、带元数据的流转方法、历史表与 Replay
+func (c *CodeGenerator) generateHistoryAPI(group *gg.Group) {
+	if c.model.HasApproval {
+		c.generateApprovalActionEnum(group)
+	}
+	c.generateTransitionRecordType(group)
+	c.generateTransitionMetaType(group)
+	c.generateTransitionToWithMetaMethod(group)
+	if c.model.HasApproval {
+		c.generateApprovalWithMetaMethods(group)
+	}
+	if c.model.HasApprovalPolicy() {
+		c.generateApproveWithMetaMethod(group)
+	}
+	c.generateHistoryTableType(group)
+	c.generateHistoryConvertMethods(group)
+	c.generateReplayFunc(group)
+}
+
+// eventFieldType 记录中事件字段的类型：定义了 on 事件时使用事件枚举
+func (c *CodeGenerator) eventFieldType() string {
+	if c.model.HasEvents() {
+		return c.model.Name + "Event"
+	}
+	return "string"
+}
+
+// generateApprovalActionEnum 生成审批动作枚举
+func (c *CodeGenerator) generateApprovalActionEnum(group *gg.Group) {
+	typeName := c.model.Name + "Approval"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 流转记录中的审批动作", typeName))
+	group.Append(gg.Type(typeName, "string"))
+
+	group.AddLine()
+	constGroup := gg.Const()
+	constGroup.AddTypedField(typeName+"None", typeName, gg.Lit(""))
+	constGroup.AddTypedField(typeName+"Requested", typeName, gg.Lit("requested"))
+	constGroup.AddTypedField(typeName+"Committed", typeName, gg.Lit("committed"))
+	constGroup.AddTypedField(typeName+"Rejected", typeName, gg.Lit("rejected"))
+	if c.model.HasApprovalPolicy() {
+		constGroup.AddTypedField(typeName+"Approved", typeName, gg.Lit("approved"))
+	}
+	group.Append(constGroup)
+}
+
+// generateTransitionRecordType 生成流转记录类型
+func (c *CodeGenerator) generateTransitionRecordType(group *gg.Group) {
+	c.gen.P("time")
+	typeName := c.model.Name + "TransitionRecord"
+	stageType := c.model.Name + "Stage"
+
+	fields := []string{
+		fmt.Sprintf("\tFrom %s `json:\"from\"`", stageType),
+		fmt.Sprintf("\tTo %s `json:\"to\"`", stageType),
+		fmt.Sprintf("\tEvent %s `json:\"event,omitempty\"`", c.eventFieldType()),
+		"\tActor string `json:\"actor,omitempty\"`",
+		"\tAt time.Time `json:\"at\"`",
+	}
+	if c.model.HasApproval {
+		fields = append(fields, fmt.Sprintf("\tApproval %sApproval `json:\"approval,omitempty\"`", c.model.Name))
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 流转记录", typeName))
+	if c.model.HasApproval {
+		group.Append(gg.LineComment("From 为流转前阶段；审批申请（requested）的 To 为审批目标"))
+	}
+	group.Append(gg.S("type %s struct {\n%s\n}", typeName, strings.Join(fields, "\n")))
+}
+
+// generateTransitionMetaType 生成流转元数据类型
+func (c *CodeGenerator) generateTransitionMetaType(group *gg.Group) {
+	c.gen.P("time")
+	typeName := c.model.Name + "TransitionMeta"
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 流转元数据", typeName))
+	group.Append(gg.S(`type %s struct {
+	Event %s
+	Actor string
+	At    time.Time // 为零值时使用 time.Now()
+}`, typeName, c.eventFieldType()))
+
+	group.AddLine()
+	group.Append(gg.S(`func (m %s) record(from, to %sStage) %sTransitionRecord {
+	at := m.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return %sTransitionRecord{From: from, To: to, Event: m.Event, Actor: m.Actor, At: at}
+}`, typeName, c.model.Name, c.model.Name, c.model.Name))
+}
+
+// generateTransitionToWithMetaMethod 生成 TransitionToWithMeta 方法
+func (c *CodeGenerator) generateTransitionToWithMetaMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	stageType := c.model.Name + "Stage"
+	recordType := c.model.Name + "TransitionRecord"
+
+	params := fmt.Sprintf("to %s", stageType)
+	call := "s.TransitionTo(to)"
+	if c.model.HasOptionalApproval {
+		params += ", withApproval bool"
+		call = "s.TransitionTo(to, withApproval)"
+	}
+
+	approval := ""
+	if c.model.HasApproval {
+		approval = fmt.Sprintf(`
+	if next.Pending != nil {
+		record.Approval = %sApprovalRequested
+	}`, c.model.Name)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("TransitionToWithMeta 与 TransitionTo 相同，同时返回本次流转的记录"))
+	group.Append(gg.S(`func (s %s) TransitionToWithMeta(%s, meta %sTransitionMeta) (%s, %s, error) {
+	next, err := %s
+	if err != nil {
+		return s, %s{}, err
+	}
+	record := meta.record(s.Current, to)%s
+	return next, record, nil
+}`, stateType, params, c.model.Name, stateType, recordType, call, recordType, approval))
+}
+
+// generateApprovalWithMetaMethods 生成 CommitWithMeta / RejectWithMeta 方法
+func (c *CodeGenerator) generateApprovalWithMetaMethods(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	recordType := c.model.Name + "TransitionRecord"
+
+	for _, m := range []struct{ name, action string }{
+		{"Commit", "Committed"},
+		{"Reject", "Rejected"},
+	} {
+		group.AddLine()
+		group.Append(gg.LineComment("%sWithMeta 与 %s 相同，同时返回本次流转的记录", m.name, m.name))
+		group.Append(gg.S(`func (s %s) %sWithMeta(meta %sTransitionMeta) (%s, %s, error) {
+	next, err := s.%s()
+	if err != nil {
+		return s, %s{}, err
+	}
+	record := meta.record(s.Current, next.Current)
+	record.Approval = %sApproval%s
+	return next, record, nil
+}`, stateType, m.name, c.model.Name, stateType, recordType, m.name, recordType, c.model.Name, m.action))
+	}
+}
+
+// generateApproveWithMetaMethod 生成 ApproveWithMeta：以 meta.Actor 作为审批人投赞成票
+func (c *CodeGenerator) generateApproveWithMetaMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	recordType := c.model.Name + "TransitionRecord"
+
+	group.AddLine()
+	group.Append(gg.LineComment("ApproveWithMeta 以 meta.Actor 作为审批人调用 Approve，同时返回本次投票的记录"))
+	group.Append(gg.LineComment("未满足审批策略时记录为 approved，满足并提交时记录为 committed"))
+	group.Append(gg.S(`func (s %s) ApproveWithMeta(meta %sTransitionMeta) (%s, %s, error) {
+	next, err := s.Approve(meta.Actor)
+	if err != nil {
+		return s, %s{}, err
+	}
+	record := meta.record(s.Current, next.Current)
+	record.Approval = %sApprovalApproved
+	if next.Pending == nil {
+		record.Approval = %sApprovalCommitted
+	}
+	return next, record, nil
+}`, stateType, c.model.Name, stateType, recordType, recordType, c.model.Name, c.model.Name))
+}
+
+// generateHistoryTableType 生成 GORM 历史表结构
+func (c *CodeGenerator) generateHistoryTableType(group *gg.Group) {
+	c.gen.P("time")
+	typeName := c.model.Name + "TransitionHistory"
+	phaseType := c.model.Name + "Phase"
+	statusType := c.model.Name + "Status"
+
+	fields := []string{
+		"\tID uint64 `gorm:\"column:id;primaryKey;autoIncrement\" json:\"id\"`",
+		"\tEntityID string `gorm:\"column:entity_id;index\" json:\"entity_id\"`",
+		fmt.Sprintf("\tFromPhase %s `gorm:\"column:from_phase\" json:\"from_phase\"`", phaseType),
+	}
+	if c.model.HasStatus {
+		fields = append(fields, fmt.Sprintf("\tFromStatus %s `gorm:\"column:from_status\" json:\"from_status\"`", statusType))
+	}
+	fields = append(fields, fmt.Sprintf("\tToPhase %s `gorm:\"column:to_phase\" json:\"to_phase\"`", phaseType))
+	if c.model.HasStatus {
+		fields = append(fields, fmt.Sprintf("\tToStatus %s `gorm:\"column:to_status\" json:\"to_status\"`", statusType))
+	}
+	fields = append(fields,
+		fmt.Sprintf("\tEvent %s `gorm:\"column:event\" json:\"event\"`", c.eventFieldType()),
+		"\tActor string `gorm:\"column:actor\" json:\"actor\"`",
+	)
+	if c.model.HasApproval {
+		fields = append(fields, fmt.Sprintf("\tApproval %sApproval `gorm:\"column:approval\" json:\"approval\"`", c.model.Name))
+	}
+	fields = append(fields, "\tAt time.Time `gorm:\"column:at;index\" json:\"at\"`")
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 流转历史表，与 %sStateColumns 配套", typeName, c.model.Name))
+	group.Append(gg.S("type %s struct {\n%s\n}", typeName, strings.Join(fields, "\n")))
+}
+
+// generateHistoryConvertMethods 生成 TransitionRecord <-> TransitionHistory 转换方法
+func (c *CodeGenerator) generateHistoryConvertMethods(group *gg.Group) {
+	recordType := c.model.Name + "TransitionRecord"
+	historyType := c.model.Name + "TransitionHistory"
+	stageType := c.model.Name + "Stage"
+
+	var toHistory, toRecord []string
+	if c.model.HasStatus {
+		toHistory = append(toHistory,
+			"\t\tFromPhase: r.From.Phase,",
+			"\t\tFromStatus: r.From.Status,",
+			"\t\tToPhase: r.To.Phase,",
+			"\t\tToStatus: r.To.Status,",
+		)
+		toRecord = append(toRecord,
+			fmt.Sprintf("\t\tFrom: %s{Phase: h.FromPhase, Status: h.FromStatus},", stageType),
+			fmt.Sprintf("\t\tTo: %s{Phase: h.ToPhase, Status: h.ToStatus},", stageType),
+		)
+	} else {
+		toHistory = append(toHistory, "\t\tFromPhase: r.From,", "\t\tToPhase: r.To,")
+		toRecord = append(toRecord, "\t\tFrom: h.FromPhase,", "\t\tTo: h.ToPhase,")
+	}
+	for _, f := range []string{"Event", "Actor", "Approval", "At"} {
+		if f == "Approval" && !c.model.HasApproval {
+			continue
+		}
+		toHistory = append(toHistory, fmt.Sprintf("\t\t%s: r.%s,", f, f))
+		toRecord = append(toRecord, fmt.Sprintf("\t\t%s: h.%s,", f, f))
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("ToHistory 转换为历史表记录"))
+	group.Append(gg.S(`func (r %s) ToHistory(entityID string) %s {
+	return %s{
+		EntityID: entityID,
+%s
+	}
+}`, recordType, historyType, historyType, strings.Join(toHistory, "\n")))
+
+	group.AddLine()
+	group.Append(gg.LineComment("ToRecord 转换为流转记录"))
+	group.Append(gg.S(`func (h %s) ToRecord() %s {
+	return %s{
+%s
+	}
+}`, historyType, recordType, recordType, strings.Join(toRecord, "\n")))
+}
+
+// generateReplayFunc 生成 Replay 函数：按记录重放流转并校验每一步
+func (c *CodeGenerator) generateReplayFunc(group *gg.Group) {
+	c.gen.P("fmt")
+	stateType := c.model.Name + "State"
+	recordType := c.model.Name + "TransitionRecord"
+	errInvalid := "Err" + c.model.Name + "InvalidTransition"
+
+	direct := "s.TransitionTo(r.To)"
+	requested := "s.TransitionTo(r.To)"
+	if c.model.HasOptionalApproval {
+		direct = "s.TransitionTo(r.To, false)"
+		requested = "s.TransitionTo(r.To, true)"
+	}
+
+	// 多级、多人审批的中间投票按审批人重放
+	approved := ""
+	if c.model.HasApprovalPolicy() {
+		approved = fmt.Sprintf(`
+		case %sApprovalApproved:
+			next, err = s.Approve(r.Actor)`, c.model.Name)
+	}
+
+	var body string
+	if c.model.HasApproval {
+		body = fmt.Sprintf(`		var next %s
+		var err error
+		switch r.Approval {
+		case %sApprovalCommitted:
+			next, err = s.Commit()
+		case %sApprovalRejected:
+			next, err = s.Reject()%s
+		case %sApprovalRequested:
+			next, err = %s
+			if err == nil && next.Pending == nil {
+				err = fmt.Errorf("transition does not require approval: %%w", %s)
+			}
+		default:
+			next, err = %s
+			if err == nil && next.Pending != nil {
+				err = fmt.Errorf("transition requires approval: %%w", %s)
+			}
+		}
+		if err == nil && r.Approval != %sApprovalRequested && next.Current != r.To {
+			err = fmt.Errorf("resulting stage %%v does not match: %%w", next.Current, %s)
+		}`, stateType, c.model.Name, c.model.Name, approved, c.model.Name, requested, errInvalid, direct, errInvalid, c.model.Name, errInvalid)
+	} else {
+		body = fmt.Sprintf(`		next, err := %s`, direct)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("Replay%s 从初始状态按顺序重放流转记录，重建并校验状态", c.model.Name))
+	group.Append(gg.LineComment("记录的 From 与当前阶段不一致、流转非法或审批动作不匹配时返回错误"))
+	group.Append(gg.S(`func Replay%s(initial %s, records []%s) (%s, error) {
+	s := initial
+	for i, r := range records {
+		if r.From != s.Current {
+			return s, fmt.Errorf("record %%d: from %%v does not match current stage %%v: %%w", i, r.From, s.Current, %s)
+		}
+%s
+		if err != nil {
+			return s, fmt.Errorf("record %%d (%%v -> %%v): %%w", i, r.From, r.To, err)
+		}
+		s = next
+	}
+	return s, nil
+}`, c.model.Name, stateType, recordType, stateType, errInvalid, body))
+}
@@ -0,0 +1,93 @@
+package stateflowgen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func generateFormatted(t *testing.T, model *StateModel) string {
+	t.Helper()
+	gen, err := NewCodeGenerator(model, "order").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	src, err := format.Source(gen.Bytes())
+	if err != nil {
+		t.Fatalf("format.Source() error = %v\n%s", err, gen.Bytes())
+	}
+	return string(src)
+}
+
+func TestCodeGenerator_HistoryAPI(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Order", history=true)
+@Flow: Created(Pending) => [ (Paid), Cancelled? via Cancelling ]
+@Flow: Created(Paid)    => [ Shipped! via Reviewing else Created(Pending) ]
+`)
+	if !model.History {
+		t.Fatal("model.History = false, want true")
+	}
+	output := generateFormatted(t, model)
+
+	for _, fragment := range []string{
+		`OrderApprovalRequested OrderApproval = "requested"`,
+		"type OrderTransitionRecord struct",
+		"Event    string        `json:\"event,omitempty\"`",
+		"func (s OrderState) TransitionToWithMeta(to OrderStage, withApproval bool, meta OrderTransitionMeta) (OrderState, OrderTransitionRecord, error)",
+		"func (s OrderState) CommitWithMeta(meta OrderTransitionMeta) (OrderState, OrderTransitionRecord, error)",
+		"record.Approval = OrderApprovalRejected",
+		"type OrderTransitionHistory struct",
+		"FromStatus OrderStatus   `gorm:\"column:from_status\" json:\"from_status\"`",
+		"func (r OrderTransitionRecord) ToHistory(entityID string) OrderTransitionHistory",
+		"From:     OrderStage{Phase: h.FromPhase, Status: h.FromStatus},",
+		"func ReplayOrder(initial OrderState, records []OrderTransitionRecord) (OrderState, error)",
+		"next, err = s.TransitionTo(r.To, true)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated output missing %q", fragment)
+		}
+	}
+	if t.Failed() {
+		t.Log(output)
+	}
+}
+
+func TestCodeGenerator_HistoryWithoutApproval(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Door", history=true)
+@Flow: Closed => [ Open on Push ]
+@Flow: Open   => [ Closed on Pull ]
+`)
+	output := generateFormatted(t, model)
+
+	for _, fragment := range []string{
+		"Event DoorEvent `json:\"event,omitempty\"`",
+		"FromPhase DoorPhase `gorm:\"column:from_phase\" json:\"from_phase\"`",
+		"FromPhase: r.From,",
+		"next, err := s.TransitionTo(r.To)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated output missing %q", fragment)
+		}
+	}
+	for _, unwanted := range []string{"OrderApproval", "DoorApproval", "CommitWithMeta", "FromStatus"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("generated output should not contain %q", unwanted)
+		}
+	}
+	if t.Failed() {
+		t.Log(output)
+	}
+}
+
+func TestCodeGenerator_NoHistory(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Door")
+@Flow: Closed => [ Open ]
+`)
+	output := generateFormatted(t, model)
+	if strings.Contains(output, "TransitionRecord") || strings.Contains(output, "Replay") {
+		t.Errorf("history API generated without history=true:\n%s", output)
+	}
+}
@@ -0,0 +1,226 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// regionType 区域子状态类型名，如 ShipmentPaymentStage
+func (c *CodeGenerator) regionType(region *Region) string {
+	return c.model.Name + utils.UpperCamelCase(region.Name) + "Stage"
+}
+
+// regionField State 上的区域字段名，如 Payment
+func (c *CodeGenerator) regionField(region *Region) string {
+	return utils.UpperCamelCase(region.Name)
+}
+
+// regionConst 区域子状态常量名，如 ShipmentPaymentStagePaid
+func (c *CodeGenerator) regionConst(region *Region, state string) string {
+	return c.regionType(region) + utils.UpperCamelCase(state)
+}
+
+// generateRegionEnums 为每个区域生成子状态枚举
+func (c *CodeGenerator) generateRegionEnums(group *gg.Group) {
+	for _, region := range c.model.Regions {
+		typeName := c.regionType(region)
+
+		group.AddLine()
+		group.Append(gg.LineComment("%s %s 区域子状态（复合状态 %s），空值表示区域未激活", typeName, region.Name, region.Composite))
+		group.Append(gg.Type(typeName, "string"))
+
+		group.AddLine()
+		constGroup := gg.Const()
+		for _, state := range region.States {
+			constGroup.AddTypedField(c.regionConst(region, state), typeName, gg.Lit(state))
+		}
+		group.Append(constGroup)
+
+		c.generateEnumAggregateVar(group, typeName, region.States)
+	}
+}
+
+// generateRegionAPI 生成区域的进入、流转与完成流转方法
+func (c *CodeGenerator) generateRegionAPI(group *gg.Group) {
+	c.generateEnterRegionsMethod(group)
+	for _, region := range c.model.Regions {
+		c.generateRegionTransitionMethod(group, region)
+		c.generateRegionValidTransitionsMethod(group, region)
+	}
+	c.generateRegionsDoneMethod(group)
+	c.generateCompleteMethod(group)
+}
+
+// generateEnterRegionsMethod 生成 enterRegions：进入复合状态时设置入口子状态，离开时清空
+func (c *CodeGenerator) generateEnterRegionsMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	var fields, zeros []string
+	for _, region := range c.model.Regions {
+		fields = append(fields, "s."+c.regionField(region))
+		zeros = append(zeros, `""`)
+	}
+
+	sw := gg.Switch("s.Current")
+	for _, phase := range c.model.CompositePhases() {
+		var body []any
+		for _, region := range c.model.RegionsOf(phase) {
+			body = append(body, gg.S("s.%s = %s", c.regionField(region), c.regionConst(region, region.Entry)))
+		}
+		sw.NewCase(gg.S(c.getStageVarName(Stage{Phase: phase}))).AddBody(body...)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("enterRegions 进入复合状态时将各区域置为入口子状态，其他状态下清空区域"))
+	group.Append(gg.Function("enterRegions").
+		WithReceiver("s", stateType).
+		AddResult("", stateType).
+		AddBody(
+			gg.S("%s = %s", strings.Join(fields, ", "), strings.Join(zeros, ", ")),
+			sw,
+			gg.S("return s"),
+		))
+}
+
+// generateRegionTransitionMethod 生成 Transition<Region>To 区域内流转方法
+func (c *CodeGenerator) generateRegionTransitionMethod(group *gg.Group, region *Region) {
+	stateType := c.model.Name + "State"
+	field := c.regionField(region)
+	methodName := "Transition" + field + "To"
+
+	sw := gg.Switch("s." + field)
+	for _, state := range region.States {
+		var targets []string
+		for _, trans := range region.Transitions {
+			if trans.From == state && !slices.Contains(targets, trans.To) {
+				targets = append(targets, trans.To)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		inner := gg.Switch("to")
+		for _, to := range targets {
+			inner.NewCase(gg.S(c.regionConst(region, to))).AddBody(
+				gg.S("s.%s = to", field),
+				gg.S("return s.complete(), nil"),
+			)
+		}
+		sw.NewCase(gg.S(c.regionConst(region, state))).AddBody(inner)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 在 %s 区域内流转，所有区域到达终态时触发 %s 的完成流转", methodName, region.Name, region.Composite))
+	group.Append(gg.Function(methodName).
+		WithReceiver("s", stateType).
+		AddParameter("to", c.regionType(region)).
+		AddResult("", stateType).
+		AddResult("", "error").
+		AddBody(
+			gg.If("s.Current != "+c.getStageVarName(Stage{Phase: region.Composite})).AddBody(
+				gg.S("return s, Err%sRegionInactive", c.model.Name),
+			),
+			sw,
+			gg.S("return s, Err%sInvalidTransition", c.model.Name),
+		))
+}
+
+// generateRegionValidTransitionsMethod 生成 Valid<Region>Transitions 方法
+func (c *CodeGenerator) generateRegionValidTransitionsMethod(group *gg.Group, region *Region) {
+	stateType := c.model.Name + "State"
+	regionType := c.regionType(region)
+	field := c.regionField(region)
+
+	sw := gg.Switch("s." + field)
+	for _, state := range region.States {
+		var targets []string
+		for _, trans := range region.Transitions {
+			if trans.From == state && !slices.Contains(targets, c.regionConst(region, trans.To)) {
+				targets = append(targets, c.regionConst(region, trans.To))
+			}
+		}
+		if len(targets) > 0 {
+			sw.NewCase(gg.S(c.regionConst(region, state))).AddBody(
+				gg.S("return []%s{%s}", regionType, strings.Join(targets, ", ")),
+			)
+		}
+	}
+
+	group.AddLine()
+	group.Append(gg.Function("Valid"+field+"Transitions").
+		WithReceiver("s", stateType).
+		AddResult("", "[]"+regionType).
+		AddBody(
+			gg.If("s.Current != "+c.getStageVarName(Stage{Phase: region.Composite})).AddBody(
+				gg.S("return nil"),
+			),
+			sw,
+			gg.S("return nil"),
+		))
+}
+
+// generateRegionsDoneMethod 生成 RegionsDone：当前复合状态的所有区域是否都已到达终态
+func (c *CodeGenerator) generateRegionsDoneMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	sw := gg.Switch("s.Current")
+	for _, phase := range c.model.CompositePhases() {
+		var conds []string
+		for _, region := range c.model.RegionsOf(phase) {
+			if len(region.Finals) == 0 {
+				conds = []string{"false"}
+				break
+			}
+			var finals []string
+			for _, state := range region.Finals {
+				finals = append(finals, fmt.Sprintf("s.%s == %s", c.regionField(region), c.regionConst(region, state)))
+			}
+			cond := strings.Join(finals, " || ")
+			if len(finals) > 1 {
+				cond = "(" + cond + ")"
+			}
+			conds = append(conds, cond)
+		}
+		sw.NewCase(gg.S(c.getStageVarName(Stage{Phase: phase}))).AddBody(
+			gg.S("return %s", strings.Join(conds, " && ")),
+		)
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("RegionsDone 当前复合状态的所有区域是否都已到达终态"))
+	group.Append(gg.Function("RegionsDone").
+		WithReceiver("s", stateType).
+		AddResult("", "bool").
+		AddBody(sw, gg.S("return false")))
+}
+
+// generateCompleteMethod 生成 complete：所有区域到达终态时执行复合状态的完成流转
+func (c *CodeGenerator) generateCompleteMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	sw := gg.Switch("s.Current")
+	for _, trans := range c.model.Completions {
+		sw.NewCase(gg.S(c.getStageVarName(trans.From))).AddBody(
+			gg.S("return %s", c.stateLit(c.getStageVarName(trans.To))),
+		)
+	}
+
+	body := []any{
+		gg.If("!s.RegionsDone()").AddBody(gg.S("return s")),
+	}
+	if len(c.model.Completions) > 0 {
+		body = append(body, sw)
+	}
+	body = append(body, gg.S("return s"))
+
+	group.AddLine()
+	group.Append(gg.LineComment("complete 所有区域到达终态时执行完成流转（done），没有完成流转时停留在复合状态"))
+	group.Append(gg.Function("complete").
+		WithReceiver("s", stateType).
+		AddResult("", stateType).
+		AddBody(body...))
+}
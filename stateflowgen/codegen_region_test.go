@@ -0,0 +1,47 @@
+package stateflowgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeGenerator_Regions(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, shipmentFlow))
+
+	for _, fragment := range []string{
+		`ShipmentPaymentStagePaid   ShipmentPaymentStage = "Paid"`,
+		"Payment  ShipmentPaymentStage  `json:\"payment,omitempty\"`",
+		"Delivery ShipmentDeliveryStage `gorm:\"column:delivery\" json:\"delivery\"`",
+		`ErrShipmentRegionInactive    = errors.New("region not active")`,
+		"return ShipmentState{Current: to}.enterRegions(), nil",
+		"s.Payment = ShipmentPaymentStageUnpaid\n\t\ts.Delivery = ShipmentDeliveryStagePacking",
+		"func (s ShipmentState) TransitionPaymentTo(to ShipmentPaymentStage) (ShipmentState, error) {",
+		"func (s ShipmentState) ValidDeliveryTransitions() []ShipmentDeliveryStage {",
+		"return s.Payment == ShipmentPaymentStagePaid && s.Delivery == ShipmentDeliveryStageDelivered",
+		"return ShipmentState{Current: StageShipmentCompleted}.enterRegions()",
+		"── <DONE> ──▶ Completed",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated code missing %q", fragment)
+		}
+	}
+}
+
+func TestFlowGraph_CompletionEdge(t *testing.T) {
+	mermaid := NewFlowGraph(buildTestModel(t, shipmentFlow)).RenderMermaid()
+	if want := `s_Processing -->|"done"| s_Completed`; !strings.Contains(mermaid, want) {
+		t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+	}
+}
+
+func TestCodeGenerator_NoRegions(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Created => [ Paid ]
+`))
+	for _, fragment := range []string{"enterRegions", "RegionsDone", "RegionInactive"} {
+		if strings.Contains(output, fragment) {
+			t.Errorf("generated code unexpectedly contains %q", fragment)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// TestCodeGenerator 生成 tests=true 的状态机测试文件
+// 测试以 @Flow 展开的流转表为预期依据，校验 TransitionTo、Commit、Reject、ValidTransitions 与 Next
+type TestCodeGenerator struct {
+	CodeGenerator
+}
+
+// NewTestCodeGenerator 创建测试代码生成器
+func NewTestCodeGenerator(model *StateModel, packageName string) *TestCodeGenerator {
+	return &TestCodeGenerator{CodeGenerator: *NewCodeGenerator(model, packageName)}
+}
+
+// Generate 生成测试代码
+func (c *TestCodeGenerator) Generate() (*gg.Generator, error) {
+	if len(c.model.Transitions) == 0 {
+		return nil, fmt.Errorf("tests=true requires at least one transition")
+	}
+
+	c.gen.P("errors")
+	c.gen.P("math/rand")
+	c.gen.P("slices")
+	c.gen.P("testing")
+
+	group := c.gen.Body()
+	c.generateTestTables(group)
+	c.generateTestHelpers(group)
+	c.generateTransitionToTest(group)
+	if c.model.HasApproval {
+		c.generateCommitRejectTest(group)
+	}
+	if c.model.HasApprovalPolicy() {
+		c.gen.P("fmt")
+		c.generateApproveTest(group)
+	}
+	c.generateValidTransitionsTest(group)
+	c.generateRandomWalkTest(group)
+	if c.model.HasTimers() {
+		c.gen.P("time")
+		c.generateTimersTest(group)
+	}
+
+	return c.gen, nil
+}
+
+// render 替换模板中的占位符并生成代码片段
+func (c *TestCodeGenerator) render(tmpl string) gg.Node {
+	return gg.S("%s", c.replace(tmpl))
+}
+
+// comment 替换占位符并生成行注释
+func (c *TestCodeGenerator) comment(text string) gg.Node {
+	return gg.LineComment("%s", c.replace(text))
+}
+
+// replace 替换模板中的类型与标识符占位符
+func (c *TestCodeGenerator) replace(tmpl string) string {
+	name := c.model.Name
+	prefix, testName := "stateFlow", "StateFlow"
+	if name != "" {
+		prefix = string(utils.EString(name).LowerCamelCase())
+		testName = name + "StateFlow"
+	}
+	// 进入复合状态时会设置区域入口子状态，预期结果需要同样处理
+	enter := ""
+	if c.model.HasRegions() {
+		enter = ".enterRegions()"
+	}
+	r := strings.NewReplacer(
+		"$State", name+"State",
+		"$Stage", name+"Stage",
+		"$Pending", name+"PendingTransition",
+		"$TimedTransition", name+"TimedTransition",
+		"$Err", "Err"+name,
+		"$test", prefix+"Test",
+		"$Test", "Test"+testName,
+		"$init", c.getStageVarName(c.model.InitStage),
+		"$enter", enter,
+	)
+	return r.Replace(tmpl)
+}
+
+// generateTestTables 生成流转表与阶段列表
+func (c *TestCodeGenerator) generateTestTables(group *gg.Group) {
+	var rows []string
+	for _, trans := range c.model.Transitions {
+		row := fmt.Sprintf("from: %s, to: %s", c.getStageVarName(trans.From), c.getStageVarName(trans.To))
+		if trans.Via.Phase != "" {
+			row += fmt.Sprintf(", via: %s, fallback: %s", c.getStageVarName(trans.Via), c.getStageVarName(trans.Fallback))
+			if trans.ApprovalRequired {
+				row += ", required: true"
+			} else {
+				row += ", optional: true"
+			}
+		}
+		rows = append(rows, "\t{"+row+"},")
+	}
+
+	fields := "from, to $Stage"
+	if c.model.HasApproval {
+		fields = "from, to, via, fallback $Stage\n\trequired, optional bool"
+	}
+
+	group.AddLine()
+	group.Append(c.comment("$test 前缀的表由 @Flow 定义展开，作为测试的预期依据"))
+	group.Append(c.render(fmt.Sprintf("var $testTransitions = []struct {\n\t%s\n}{\n%s\n}", fields, strings.Join(rows, "\n"))))
+
+	var stages []string
+	for _, stage := range c.model.GetAllStages() {
+		stages = append(stages, "\t"+c.getStageVarName(stage)+",")
+	}
+	group.AddLine()
+	group.Append(c.render(fmt.Sprintf("var $testStages = []$Stage{\n%s\n}", strings.Join(stages, "\n"))))
+
+	approvals := "false"
+	if c.model.HasOptionalApproval {
+		approvals = "false, true"
+	}
+	group.AddLine()
+	group.Append(c.render(fmt.Sprintf("var $testApprovals = []bool{%s}", approvals)))
+}
+
+// generateTestHelpers 生成调用、预期与比较辅助函数
+func (c *TestCodeGenerator) generateTestHelpers(group *gg.Group) {
+	group.AddLine()
+	if c.model.HasOptionalApproval {
+		group.Append(c.render(`func $testTransition(s $State, to $Stage, withApproval bool) ($State, error) {
+	return s.TransitionTo(to, withApproval)
+}`))
+	} else {
+		group.Append(c.render(`func $testTransition(s $State, to $Stage, _ bool) ($State, error) {
+	return s.TransitionTo(to)
+}`))
+	}
+
+	group.AddLine()
+	group.Append(c.comment("$testExpect 按流转表计算 TransitionTo 的预期结果"))
+	if c.model.HasApproval {
+		group.Append(c.render(`func $testExpect(s $State, to $Stage, withApproval bool) ($State, error) {
+	for _, tr := range $testTransitions {
+		if tr.from != s.Current || tr.to != to {
+			continue
+		}
+		if tr.required || (tr.optional && withApproval) {
+			if s.Pending != nil {
+				return s, $ErrApprovalInProgress
+			}
+			return $State{Current: tr.via, Pending: &$Pending{From: s.Current, To: to, Fallback: tr.fallback}}, nil
+		}
+		return $State{Current: to}$enter, nil
+	}
+	return s, $ErrInvalidTransition
+}`))
+
+		group.AddLine()
+		if c.model.HasApprovalPolicy() {
+			group.Append(c.render(`func $testEqual(a, b $State) bool {
+	if a.Current != b.Current || (a.Pending == nil) != (b.Pending == nil) {
+		return false
+	}
+	if a.Pending == nil {
+		return true
+	}
+	pa, pb := *a.Pending, *b.Pending
+	return pa.From == pb.From && pa.To == pb.To && pa.Fallback == pb.Fallback && slices.Equal(pa.Ledger, pb.Ledger)
+}`))
+		} else {
+			group.Append(c.render(`func $testEqual(a, b $State) bool {
+	if a.Current != b.Current || (a.Pending == nil) != (b.Pending == nil) {
+		return false
+	}
+	return a.Pending == nil || *a.Pending == *b.Pending
+}`))
+		}
+	} else {
+		group.Append(c.render(`func $testExpect(s $State, to $Stage, _ bool) ($State, error) {
+	for _, tr := range $testTransitions {
+		if tr.from == s.Current && tr.to == to {
+			return $State{Current: to}$enter, nil
+		}
+	}
+	return s, $ErrInvalidTransition
+}`))
+
+		group.AddLine()
+		if c.model.HasTimers() {
+			group.Append(c.render(`func $testEqual(a, b $State) bool {
+	// EnteredAt 取决于时钟，不参与比较
+	a.EnteredAt, b.EnteredAt = time.Time{}, time.Time{}
+	return a == b
+}`))
+		} else {
+			group.Append(c.render(`func $testEqual(a, b $State) bool {
+	return a == b
+}`))
+		}
+	}
+}
+
+// generateTransitionToTest 枚举所有 (from, to, withApproval) 组合
+func (c *TestCodeGenerator) generateTransitionToTest(group *gg.Group) {
+	group.AddLine()
+	group.Append(c.comment("$Test_TransitionTo 枚举所有 (from, to, withApproval) 组合，校验结果与错误"))
+	if !c.model.HasApproval {
+		group.Append(c.render(`func $Test_TransitionTo(t *testing.T) {
+	for _, from := range $testStages {
+		for _, to := range $testStages {
+			for _, withApproval := range $testApprovals {
+				s := $State{Current: from}
+				want, wantErr := $testExpect(s, to, withApproval)
+				got, err := $testTransition(s, to, withApproval)
+				if !errors.Is(err, wantErr) {
+					t.Errorf("%v -> %v: error = %v, want %v", from, to, err, wantErr)
+					continue
+				}
+				if !$testEqual(got, want) {
+					t.Errorf("%v -> %v: state = %+v, want %+v", from, to, got, want)
+				}
+			}
+		}
+	}
+}`))
+		return
+	}
+
+	group.Append(c.render(`func $Test_TransitionTo(t *testing.T) {
+	for _, from := range $testStages {
+		for _, pending := range []*$Pending{nil, {From: from, To: from, Fallback: from}} {
+			for _, to := range $testStages {
+				for _, withApproval := range $testApprovals {
+					s := $State{Current: from, Pending: pending}
+					want, wantErr := $testExpect(s, to, withApproval)
+					got, err := $testTransition(s, to, withApproval)
+					if !errors.Is(err, wantErr) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): error = %v, want %v", from, to, withApproval, pending != nil, err, wantErr)
+						continue
+					}
+					if !$testEqual(got, want) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): state = %+v, want %+v", from, to, withApproval, pending != nil, got, want)
+					}
+				}
+			}
+		}
+	}
+}`))
+}
+
+// generateCommitRejectTest 校验每条审批流转的 Commit / Reject 结果
+func (c *TestCodeGenerator) generateCommitRejectTest(group *gg.Group) {
+	group.AddLine()
+	group.Append(c.comment("$Test_CommitReject 校验每条审批流转的提交、拒绝结果，以及非审批状态下的 NotInApproval"))
+	group.Append(c.render(`func $Test_CommitReject(t *testing.T) {
+	for _, tr := range $testTransitions {
+		if !tr.required && !tr.optional {
+			continue
+		}
+		s, err := $testTransition($State{Current: tr.from}, tr.to, true)
+		if err != nil {
+			t.Errorf("%v -> %v: TransitionTo error = %v", tr.from, tr.to, err)
+			continue
+		}
+		if !s.IsApprovalPending() || s.Current != tr.via {
+			t.Errorf("%v -> %v: state = %+v, want pending in %v", tr.from, tr.to, s, tr.via)
+			continue
+		}
+
+		committed, err := s.Commit()
+		if err != nil || !$testEqual(committed, $State{Current: tr.to}$enter) {
+			t.Errorf("%v -> %v: Commit() = %+v, %v; want %v", tr.from, tr.to, committed, err, tr.to)
+		}
+		rejected, err := s.Reject()
+		if err != nil || !$testEqual(rejected, $State{Current: tr.fallback}$enter) {
+			t.Errorf("%v -> %v: Reject() = %+v, %v; want %v", tr.from, tr.to, rejected, err, tr.fallback)
+		}
+	}
+
+	for _, stage := range $testStages {
+		s := $State{Current: stage}
+		if got, err := s.Commit(); !errors.Is(err, $ErrNotInApproval) || !$testEqual(got, s) {
+			t.Errorf("%v: Commit() = %+v, %v; want %v", stage, got, err, $ErrNotInApproval)
+		}
+		if got, err := s.Reject(); !errors.Is(err, $ErrNotInApproval) || !$testEqual(got, s) {
+			t.Errorf("%v: Reject() = %+v, %v; want %v", stage, got, err, $ErrNotInApproval)
+		}
+	}
+}`))
+}
+
+// generateApproveTest 校验多级、多人审批按策略逐级通过，以及重复投票与否决
+func (c *TestCodeGenerator) generateApproveTest(group *gg.Group) {
+	var rows []string
+	seen := make(map[string]bool)
+	for _, trans := range c.model.Transitions {
+		key := trans.From.String() + "|" + trans.To.String()
+		if !trans.HasApprovalPolicy() || seen[key] {
+			continue
+		}
+		seen[key] = true
+		var steps []string
+		for _, step := range trans.ApprovalSteps() {
+			steps = append(steps, c.getStageVarName(step))
+		}
+		rows = append(rows, fmt.Sprintf("\t{from: %s, to: %s, fallback: %s, steps: []$Stage{%s}, quorum: %d},",
+			c.getStageVarName(trans.From), c.getStageVarName(trans.To), c.getStageVarName(trans.Fallback), strings.Join(steps, ", "), max(trans.Quorum, 1)))
+	}
+
+	group.AddLine()
+	group.Append(c.render(fmt.Sprintf("var $testPolicies = []struct {\n\tfrom, to, fallback $Stage\n\tsteps              []$Stage\n\tquorum             int\n}{\n%s\n}", strings.Join(rows, "\n"))))
+
+	group.AddLine()
+	group.Append(c.comment("$Test_Approve 校验每条多级/多人审批：每一级需要 quorum 位不同审批人，重复投票被拒绝，任一审批人可否决"))
+	group.Append(c.render(`func $Test_Approve(t *testing.T) {
+	for _, tr := range $testPolicies {
+		s, err := $testTransition($State{Current: tr.from}, tr.to, true)
+		if err != nil || s.Pending == nil {
+			t.Errorf("%v -> %v: TransitionTo = %+v, %v; want pending", tr.from, tr.to, s, err)
+			continue
+		}
+		if steps, quorum := s.Pending.Policy(); !slices.Equal(steps, tr.steps) || quorum != tr.quorum {
+			t.Errorf("%v -> %v: Policy() = %v, %d; want %v, %d", tr.from, tr.to, steps, quorum, tr.steps, tr.quorum)
+		}
+		if _, err := s.Approve(""); !errors.Is(err, $ErrApproverRequired) {
+			t.Errorf("%v -> %v: Approve(\"\") error = %v, want %v", tr.from, tr.to, err, $ErrApproverRequired)
+		}
+		if rejected, err := s.RejectBy("approver-0"); err != nil || !$testEqual(rejected, $State{Current: tr.fallback}$enter) {
+			t.Errorf("%v -> %v: RejectBy() = %+v, %v; want %v", tr.from, tr.to, rejected, err, tr.fallback)
+		}
+
+		voter := 0
+		for _, step := range tr.steps {
+			for n := 0; n < tr.quorum; n++ {
+				if s.Current != step || s.Pending == nil {
+					t.Fatalf("%v -> %v: state = %+v, want pending in %v", tr.from, tr.to, s, step)
+				}
+				voter++
+				approver := fmt.Sprintf("approver-%d", voter)
+				next, err := s.Approve(approver)
+				if err != nil {
+					t.Fatalf("%v -> %v: Approve(%s) error = %v", tr.from, tr.to, approver, err)
+				}
+				if next.Pending != nil {
+					if _, err := next.Approve(approver); !errors.Is(err, $ErrDuplicateVote) {
+						t.Errorf("%v -> %v: duplicate Approve(%s) error = %v, want %v", tr.from, tr.to, approver, err, $ErrDuplicateVote)
+					}
+					if _, err := next.RejectBy(approver); !errors.Is(err, $ErrDuplicateVote) {
+						t.Errorf("%v -> %v: RejectBy(%s) after approving error = %v, want %v", tr.from, tr.to, approver, err, $ErrDuplicateVote)
+					}
+				}
+				s = next
+			}
+		}
+		if !$testEqual(s, $State{Current: tr.to}$enter) {
+			t.Errorf("%v -> %v: state after all approvals = %+v, want %v", tr.from, tr.to, s, tr.to)
+		}
+	}
+
+	if _, err := ($State{Current: $init}).Approve("approver"); !errors.Is(err, $ErrNotInApproval) {
+		t.Errorf("Approve() without pending approval error = %v, want %v", err, $ErrNotInApproval)
+	}
+}`))
+}
+
+// generateValidTransitionsTest 校验 ValidTransitions 与 Next 和流转表一致
+func (c *TestCodeGenerator) generateValidTransitionsTest(group *gg.Group) {
+	pendingCheck := ""
+	if c.model.HasApproval {
+		pendingCheck = `
+		pending := $State{Current: stage, Pending: &$Pending{From: stage, To: stage, Fallback: stage}}
+		if next := pending.Next(); next != nil {
+			t.Errorf("%v: Next() with pending approval = %+v, want nil", stage, next)
+		}`
+	}
+
+	group.AddLine()
+	group.Append(c.comment("$Test_ValidTransitions 校验 ValidTransitions、Next 与流转表一致"))
+	group.Append(c.render(`func $Test_ValidTransitions(t *testing.T) {
+	for _, stage := range $testStages {
+		var want []$Stage
+		for _, tr := range $testTransitions {
+			if tr.from == stage && !slices.Contains(want, tr.to) {
+				want = append(want, tr.to)
+			}
+		}
+
+		s := $State{Current: stage}
+		if got := s.ValidTransitions(); !slices.Equal(got, want) {
+			t.Errorf("%v: ValidTransitions() = %v, want %v", stage, got, want)
+		}
+		next := s.Next()
+		if len(next) != len(want) {
+			t.Errorf("%v: Next() = %+v, want %v", stage, next, want)
+			continue
+		}
+		for i := range next {
+			if !$testEqual(next[i], $State{Current: want[i]}$enter) {
+				t.Errorf("%v: Next()[%d] = %+v, want %v", stage, i, next[i], want[i])
+			}
+		}` + pendingCheck + `
+	}
+}`))
+}
+
+// generateTimersTest 校验每条定时流转的 Deadline、DueTransitions 与 ApplyDue
+func (c *TestCodeGenerator) generateTimersTest(group *gg.Group) {
+	approvalTimeout := c.hasApprovalTimeout()
+
+	var rows []string
+	for _, trans := range c.timedTransitions() {
+		row := fmt.Sprintf("from: %s, to: %s, after: %s", c.getStageVarName(trans.From), c.getStageVarName(trans.To), durationExpr(trans.After))
+		if trans.Via.Phase != "" {
+			row += fmt.Sprintf(", via: %s, fallback: %s, reject: true", c.getStageVarName(trans.Via), c.getStageVarName(trans.Fallback))
+		}
+		rows = append(rows, "\t{"+row+"},")
+	}
+	fields := "from, to $Stage\n\tafter    time.Duration"
+	setup := `
+		s, want := $State{Current: tr.from, EnteredAt: start}, tr.to`
+	if approvalTimeout {
+		fields = "from, to, via, fallback $Stage\n\tafter                 time.Duration\n\treject                bool"
+		setup += `
+		if tr.reject {
+			s = $State{Current: tr.via, Pending: &$Pending{From: tr.from, To: tr.to, Fallback: tr.fallback}, EnteredAt: start}
+			want = tr.fallback
+		}`
+	}
+
+	group.AddLine()
+	group.Append(c.render(fmt.Sprintf("var $testTimers = []struct {\n\t%s\n}{\n%s\n}", fields, strings.Join(rows, "\n"))))
+
+	group.AddLine()
+	group.Append(c.comment("$Test_Timers 校验每条定时流转在到期前不触发、到期时出现在 DueTransitions 中并可由 ApplyDue 执行"))
+	group.Append(c.render(`func $Test_Timers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tr := range $testTimers {` + setup + `
+		match := func(timer $TimedTransition) bool {
+			return timer.To == want && timer.After == tr.after
+		}
+
+		if deadline, ok := s.Deadline(); !ok || deadline.After(start.Add(tr.after)) {
+			t.Errorf("%v -> %v: Deadline() = %v, %v; want no later than %v", tr.from, want, deadline, ok, start.Add(tr.after))
+		}
+		if due := s.DueTransitions(start.Add(tr.after - time.Nanosecond)); slices.ContainsFunc(due, match) {
+			t.Errorf("%v -> %v: due before %v", tr.from, want, tr.after)
+		}
+		due := s.DueTransitions(start.Add(tr.after))
+		if !slices.ContainsFunc(due, match) {
+			t.Errorf("%v -> %v: DueTransitions() = %+v, want it due after %v", tr.from, want, due, tr.after)
+			continue
+		}
+		if !match(due[0]) {
+			continue
+		}
+		next, ok, err := s.ApplyDue(start.Add(tr.after))
+		if err != nil || !ok || next.Current != want || !next.EnteredAt.Equal(start.Add(tr.after)) {
+			t.Errorf("%v -> %v: ApplyDue() = %+v, %v, %v", tr.from, want, next, ok, err)
+		}
+	}
+
+	if _, ok := ($State{Current: $init}).Deadline(); ok {
+		t.Error("Deadline() without EnteredAt should report false")
+	}
+}`))
+}
+
+// generateRandomWalkTest 随机游走，校验 ValidTransitions 与 TransitionTo 始终一致
+func (c *TestCodeGenerator) generateRandomWalkTest(group *gg.Group) {
+	approvalStep := ""
+	if c.model.HasApproval {
+		approvalStep = `
+			if s.Pending != nil {
+				want := $State{Current: s.Pending.To}$enter
+				next, err := s.Commit()
+				if r.Intn(2) == 0 {
+					want = $State{Current: s.Pending.Fallback}$enter
+					next, err = s.Reject()
+				}
+				if err != nil || !$testEqual(next, want) {
+					t.Fatalf("seed %d step %d: resolve %+v = %+v, %v; want %+v", seed, step, s, next, err, want)
+				}
+				s = next
+				continue
+			}
+`
+	}
+
+	group.AddLine()
+	group.Append(c.comment("$Test_RandomWalk 从初始状态随机游走，每一步校验 ValidTransitions 与 TransitionTo 是否成功一致"))
+	group.Append(c.render(`func $Test_RandomWalk(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		s := $State{Current: $init}
+		for step := 0; step < 100; step++ {` + approvalStep + `
+			valid := s.ValidTransitions()
+			for _, to := range $testStages {
+				_, err := $testTransition(s, to, false)
+				if (err == nil) != slices.Contains(valid, to) {
+					t.Fatalf("seed %d step %d: %v -> %v: ValidTransitions() = %v, TransitionTo error = %v", seed, step, s.Current, to, valid, err)
+				}
+			}
+			if len(valid) == 0 {
+				break
+			}
+
+			to := valid[r.Intn(len(valid))]
+			withApproval := r.Intn(2) == 0
+			want, _ := $testExpect(s, to, withApproval)
+			next, err := $testTransition(s, to, withApproval)
+			if err != nil || !$testEqual(next, want) {
+				t.Fatalf("seed %d step %d: %v -> %v = %+v, %v; want %+v", seed, step, s.Current, to, next, err, want)
+			}
+			s = next
+		}
+	}
+}`))
+}
@@ -0,0 +1,99 @@
+package stateflowgen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestTestCodeGenerator(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Order", tests=true)
+@Flow: Created => [ Paid, Cancelled? via Cancelling ]
+@Flow: Paid    => [ Shipped! via Reviewing else Created ]
+`)
+	gen, err := NewTestCodeGenerator(model, "order").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	src, err := format.Source(gen.Bytes())
+	if err != nil {
+		t.Fatalf("format.Source() error = %v\n%s", err, gen.Bytes())
+	}
+	output := string(src)
+
+	for _, fragment := range []string{
+		`"math/rand"`,
+		"{from: StageOrderCreated, to: StageOrderPaid},",
+		"{from: StageOrderCreated, to: StageOrderCancelled, via: StageOrderCancelling, fallback: StageOrderCreated, optional: true},",
+		"{from: StageOrderPaid, to: StageOrderShipped, via: StageOrderReviewing, fallback: StageOrderCreated, required: true},",
+		"var orderTestApprovals = []bool{false, true}",
+		"return s.TransitionTo(to, withApproval)",
+		"return s, ErrOrderApprovalInProgress",
+		"func TestOrderStateFlow_TransitionTo(t *testing.T)",
+		"func TestOrderStateFlow_CommitReject(t *testing.T)",
+		"!errors.Is(err, ErrOrderNotInApproval)",
+		"func TestOrderStateFlow_ValidTransitions(t *testing.T)",
+		"func TestOrderStateFlow_RandomWalk(t *testing.T)",
+		"s := OrderState{Current: StageOrderCreated}",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated output missing %q", fragment)
+		}
+	}
+	if t.Failed() {
+		t.Log(output)
+	}
+}
+
+func TestTestCodeGenerator_WithoutApproval(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow
+@Flow: Closed => [ Open ]
+@Flow: Open   => [ Closed ]
+`)
+	gen, err := NewTestCodeGenerator(model, "door").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	src, err := format.Source(gen.Bytes())
+	if err != nil {
+		t.Fatalf("format.Source() error = %v\n%s", err, gen.Bytes())
+	}
+	output := string(src)
+
+	for _, fragment := range []string{
+		"var stateFlowTestApprovals = []bool{false}",
+		"func stateFlowTestTransition(s State, to Stage, _ bool) (State, error) {",
+		"return s.TransitionTo(to)",
+		"return a == b",
+		"func TestStateFlow_RandomWalk(t *testing.T)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated output missing %q", fragment)
+		}
+	}
+	for _, unwanted := range []string{"Pending", "CommitReject"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("generated output should not contain %q", unwanted)
+		}
+	}
+	if t.Failed() {
+		t.Log(output)
+	}
+}
+
+func TestTestOutputPath(t *testing.T) {
+	tests := []struct {
+		goOutput, name, want string
+	}{
+		{"models/order_stateflow.go", "Order", "models/order_stateflow_test.go"},
+		{"models/types_state.go", "", "models/types_state_test.go"},
+		{"models/generate.go", "OrderItem", "models/order_item_stateflow_test.go"},
+	}
+	for _, tt := range tests {
+		if got := testOutputPath(tt.goOutput, tt.name); got != tt.want {
+			t.Errorf("testOutputPath(%q, %q) = %q, want %q", tt.goOutput, tt.name, got, tt.want)
+		}
+	}
+}
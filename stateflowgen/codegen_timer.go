@@ -0,0 +1,234 @@
+package stateflowgen
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/donutnomad/gg"
+)
+
+// timedTransitions 返回带 after 的流转，按等待时长升序（相同时保持定义顺序）
+func (c *CodeGenerator) timedTransitions() []Transition {
+	var timed []Transition
+	for _, trans := range c.model.Transitions {
+		if trans.After > 0 {
+			timed = append(timed, trans)
+		}
+	}
+	slices.SortStableFunc(timed, func(a, b Transition) int {
+		return cmp.Compare(a.After, b.After)
+	})
+	return timed
+}
+
+// hasApprovalTimeout 是否有审批超时（审批目标上的 after）
+func (c *CodeGenerator) hasApprovalTimeout() bool {
+	for _, trans := range c.model.Transitions {
+		if trans.After > 0 && trans.Via.Phase != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// enteredAtExpr 新状态的进入时间表达式
+func (c *CodeGenerator) enteredAtExpr() string {
+	return c.model.Name + "Clock.Now()"
+}
+
+// generateTimerAPI 生成时钟变量、定时流转类型与 Deadline/DueTransitions/ApplyDue
+// 时钟只要求 Now() time.Time，默认实现生成在输出文件中，生成代码不依赖 gogen 的包
+func (c *CodeGenerator) generateTimerAPI(group *gg.Group) {
+	c.gen.P("time")
+
+	clockVar := c.model.Name + "Clock"
+	systemType := c.model.Name + "SystemClock"
+	systemType = strings.ToLower(systemType[:1]) + systemType[1:]
+	group.AddLine()
+	group.Append(gg.LineComment("%s 记录 EnteredAt 使用的时钟，测试中可替换为 clock.NewFake", clockVar))
+	group.Append(gg.S("var %s interface{ Now() time.Time } = %s{}", clockVar, systemType))
+	group.AddLine()
+	group.Append(gg.S("type %s struct{}", systemType))
+	group.AddLine()
+	group.Append(gg.S("func (%s) Now() time.Time { return time.Now() }", systemType))
+
+	c.generateTimedTransitionType(group)
+	c.generateTimedTransitionsMethod(group)
+	c.generateDeadlineMethod(group)
+	c.generateDueTransitionsMethod(group)
+	c.generateApplyDueMethod(group)
+}
+
+// generateTimedTransitionType 生成 <Name>TimedTransition
+func (c *CodeGenerator) generateTimedTransitionType(group *gg.Group) {
+	typeName := c.model.Name + "TimedTransition"
+	stageType := c.model.Name + "Stage"
+
+	st := gg.Struct(typeName)
+	st.AddField("To", stageType+" // 目标阶段（审批超时为拒绝后的回退阶段）")
+	st.AddField("After", "time.Duration // 进入当前阶段后的等待时长")
+	if c.hasApprovalTimeout() {
+		st.AddField("Reject", "bool // 审批超时，到期后执行 Reject")
+	}
+
+	group.AddLine()
+	group.Append(gg.LineComment("%s 定时流转（after）", typeName))
+	group.Append(st)
+}
+
+// generateTimedTransitionsMethod 生成 TimedTransitions：当前状态上的所有定时流转
+func (c *CodeGenerator) generateTimedTransitionsMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	timerType := c.model.Name + "TimedTransition"
+
+	var body []any
+
+	if c.hasApprovalTimeout() {
+		sw := gg.Switch("")
+		seen := make(map[string]bool)
+		for _, trans := range c.timedTransitions() {
+			key := trans.From.String() + "|" + trans.To.String()
+			if trans.Via.Phase == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			sw.NewCase(gg.S("s.Pending.From == %s && s.Pending.To == %s", c.getStageVarName(trans.From), c.getStageVarName(trans.To))).AddBody(
+				gg.S("return []%s{{To: s.Pending.Fallback, After: %s, Reject: true}}", timerType, durationExpr(trans.After)),
+			)
+		}
+		body = append(body, gg.If("s.Pending != nil").AddBody(sw, gg.S("return nil")))
+	} else if c.model.HasApproval {
+		body = append(body, gg.If("s.Pending != nil").AddBody(gg.S("return nil")))
+	}
+
+	sw := gg.Switch("s.Current")
+	for _, stage := range c.model.GetAllStages() {
+		var items []string
+		for _, trans := range c.timedTransitions() {
+			if trans.Via.Phase == "" && trans.From.Equal(stage) {
+				items = append(items, fmt.Sprintf("{To: %s, After: %s}", c.getStageVarName(trans.To), durationExpr(trans.After)))
+			}
+		}
+		if len(items) > 0 {
+			sw.NewCase(gg.S(c.getStageVarName(stage))).AddBody(
+				gg.S("return []%s{%s}", timerType, strings.Join(items, ", ")),
+			)
+		}
+	}
+	body = append(body, sw, gg.S("return nil"))
+
+	group.AddLine()
+	group.Append(gg.LineComment("TimedTransitions 返回当前状态上的定时流转，按等待时长升序；审批中时只返回审批超时"))
+	group.Append(gg.Function("TimedTransitions").
+		WithReceiver("s", stateType).
+		AddResult("", "[]"+timerType).
+		AddBody(body...))
+}
+
+// generateDeadlineMethod 生成 Deadline：最早的定时流转到期时间
+func (c *CodeGenerator) generateDeadlineMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	group.AddLine()
+	group.Append(gg.LineComment("Deadline 返回最早的定时流转到期时间，无法计算时（无定时流转或 EnteredAt 为零）返回 false"))
+	group.Append(gg.Function("Deadline").
+		WithReceiver("s", stateType).
+		AddResult("", "time.Time").
+		AddResult("", "bool").
+		AddBody(
+			gg.S("timers := s.TimedTransitions()"),
+			gg.If("len(timers) == 0 || s.EnteredAt.IsZero()").AddBody(gg.S("return time.Time{}, false")),
+			gg.S("return s.EnteredAt.Add(timers[0].After), true"),
+		))
+}
+
+// generateDueTransitionsMethod 生成 DueTransitions：now 时已到期的定时流转
+func (c *CodeGenerator) generateDueTransitionsMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+	timerType := c.model.Name + "TimedTransition"
+
+	group.AddLine()
+	group.Append(gg.LineComment("DueTransitions 返回 now 时已到期的定时流转，按等待时长升序"))
+	group.Append(gg.Function("DueTransitions").
+		WithReceiver("s", stateType).
+		AddParameter("now", "time.Time").
+		AddResult("", "[]"+timerType).
+		AddBody(
+			gg.If("s.EnteredAt.IsZero()").AddBody(gg.S("return nil")),
+			gg.S("var due []%s", timerType),
+			gg.S("for _, timer := range s.TimedTransitions() {"),
+			gg.S("	if !now.Before(s.EnteredAt.Add(timer.After)) {"),
+			gg.S("		due = append(due, timer)"),
+			gg.S("	}"),
+			gg.S("}"),
+			gg.S("return due"),
+		))
+}
+
+// generateApplyDueMethod 生成 ApplyDue：执行最早到期的定时流转
+func (c *CodeGenerator) generateApplyDueMethod(group *gg.Group) {
+	stateType := c.model.Name + "State"
+
+	transition := "s.TransitionTo(due[0].To)"
+	if c.model.HasOptionalApproval {
+		transition = "s.TransitionTo(due[0].To, false)"
+	}
+
+	var apply []any
+	if c.hasApprovalTimeout() {
+		apply = append(apply,
+			gg.S("var next %s", stateType),
+			gg.S("var err error"),
+			gg.S("if due[0].Reject {\n\tnext, err = s.Reject()\n} else {\n\tnext, err = %s\n}", transition),
+		)
+	} else {
+		apply = append(apply, gg.S("next, err := %s", transition))
+	}
+
+	body := []any{
+		gg.S("due := s.DueTransitions(now)"),
+		gg.If("len(due) == 0").AddBody(gg.S("return s, false, nil")),
+	}
+	body = append(body, apply...)
+	body = append(body,
+		gg.If("err != nil").AddBody(gg.S("return s, false, err")),
+		gg.LineComment("以到期时间作为新状态的进入时间，调度延迟不会推迟后续定时流转"),
+		gg.S("next.EnteredAt = s.EnteredAt.Add(due[0].After)"),
+		gg.S("return next, true, nil"),
+	)
+
+	group.AddLine()
+	group.Append(gg.LineComment("ApplyDue 执行 now 时最早到期的定时流转，没有到期时返回 false"))
+	group.Append(gg.Function("ApplyDue").
+		WithReceiver("s", stateType).
+		AddParameter("now", "time.Time").
+		AddResult("", stateType).
+		AddResult("", "bool").
+		AddResult("", "error").
+		AddBody(body...))
+}
+
+// durationExpr 将时长转换为 Go 表达式，如 30 * time.Minute
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			if d == u.unit {
+				return u.name
+			}
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
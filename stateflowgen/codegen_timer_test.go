@@ -0,0 +1,76 @@
+package stateflowgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const orderTimerFlow = `
+@StateFlow(name="Order")
+@Flow: PendingPayment => [ Paid, Expired final after 30m ]
+@Flow: Paid           => [ Shipped! via Reviewing after 72h ]
+@Flow: Shipped        => [ Delivered final, Lost final after 14d ]
+`
+
+func TestCodeGenerator_Timers(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, orderTimerFlow))
+
+	for _, fragment := range []string{
+		"var OrderClock interface{ Now() time.Time } = orderSystemClock{}",
+		"func (orderSystemClock) Now() time.Time { return time.Now() }",
+		"func (s OrderState) TimedTransitions() []OrderTimedTransition {",
+		"return OrderState{Current: to, EnteredAt: OrderClock.Now()}, nil",
+		"return []OrderTimedTransition{{To: StageOrderExpired, After: 30 * time.Minute}}",
+		"return []OrderTimedTransition{{To: StageOrderLost, After: 336 * time.Hour}}",
+		"return []OrderTimedTransition{{To: s.Pending.Fallback, After: 72 * time.Hour, Reject: true}}",
+		"func (s OrderState) Deadline() (time.Time, bool) {",
+		"func (s OrderState) ApplyDue(now time.Time) (OrderState, bool, error) {",
+		"next, err = s.Reject()",
+		"next.EnteredAt = s.EnteredAt.Add(due[0].After)",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("generated code missing %q", fragment)
+		}
+	}
+	if strings.Contains(output, "github.com/donutnomad/gogen") {
+		t.Errorf("generated code should not import gogen packages")
+	}
+}
+
+func TestCodeGenerator_NoTimers(t *testing.T) {
+	output := generateFormatted(t, buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Created => [ Paid ]
+`))
+	for _, fragment := range []string{"EnteredAt", "OrderClock", "TimedTransition", "ApplyDue"} {
+		if strings.Contains(output, fragment) {
+			t.Errorf("generated code unexpectedly contains %q", fragment)
+		}
+	}
+}
+
+func TestFlowGraph_AfterLabel(t *testing.T) {
+	mermaid := NewFlowGraph(buildTestModel(t, orderTimerFlow)).RenderMermaid()
+	if want := `"after 30m"`; !strings.Contains(mermaid, want) {
+		t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+	}
+}
+
+func TestDurationExpr(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Hour, "time.Hour"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{72 * time.Hour, "72 * time.Hour"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond"},
+		{time.Duration(1500), "time.Duration(1500)"},
+	}
+	for _, tt := range tests {
+		if got := durationExpr(tt.d); got != tt.want {
+			t.Errorf("durationExpr(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,9 @@ import (
 type CodeGeneratorV2 struct {
 	model *StateFlowV2Model
 	gen   *gg.Generator
+
+	// ASCIIDiagram 为 true 时在文件顶部生成 /* Flowchart: */ 注释
+	ASCIIDiagram bool
 }
 
 func NewCodeGeneratorV2(model *StateFlowV2Model, packageName string) *CodeGeneratorV2 {
@@ -25,6 +28,12 @@ func NewCodeGeneratorV2(model *StateFlowV2Model, packageName string) *CodeGenera
 func (c *CodeGeneratorV2) Generate() (*gg.Generator, error) {
 	group := c.gen.Body()
 
+	if c.ASCIIDiagram && len(c.model.Transitions) > 0 {
+		if comment := newFlowRenderer(c.model.stageTransitions()).RenderAsComment(); comment != "" {
+			group.Append(gg.S(comment))
+		}
+	}
+
 	c.generateV2StateType(group)
 	c.generateV2TransitionType(group)
 	c.generateV2StateColumnsType(group)
@@ -0,0 +1,177 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// SQLDialect 数据库约束的 SQL 方言
+type SQLDialect string
+
+const (
+	SQLMySQL    SQLDialect = "mysql"
+	SQLPostgres SQLDialect = "postgres"
+)
+
+// ParseSQLDialect 解析 sql 参数，空字符串表示不生成数据库约束
+func ParseSQLDialect(s string) (SQLDialect, error) {
+	switch d := SQLDialect(strings.ToLower(strings.TrimSpace(s))); d {
+	case "", SQLMySQL, SQLPostgres:
+		return d, nil
+	case "postgresql", "pg":
+		return SQLPostgres, nil
+	}
+	return "", fmt.Errorf("unsupported sql dialect %q (want mysql or postgres)", s)
+}
+
+// quote 按方言转义标识符
+func (d SQLDialect) quote(ident string) string {
+	if d == SQLMySQL {
+		return "`" + ident + "`"
+	}
+	return `"` + ident + `"`
+}
+
+// StoredTransitions 返回持久化层允许出现的阶段变化（from -> to，不含自我流转），按定义顺序去重
+// 审批流转展开为进入中间态、逐级推进、提交与拒绝回退，可选审批额外包含直接流转
+func (m *StateModel) StoredTransitions() [][2]Stage {
+	var result [][2]Stage
+	seen := make(map[[2]Stage]bool)
+	add := func(from, to Stage) {
+		key := [2]Stage{from, to}
+		if from.Equal(to) || seen[key] {
+			return
+		}
+		seen[key] = true
+		result = append(result, key)
+	}
+
+	for _, trans := range m.flowTransitions() {
+		steps := trans.ApprovalSteps()
+		if len(steps) == 0 || trans.ApprovalOptional {
+			add(trans.From, trans.To)
+		}
+		if len(steps) == 0 {
+			continue
+		}
+		add(trans.From, steps[0])
+		for i, step := range steps {
+			if i+1 < len(steps) {
+				add(step, steps[i+1])
+			} else {
+				add(step, trans.To)
+			}
+			add(step, trans.Fallback)
+		}
+	}
+	return result
+}
+
+// sqlTable 约束作用的表名：table 参数，默认为 name 的 snake_case
+func (m *StateModel) sqlTable() string {
+	if m.Table != "" {
+		return m.Table
+	}
+	if m.Name == "" {
+		return "stateflow"
+	}
+	return utils.ToSnakeCase(m.Name)
+}
+
+// sqlTransitionTable 允许流转表的表名
+func (m *StateModel) sqlTransitionTable() string {
+	if m.Name == "" {
+		return "stateflow_transitions"
+	}
+	return utils.ToSnakeCase(m.Name) + "_transitions"
+}
+
+// RenderSQL 生成状态列的 CHECK 约束、允许流转表及其种子数据
+func (m *StateModel) RenderSQL() string {
+	d := m.SQL
+	if d == "" {
+		d = SQLMySQL
+	}
+	table := m.sqlTable()
+	transTable := m.sqlTransitionTable()
+
+	var sb strings.Builder
+	sb.WriteString("-- Code generated by gogen. DO NOT EDIT.\n")
+	fmt.Fprintf(&sb, "-- %s 状态列约束与允许流转表（%s）\n\n", m.Name, d)
+
+	// CHECK 约束：phase/status 只能是定义过的阶段，区域列只能是该区域的子状态或空值
+	var conds []string
+	if m.HasStatus {
+		var plain []string
+		for _, phase := range m.Phases {
+			if statuses := m.PhaseStatus[phase]; len(statuses) > 0 {
+				conds = append(conds, fmt.Sprintf("(%s = %s AND %s IN (%s))",
+					d.quote("phase"), sqlString(phase), d.quote("status"), sqlStrings(statuses)))
+			} else {
+				plain = append(plain, phase)
+			}
+		}
+		if len(plain) > 0 {
+			conds = append(conds, fmt.Sprintf("(%s IN (%s) AND %s = '')", d.quote("phase"), sqlStrings(plain), d.quote("status")))
+		}
+	} else {
+		conds = append(conds, fmt.Sprintf("%s IN (%s)", d.quote("phase"), sqlStrings(m.Phases)))
+	}
+	sb.WriteString("-- 阶段取值约束\n")
+	fmt.Fprintf(&sb, "ALTER TABLE %s ADD CONSTRAINT %s CHECK (\n  %s\n);\n",
+		d.quote(table), d.quote("chk_"+table+"_stage"), strings.Join(conds, "\n  OR "))
+	for _, region := range m.Regions {
+		column := utils.ToSnakeCase(region.Name)
+		fmt.Fprintf(&sb, "ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s IN (%s));\n",
+			d.quote(table), d.quote("chk_"+table+"_"+column), d.quote(column), sqlStrings(append([]string{""}, region.States...)))
+	}
+
+	// 允许流转表
+	columns := []string{"from_phase", "to_phase"}
+	if m.HasStatus {
+		columns = []string{"from_phase", "from_status", "to_phase", "to_status"}
+	}
+	var quoted, defs []string
+	for _, column := range columns {
+		quoted = append(quoted, d.quote(column))
+		defs = append(defs, fmt.Sprintf("  %s VARCHAR(64) NOT NULL DEFAULT ''", d.quote(column)))
+	}
+	sb.WriteString("\n-- 允许的流转（含审批中间态的进入、提交与回退）\n")
+	fmt.Fprintf(&sb, "CREATE TABLE IF NOT EXISTS %s (\n%s,\n  PRIMARY KEY (%s)\n);\n",
+		d.quote(transTable), strings.Join(defs, ",\n"), strings.Join(quoted, ", "))
+
+	var rows []string
+	for _, pair := range m.StoredTransitions() {
+		values := []string{sqlString(pair[0].Phase), sqlString(pair[1].Phase)}
+		if m.HasStatus {
+			values = []string{sqlString(pair[0].Phase), sqlString(pair[0].Status), sqlString(pair[1].Phase), sqlString(pair[1].Status)}
+		}
+		rows = append(rows, "  ("+strings.Join(values, ", ")+")")
+	}
+	if len(rows) == 0 {
+		return sb.String()
+	}
+	insert, suffix := "INSERT INTO", "\nON CONFLICT DO NOTHING"
+	if d == SQLMySQL {
+		insert, suffix = "INSERT IGNORE INTO", ""
+	}
+	fmt.Fprintf(&sb, "\n%s %s (%s) VALUES\n%s%s;\n",
+		insert, d.quote(transTable), strings.Join(quoted, ", "), strings.Join(rows, ",\n"), suffix)
+	return sb.String()
+}
+
+// sqlString 单引号字符串字面量
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlStrings 逗号分隔的字符串字面量列表
+func sqlStrings(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = sqlString(s)
+	}
+	return strings.Join(quoted, ", ")
+}
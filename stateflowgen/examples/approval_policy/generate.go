@@ -0,0 +1,290 @@
+// Code generated by gogen. DO NOT EDIT.
+package approval_policy
+
+import (
+	"errors"
+	"slices"
+
+	"gorm.io/datatypes"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                                                           ┌── <COMMIT> ──▶ Approved ──▶ Paid
+                                                           │
+                      ┌──▶ FinanceReview (via, quorum 2) ──┤
+                      │                                    │
+                      │                                    └── <REJECT> ──▶ Submitted 🔁
+                      │
+                      │
+                      │
+Draft ──▶ Submitted ──┤
+                      │                                                                   ┌── <COMMIT> ──▶ Escalated ──▶ Paid
+                      │                                                                   │
+                      │                          ┌── <APPROVE> ──▶ DirectorReview (via) ──┤
+                      │                          │                                        │
+                      │                          │                                        └── <REJECT> ──▶ Draft 🔁
+                      └──▶ ManagerReview (via) ──┤
+                                                 │
+                                                 │
+                                                 └── <REJECT> ──▶ Draft 🔁
+*/
+
+// PaymentPhase 阶段枚举
+type PaymentPhase string
+
+const (
+	PaymentPhaseDraft          PaymentPhase = "Draft"
+	PaymentPhaseSubmitted      PaymentPhase = "Submitted"
+	PaymentPhaseApproved       PaymentPhase = "Approved"
+	PaymentPhaseFinanceReview  PaymentPhase = "FinanceReview"
+	PaymentPhaseEscalated      PaymentPhase = "Escalated"
+	PaymentPhaseManagerReview  PaymentPhase = "ManagerReview"
+	PaymentPhaseDirectorReview PaymentPhase = "DirectorReview"
+	PaymentPhasePaid           PaymentPhase = "Paid"
+)
+
+var PaymentPhaseEnums = struct {
+	Draft          PaymentPhase
+	Submitted      PaymentPhase
+	Approved       PaymentPhase
+	FinanceReview  PaymentPhase
+	Escalated      PaymentPhase
+	ManagerReview  PaymentPhase
+	DirectorReview PaymentPhase
+	Paid           PaymentPhase
+}{
+	Draft:          PaymentPhaseDraft,
+	Submitted:      PaymentPhaseSubmitted,
+	Approved:       PaymentPhaseApproved,
+	FinanceReview:  PaymentPhaseFinanceReview,
+	Escalated:      PaymentPhaseEscalated,
+	ManagerReview:  PaymentPhaseManagerReview,
+	DirectorReview: PaymentPhaseDirectorReview,
+	Paid:           PaymentPhasePaid,
+}
+
+// PaymentStage 阶段（Phase + Status）
+type PaymentStage = PaymentPhase
+
+// 预定义阶段
+var (
+	StagePaymentDraft          = PaymentPhaseDraft
+	StagePaymentSubmitted      = PaymentPhaseSubmitted
+	StagePaymentApproved       = PaymentPhaseApproved
+	StagePaymentFinanceReview  = PaymentPhaseFinanceReview
+	StagePaymentEscalated      = PaymentPhaseEscalated
+	StagePaymentManagerReview  = PaymentPhaseManagerReview
+	StagePaymentDirectorReview = PaymentPhaseDirectorReview
+	StagePaymentPaid           = PaymentPhasePaid
+)
+
+// PaymentDecision 审批人的决定
+type PaymentDecision string
+
+const (
+	PaymentDecisionApproved PaymentDecision = "approved"
+	PaymentDecisionRejected PaymentDecision = "rejected"
+)
+
+// PaymentApprovalVote 审批账本中的一票
+type PaymentApprovalVote struct {
+	Approver string          `json:"approver"`
+	Decision PaymentDecision `json:"decision"`
+	Stage    PaymentStage    `json:"stage"` // 投票时所在的审批阶段
+}
+
+// PaymentPendingTransition 审批事务
+type PaymentPendingTransition struct {
+	From     PaymentStage          `json:"from"`
+	To       PaymentStage          `json:"to"`
+	Fallback PaymentStage          `json:"fallback"`
+	Ledger   []PaymentApprovalVote `json:"ledger,omitempty"` // 审批账本，按投票顺序
+}
+
+// PaymentState 完整状态
+type PaymentState struct {
+	Current PaymentStage              `json:"current"`
+	Pending *PaymentPendingTransition `json:"pending,omitempty"`
+}
+
+// PaymentStateColumns 数据库存储结构
+type PaymentStateColumns struct {
+	Phase   PaymentPhase                                  `gorm:"column:phase" json:"phase"`
+	Pending datatypes.JSONType[*PaymentPendingTransition] `gorm:"column:pending" json:"pending"`
+}
+
+func (s PaymentState) ToColumns() PaymentStateColumns {
+	return PaymentStateColumns{
+		Phase:   s.Current,
+		Pending: datatypes.NewJSONType(s.Pending),
+	}
+}
+
+func (c PaymentStateColumns) ToState() PaymentState {
+	return PaymentState{
+		Current: c.Phase,
+		Pending: c.Pending.Data(),
+	}
+}
+
+// 错误定义
+var (
+	ErrPaymentInvalidTransition  = errors.New("invalid transition")
+	ErrPaymentApprovalInProgress = errors.New("approval in progress")
+	ErrPaymentNotInApproval      = errors.New("not in approval")
+	ErrPaymentApproverRequired   = errors.New("approver required")
+	ErrPaymentDuplicateVote      = errors.New("duplicate vote")
+)
+
+func (s PaymentState) TransitionTo(to PaymentStage) (PaymentState, error) {
+	switch s.Current {
+	case StagePaymentDraft:
+		switch to {
+		case StagePaymentSubmitted:
+			return PaymentState{Current: to}, nil
+		}
+	case StagePaymentSubmitted:
+		switch to {
+		case StagePaymentApproved:
+			if s.Pending != nil {
+				return s, ErrPaymentApprovalInProgress
+			}
+			return PaymentState{Current: StagePaymentFinanceReview, Pending: &PaymentPendingTransition{From: s.Current, To: to, Fallback: StagePaymentSubmitted}}, nil
+		case StagePaymentEscalated:
+			if s.Pending != nil {
+				return s, ErrPaymentApprovalInProgress
+			}
+			return PaymentState{Current: StagePaymentManagerReview, Pending: &PaymentPendingTransition{From: s.Current, To: to, Fallback: StagePaymentDraft}}, nil
+		}
+	case StagePaymentApproved:
+		switch to {
+		case StagePaymentPaid:
+			return PaymentState{Current: to}, nil
+		}
+	case StagePaymentEscalated:
+		switch to {
+		case StagePaymentPaid:
+			return PaymentState{Current: to}, nil
+		}
+	}
+	return s, ErrPaymentInvalidTransition
+}
+
+func (s PaymentState) Commit() (PaymentState, error) {
+	if s.Pending == nil {
+		return s, ErrPaymentNotInApproval
+	}
+	return PaymentState{Current: s.Pending.To}, nil
+}
+
+func (s PaymentState) Reject() (PaymentState, error) {
+	if s.Pending == nil {
+		return s, ErrPaymentNotInApproval
+	}
+	return PaymentState{Current: s.Pending.Fallback}, nil
+}
+
+func (s PaymentState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+// Policy 返回审批策略：依次经过的审批阶段，以及每一级需要的不同审批人数
+func (p PaymentPendingTransition) Policy() (steps []PaymentStage, quorum int) {
+	switch {
+	case p.From == StagePaymentSubmitted && p.To == StagePaymentApproved:
+		return []PaymentStage{StagePaymentFinanceReview}, 2
+	case p.From == StagePaymentSubmitted && p.To == StagePaymentEscalated:
+		return []PaymentStage{StagePaymentManagerReview, StagePaymentDirectorReview}, 1
+	}
+	return nil, 1
+}
+
+// Approvals 返回指定审批阶段已获得的赞成票数
+func (p PaymentPendingTransition) Approvals(stage PaymentStage) int {
+	n := 0
+	for _, vote := range p.Ledger {
+		if vote.Stage == stage && vote.Decision == PaymentDecisionApproved {
+			n++
+		}
+	}
+	return n
+}
+
+// HasVoted 审批人是否已在本次审批中投过票（任一级）
+func (p PaymentPendingTransition) HasVoted(approver string) bool {
+	for _, vote := range p.Ledger {
+		if vote.Approver == approver {
+			return true
+		}
+	}
+	return false
+}
+
+func (s PaymentState) checkVote(approver string) error {
+	if s.Pending == nil {
+		return ErrPaymentNotInApproval
+	}
+	if approver == "" {
+		return ErrPaymentApproverRequired
+	}
+	if s.Pending.HasVoted(approver) {
+		return ErrPaymentDuplicateVote
+	}
+	return nil
+}
+
+// Approve 以 approver 的身份投赞成票，本级票数达到 quorum 后进入下一级，最后一级满足时提交
+// 同一审批人在一次审批中只能投一票，重复投票返回 ErrPaymentDuplicateVote
+func (s PaymentState) Approve(approver string) (PaymentState, error) {
+	if err := s.checkVote(approver); err != nil {
+		return s, err
+	}
+	pending := *s.Pending
+	pending.Ledger = append(slices.Clip(pending.Ledger), PaymentApprovalVote{Approver: approver, Decision: PaymentDecisionApproved, Stage: s.Current})
+	next := s
+	next.Pending = &pending
+	steps, quorum := pending.Policy()
+	if pending.Approvals(s.Current) < quorum {
+		return next, nil
+	}
+	if i := slices.Index(steps, s.Current); i >= 0 && i+1 < len(steps) {
+		next.Current = steps[i+1]
+		return next, nil
+	}
+	return s.Commit()
+}
+
+// RejectBy 以 approver 的身份否决审批，任一级的任一审批人否决即回退
+func (s PaymentState) RejectBy(approver string) (PaymentState, error) {
+	if err := s.checkVote(approver); err != nil {
+		return s, err
+	}
+	return s.Reject()
+}
+
+func (s PaymentState) ValidTransitions() []PaymentStage {
+	switch s.Current {
+	case StagePaymentDraft:
+		return []PaymentStage{StagePaymentSubmitted}
+	case StagePaymentSubmitted:
+		return []PaymentStage{StagePaymentApproved, StagePaymentEscalated}
+	case StagePaymentApproved:
+		return []PaymentStage{StagePaymentPaid}
+	case StagePaymentEscalated:
+		return []PaymentStage{StagePaymentPaid}
+	}
+	return nil
+}
+
+func (s PaymentState) Next() []PaymentState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []PaymentState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, PaymentState{Current: stage})
+	}
+	return result
+}
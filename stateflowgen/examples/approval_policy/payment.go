@@ -0,0 +1,11 @@
+package approval_policy
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 多级与多人审批：quorum N 需要 N 位不同审批人通过；via [A, B] 按顺序逐级审批
+// @StateFlow(name="Payment", tests=true)
+// @Flow: Draft     => [ Submitted ]
+// @Flow: Submitted => [ Approved! via FinanceReview quorum 2, Escalated! via [ManagerReview, DirectorReview] else Draft ]
+// @Flow: Approved  => [ Paid final ]
+// @Flow: Escalated => [ Paid final ]
+const _ = ""
@@ -0,0 +1,265 @@
+// Code generated by gogen. DO NOT EDIT.
+package approval_policy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// ================ stateflow ================
+
+// paymentTest 前缀的表由 @Flow 定义展开，作为测试的预期依据
+var paymentTestTransitions = []struct {
+	from, to, via, fallback PaymentStage
+	required, optional      bool
+}{
+	{from: StagePaymentDraft, to: StagePaymentSubmitted},
+	{from: StagePaymentSubmitted, to: StagePaymentApproved, via: StagePaymentFinanceReview, fallback: StagePaymentSubmitted, required: true},
+	{from: StagePaymentSubmitted, to: StagePaymentEscalated, via: StagePaymentManagerReview, fallback: StagePaymentDraft, required: true},
+	{from: StagePaymentApproved, to: StagePaymentPaid},
+	{from: StagePaymentEscalated, to: StagePaymentPaid},
+}
+
+var paymentTestStages = []PaymentStage{
+	StagePaymentDraft,
+	StagePaymentSubmitted,
+	StagePaymentApproved,
+	StagePaymentFinanceReview,
+	StagePaymentEscalated,
+	StagePaymentManagerReview,
+	StagePaymentDirectorReview,
+	StagePaymentPaid,
+}
+
+var paymentTestApprovals = []bool{false}
+
+func paymentTestTransition(s PaymentState, to PaymentStage, _ bool) (PaymentState, error) {
+	return s.TransitionTo(to)
+}
+
+// paymentTestExpect 按流转表计算 TransitionTo 的预期结果
+func paymentTestExpect(s PaymentState, to PaymentStage, withApproval bool) (PaymentState, error) {
+	for _, tr := range paymentTestTransitions {
+		if tr.from != s.Current || tr.to != to {
+			continue
+		}
+		if tr.required || (tr.optional && withApproval) {
+			if s.Pending != nil {
+				return s, ErrPaymentApprovalInProgress
+			}
+			return PaymentState{Current: tr.via, Pending: &PaymentPendingTransition{From: s.Current, To: to, Fallback: tr.fallback}}, nil
+		}
+		return PaymentState{Current: to}, nil
+	}
+	return s, ErrPaymentInvalidTransition
+}
+
+func paymentTestEqual(a, b PaymentState) bool {
+	if a.Current != b.Current || (a.Pending == nil) != (b.Pending == nil) {
+		return false
+	}
+	if a.Pending == nil {
+		return true
+	}
+	pa, pb := *a.Pending, *b.Pending
+	return pa.From == pb.From && pa.To == pb.To && pa.Fallback == pb.Fallback && slices.Equal(pa.Ledger, pb.Ledger)
+}
+
+// TestPaymentStateFlow_TransitionTo 枚举所有 (from, to, withApproval) 组合，校验结果与错误
+func TestPaymentStateFlow_TransitionTo(t *testing.T) {
+	for _, from := range paymentTestStages {
+		for _, pending := range []*PaymentPendingTransition{nil, {From: from, To: from, Fallback: from}} {
+			for _, to := range paymentTestStages {
+				for _, withApproval := range paymentTestApprovals {
+					s := PaymentState{Current: from, Pending: pending}
+					want, wantErr := paymentTestExpect(s, to, withApproval)
+					got, err := paymentTestTransition(s, to, withApproval)
+					if !errors.Is(err, wantErr) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): error = %v, want %v", from, to, withApproval, pending != nil, err, wantErr)
+						continue
+					}
+					if !paymentTestEqual(got, want) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): state = %+v, want %+v", from, to, withApproval, pending != nil, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestPaymentStateFlow_CommitReject 校验每条审批流转的提交、拒绝结果，以及非审批状态下的
+// NotInApproval
+func TestPaymentStateFlow_CommitReject(t *testing.T) {
+	for _, tr := range paymentTestTransitions {
+		if !tr.required && !tr.optional {
+			continue
+		}
+		s, err := paymentTestTransition(PaymentState{Current: tr.from}, tr.to, true)
+		if err != nil {
+			t.Errorf("%v -> %v: TransitionTo error = %v", tr.from, tr.to, err)
+			continue
+		}
+		if !s.IsApprovalPending() || s.Current != tr.via {
+			t.Errorf("%v -> %v: state = %+v, want pending in %v", tr.from, tr.to, s, tr.via)
+			continue
+		}
+
+		committed, err := s.Commit()
+		if err != nil || !paymentTestEqual(committed, PaymentState{Current: tr.to}) {
+			t.Errorf("%v -> %v: Commit() = %+v, %v; want %v", tr.from, tr.to, committed, err, tr.to)
+		}
+		rejected, err := s.Reject()
+		if err != nil || !paymentTestEqual(rejected, PaymentState{Current: tr.fallback}) {
+			t.Errorf("%v -> %v: Reject() = %+v, %v; want %v", tr.from, tr.to, rejected, err, tr.fallback)
+		}
+	}
+
+	for _, stage := range paymentTestStages {
+		s := PaymentState{Current: stage}
+		if got, err := s.Commit(); !errors.Is(err, ErrPaymentNotInApproval) || !paymentTestEqual(got, s) {
+			t.Errorf("%v: Commit() = %+v, %v; want %v", stage, got, err, ErrPaymentNotInApproval)
+		}
+		if got, err := s.Reject(); !errors.Is(err, ErrPaymentNotInApproval) || !paymentTestEqual(got, s) {
+			t.Errorf("%v: Reject() = %+v, %v; want %v", stage, got, err, ErrPaymentNotInApproval)
+		}
+	}
+}
+
+var paymentTestPolicies = []struct {
+	from, to, fallback PaymentStage
+	steps              []PaymentStage
+	quorum             int
+}{
+	{from: StagePaymentSubmitted, to: StagePaymentApproved, fallback: StagePaymentSubmitted, steps: []PaymentStage{StagePaymentFinanceReview}, quorum: 2},
+	{from: StagePaymentSubmitted, to: StagePaymentEscalated, fallback: StagePaymentDraft, steps: []PaymentStage{StagePaymentManagerReview, StagePaymentDirectorReview}, quorum: 1},
+}
+
+// TestPaymentStateFlow_Approve 校验每条多级/多人审批：每一级需要 quorum
+// 位不同审批人，重复投票被拒绝，任一审批人可否决
+func TestPaymentStateFlow_Approve(t *testing.T) {
+	for _, tr := range paymentTestPolicies {
+		s, err := paymentTestTransition(PaymentState{Current: tr.from}, tr.to, true)
+		if err != nil || s.Pending == nil {
+			t.Errorf("%v -> %v: TransitionTo = %+v, %v; want pending", tr.from, tr.to, s, err)
+			continue
+		}
+		if steps, quorum := s.Pending.Policy(); !slices.Equal(steps, tr.steps) || quorum != tr.quorum {
+			t.Errorf("%v -> %v: Policy() = %v, %d; want %v, %d", tr.from, tr.to, steps, quorum, tr.steps, tr.quorum)
+		}
+		if _, err := s.Approve(""); !errors.Is(err, ErrPaymentApproverRequired) {
+			t.Errorf("%v -> %v: Approve(\"\") error = %v, want %v", tr.from, tr.to, err, ErrPaymentApproverRequired)
+		}
+		if rejected, err := s.RejectBy("approver-0"); err != nil || !paymentTestEqual(rejected, PaymentState{Current: tr.fallback}) {
+			t.Errorf("%v -> %v: RejectBy() = %+v, %v; want %v", tr.from, tr.to, rejected, err, tr.fallback)
+		}
+
+		voter := 0
+		for _, step := range tr.steps {
+			for n := 0; n < tr.quorum; n++ {
+				if s.Current != step || s.Pending == nil {
+					t.Fatalf("%v -> %v: state = %+v, want pending in %v", tr.from, tr.to, s, step)
+				}
+				voter++
+				approver := fmt.Sprintf("approver-%d", voter)
+				next, err := s.Approve(approver)
+				if err != nil {
+					t.Fatalf("%v -> %v: Approve(%s) error = %v", tr.from, tr.to, approver, err)
+				}
+				if next.Pending != nil {
+					if _, err := next.Approve(approver); !errors.Is(err, ErrPaymentDuplicateVote) {
+						t.Errorf("%v -> %v: duplicate Approve(%s) error = %v, want %v", tr.from, tr.to, approver, err, ErrPaymentDuplicateVote)
+					}
+					if _, err := next.RejectBy(approver); !errors.Is(err, ErrPaymentDuplicateVote) {
+						t.Errorf("%v -> %v: RejectBy(%s) after approving error = %v, want %v", tr.from, tr.to, approver, err, ErrPaymentDuplicateVote)
+					}
+				}
+				s = next
+			}
+		}
+		if !paymentTestEqual(s, PaymentState{Current: tr.to}) {
+			t.Errorf("%v -> %v: state after all approvals = %+v, want %v", tr.from, tr.to, s, tr.to)
+		}
+	}
+
+	if _, err := (PaymentState{Current: StagePaymentDraft}).Approve("approver"); !errors.Is(err, ErrPaymentNotInApproval) {
+		t.Errorf("Approve() without pending approval error = %v, want %v", err, ErrPaymentNotInApproval)
+	}
+}
+
+// TestPaymentStateFlow_ValidTransitions 校验 ValidTransitions、Next 与流转表一致
+func TestPaymentStateFlow_ValidTransitions(t *testing.T) {
+	for _, stage := range paymentTestStages {
+		var want []PaymentStage
+		for _, tr := range paymentTestTransitions {
+			if tr.from == stage && !slices.Contains(want, tr.to) {
+				want = append(want, tr.to)
+			}
+		}
+
+		s := PaymentState{Current: stage}
+		if got := s.ValidTransitions(); !slices.Equal(got, want) {
+			t.Errorf("%v: ValidTransitions() = %v, want %v", stage, got, want)
+		}
+		next := s.Next()
+		if len(next) != len(want) {
+			t.Errorf("%v: Next() = %+v, want %v", stage, next, want)
+			continue
+		}
+		for i := range next {
+			if !paymentTestEqual(next[i], PaymentState{Current: want[i]}) {
+				t.Errorf("%v: Next()[%d] = %+v, want %v", stage, i, next[i], want[i])
+			}
+		}
+		pending := PaymentState{Current: stage, Pending: &PaymentPendingTransition{From: stage, To: stage, Fallback: stage}}
+		if next := pending.Next(); next != nil {
+			t.Errorf("%v: Next() with pending approval = %+v, want nil", stage, next)
+		}
+	}
+}
+
+// TestPaymentStateFlow_RandomWalk 从初始状态随机游走，每一步校验 ValidTransitions
+// 与 TransitionTo 是否成功一致
+func TestPaymentStateFlow_RandomWalk(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		s := PaymentState{Current: StagePaymentDraft}
+		for step := 0; step < 100; step++ {
+			if s.Pending != nil {
+				want := PaymentState{Current: s.Pending.To}
+				next, err := s.Commit()
+				if r.Intn(2) == 0 {
+					want = PaymentState{Current: s.Pending.Fallback}
+					next, err = s.Reject()
+				}
+				if err != nil || !paymentTestEqual(next, want) {
+					t.Fatalf("seed %d step %d: resolve %+v = %+v, %v; want %+v", seed, step, s, next, err, want)
+				}
+				s = next
+				continue
+			}
+
+			valid := s.ValidTransitions()
+			for _, to := range paymentTestStages {
+				_, err := paymentTestTransition(s, to, false)
+				if (err == nil) != slices.Contains(valid, to) {
+					t.Fatalf("seed %d step %d: %v -> %v: ValidTransitions() = %v, TransitionTo error = %v", seed, step, s.Current, to, valid, err)
+				}
+			}
+			if len(valid) == 0 {
+				break
+			}
+
+			to := valid[r.Intn(len(valid))]
+			withApproval := r.Intn(2) == 0
+			want, _ := paymentTestExpect(s, to, withApproval)
+			next, err := paymentTestTransition(s, to, withApproval)
+			if err != nil || !paymentTestEqual(next, want) {
+				t.Fatalf("seed %d step %d: %v -> %v = %+v, %v; want %+v", seed, step, s.Current, to, next, err, want)
+			}
+			s = next
+		}
+	}
+}
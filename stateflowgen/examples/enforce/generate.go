@@ -0,0 +1,243 @@
+// Code generated by gogen. DO NOT EDIT.
+package enforce
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                                                         ┌── <COMMIT> ──▶ Shipped ──▶ Delivered
+                                                         │
+                   ┌──▶ Created(Paid) ──▶ Review (via) ──┤
+                   │                                     │
+                   │                                     └── <REJECT> ──▶ Created(Pending) 🔁
+Created(Pending) ──┤
+                   │
+                   │
+                   └──▶ Cancelled
+*/
+
+// OrderPhase 阶段枚举
+type OrderPhase string
+
+const (
+	OrderPhaseCreated   OrderPhase = "Created"
+	OrderPhaseCancelled OrderPhase = "Cancelled"
+	OrderPhaseShipped   OrderPhase = "Shipped"
+	OrderPhaseReview    OrderPhase = "Review"
+	OrderPhaseDelivered OrderPhase = "Delivered"
+)
+
+var OrderPhaseEnums = struct {
+	Created   OrderPhase
+	Cancelled OrderPhase
+	Shipped   OrderPhase
+	Review    OrderPhase
+	Delivered OrderPhase
+}{
+	Created:   OrderPhaseCreated,
+	Cancelled: OrderPhaseCancelled,
+	Shipped:   OrderPhaseShipped,
+	Review:    OrderPhaseReview,
+	Delivered: OrderPhaseDelivered,
+}
+
+// OrderStatus 状态枚举
+type OrderStatus string
+
+const (
+	OrderStatusNone    OrderStatus = ""
+	OrderStatusPaid    OrderStatus = "Paid"
+	OrderStatusPending OrderStatus = "Pending"
+)
+
+var OrderStatusEnums = struct {
+	None    OrderStatus
+	Paid    OrderStatus
+	Pending OrderStatus
+}{
+	None:    OrderStatusNone,
+	Paid:    OrderStatusPaid,
+	Pending: OrderStatusPending,
+}
+
+// OrderStage 阶段（Phase + Status）
+type OrderStage struct {
+	Phase  OrderPhase  `json:"phase"`
+	Status OrderStatus `json:"status"`
+}
+
+// 预定义阶段
+var (
+	StageOrderCreatedPaid    = OrderStage{OrderPhaseCreated, OrderStatusPaid}
+	StageOrderCreatedPending = OrderStage{OrderPhaseCreated, OrderStatusPending}
+	StageOrderCancelled      = OrderStage{OrderPhaseCancelled, OrderStatusNone}
+	StageOrderShipped        = OrderStage{OrderPhaseShipped, OrderStatusNone}
+	StageOrderReview         = OrderStage{OrderPhaseReview, OrderStatusNone}
+	StageOrderDelivered      = OrderStage{OrderPhaseDelivered, OrderStatusNone}
+)
+
+// OrderPendingTransition 审批事务
+type OrderPendingTransition struct {
+	From     OrderStage `json:"from"`
+	To       OrderStage `json:"to"`
+	Fallback OrderStage `json:"fallback"`
+}
+
+// OrderState 完整状态
+type OrderState struct {
+	Current OrderStage              `json:"current"`
+	Pending *OrderPendingTransition `json:"pending,omitempty"`
+}
+
+// OrderStateColumns 数据库存储结构
+type OrderStateColumns struct {
+	Phase   OrderPhase                                  `gorm:"column:phase" json:"phase"`
+	Status  OrderStatus                                 `gorm:"column:status" json:"status"`
+	Pending datatypes.JSONType[*OrderPendingTransition] `gorm:"column:pending" json:"pending"`
+}
+
+func (s OrderState) ToColumns() OrderStateColumns {
+	return OrderStateColumns{
+		Phase:   s.Current.Phase,
+		Status:  s.Current.Status,
+		Pending: datatypes.NewJSONType(s.Pending),
+	}
+}
+
+func (c OrderStateColumns) ToState() OrderState {
+	return OrderState{
+		Current: OrderStage{Phase: c.Phase, Status: c.Status},
+		Pending: c.Pending.Data(),
+	}
+}
+
+// 错误定义
+var (
+	ErrOrderInvalidTransition  = errors.New("invalid transition")
+	ErrOrderApprovalInProgress = errors.New("approval in progress")
+	ErrOrderNotInApproval      = errors.New("not in approval")
+)
+
+func (s OrderState) TransitionTo(to OrderStage) (OrderState, error) {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		switch to {
+		case StageOrderShipped:
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: StageOrderReview, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCreatedPending}}, nil
+		}
+	case StageOrderCreatedPending:
+		switch to {
+		case StageOrderCreatedPaid:
+			return OrderState{Current: to}, nil
+		case StageOrderCancelled:
+			return OrderState{Current: to}, nil
+		}
+	case StageOrderShipped:
+		switch to {
+		case StageOrderDelivered:
+			return OrderState{Current: to}, nil
+		}
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func (s OrderState) Commit() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.To}, nil
+}
+
+func (s OrderState) Reject() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.Fallback}, nil
+}
+
+func (s OrderState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+func (s OrderState) ValidTransitions() []OrderStage {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		return []OrderStage{StageOrderShipped}
+	case StageOrderCreatedPending:
+		return []OrderStage{StageOrderCreatedPaid, StageOrderCancelled}
+	case StageOrderShipped:
+		return []OrderStage{StageOrderDelivered}
+	}
+	return nil
+}
+
+func (s OrderState) Next() []OrderState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []OrderState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, OrderState{Current: stage})
+	}
+	return result
+}
+
+// OrderAllowedTransitions 持久化层允许的阶段变化（含审批中间态）
+// 与生成的 .sql 文件中的允许流转表一致
+var OrderAllowedTransitions = map[OrderStage][]OrderStage{
+	StageOrderCreatedPending: {StageOrderCreatedPaid, StageOrderCancelled},
+	StageOrderCreatedPaid:    {StageOrderReview},
+	StageOrderReview:         {StageOrderShipped, StageOrderCreatedPending},
+	StageOrderShipped:        {StageOrderDelivered},
+}
+
+// ValidateUpdate 校验从 prev 更新为 c 是否合法：阶段未变或为允许的流转
+func (c OrderStateColumns) ValidateUpdate(prev OrderStateColumns) error {
+	from, to := prev.ToState().Current, c.ToState().Current
+	if from == to || slices.Contains(OrderAllowedTransitions[from], to) {
+		return nil
+	}
+	return fmt.Errorf("%v -> %v: %w", from, to, ErrOrderInvalidTransition)
+}
+
+// OrderBeforeUpdate 供模型的 BeforeUpdate 钩子调用
+// 按主键加载更新前的状态列，拒绝 ValidateUpdate 不允许的流转
+func OrderBeforeUpdate(tx *gorm.DB, next OrderStateColumns) error {
+	stmt := tx.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("%s: primary key required to load previous state", stmt.Table)
+	}
+	if stmt.ReflectValue.Kind() != reflect.Struct {
+		return nil
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	id, zero := field.ValueOf(stmt.Context, stmt.ReflectValue)
+	if zero {
+		return nil
+	}
+	var prev OrderStateColumns
+	err := tx.Session(&gorm.Session{NewDB: true}).
+		Table(stmt.Table).
+		Where(clause.Eq{Column: clause.Column{Name: field.DBName}, Value: id}).
+		Take(&prev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return next.ValidateUpdate(prev)
+}
@@ -0,0 +1,24 @@
+package enforce
+
+import "gorm.io/gorm"
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 数据库约束：sql=mysql 在生成代码旁输出 order_stateflow.sql（CHECK 约束与允许流转表），
+// 并生成 OrderAllowedTransitions、ValidateUpdate 与 OrderBeforeUpdate 钩子
+// @StateFlow(name="Order", sql=mysql, table=orders)
+// @Flow: Created(Pending) => [ (Paid), Cancelled final ]
+// @Flow: Created(Paid)    => [ Shipped! via Review else Created(Pending) ]
+// @Flow: Shipped          => [ Delivered final ]
+const _ = ""
+
+// Order 订单实体，嵌入状态列
+type Order struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+	OrderStateColumns
+}
+
+// BeforeUpdate 拒绝非法的状态流转
+func (m *Order) BeforeUpdate(tx *gorm.DB) error {
+	return OrderBeforeUpdate(tx, m.OrderStateColumns)
+}
@@ -0,0 +1,25 @@
+-- Code generated by gogen. DO NOT EDIT.
+-- Order 状态列约束与允许流转表（mysql）
+
+-- 阶段取值约束
+ALTER TABLE `orders` ADD CONSTRAINT `chk_orders_stage` CHECK (
+  (`phase` = 'Created' AND `status` IN ('Paid', 'Pending'))
+  OR (`phase` IN ('Cancelled', 'Shipped', 'Review', 'Delivered') AND `status` = '')
+);
+
+-- 允许的流转（含审批中间态的进入、提交与回退）
+CREATE TABLE IF NOT EXISTS `order_transitions` (
+  `from_phase` VARCHAR(64) NOT NULL DEFAULT '',
+  `from_status` VARCHAR(64) NOT NULL DEFAULT '',
+  `to_phase` VARCHAR(64) NOT NULL DEFAULT '',
+  `to_status` VARCHAR(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (`from_phase`, `from_status`, `to_phase`, `to_status`)
+);
+
+INSERT IGNORE INTO `order_transitions` (`from_phase`, `from_status`, `to_phase`, `to_status`) VALUES
+  ('Created', 'Pending', 'Created', 'Paid'),
+  ('Created', 'Pending', 'Cancelled', ''),
+  ('Created', 'Paid', 'Review', ''),
+  ('Review', '', 'Shipped', ''),
+  ('Review', '', 'Created', 'Pending'),
+  ('Shipped', '', 'Delivered', '');
@@ -0,0 +1,525 @@
+// Code generated by gogen. DO NOT EDIT.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/datatypes"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                      ┌──▶ Shipped ──▶ Delivered
+                      │
+                      │
+          ┌──▶ Paid ──┤
+          │           │                      ┌── <COMMIT> ──▶ Refunded
+          │           │                      │
+          │           └──▶ Refunding (via) ──┤
+          │                                  │
+          │                                  └── <REJECT> ──▶ Paid 🔁
+Created ──┤
+          │
+          │
+          │
+          │
+          │
+          └──▶ Cancelled
+*/
+
+// OrderPhase 阶段枚举
+type OrderPhase string
+
+const (
+	OrderPhaseCreated   OrderPhase = "Created"
+	OrderPhasePaid      OrderPhase = "Paid"
+	OrderPhaseCancelled OrderPhase = "Cancelled"
+	OrderPhaseShipped   OrderPhase = "Shipped"
+	OrderPhaseRefunded  OrderPhase = "Refunded"
+	OrderPhaseRefunding OrderPhase = "Refunding"
+	OrderPhaseDelivered OrderPhase = "Delivered"
+)
+
+var OrderPhaseEnums = struct {
+	Created   OrderPhase
+	Paid      OrderPhase
+	Cancelled OrderPhase
+	Shipped   OrderPhase
+	Refunded  OrderPhase
+	Refunding OrderPhase
+	Delivered OrderPhase
+}{
+	Created:   OrderPhaseCreated,
+	Paid:      OrderPhasePaid,
+	Cancelled: OrderPhaseCancelled,
+	Shipped:   OrderPhaseShipped,
+	Refunded:  OrderPhaseRefunded,
+	Refunding: OrderPhaseRefunding,
+	Delivered: OrderPhaseDelivered,
+}
+
+// OrderStage 阶段（Phase + Status）
+type OrderStage = OrderPhase
+
+// 预定义阶段
+var (
+	StageOrderCreated   = OrderPhaseCreated
+	StageOrderPaid      = OrderPhasePaid
+	StageOrderCancelled = OrderPhaseCancelled
+	StageOrderShipped   = OrderPhaseShipped
+	StageOrderRefunded  = OrderPhaseRefunded
+	StageOrderRefunding = OrderPhaseRefunding
+	StageOrderDelivered = OrderPhaseDelivered
+)
+
+// OrderPendingTransition 审批事务
+type OrderPendingTransition struct {
+	From     OrderStage `json:"from"`
+	To       OrderStage `json:"to"`
+	Fallback OrderStage `json:"fallback"`
+}
+
+// OrderState 完整状态
+type OrderState struct {
+	Current OrderStage              `json:"current"`
+	Pending *OrderPendingTransition `json:"pending,omitempty"`
+}
+
+// OrderStateColumns 数据库存储结构
+type OrderStateColumns struct {
+	Phase   OrderPhase                                  `gorm:"column:phase" json:"phase"`
+	Pending datatypes.JSONType[*OrderPendingTransition] `gorm:"column:pending" json:"pending"`
+}
+
+func (s OrderState) ToColumns() OrderStateColumns {
+	return OrderStateColumns{
+		Phase:   s.Current,
+		Pending: datatypes.NewJSONType(s.Pending),
+	}
+}
+
+func (c OrderStateColumns) ToState() OrderState {
+	return OrderState{
+		Current: c.Phase,
+		Pending: c.Pending.Data(),
+	}
+}
+
+// 错误定义
+var (
+	ErrOrderInvalidTransition   = errors.New("invalid transition")
+	ErrOrderApprovalInProgress  = errors.New("approval in progress")
+	ErrOrderNotInApproval       = errors.New("not in approval")
+	ErrOrderGuardsNotConfigured = errors.New("guards not configured")
+)
+
+func (s OrderState) TransitionTo(to OrderStage) (OrderState, error) {
+	switch s.Current {
+	case StageOrderCreated:
+		switch to {
+		case StageOrderPaid:
+			return OrderState{Current: to}, nil
+		case StageOrderCancelled:
+			return OrderState{Current: to}, nil
+		}
+	case StageOrderPaid:
+		switch to {
+		case StageOrderShipped:
+			return OrderState{Current: to}, nil
+		case StageOrderRefunded:
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: StageOrderRefunding, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderPaid}}, nil
+		}
+	case StageOrderShipped:
+		switch to {
+		case StageOrderDelivered:
+			return OrderState{Current: to}, nil
+		}
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func (s OrderState) Commit() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.To}, nil
+}
+
+func (s OrderState) Reject() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.Fallback}, nil
+}
+
+func (s OrderState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+func (s OrderState) ValidTransitions() []OrderStage {
+	switch s.Current {
+	case StageOrderCreated:
+		return []OrderStage{StageOrderPaid, StageOrderCancelled}
+	case StageOrderPaid:
+		return []OrderStage{StageOrderShipped, StageOrderRefunded}
+	case StageOrderShipped:
+		return []OrderStage{StageOrderDelivered}
+	}
+	return nil
+}
+
+func (s OrderState) Next() []OrderState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []OrderState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, OrderState{Current: stage})
+	}
+	return result
+}
+
+// OrderEvent 事件枚举
+type OrderEvent string
+
+const (
+	OrderEventPay     OrderEvent = "Pay"
+	OrderEventCancel  OrderEvent = "Cancel"
+	OrderEventShip    OrderEvent = "Ship"
+	OrderEventRefund  OrderEvent = "Refund"
+	OrderEventDeliver OrderEvent = "Deliver"
+)
+
+var OrderEventEnums = struct {
+	Pay     OrderEvent
+	Cancel  OrderEvent
+	Ship    OrderEvent
+	Refund  OrderEvent
+	Deliver OrderEvent
+}{
+	Pay:     OrderEventPay,
+	Cancel:  OrderEventCancel,
+	Ship:    OrderEventShip,
+	Refund:  OrderEventRefund,
+	Deliver: OrderEventDeliver,
+}
+
+// OrderGuards 流转守卫，返回非 nil 错误时拒绝流转
+type OrderGuards interface {
+	CanShip(ctx context.Context, s OrderState, to OrderStage) error
+	CanRefund(ctx context.Context, s OrderState, to OrderStage) error
+}
+
+// OrderHooks 流转钩子，任一钩子返回错误时中止流转
+// 嵌入 OrderNopHooks 后只需实现关心的方法
+type OrderHooks interface {
+	BeforePay(ctx context.Context, s OrderState, to OrderStage) error
+	AfterPay(ctx context.Context, from OrderState, to OrderState) error
+	BeforeCancel(ctx context.Context, s OrderState, to OrderStage) error
+	AfterCancel(ctx context.Context, from OrderState, to OrderState) error
+	BeforeShip(ctx context.Context, s OrderState, to OrderStage) error
+	AfterShip(ctx context.Context, from OrderState, to OrderState) error
+	BeforeRefund(ctx context.Context, s OrderState, to OrderStage) error
+	AfterRefund(ctx context.Context, from OrderState, to OrderState) error
+	BeforeDeliver(ctx context.Context, s OrderState, to OrderStage) error
+	AfterDeliver(ctx context.Context, from OrderState, to OrderState) error
+	OnEnterCreated(ctx context.Context, s OrderState) error
+	OnExitCreated(ctx context.Context, s OrderState) error
+	OnEnterPaid(ctx context.Context, s OrderState) error
+	OnExitPaid(ctx context.Context, s OrderState) error
+	OnEnterCancelled(ctx context.Context, s OrderState) error
+	OnExitCancelled(ctx context.Context, s OrderState) error
+	OnEnterShipped(ctx context.Context, s OrderState) error
+	OnExitShipped(ctx context.Context, s OrderState) error
+	OnEnterRefunded(ctx context.Context, s OrderState) error
+	OnExitRefunded(ctx context.Context, s OrderState) error
+	OnEnterRefunding(ctx context.Context, s OrderState) error
+	OnExitRefunding(ctx context.Context, s OrderState) error
+	OnEnterDelivered(ctx context.Context, s OrderState) error
+	OnExitDelivered(ctx context.Context, s OrderState) error
+}
+
+// OrderNopHooks OrderHooks 的空实现
+type OrderNopHooks struct{}
+
+func (OrderNopHooks) BeforePay(context.Context, OrderState, OrderStage) error { return nil }
+
+func (OrderNopHooks) AfterPay(context.Context, OrderState, OrderState) error { return nil }
+
+func (OrderNopHooks) BeforeCancel(context.Context, OrderState, OrderStage) error { return nil }
+
+func (OrderNopHooks) AfterCancel(context.Context, OrderState, OrderState) error { return nil }
+
+func (OrderNopHooks) BeforeShip(context.Context, OrderState, OrderStage) error { return nil }
+
+func (OrderNopHooks) AfterShip(context.Context, OrderState, OrderState) error { return nil }
+
+func (OrderNopHooks) BeforeRefund(context.Context, OrderState, OrderStage) error { return nil }
+
+func (OrderNopHooks) AfterRefund(context.Context, OrderState, OrderState) error { return nil }
+
+func (OrderNopHooks) BeforeDeliver(context.Context, OrderState, OrderStage) error { return nil }
+
+func (OrderNopHooks) AfterDeliver(context.Context, OrderState, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterCreated(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitCreated(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterPaid(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitPaid(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterCancelled(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitCancelled(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterShipped(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitShipped(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterRefunded(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitRefunded(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterRefunding(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitRefunding(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnEnterDelivered(context.Context, OrderState) error { return nil }
+
+func (OrderNopHooks) OnExitDelivered(context.Context, OrderState) error { return nil }
+
+// OrderContext Fire 的执行上下文
+type OrderContext struct {
+	Ctx    context.Context // 为 nil 时使用 context.Background()
+	Guards OrderGuards     // 存在 if 守卫时必须设置
+	Hooks  OrderHooks      // 可为 nil
+}
+
+// OrderGuardError 守卫拒绝流转时返回的错误
+type OrderGuardError struct {
+	Guard string
+	Event OrderEvent
+	From  OrderStage
+	To    OrderStage
+	Err   error
+}
+
+func (e *OrderGuardError) Error() string {
+	return fmt.Sprintf("guard %s rejected %s (%v -> %v): %v", e.Guard, e.Event, e.From, e.To, e.Err)
+}
+
+func (e *OrderGuardError) Unwrap() error {
+	return e.Err
+}
+
+// Fire 触发事件，依次执行守卫、Before、TransitionTo、OnExit、OnEnter、After
+// OnExit/OnEnter 仅在当前阶段发生变化时调用；任一步骤失败时返回原状态
+// 发起审批时停在 via 阶段，之后以 CommitWith / RejectWith 完成审批，同样执行守卫与钩子
+func (s OrderState) Fire(event OrderEvent, ctx OrderContext) (OrderState, error) {
+	if ctx.Ctx == nil {
+		ctx.Ctx = context.Background()
+	}
+	to, guard, ok := s.eventTarget(event)
+	if !ok {
+		return s, ErrOrderInvalidTransition
+	}
+	if guard != "" {
+		if err := s.checkGuard(ctx, guard, event, to); err != nil {
+			return s, err
+		}
+	}
+	if ctx.Hooks != nil {
+		if err := s.beforeEvent(ctx, event, to); err != nil {
+			return s, err
+		}
+	}
+	next, err := s.TransitionTo(to)
+	if err != nil {
+		return s, err
+	}
+	if ctx.Hooks != nil {
+		if next.Current != s.Current {
+			if err := s.exitStage(ctx); err != nil {
+				return s, err
+			}
+			if err := next.enterStage(ctx); err != nil {
+				return s, err
+			}
+		}
+		if err := s.afterEvent(ctx, event, next); err != nil {
+			return s, err
+		}
+	}
+	return next, nil
+}
+
+func (s OrderState) eventTarget(event OrderEvent) (OrderStage, string, bool) {
+	switch s.Current {
+	case StageOrderCreated:
+		switch event {
+		case OrderEventPay:
+			return StageOrderPaid, "", true
+		case OrderEventCancel:
+			return StageOrderCancelled, "", true
+		}
+	case StageOrderPaid:
+		switch event {
+		case OrderEventShip:
+			return StageOrderShipped, "canShip", true
+		case OrderEventRefund:
+			return StageOrderRefunded, "canRefund", true
+		}
+	case StageOrderShipped:
+		switch event {
+		case OrderEventDeliver:
+			return StageOrderDelivered, "", true
+		}
+	}
+	return s.Current, "", false
+}
+
+func (s OrderState) checkGuard(ctx OrderContext, guard string, event OrderEvent, to OrderStage) error {
+	if ctx.Guards == nil {
+		return &OrderGuardError{Guard: guard, Event: event, From: s.Current, To: to, Err: ErrOrderGuardsNotConfigured}
+	}
+	var err error
+	switch guard {
+	case "canShip":
+		err = ctx.Guards.CanShip(ctx.Ctx, s, to)
+	case "canRefund":
+		err = ctx.Guards.CanRefund(ctx.Ctx, s, to)
+	}
+	if err != nil {
+		return &OrderGuardError{Guard: guard, Event: event, From: s.Current, To: to, Err: err}
+	}
+	return nil
+}
+
+// CommitWith 与 Commit 相同，同时检查守卫并执行 OnExit（via 阶段）、OnEnter（目标阶段）钩子
+func (s OrderState) CommitWith(ctx OrderContext) (OrderState, error) {
+	next, err := s.Commit()
+	if err != nil {
+		return s, err
+	}
+	return s.resolveApproval(ctx, next, true)
+}
+
+// RejectWith 与 Reject 相同，同时执行 OnExit（via 阶段）、OnEnter（回退阶段）钩子
+func (s OrderState) RejectWith(ctx OrderContext) (OrderState, error) {
+	next, err := s.Reject()
+	if err != nil {
+		return s, err
+	}
+	return s.resolveApproval(ctx, next, false)
+}
+
+// resolveApproval 审批状态变化后执行的守卫与钩子，committed 表示审批已提交到目标阶段
+func (s OrderState) resolveApproval(ctx OrderContext, next OrderState, committed bool) (OrderState, error) {
+	if ctx.Ctx == nil {
+		ctx.Ctx = context.Background()
+	}
+	if committed {
+		if event, guard := s.Pending.guard(); guard != "" {
+			if err := s.checkGuard(ctx, guard, event, next.Current); err != nil {
+				return s, err
+			}
+		}
+	}
+	if ctx.Hooks != nil && next.Current != s.Current {
+		if err := s.exitStage(ctx); err != nil {
+			return s, err
+		}
+		if err := next.enterStage(ctx); err != nil {
+			return s, err
+		}
+	}
+	return next, nil
+}
+
+func (p *OrderPendingTransition) guard() (OrderEvent, string) {
+	switch {
+	case p.From == StageOrderPaid && p.To == StageOrderRefunded:
+		return OrderEventRefund, "canRefund"
+	}
+	return "", ""
+}
+
+func (s OrderState) beforeEvent(ctx OrderContext, event OrderEvent, to OrderStage) error {
+	switch event {
+	case OrderEventPay:
+		return ctx.Hooks.BeforePay(ctx.Ctx, s, to)
+	case OrderEventCancel:
+		return ctx.Hooks.BeforeCancel(ctx.Ctx, s, to)
+	case OrderEventShip:
+		return ctx.Hooks.BeforeShip(ctx.Ctx, s, to)
+	case OrderEventRefund:
+		return ctx.Hooks.BeforeRefund(ctx.Ctx, s, to)
+	case OrderEventDeliver:
+		return ctx.Hooks.BeforeDeliver(ctx.Ctx, s, to)
+	}
+	return nil
+}
+
+func (s OrderState) afterEvent(ctx OrderContext, event OrderEvent, next OrderState) error {
+	switch event {
+	case OrderEventPay:
+		return ctx.Hooks.AfterPay(ctx.Ctx, s, next)
+	case OrderEventCancel:
+		return ctx.Hooks.AfterCancel(ctx.Ctx, s, next)
+	case OrderEventShip:
+		return ctx.Hooks.AfterShip(ctx.Ctx, s, next)
+	case OrderEventRefund:
+		return ctx.Hooks.AfterRefund(ctx.Ctx, s, next)
+	case OrderEventDeliver:
+		return ctx.Hooks.AfterDeliver(ctx.Ctx, s, next)
+	}
+	return nil
+}
+
+func (s OrderState) enterStage(ctx OrderContext) error {
+	switch s.Current {
+	case StageOrderCreated:
+		return ctx.Hooks.OnEnterCreated(ctx.Ctx, s)
+	case StageOrderPaid:
+		return ctx.Hooks.OnEnterPaid(ctx.Ctx, s)
+	case StageOrderCancelled:
+		return ctx.Hooks.OnEnterCancelled(ctx.Ctx, s)
+	case StageOrderShipped:
+		return ctx.Hooks.OnEnterShipped(ctx.Ctx, s)
+	case StageOrderRefunded:
+		return ctx.Hooks.OnEnterRefunded(ctx.Ctx, s)
+	case StageOrderRefunding:
+		return ctx.Hooks.OnEnterRefunding(ctx.Ctx, s)
+	case StageOrderDelivered:
+		return ctx.Hooks.OnEnterDelivered(ctx.Ctx, s)
+	}
+	return nil
+}
+
+func (s OrderState) exitStage(ctx OrderContext) error {
+	switch s.Current {
+	case StageOrderCreated:
+		return ctx.Hooks.OnExitCreated(ctx.Ctx, s)
+	case StageOrderPaid:
+		return ctx.Hooks.OnExitPaid(ctx.Ctx, s)
+	case StageOrderCancelled:
+		return ctx.Hooks.OnExitCancelled(ctx.Ctx, s)
+	case StageOrderShipped:
+		return ctx.Hooks.OnExitShipped(ctx.Ctx, s)
+	case StageOrderRefunded:
+		return ctx.Hooks.OnExitRefunded(ctx.Ctx, s)
+	case StageOrderRefunding:
+		return ctx.Hooks.OnExitRefunding(ctx.Ctx, s)
+	case StageOrderDelivered:
+		return ctx.Hooks.OnExitDelivered(ctx.Ctx, s)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package events
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 事件、守卫与钩子测试
+// on 为流转命名事件，if 指定守卫（需配合 on），Fire 按事件执行流转
+// @StateFlow(name="Order")
+// @Flow: Created => [ Paid on Pay, Cancelled on Cancel ]
+// @Flow: Paid    => [ Shipped ] on Ship if canShip
+// @Flow: Paid    => [ Refunded! via Refunding ] on Refund if canRefund
+// @Flow: Shipped => [ Delivered on Deliver ]
+const _ = ""
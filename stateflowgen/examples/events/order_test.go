@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type recordingHooks struct {
+	OrderNopHooks
+	calls []string
+}
+
+func (h *recordingHooks) OnExitPaid(context.Context, OrderState) error {
+	h.calls = append(h.calls, "OnExitPaid")
+	return nil
+}
+
+func (h *recordingHooks) OnEnterRefunding(context.Context, OrderState) error {
+	h.calls = append(h.calls, "OnEnterRefunding")
+	return nil
+}
+
+func (h *recordingHooks) OnExitRefunding(context.Context, OrderState) error {
+	h.calls = append(h.calls, "OnExitRefunding")
+	return nil
+}
+
+func (h *recordingHooks) OnEnterRefunded(context.Context, OrderState) error {
+	h.calls = append(h.calls, "OnEnterRefunded")
+	return nil
+}
+
+func (h *recordingHooks) OnEnterPaid(context.Context, OrderState) error {
+	h.calls = append(h.calls, "OnEnterPaid")
+	return nil
+}
+
+type refundGuards struct {
+	allow bool
+}
+
+func (refundGuards) CanShip(context.Context, OrderState, OrderStage) error { return nil }
+
+func (g *refundGuards) CanRefund(context.Context, OrderState, OrderStage) error {
+	if !g.allow {
+		return errors.New("refund window closed")
+	}
+	return nil
+}
+
+func TestApprovalRunsHooks(t *testing.T) {
+	hooks := &recordingHooks{}
+	guards := &refundGuards{allow: true}
+	ctx := OrderContext{Guards: guards, Hooks: hooks}
+
+	refunding, err := OrderState{Current: StageOrderPaid}.Fire(OrderEventRefund, ctx)
+	if err != nil || refunding.Current != StageOrderRefunding {
+		t.Fatalf("Fire(Refund) = %v, %v", refunding.Current, err)
+	}
+
+	refunded, err := refunding.CommitWith(ctx)
+	if err != nil || refunded.Current != StageOrderRefunded {
+		t.Fatalf("CommitWith() = %v, %v", refunded.Current, err)
+	}
+	want := []string{"OnExitPaid", "OnEnterRefunding", "OnExitRefunding", "OnEnterRefunded"}
+	if !slices.Equal(hooks.calls, want) {
+		t.Errorf("hooks = %v, want %v", hooks.calls, want)
+	}
+
+	hooks.calls = nil
+	paid, err := refunding.RejectWith(ctx)
+	if err != nil || paid.Current != StageOrderPaid {
+		t.Fatalf("RejectWith() = %v, %v", paid.Current, err)
+	}
+	if want := []string{"OnExitRefunding", "OnEnterPaid"}; !slices.Equal(hooks.calls, want) {
+		t.Errorf("hooks = %v, want %v", hooks.calls, want)
+	}
+}
+
+func TestCommitWithChecksGuard(t *testing.T) {
+	hooks := &recordingHooks{}
+	guards := &refundGuards{allow: true}
+	ctx := OrderContext{Guards: guards, Hooks: hooks}
+
+	refunding, err := OrderState{Current: StageOrderPaid}.Fire(OrderEventRefund, ctx)
+	if err != nil {
+		t.Fatalf("Fire(Refund) error = %v", err)
+	}
+
+	// 审批期间条件变化，提交时守卫拒绝，状态与钩子均不变
+	guards.allow = false
+	hooks.calls = nil
+	got, err := refunding.CommitWith(ctx)
+	var guardErr *OrderGuardError
+	if !errors.As(err, &guardErr) || guardErr.Guard != "canRefund" || guardErr.Event != OrderEventRefund {
+		t.Fatalf("CommitWith() error = %v, want OrderGuardError for canRefund", err)
+	}
+	if got.Current != StageOrderRefunding || len(hooks.calls) != 0 {
+		t.Errorf("CommitWith() = %v with hooks %v, want unchanged state and no hooks", got.Current, hooks.calls)
+	}
+}
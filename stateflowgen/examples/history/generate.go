@@ -0,0 +1,361 @@
+// Code generated by gogen. DO NOT EDIT.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                                                            ┌── <COMMIT> ──▶ Shipped ──▶ Delivered
+                                                            │
+                   ┌──▶ Created(Paid) ──▶ Reviewing (via) ──┤
+                   │                                        │
+                   │                                        └── <REJECT> ──▶ Created(Pending) 🔁
+                   │
+                   │
+                   │
+Created(Pending) ──┤
+                   │                                           ┌── <COMMIT> ──▶ Cancelled
+                   │                                           │
+                   │                   ┌──▶ Cancelling (via) ──┤
+                   │                   │                       │
+                   │                   │                       └── <REJECT> ──▶ Created(Pending) 🔁
+                   └──▶ <?APPROVAL?> ──┤
+                                       │
+                                       │
+                                       └──▶ Cancelled
+*/
+
+// OrderPhase 阶段枚举
+type OrderPhase string
+
+const (
+	OrderPhaseCreated    OrderPhase = "Created"
+	OrderPhaseCancelled  OrderPhase = "Cancelled"
+	OrderPhaseCancelling OrderPhase = "Cancelling"
+	OrderPhaseShipped    OrderPhase = "Shipped"
+	OrderPhaseReviewing  OrderPhase = "Reviewing"
+	OrderPhaseDelivered  OrderPhase = "Delivered"
+)
+
+var OrderPhaseEnums = struct {
+	Created    OrderPhase
+	Cancelled  OrderPhase
+	Cancelling OrderPhase
+	Shipped    OrderPhase
+	Reviewing  OrderPhase
+	Delivered  OrderPhase
+}{
+	Created:    OrderPhaseCreated,
+	Cancelled:  OrderPhaseCancelled,
+	Cancelling: OrderPhaseCancelling,
+	Shipped:    OrderPhaseShipped,
+	Reviewing:  OrderPhaseReviewing,
+	Delivered:  OrderPhaseDelivered,
+}
+
+// OrderStatus 状态枚举
+type OrderStatus string
+
+const (
+	OrderStatusNone    OrderStatus = ""
+	OrderStatusPaid    OrderStatus = "Paid"
+	OrderStatusPending OrderStatus = "Pending"
+)
+
+var OrderStatusEnums = struct {
+	None    OrderStatus
+	Paid    OrderStatus
+	Pending OrderStatus
+}{
+	None:    OrderStatusNone,
+	Paid:    OrderStatusPaid,
+	Pending: OrderStatusPending,
+}
+
+// OrderStage 阶段（Phase + Status）
+type OrderStage struct {
+	Phase  OrderPhase  `json:"phase"`
+	Status OrderStatus `json:"status"`
+}
+
+// 预定义阶段
+var (
+	StageOrderCreatedPaid    = OrderStage{OrderPhaseCreated, OrderStatusPaid}
+	StageOrderCreatedPending = OrderStage{OrderPhaseCreated, OrderStatusPending}
+	StageOrderCancelled      = OrderStage{OrderPhaseCancelled, OrderStatusNone}
+	StageOrderCancelling     = OrderStage{OrderPhaseCancelling, OrderStatusNone}
+	StageOrderShipped        = OrderStage{OrderPhaseShipped, OrderStatusNone}
+	StageOrderReviewing      = OrderStage{OrderPhaseReviewing, OrderStatusNone}
+	StageOrderDelivered      = OrderStage{OrderPhaseDelivered, OrderStatusNone}
+)
+
+// OrderPendingTransition 审批事务
+type OrderPendingTransition struct {
+	From     OrderStage `json:"from"`
+	To       OrderStage `json:"to"`
+	Fallback OrderStage `json:"fallback"`
+}
+
+// OrderState 完整状态
+type OrderState struct {
+	Current OrderStage              `json:"current"`
+	Pending *OrderPendingTransition `json:"pending,omitempty"`
+}
+
+// OrderStateColumns 数据库存储结构
+type OrderStateColumns struct {
+	Phase   OrderPhase                                  `gorm:"column:phase" json:"phase"`
+	Status  OrderStatus                                 `gorm:"column:status" json:"status"`
+	Pending datatypes.JSONType[*OrderPendingTransition] `gorm:"column:pending" json:"pending"`
+}
+
+func (s OrderState) ToColumns() OrderStateColumns {
+	return OrderStateColumns{
+		Phase:   s.Current.Phase,
+		Status:  s.Current.Status,
+		Pending: datatypes.NewJSONType(s.Pending),
+	}
+}
+
+func (c OrderStateColumns) ToState() OrderState {
+	return OrderState{
+		Current: OrderStage{Phase: c.Phase, Status: c.Status},
+		Pending: c.Pending.Data(),
+	}
+}
+
+// 错误定义
+var (
+	ErrOrderInvalidTransition  = errors.New("invalid transition")
+	ErrOrderApprovalInProgress = errors.New("approval in progress")
+	ErrOrderNotInApproval      = errors.New("not in approval")
+)
+
+func (s OrderState) TransitionTo(to OrderStage, withApproval bool) (OrderState, error) {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		switch to {
+		case StageOrderShipped:
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: StageOrderReviewing, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCreatedPending}}, nil
+		}
+	case StageOrderCreatedPending:
+		switch to {
+		case StageOrderCreatedPaid:
+			return OrderState{Current: to}, nil
+		case StageOrderCancelled:
+			if withApproval {
+				if s.Pending != nil {
+					return s, ErrOrderApprovalInProgress
+				}
+				return OrderState{Current: StageOrderCancelling, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCreatedPending}}, nil
+			}
+			return OrderState{Current: to}, nil
+		}
+	case StageOrderShipped:
+		switch to {
+		case StageOrderDelivered:
+			return OrderState{Current: to}, nil
+		}
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func (s OrderState) Commit() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.To}, nil
+}
+
+func (s OrderState) Reject() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.Fallback}, nil
+}
+
+func (s OrderState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+func (s OrderState) ValidTransitions() []OrderStage {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		return []OrderStage{StageOrderShipped}
+	case StageOrderCreatedPending:
+		return []OrderStage{StageOrderCreatedPaid, StageOrderCancelled}
+	case StageOrderShipped:
+		return []OrderStage{StageOrderDelivered}
+	}
+	return nil
+}
+
+func (s OrderState) Next() []OrderState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []OrderState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, OrderState{Current: stage})
+	}
+	return result
+}
+
+// OrderApproval 流转记录中的审批动作
+type OrderApproval string
+
+const (
+	OrderApprovalNone      OrderApproval = ""
+	OrderApprovalRequested OrderApproval = "requested"
+	OrderApprovalCommitted OrderApproval = "committed"
+	OrderApprovalRejected  OrderApproval = "rejected"
+)
+
+// OrderTransitionRecord 流转记录
+// From 为流转前阶段；审批申请（requested）的 To 为审批目标
+type OrderTransitionRecord struct {
+	From     OrderStage    `json:"from"`
+	To       OrderStage    `json:"to"`
+	Event    string        `json:"event,omitempty"`
+	Actor    string        `json:"actor,omitempty"`
+	At       time.Time     `json:"at"`
+	Approval OrderApproval `json:"approval,omitempty"`
+}
+
+// OrderTransitionMeta 流转元数据
+type OrderTransitionMeta struct {
+	Event string
+	Actor string
+	At    time.Time // 为零值时使用 time.Now()
+}
+
+func (m OrderTransitionMeta) record(from, to OrderStage) OrderTransitionRecord {
+	at := m.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return OrderTransitionRecord{From: from, To: to, Event: m.Event, Actor: m.Actor, At: at}
+}
+
+// TransitionToWithMeta 与 TransitionTo 相同，同时返回本次流转的记录
+func (s OrderState) TransitionToWithMeta(to OrderStage, withApproval bool, meta OrderTransitionMeta) (OrderState, OrderTransitionRecord, error) {
+	next, err := s.TransitionTo(to, withApproval)
+	if err != nil {
+		return s, OrderTransitionRecord{}, err
+	}
+	record := meta.record(s.Current, to)
+	if next.Pending != nil {
+		record.Approval = OrderApprovalRequested
+	}
+	return next, record, nil
+}
+
+// CommitWithMeta 与 Commit 相同，同时返回本次流转的记录
+func (s OrderState) CommitWithMeta(meta OrderTransitionMeta) (OrderState, OrderTransitionRecord, error) {
+	next, err := s.Commit()
+	if err != nil {
+		return s, OrderTransitionRecord{}, err
+	}
+	record := meta.record(s.Current, next.Current)
+	record.Approval = OrderApprovalCommitted
+	return next, record, nil
+}
+
+// RejectWithMeta 与 Reject 相同，同时返回本次流转的记录
+func (s OrderState) RejectWithMeta(meta OrderTransitionMeta) (OrderState, OrderTransitionRecord, error) {
+	next, err := s.Reject()
+	if err != nil {
+		return s, OrderTransitionRecord{}, err
+	}
+	record := meta.record(s.Current, next.Current)
+	record.Approval = OrderApprovalRejected
+	return next, record, nil
+}
+
+// OrderTransitionHistory 流转历史表，与 OrderStateColumns 配套
+type OrderTransitionHistory struct {
+	ID         uint64        `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	EntityID   string        `gorm:"column:entity_id;index" json:"entity_id"`
+	FromPhase  OrderPhase    `gorm:"column:from_phase" json:"from_phase"`
+	FromStatus OrderStatus   `gorm:"column:from_status" json:"from_status"`
+	ToPhase    OrderPhase    `gorm:"column:to_phase" json:"to_phase"`
+	ToStatus   OrderStatus   `gorm:"column:to_status" json:"to_status"`
+	Event      string        `gorm:"column:event" json:"event"`
+	Actor      string        `gorm:"column:actor" json:"actor"`
+	Approval   OrderApproval `gorm:"column:approval" json:"approval"`
+	At         time.Time     `gorm:"column:at;index" json:"at"`
+}
+
+// ToHistory 转换为历史表记录
+func (r OrderTransitionRecord) ToHistory(entityID string) OrderTransitionHistory {
+	return OrderTransitionHistory{
+		EntityID:   entityID,
+		FromPhase:  r.From.Phase,
+		FromStatus: r.From.Status,
+		ToPhase:    r.To.Phase,
+		ToStatus:   r.To.Status,
+		Event:      r.Event,
+		Actor:      r.Actor,
+		Approval:   r.Approval,
+		At:         r.At,
+	}
+}
+
+// ToRecord 转换为流转记录
+func (h OrderTransitionHistory) ToRecord() OrderTransitionRecord {
+	return OrderTransitionRecord{
+		From:     OrderStage{Phase: h.FromPhase, Status: h.FromStatus},
+		To:       OrderStage{Phase: h.ToPhase, Status: h.ToStatus},
+		Event:    h.Event,
+		Actor:    h.Actor,
+		Approval: h.Approval,
+		At:       h.At,
+	}
+}
+
+// ReplayOrder 从初始状态按顺序重放流转记录，重建并校验状态
+// 记录的 From 与当前阶段不一致、流转非法或审批动作不匹配时返回错误
+func ReplayOrder(initial OrderState, records []OrderTransitionRecord) (OrderState, error) {
+	s := initial
+	for i, r := range records {
+		if r.From != s.Current {
+			return s, fmt.Errorf("record %d: from %v does not match current stage %v: %w", i, r.From, s.Current, ErrOrderInvalidTransition)
+		}
+		var next OrderState
+		var err error
+		switch r.Approval {
+		case OrderApprovalCommitted:
+			next, err = s.Commit()
+		case OrderApprovalRejected:
+			next, err = s.Reject()
+		case OrderApprovalRequested:
+			next, err = s.TransitionTo(r.To, true)
+			if err == nil && next.Pending == nil {
+				err = fmt.Errorf("transition does not require approval: %w", ErrOrderInvalidTransition)
+			}
+		default:
+			next, err = s.TransitionTo(r.To, false)
+			if err == nil && next.Pending != nil {
+				err = fmt.Errorf("transition requires approval: %w", ErrOrderInvalidTransition)
+			}
+		}
+		if err == nil && r.Approval != OrderApprovalRequested && next.Current != r.To {
+			err = fmt.Errorf("resulting stage %v does not match: %w", next.Current, ErrOrderInvalidTransition)
+		}
+		if err != nil {
+			return s, fmt.Errorf("record %d (%v -> %v): %w", i, r.From, r.To, err)
+		}
+		s = next
+	}
+	return s, nil
+}
@@ -0,0 +1,11 @@
+package history
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 流转历史测试
+// history=true 生成流转记录、TransitionToWithMeta、GORM 历史表与 Replay
+// @StateFlow(name="Order", history=true)
+// @Flow: Created(Pending) => [ (Paid), Cancelled? via Cancelling ]
+// @Flow: Created(Paid)    => [ Shipped! via Reviewing else Created(Pending) ]
+// @Flow: Shipped          => [ Delivered ]
+const _ = ""
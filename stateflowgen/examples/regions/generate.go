@@ -0,0 +1,268 @@
+// Code generated by gogen. DO NOT EDIT.
+package regions
+
+import "errors"
+
+// ================ stateflow ================
+
+/* Flowchart:
+                            ┌──▶ Cancelled
+                            │
+          ┌──▶ Processing ──┤
+          │                 │
+          │                 └── <DONE> ──▶ Completed
+Created ──┤
+          │
+          │
+          └──▶ Cancelled
+*/
+
+// ShipmentPhase 阶段枚举
+type ShipmentPhase string
+
+const (
+	ShipmentPhaseCreated    ShipmentPhase = "Created"
+	ShipmentPhaseProcessing ShipmentPhase = "Processing"
+	ShipmentPhaseCancelled  ShipmentPhase = "Cancelled"
+	ShipmentPhaseCompleted  ShipmentPhase = "Completed"
+)
+
+var ShipmentPhaseEnums = struct {
+	Created    ShipmentPhase
+	Processing ShipmentPhase
+	Cancelled  ShipmentPhase
+	Completed  ShipmentPhase
+}{
+	Created:    ShipmentPhaseCreated,
+	Processing: ShipmentPhaseProcessing,
+	Cancelled:  ShipmentPhaseCancelled,
+	Completed:  ShipmentPhaseCompleted,
+}
+
+// ShipmentStage 阶段（Phase + Status）
+type ShipmentStage = ShipmentPhase
+
+// 预定义阶段
+var (
+	StageShipmentCreated    = ShipmentPhaseCreated
+	StageShipmentProcessing = ShipmentPhaseProcessing
+	StageShipmentCancelled  = ShipmentPhaseCancelled
+	StageShipmentCompleted  = ShipmentPhaseCompleted
+)
+
+// ShipmentPaymentStage payment 区域子状态（复合状态 Processing），空值表示区域未激活
+type ShipmentPaymentStage string
+
+const (
+	ShipmentPaymentStageUnpaid ShipmentPaymentStage = "Unpaid"
+	ShipmentPaymentStagePaid   ShipmentPaymentStage = "Paid"
+	ShipmentPaymentStageFailed ShipmentPaymentStage = "Failed"
+)
+
+var ShipmentPaymentStageEnums = struct {
+	Unpaid ShipmentPaymentStage
+	Paid   ShipmentPaymentStage
+	Failed ShipmentPaymentStage
+}{
+	Unpaid: ShipmentPaymentStageUnpaid,
+	Paid:   ShipmentPaymentStagePaid,
+	Failed: ShipmentPaymentStageFailed,
+}
+
+// ShipmentDeliveryStage delivery 区域子状态（复合状态 Processing），空值表示区域未激活
+type ShipmentDeliveryStage string
+
+const (
+	ShipmentDeliveryStagePacking   ShipmentDeliveryStage = "Packing"
+	ShipmentDeliveryStageShipped   ShipmentDeliveryStage = "Shipped"
+	ShipmentDeliveryStageDelivered ShipmentDeliveryStage = "Delivered"
+)
+
+var ShipmentDeliveryStageEnums = struct {
+	Packing   ShipmentDeliveryStage
+	Shipped   ShipmentDeliveryStage
+	Delivered ShipmentDeliveryStage
+}{
+	Packing:   ShipmentDeliveryStagePacking,
+	Shipped:   ShipmentDeliveryStageShipped,
+	Delivered: ShipmentDeliveryStageDelivered,
+}
+
+// ShipmentState 完整状态
+type ShipmentState struct {
+	Current  ShipmentStage         `json:"current"`
+	Payment  ShipmentPaymentStage  `json:"payment,omitempty"`
+	Delivery ShipmentDeliveryStage `json:"delivery,omitempty"`
+}
+
+// ShipmentStateColumns 数据库存储结构
+type ShipmentStateColumns struct {
+	Phase    ShipmentPhase         `gorm:"column:phase" json:"phase"`
+	Payment  ShipmentPaymentStage  `gorm:"column:payment" json:"payment"`
+	Delivery ShipmentDeliveryStage `gorm:"column:delivery" json:"delivery"`
+}
+
+func (s ShipmentState) ToColumns() ShipmentStateColumns {
+	return ShipmentStateColumns{
+		Phase:    s.Current,
+		Payment:  s.Payment,
+		Delivery: s.Delivery,
+	}
+}
+
+func (c ShipmentStateColumns) ToState() ShipmentState {
+	return ShipmentState{
+		Current:  c.Phase,
+		Payment:  c.Payment,
+		Delivery: c.Delivery,
+	}
+}
+
+// 错误定义
+var (
+	ErrShipmentInvalidTransition = errors.New("invalid transition")
+	ErrShipmentRegionInactive    = errors.New("region not active")
+)
+
+func (s ShipmentState) TransitionTo(to ShipmentStage) (ShipmentState, error) {
+	switch s.Current {
+	case StageShipmentCreated:
+		switch to {
+		case StageShipmentProcessing:
+			return ShipmentState{Current: to}.enterRegions(), nil
+		case StageShipmentCancelled:
+			return ShipmentState{Current: to}.enterRegions(), nil
+		}
+	case StageShipmentProcessing:
+		switch to {
+		case StageShipmentCancelled:
+			return ShipmentState{Current: to}.enterRegions(), nil
+		}
+	}
+	return s, ErrShipmentInvalidTransition
+}
+
+func (s ShipmentState) ValidTransitions() []ShipmentStage {
+	switch s.Current {
+	case StageShipmentCreated:
+		return []ShipmentStage{StageShipmentProcessing, StageShipmentCancelled}
+	case StageShipmentProcessing:
+		return []ShipmentStage{StageShipmentCancelled}
+	}
+	return nil
+}
+
+func (s ShipmentState) Next() []ShipmentState {
+	var result []ShipmentState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, ShipmentState{Current: stage}.enterRegions())
+	}
+	return result
+}
+
+// enterRegions 进入复合状态时将各区域置为入口子状态，其他状态下清空区域
+func (s ShipmentState) enterRegions() ShipmentState {
+	s.Payment, s.Delivery = "", ""
+	switch s.Current {
+	case StageShipmentProcessing:
+		s.Payment = ShipmentPaymentStageUnpaid
+		s.Delivery = ShipmentDeliveryStagePacking
+	}
+	return s
+}
+
+// TransitionPaymentTo 在 payment 区域内流转，所有区域到达终态时触发
+// Processing 的完成流转
+func (s ShipmentState) TransitionPaymentTo(to ShipmentPaymentStage) (ShipmentState, error) {
+	if s.Current != StageShipmentProcessing {
+		return s, ErrShipmentRegionInactive
+	}
+	switch s.Payment {
+	case ShipmentPaymentStageUnpaid:
+		switch to {
+		case ShipmentPaymentStagePaid:
+			s.Payment = to
+			return s.complete(), nil
+		case ShipmentPaymentStageFailed:
+			s.Payment = to
+			return s.complete(), nil
+		}
+	case ShipmentPaymentStageFailed:
+		switch to {
+		case ShipmentPaymentStageUnpaid:
+			s.Payment = to
+			return s.complete(), nil
+		}
+	}
+	return s, ErrShipmentInvalidTransition
+}
+
+func (s ShipmentState) ValidPaymentTransitions() []ShipmentPaymentStage {
+	if s.Current != StageShipmentProcessing {
+		return nil
+	}
+	switch s.Payment {
+	case ShipmentPaymentStageUnpaid:
+		return []ShipmentPaymentStage{ShipmentPaymentStagePaid, ShipmentPaymentStageFailed}
+	case ShipmentPaymentStageFailed:
+		return []ShipmentPaymentStage{ShipmentPaymentStageUnpaid}
+	}
+	return nil
+}
+
+// TransitionDeliveryTo 在 delivery 区域内流转，所有区域到达终态时触发
+// Processing 的完成流转
+func (s ShipmentState) TransitionDeliveryTo(to ShipmentDeliveryStage) (ShipmentState, error) {
+	if s.Current != StageShipmentProcessing {
+		return s, ErrShipmentRegionInactive
+	}
+	switch s.Delivery {
+	case ShipmentDeliveryStagePacking:
+		switch to {
+		case ShipmentDeliveryStageShipped:
+			s.Delivery = to
+			return s.complete(), nil
+		}
+	case ShipmentDeliveryStageShipped:
+		switch to {
+		case ShipmentDeliveryStageDelivered:
+			s.Delivery = to
+			return s.complete(), nil
+		}
+	}
+	return s, ErrShipmentInvalidTransition
+}
+
+func (s ShipmentState) ValidDeliveryTransitions() []ShipmentDeliveryStage {
+	if s.Current != StageShipmentProcessing {
+		return nil
+	}
+	switch s.Delivery {
+	case ShipmentDeliveryStagePacking:
+		return []ShipmentDeliveryStage{ShipmentDeliveryStageShipped}
+	case ShipmentDeliveryStageShipped:
+		return []ShipmentDeliveryStage{ShipmentDeliveryStageDelivered}
+	}
+	return nil
+}
+
+// RegionsDone 当前复合状态的所有区域是否都已到达终态
+func (s ShipmentState) RegionsDone() bool {
+	switch s.Current {
+	case StageShipmentProcessing:
+		return s.Payment == ShipmentPaymentStagePaid && s.Delivery == ShipmentDeliveryStageDelivered
+	}
+	return false
+}
+
+// complete 所有区域到达终态时执行完成流转（done），没有完成流转时停留在复合状态
+func (s ShipmentState) complete() ShipmentState {
+	if !s.RegionsDone() {
+		return s
+	}
+	switch s.Current {
+	case StageShipmentProcessing:
+		return ShipmentState{Current: StageShipmentCompleted}.enterRegions()
+	}
+	return s
+}
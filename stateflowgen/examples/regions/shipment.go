@@ -0,0 +1,17 @@
+package regions
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 复合状态测试
+// Processing 包含并行的 payment 与 delivery 区域，两者都到达终态后自动流转到 Completed
+// @StateFlow(name="Shipment")
+// @Flow: Created    => [ Processing, Cancelled ]
+// @Flow: Processing regions [ payment, delivery:Packing ]
+// @Flow: payment.Unpaid  => [ Paid final, Failed ]
+// @Flow: payment.Failed  => [ Unpaid ]
+// @Flow: delivery.Packing => [ Shipped ]
+// @Flow: delivery.Shipped => [ Delivered final ]
+// @Flow: Processing done => [ Completed final ]
+// @Flow: Processing      => [ Cancelled ]
+// @Flow: Cancelled final
+const _ = ""
@@ -0,0 +1,10 @@
+package schema
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 导出定义：schema="json,ts" 在生成代码旁输出 order_stateflow.json 与 order_stateflow.ts
+// @StateFlow(name="Order", schema="json,ts")
+// @Flow: Created(Pending) => [ (Paid), Cancelled final ]
+// @Flow: Created(Paid)    => [ Shipped! via Review else Created(Pending) ]
+// @Flow: Shipped          => [ Delivered final ]
+const _ = ""
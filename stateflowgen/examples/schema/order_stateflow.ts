@@ -0,0 +1,86 @@
+// Code generated by gogen. DO NOT EDIT.
+
+export type OrderStage = "Created(Paid)" | "Created(Pending)" | "Cancelled" | "Shipped" | "Review" | "Delivered";
+
+export const orderFlow = {
+  "name": "Order",
+  "init": "Created(Pending)",
+  "phases": [
+    "Created",
+    "Cancelled",
+    "Shipped",
+    "Review",
+    "Delivered"
+  ],
+  "statuses": {
+    "Created": [
+      "Paid",
+      "Pending"
+    ]
+  },
+  "stages": [
+    {
+      "id": "Created(Paid)",
+      "phase": "Created",
+      "status": "Paid"
+    },
+    {
+      "id": "Created(Pending)",
+      "phase": "Created",
+      "status": "Pending"
+    },
+    {
+      "id": "Cancelled",
+      "phase": "Cancelled",
+      "final": true
+    },
+    {
+      "id": "Shipped",
+      "phase": "Shipped"
+    },
+    {
+      "id": "Review",
+      "phase": "Review",
+      "via": true
+    },
+    {
+      "id": "Delivered",
+      "phase": "Delivered",
+      "final": true
+    }
+  ],
+  "transitions": [
+    {
+      "from": "Created(Pending)",
+      "to": "Created(Paid)"
+    },
+    {
+      "from": "Created(Pending)",
+      "to": "Cancelled"
+    },
+    {
+      "from": "Created(Paid)",
+      "to": "Shipped",
+      "approval": "required",
+      "via": [
+        "Review"
+      ],
+      "fallback": "Created(Pending)"
+    },
+    {
+      "from": "Shipped",
+      "to": "Delivered"
+    }
+  ]
+} as const;
+
+/** 从 from 出发的合法目标阶段（去重，保持定义顺序） */
+export function orderValidTransitions(from: OrderStage): OrderStage[] {
+  const targets = orderFlow.transitions.filter((t) => t.from === from).map((t) => t.to as OrderStage);
+  return Array.from(new Set(targets));
+}
+
+/** from -> to 是否为合法流转 */
+export function orderCanTransition(from: OrderStage, to: OrderStage): boolean {
+  return orderFlow.transitions.some((t) => t.from === from && t.to === to);
+}
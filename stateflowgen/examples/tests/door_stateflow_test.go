@@ -0,0 +1,124 @@
+// Code generated by gogen. DO NOT EDIT.
+package tests
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// ================ stateflow ================
+
+// doorTest 前缀的表由 @Flow 定义展开，作为测试的预期依据
+var doorTestTransitions = []struct {
+	from, to DoorStage
+}{
+	{from: StageDoorClosed, to: StageDoorOpen},
+	{from: StageDoorClosed, to: StageDoorLocked},
+	{from: StageDoorOpen, to: StageDoorClosed},
+	{from: StageDoorLocked, to: StageDoorClosed},
+}
+
+var doorTestStages = []DoorStage{
+	StageDoorClosed,
+	StageDoorOpen,
+	StageDoorLocked,
+}
+
+var doorTestApprovals = []bool{false}
+
+func doorTestTransition(s DoorState, to DoorStage, _ bool) (DoorState, error) {
+	return s.TransitionTo(to)
+}
+
+// doorTestExpect 按流转表计算 TransitionTo 的预期结果
+func doorTestExpect(s DoorState, to DoorStage, _ bool) (DoorState, error) {
+	for _, tr := range doorTestTransitions {
+		if tr.from == s.Current && tr.to == to {
+			return DoorState{Current: to}, nil
+		}
+	}
+	return s, ErrDoorInvalidTransition
+}
+
+func doorTestEqual(a, b DoorState) bool {
+	return a == b
+}
+
+// TestDoorStateFlow_TransitionTo 枚举所有 (from, to, withApproval) 组合，校验结果与错误
+func TestDoorStateFlow_TransitionTo(t *testing.T) {
+	for _, from := range doorTestStages {
+		for _, to := range doorTestStages {
+			for _, withApproval := range doorTestApprovals {
+				s := DoorState{Current: from}
+				want, wantErr := doorTestExpect(s, to, withApproval)
+				got, err := doorTestTransition(s, to, withApproval)
+				if !errors.Is(err, wantErr) {
+					t.Errorf("%v -> %v: error = %v, want %v", from, to, err, wantErr)
+					continue
+				}
+				if !doorTestEqual(got, want) {
+					t.Errorf("%v -> %v: state = %+v, want %+v", from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestDoorStateFlow_ValidTransitions 校验 ValidTransitions、Next 与流转表一致
+func TestDoorStateFlow_ValidTransitions(t *testing.T) {
+	for _, stage := range doorTestStages {
+		var want []DoorStage
+		for _, tr := range doorTestTransitions {
+			if tr.from == stage && !slices.Contains(want, tr.to) {
+				want = append(want, tr.to)
+			}
+		}
+
+		s := DoorState{Current: stage}
+		if got := s.ValidTransitions(); !slices.Equal(got, want) {
+			t.Errorf("%v: ValidTransitions() = %v, want %v", stage, got, want)
+		}
+		next := s.Next()
+		if len(next) != len(want) {
+			t.Errorf("%v: Next() = %+v, want %v", stage, next, want)
+			continue
+		}
+		for i := range next {
+			if !doorTestEqual(next[i], DoorState{Current: want[i]}) {
+				t.Errorf("%v: Next()[%d] = %+v, want %v", stage, i, next[i], want[i])
+			}
+		}
+	}
+}
+
+// TestDoorStateFlow_RandomWalk 从初始状态随机游走，每一步校验 ValidTransitions
+// 与 TransitionTo 是否成功一致
+func TestDoorStateFlow_RandomWalk(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		s := DoorState{Current: StageDoorClosed}
+		for step := 0; step < 100; step++ {
+			valid := s.ValidTransitions()
+			for _, to := range doorTestStages {
+				_, err := doorTestTransition(s, to, false)
+				if (err == nil) != slices.Contains(valid, to) {
+					t.Fatalf("seed %d step %d: %v -> %v: ValidTransitions() = %v, TransitionTo error = %v", seed, step, s.Current, to, valid, err)
+				}
+			}
+			if len(valid) == 0 {
+				break
+			}
+
+			to := valid[r.Intn(len(valid))]
+			withApproval := r.Intn(2) == 0
+			want, _ := doorTestExpect(s, to, withApproval)
+			next, err := doorTestTransition(s, to, withApproval)
+			if err != nil || !doorTestEqual(next, want) {
+				t.Fatalf("seed %d step %d: %v -> %v = %+v, %v; want %+v", seed, step, s.Current, to, next, err, want)
+			}
+			s = next
+		}
+	}
+}
@@ -0,0 +1,352 @@
+// Code generated by gogen. DO NOT EDIT.
+package tests
+
+import (
+	"errors"
+
+	"gorm.io/datatypes"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                                                            ┌── <COMMIT> ──▶ Shipped ──▶ Delivered
+                                                            │
+                   ┌──▶ Created(Paid) ──▶ Reviewing (via) ──┤
+                   │                                        │
+                   │                                        └── <REJECT> ──▶ Created(Pending) 🔁
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+                   │
+Created(Pending) ──┤
+                   │                                                                                                                ┌── <COMMIT> ──▶ Cancelled 🔁
+                   │                                                                                                                │
+                   │                                                                                         ┌──▶ Reopening (via) ──┤
+                   │                                                                                         │                      │
+                   │                                                                                         │                      └── <REJECT> ──▶ Cancelled 🔁
+                   │                                           ┌── <COMMIT> ──▶ Cancelled ──▶ <?APPROVAL?> ──┤
+                   │                                           │                                             │
+                   │                                           │                                             │
+                   │                                           │                                             └──▶ Cancelled 🔁
+                   │                   ┌──▶ Cancelling (via) ──┤
+                   │                   │                       │
+                   │                   │                       │
+                   │                   │                       │
+                   │                   │                       └── <REJECT> ──▶ Created(Pending) 🔁
+                   └──▶ <?APPROVAL?> ──┤
+                                       │
+                                       │
+                                       │
+                                       │
+                                       └──▶ Cancelled 🔁
+*/
+
+// OrderPhase 阶段枚举
+type OrderPhase string
+
+const (
+	OrderPhaseCreated    OrderPhase = "Created"
+	OrderPhaseCancelled  OrderPhase = "Cancelled"
+	OrderPhaseCancelling OrderPhase = "Cancelling"
+	OrderPhaseShipped    OrderPhase = "Shipped"
+	OrderPhaseReviewing  OrderPhase = "Reviewing"
+	OrderPhaseDelivered  OrderPhase = "Delivered"
+	OrderPhaseReopening  OrderPhase = "Reopening"
+)
+
+var OrderPhaseEnums = struct {
+	Created    OrderPhase
+	Cancelled  OrderPhase
+	Cancelling OrderPhase
+	Shipped    OrderPhase
+	Reviewing  OrderPhase
+	Delivered  OrderPhase
+	Reopening  OrderPhase
+}{
+	Created:    OrderPhaseCreated,
+	Cancelled:  OrderPhaseCancelled,
+	Cancelling: OrderPhaseCancelling,
+	Shipped:    OrderPhaseShipped,
+	Reviewing:  OrderPhaseReviewing,
+	Delivered:  OrderPhaseDelivered,
+	Reopening:  OrderPhaseReopening,
+}
+
+// OrderStatus 状态枚举
+type OrderStatus string
+
+const (
+	OrderStatusNone    OrderStatus = ""
+	OrderStatusPaid    OrderStatus = "Paid"
+	OrderStatusPending OrderStatus = "Pending"
+)
+
+var OrderStatusEnums = struct {
+	None    OrderStatus
+	Paid    OrderStatus
+	Pending OrderStatus
+}{
+	None:    OrderStatusNone,
+	Paid:    OrderStatusPaid,
+	Pending: OrderStatusPending,
+}
+
+// OrderStage 阶段（Phase + Status）
+type OrderStage struct {
+	Phase  OrderPhase  `json:"phase"`
+	Status OrderStatus `json:"status"`
+}
+
+// 预定义阶段
+var (
+	StageOrderCreatedPaid    = OrderStage{OrderPhaseCreated, OrderStatusPaid}
+	StageOrderCreatedPending = OrderStage{OrderPhaseCreated, OrderStatusPending}
+	StageOrderCancelled      = OrderStage{OrderPhaseCancelled, OrderStatusNone}
+	StageOrderCancelling     = OrderStage{OrderPhaseCancelling, OrderStatusNone}
+	StageOrderShipped        = OrderStage{OrderPhaseShipped, OrderStatusNone}
+	StageOrderReviewing      = OrderStage{OrderPhaseReviewing, OrderStatusNone}
+	StageOrderDelivered      = OrderStage{OrderPhaseDelivered, OrderStatusNone}
+	StageOrderReopening      = OrderStage{OrderPhaseReopening, OrderStatusNone}
+)
+
+// OrderPendingTransition 审批事务
+type OrderPendingTransition struct {
+	From     OrderStage `json:"from"`
+	To       OrderStage `json:"to"`
+	Fallback OrderStage `json:"fallback"`
+}
+
+// OrderState 完整状态
+type OrderState struct {
+	Current OrderStage              `json:"current"`
+	Pending *OrderPendingTransition `json:"pending,omitempty"`
+}
+
+// OrderStateColumns 数据库存储结构
+type OrderStateColumns struct {
+	Phase   OrderPhase                                  `gorm:"column:phase" json:"phase"`
+	Status  OrderStatus                                 `gorm:"column:status" json:"status"`
+	Pending datatypes.JSONType[*OrderPendingTransition] `gorm:"column:pending" json:"pending"`
+}
+
+func (s OrderState) ToColumns() OrderStateColumns {
+	return OrderStateColumns{
+		Phase:   s.Current.Phase,
+		Status:  s.Current.Status,
+		Pending: datatypes.NewJSONType(s.Pending),
+	}
+}
+
+func (c OrderStateColumns) ToState() OrderState {
+	return OrderState{
+		Current: OrderStage{Phase: c.Phase, Status: c.Status},
+		Pending: c.Pending.Data(),
+	}
+}
+
+// 错误定义
+var (
+	ErrOrderInvalidTransition  = errors.New("invalid transition")
+	ErrOrderApprovalInProgress = errors.New("approval in progress")
+	ErrOrderNotInApproval      = errors.New("not in approval")
+)
+
+func (s OrderState) TransitionTo(to OrderStage, withApproval bool) (OrderState, error) {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		switch to {
+		case StageOrderShipped:
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: StageOrderReviewing, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCreatedPending}}, nil
+		}
+	case StageOrderCreatedPending:
+		switch to {
+		case StageOrderCreatedPaid:
+			return OrderState{Current: to}, nil
+		case StageOrderCancelled:
+			if withApproval {
+				if s.Pending != nil {
+					return s, ErrOrderApprovalInProgress
+				}
+				return OrderState{Current: StageOrderCancelling, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCreatedPending}}, nil
+			}
+			return OrderState{Current: to}, nil
+		}
+	case StageOrderCancelled:
+		switch to {
+		case StageOrderCancelled:
+			if withApproval {
+				if s.Pending != nil {
+					return s, ErrOrderApprovalInProgress
+				}
+				return OrderState{Current: StageOrderReopening, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderCancelled}}, nil
+			}
+			return OrderState{Current: to}, nil
+		}
+	case StageOrderShipped:
+		switch to {
+		case StageOrderDelivered:
+			return OrderState{Current: to}, nil
+		}
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func (s OrderState) Commit() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.To}, nil
+}
+
+func (s OrderState) Reject() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.Fallback}, nil
+}
+
+func (s OrderState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+func (s OrderState) ValidTransitions() []OrderStage {
+	switch s.Current {
+	case StageOrderCreatedPaid:
+		return []OrderStage{StageOrderShipped}
+	case StageOrderCreatedPending:
+		return []OrderStage{StageOrderCreatedPaid, StageOrderCancelled}
+	case StageOrderCancelled:
+		return []OrderStage{StageOrderCancelled}
+	case StageOrderShipped:
+		return []OrderStage{StageOrderDelivered}
+	}
+	return nil
+}
+
+func (s OrderState) Next() []OrderState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []OrderState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, OrderState{Current: stage})
+	}
+	return result
+}
+
+/* Flowchart:
+         ┌──▶ Open ──▶ Closed 🔁
+         │
+Closed ──┤
+         │
+         └──▶ Locked ──▶ Closed 🔁
+*/
+
+// DoorPhase 阶段枚举
+type DoorPhase string
+
+const (
+	DoorPhaseClosed DoorPhase = "Closed"
+	DoorPhaseOpen   DoorPhase = "Open"
+	DoorPhaseLocked DoorPhase = "Locked"
+)
+
+var DoorPhaseEnums = struct {
+	Closed DoorPhase
+	Open   DoorPhase
+	Locked DoorPhase
+}{
+	Closed: DoorPhaseClosed,
+	Open:   DoorPhaseOpen,
+	Locked: DoorPhaseLocked,
+}
+
+// DoorStage 阶段（Phase + Status）
+type DoorStage = DoorPhase
+
+// 预定义阶段
+var (
+	StageDoorClosed = DoorPhaseClosed
+	StageDoorOpen   = DoorPhaseOpen
+	StageDoorLocked = DoorPhaseLocked
+)
+
+// DoorState 完整状态
+type DoorState struct {
+	Current DoorStage `json:"current"`
+}
+
+// DoorStateColumns 数据库存储结构
+type DoorStateColumns struct {
+	Phase DoorPhase `gorm:"column:phase" json:"phase"`
+}
+
+func (s DoorState) ToColumns() DoorStateColumns {
+	return DoorStateColumns{
+		Phase: s.Current,
+	}
+}
+
+func (c DoorStateColumns) ToState() DoorState {
+	return DoorState{
+		Current: c.Phase,
+	}
+}
+
+// 错误定义
+var ErrDoorInvalidTransition = errors.New("invalid transition")
+
+func (s DoorState) TransitionTo(to DoorStage) (DoorState, error) {
+	switch s.Current {
+	case StageDoorClosed:
+		switch to {
+		case StageDoorOpen:
+			return DoorState{Current: to}, nil
+		case StageDoorLocked:
+			return DoorState{Current: to}, nil
+		}
+	case StageDoorOpen:
+		switch to {
+		case StageDoorClosed:
+			return DoorState{Current: to}, nil
+		}
+	case StageDoorLocked:
+		switch to {
+		case StageDoorClosed:
+			return DoorState{Current: to}, nil
+		}
+	}
+	return s, ErrDoorInvalidTransition
+}
+
+func (s DoorState) ValidTransitions() []DoorStage {
+	switch s.Current {
+	case StageDoorClosed:
+		return []DoorStage{StageDoorOpen, StageDoorLocked}
+	case StageDoorOpen:
+		return []DoorStage{StageDoorClosed}
+	case StageDoorLocked:
+		return []DoorStage{StageDoorClosed}
+	}
+	return nil
+}
+
+func (s DoorState) Next() []DoorState {
+	var result []DoorState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, DoorState{Current: stage})
+	}
+	return result
+}
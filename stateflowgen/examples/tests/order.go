@@ -0,0 +1,18 @@
+package tests
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 生成测试
+// tests=true 额外生成 order_stateflow_test.go，按 @Flow 定义校验生成的状态机
+// @StateFlow(name="Order", tests=true)
+// @Flow: Created(Pending) => [ (Paid), Cancelled? via Cancelling ]
+// @Flow: Created(Paid)    => [ Shipped! via Reviewing else Created(Pending) ]
+// @Flow: Shipped          => [ Delivered ]
+// @Flow: Cancelled        => [ (=)? via Reopening ]
+const _ = ""
+
+// @StateFlow(name="Door", tests=true)
+// @Flow: Closed => [ Open, Locked ]
+// @Flow: Open   => [ Closed ]
+// @Flow: Locked => [ Closed ]
+const _ = ""
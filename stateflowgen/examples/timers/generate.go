@@ -0,0 +1,263 @@
+// Code generated by gogen. DO NOT EDIT.
+package timers
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ================ stateflow ================
+
+/* Flowchart:
+                                                                            ┌──▶ Delivered
+                                                                            │
+                                                 ┌── <COMMIT> ──▶ Shipped ──┤
+                                                 │                          │
+                                                 │                          └──▶ Lost
+                 ┌──▶ Paid ──▶ Reviewing (via) ──┤
+                 │                               │
+                 │                               │
+                 │                               └── <REJECT> ──▶ Paid 🔁
+PendingPayment ──┤
+                 │
+                 │
+                 │
+                 └──▶ Expired
+*/
+
+// OrderPhase 阶段枚举
+type OrderPhase string
+
+const (
+	OrderPhasePendingPayment OrderPhase = "PendingPayment"
+	OrderPhasePaid           OrderPhase = "Paid"
+	OrderPhaseExpired        OrderPhase = "Expired"
+	OrderPhaseShipped        OrderPhase = "Shipped"
+	OrderPhaseReviewing      OrderPhase = "Reviewing"
+	OrderPhaseDelivered      OrderPhase = "Delivered"
+	OrderPhaseLost           OrderPhase = "Lost"
+)
+
+var OrderPhaseEnums = struct {
+	PendingPayment OrderPhase
+	Paid           OrderPhase
+	Expired        OrderPhase
+	Shipped        OrderPhase
+	Reviewing      OrderPhase
+	Delivered      OrderPhase
+	Lost           OrderPhase
+}{
+	PendingPayment: OrderPhasePendingPayment,
+	Paid:           OrderPhasePaid,
+	Expired:        OrderPhaseExpired,
+	Shipped:        OrderPhaseShipped,
+	Reviewing:      OrderPhaseReviewing,
+	Delivered:      OrderPhaseDelivered,
+	Lost:           OrderPhaseLost,
+}
+
+// OrderStage 阶段（Phase + Status）
+type OrderStage = OrderPhase
+
+// 预定义阶段
+var (
+	StageOrderPendingPayment = OrderPhasePendingPayment
+	StageOrderPaid           = OrderPhasePaid
+	StageOrderExpired        = OrderPhaseExpired
+	StageOrderShipped        = OrderPhaseShipped
+	StageOrderReviewing      = OrderPhaseReviewing
+	StageOrderDelivered      = OrderPhaseDelivered
+	StageOrderLost           = OrderPhaseLost
+)
+
+// OrderPendingTransition 审批事务
+type OrderPendingTransition struct {
+	From     OrderStage `json:"from"`
+	To       OrderStage `json:"to"`
+	Fallback OrderStage `json:"fallback"`
+}
+
+// OrderState 完整状态
+type OrderState struct {
+	Current   OrderStage              `json:"current"`
+	Pending   *OrderPendingTransition `json:"pending,omitempty"`
+	EnteredAt time.Time               `json:"entered_at"`
+}
+
+// OrderStateColumns 数据库存储结构
+type OrderStateColumns struct {
+	Phase     OrderPhase                                  `gorm:"column:phase" json:"phase"`
+	Pending   datatypes.JSONType[*OrderPendingTransition] `gorm:"column:pending" json:"pending"`
+	EnteredAt time.Time                                   `gorm:"column:entered_at" json:"entered_at"`
+}
+
+func (s OrderState) ToColumns() OrderStateColumns {
+	return OrderStateColumns{
+		Phase:     s.Current,
+		Pending:   datatypes.NewJSONType(s.Pending),
+		EnteredAt: s.EnteredAt,
+	}
+}
+
+func (c OrderStateColumns) ToState() OrderState {
+	return OrderState{
+		Current:   c.Phase,
+		Pending:   c.Pending.Data(),
+		EnteredAt: c.EnteredAt,
+	}
+}
+
+// 错误定义
+var (
+	ErrOrderInvalidTransition  = errors.New("invalid transition")
+	ErrOrderApprovalInProgress = errors.New("approval in progress")
+	ErrOrderNotInApproval      = errors.New("not in approval")
+)
+
+func (s OrderState) TransitionTo(to OrderStage) (OrderState, error) {
+	switch s.Current {
+	case StageOrderPendingPayment:
+		switch to {
+		case StageOrderPaid:
+			return OrderState{Current: to, EnteredAt: OrderClock.Now()}, nil
+		case StageOrderExpired:
+			return OrderState{Current: to, EnteredAt: OrderClock.Now()}, nil
+		}
+	case StageOrderPaid:
+		switch to {
+		case StageOrderShipped:
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: StageOrderReviewing, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: StageOrderPaid}, EnteredAt: OrderClock.Now()}, nil
+		}
+	case StageOrderShipped:
+		switch to {
+		case StageOrderDelivered:
+			return OrderState{Current: to, EnteredAt: OrderClock.Now()}, nil
+		case StageOrderLost:
+			return OrderState{Current: to, EnteredAt: OrderClock.Now()}, nil
+		}
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func (s OrderState) Commit() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.To, EnteredAt: OrderClock.Now()}, nil
+}
+
+func (s OrderState) Reject() (OrderState, error) {
+	if s.Pending == nil {
+		return s, ErrOrderNotInApproval
+	}
+	return OrderState{Current: s.Pending.Fallback, EnteredAt: OrderClock.Now()}, nil
+}
+
+func (s OrderState) IsApprovalPending() bool {
+	return s.Pending != nil
+}
+
+func (s OrderState) ValidTransitions() []OrderStage {
+	switch s.Current {
+	case StageOrderPendingPayment:
+		return []OrderStage{StageOrderPaid, StageOrderExpired}
+	case StageOrderPaid:
+		return []OrderStage{StageOrderShipped}
+	case StageOrderShipped:
+		return []OrderStage{StageOrderDelivered, StageOrderLost}
+	}
+	return nil
+}
+
+func (s OrderState) Next() []OrderState {
+	if s.Pending != nil {
+		return nil
+	}
+	var result []OrderState
+	for _, stage := range s.ValidTransitions() {
+		result = append(result, OrderState{Current: stage, EnteredAt: OrderClock.Now()})
+	}
+	return result
+}
+
+// OrderClock 记录 EnteredAt 使用的时钟，测试中可替换为 clock.NewFake
+var OrderClock interface{ Now() time.Time } = orderSystemClock{}
+
+type orderSystemClock struct{}
+
+func (orderSystemClock) Now() time.Time { return time.Now() }
+
+// OrderTimedTransition 定时流转（after）
+type OrderTimedTransition struct {
+	To     OrderStage    // 目标阶段（审批超时为拒绝后的回退阶段）
+	After  time.Duration // 进入当前阶段后的等待时长
+	Reject bool          // 审批超时，到期后执行 Reject
+}
+
+// TimedTransitions 返回当前状态上的定时流转，按等待时长升序；审批中时只返回审批超时
+func (s OrderState) TimedTransitions() []OrderTimedTransition {
+	if s.Pending != nil {
+		switch {
+		case s.Pending.From == StageOrderPaid && s.Pending.To == StageOrderShipped:
+			return []OrderTimedTransition{{To: s.Pending.Fallback, After: 72 * time.Hour, Reject: true}}
+		}
+		return nil
+	}
+	switch s.Current {
+	case StageOrderPendingPayment:
+		return []OrderTimedTransition{{To: StageOrderExpired, After: 30 * time.Minute}}
+	case StageOrderShipped:
+		return []OrderTimedTransition{{To: StageOrderLost, After: 336 * time.Hour}}
+	}
+	return nil
+}
+
+// Deadline 返回最早的定时流转到期时间，无法计算时（无定时流转或
+// EnteredAt 为零）返回 false
+func (s OrderState) Deadline() (time.Time, bool) {
+	timers := s.TimedTransitions()
+	if len(timers) == 0 || s.EnteredAt.IsZero() {
+		return time.Time{}, false
+	}
+	return s.EnteredAt.Add(timers[0].After), true
+}
+
+// DueTransitions 返回 now 时已到期的定时流转，按等待时长升序
+func (s OrderState) DueTransitions(now time.Time) []OrderTimedTransition {
+	if s.EnteredAt.IsZero() {
+		return nil
+	}
+	var due []OrderTimedTransition
+	for _, timer := range s.TimedTransitions() {
+		if !now.Before(s.EnteredAt.Add(timer.After)) {
+			due = append(due, timer)
+		}
+	}
+	return due
+}
+
+// ApplyDue 执行 now 时最早到期的定时流转，没有到期时返回 false
+func (s OrderState) ApplyDue(now time.Time) (OrderState, bool, error) {
+	due := s.DueTransitions(now)
+	if len(due) == 0 {
+		return s, false, nil
+	}
+	var next OrderState
+	var err error
+	if due[0].Reject {
+		next, err = s.Reject()
+	} else {
+		next, err = s.TransitionTo(due[0].To)
+	}
+	if err != nil {
+		return s, false, err
+	}
+	// 以到期时间作为新状态的进入时间，调度延迟不会推迟后续定时流转
+	next.EnteredAt = s.EnteredAt.Add(due[0].After)
+	return next, true, nil
+}
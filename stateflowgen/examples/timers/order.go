@@ -0,0 +1,11 @@
+package timers
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 定时流转测试
+// after 为普通目标设置超时自动流转，为审批目标设置审批超时（到期自动拒绝）
+// @StateFlow(name="Order", tests=true)
+// @Flow: PendingPayment => [ Paid, Expired final after 30m ]
+// @Flow: Paid           => [ Shipped! via Reviewing after 72h ]
+// @Flow: Shipped        => [ Delivered final, Lost final after 14d ]
+const _ = ""
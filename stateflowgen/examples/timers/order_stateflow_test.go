@@ -0,0 +1,248 @@
+// Code generated by gogen. DO NOT EDIT.
+package timers
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// ================ stateflow ================
+
+// orderTest 前缀的表由 @Flow 定义展开，作为测试的预期依据
+var orderTestTransitions = []struct {
+	from, to, via, fallback OrderStage
+	required, optional      bool
+}{
+	{from: StageOrderPendingPayment, to: StageOrderPaid},
+	{from: StageOrderPendingPayment, to: StageOrderExpired},
+	{from: StageOrderPaid, to: StageOrderShipped, via: StageOrderReviewing, fallback: StageOrderPaid, required: true},
+	{from: StageOrderShipped, to: StageOrderDelivered},
+	{from: StageOrderShipped, to: StageOrderLost},
+}
+
+var orderTestStages = []OrderStage{
+	StageOrderPendingPayment,
+	StageOrderPaid,
+	StageOrderExpired,
+	StageOrderShipped,
+	StageOrderReviewing,
+	StageOrderDelivered,
+	StageOrderLost,
+}
+
+var orderTestApprovals = []bool{false}
+
+func orderTestTransition(s OrderState, to OrderStage, _ bool) (OrderState, error) {
+	return s.TransitionTo(to)
+}
+
+// orderTestExpect 按流转表计算 TransitionTo 的预期结果
+func orderTestExpect(s OrderState, to OrderStage, withApproval bool) (OrderState, error) {
+	for _, tr := range orderTestTransitions {
+		if tr.from != s.Current || tr.to != to {
+			continue
+		}
+		if tr.required || (tr.optional && withApproval) {
+			if s.Pending != nil {
+				return s, ErrOrderApprovalInProgress
+			}
+			return OrderState{Current: tr.via, Pending: &OrderPendingTransition{From: s.Current, To: to, Fallback: tr.fallback}}, nil
+		}
+		return OrderState{Current: to}, nil
+	}
+	return s, ErrOrderInvalidTransition
+}
+
+func orderTestEqual(a, b OrderState) bool {
+	if a.Current != b.Current || (a.Pending == nil) != (b.Pending == nil) {
+		return false
+	}
+	return a.Pending == nil || *a.Pending == *b.Pending
+}
+
+// TestOrderStateFlow_TransitionTo 枚举所有 (from, to, withApproval) 组合，校验结果与错误
+func TestOrderStateFlow_TransitionTo(t *testing.T) {
+	for _, from := range orderTestStages {
+		for _, pending := range []*OrderPendingTransition{nil, {From: from, To: from, Fallback: from}} {
+			for _, to := range orderTestStages {
+				for _, withApproval := range orderTestApprovals {
+					s := OrderState{Current: from, Pending: pending}
+					want, wantErr := orderTestExpect(s, to, withApproval)
+					got, err := orderTestTransition(s, to, withApproval)
+					if !errors.Is(err, wantErr) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): error = %v, want %v", from, to, withApproval, pending != nil, err, wantErr)
+						continue
+					}
+					if !orderTestEqual(got, want) {
+						t.Errorf("%v -> %v (withApproval=%v, pending=%v): state = %+v, want %+v", from, to, withApproval, pending != nil, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestOrderStateFlow_CommitReject 校验每条审批流转的提交、拒绝结果，以及非审批状态下的
+// NotInApproval
+func TestOrderStateFlow_CommitReject(t *testing.T) {
+	for _, tr := range orderTestTransitions {
+		if !tr.required && !tr.optional {
+			continue
+		}
+		s, err := orderTestTransition(OrderState{Current: tr.from}, tr.to, true)
+		if err != nil {
+			t.Errorf("%v -> %v: TransitionTo error = %v", tr.from, tr.to, err)
+			continue
+		}
+		if !s.IsApprovalPending() || s.Current != tr.via {
+			t.Errorf("%v -> %v: state = %+v, want pending in %v", tr.from, tr.to, s, tr.via)
+			continue
+		}
+
+		committed, err := s.Commit()
+		if err != nil || !orderTestEqual(committed, OrderState{Current: tr.to}) {
+			t.Errorf("%v -> %v: Commit() = %+v, %v; want %v", tr.from, tr.to, committed, err, tr.to)
+		}
+		rejected, err := s.Reject()
+		if err != nil || !orderTestEqual(rejected, OrderState{Current: tr.fallback}) {
+			t.Errorf("%v -> %v: Reject() = %+v, %v; want %v", tr.from, tr.to, rejected, err, tr.fallback)
+		}
+	}
+
+	for _, stage := range orderTestStages {
+		s := OrderState{Current: stage}
+		if got, err := s.Commit(); !errors.Is(err, ErrOrderNotInApproval) || !orderTestEqual(got, s) {
+			t.Errorf("%v: Commit() = %+v, %v; want %v", stage, got, err, ErrOrderNotInApproval)
+		}
+		if got, err := s.Reject(); !errors.Is(err, ErrOrderNotInApproval) || !orderTestEqual(got, s) {
+			t.Errorf("%v: Reject() = %+v, %v; want %v", stage, got, err, ErrOrderNotInApproval)
+		}
+	}
+}
+
+// TestOrderStateFlow_ValidTransitions 校验 ValidTransitions、Next 与流转表一致
+func TestOrderStateFlow_ValidTransitions(t *testing.T) {
+	for _, stage := range orderTestStages {
+		var want []OrderStage
+		for _, tr := range orderTestTransitions {
+			if tr.from == stage && !slices.Contains(want, tr.to) {
+				want = append(want, tr.to)
+			}
+		}
+
+		s := OrderState{Current: stage}
+		if got := s.ValidTransitions(); !slices.Equal(got, want) {
+			t.Errorf("%v: ValidTransitions() = %v, want %v", stage, got, want)
+		}
+		next := s.Next()
+		if len(next) != len(want) {
+			t.Errorf("%v: Next() = %+v, want %v", stage, next, want)
+			continue
+		}
+		for i := range next {
+			if !orderTestEqual(next[i], OrderState{Current: want[i]}) {
+				t.Errorf("%v: Next()[%d] = %+v, want %v", stage, i, next[i], want[i])
+			}
+		}
+		pending := OrderState{Current: stage, Pending: &OrderPendingTransition{From: stage, To: stage, Fallback: stage}}
+		if next := pending.Next(); next != nil {
+			t.Errorf("%v: Next() with pending approval = %+v, want nil", stage, next)
+		}
+	}
+}
+
+// TestOrderStateFlow_RandomWalk 从初始状态随机游走，每一步校验 ValidTransitions
+// 与 TransitionTo 是否成功一致
+func TestOrderStateFlow_RandomWalk(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		s := OrderState{Current: StageOrderPendingPayment}
+		for step := 0; step < 100; step++ {
+			if s.Pending != nil {
+				want := OrderState{Current: s.Pending.To}
+				next, err := s.Commit()
+				if r.Intn(2) == 0 {
+					want = OrderState{Current: s.Pending.Fallback}
+					next, err = s.Reject()
+				}
+				if err != nil || !orderTestEqual(next, want) {
+					t.Fatalf("seed %d step %d: resolve %+v = %+v, %v; want %+v", seed, step, s, next, err, want)
+				}
+				s = next
+				continue
+			}
+
+			valid := s.ValidTransitions()
+			for _, to := range orderTestStages {
+				_, err := orderTestTransition(s, to, false)
+				if (err == nil) != slices.Contains(valid, to) {
+					t.Fatalf("seed %d step %d: %v -> %v: ValidTransitions() = %v, TransitionTo error = %v", seed, step, s.Current, to, valid, err)
+				}
+			}
+			if len(valid) == 0 {
+				break
+			}
+
+			to := valid[r.Intn(len(valid))]
+			withApproval := r.Intn(2) == 0
+			want, _ := orderTestExpect(s, to, withApproval)
+			next, err := orderTestTransition(s, to, withApproval)
+			if err != nil || !orderTestEqual(next, want) {
+				t.Fatalf("seed %d step %d: %v -> %v = %+v, %v; want %+v", seed, step, s.Current, to, next, err, want)
+			}
+			s = next
+		}
+	}
+}
+
+var orderTestTimers = []struct {
+	from, to, via, fallback OrderStage
+	after                   time.Duration
+	reject                  bool
+}{
+	{from: StageOrderPendingPayment, to: StageOrderExpired, after: 30 * time.Minute},
+	{from: StageOrderPaid, to: StageOrderShipped, after: 72 * time.Hour, via: StageOrderReviewing, fallback: StageOrderPaid, reject: true},
+	{from: StageOrderShipped, to: StageOrderLost, after: 336 * time.Hour},
+}
+
+// TestOrderStateFlow_Timers 校验每条定时流转在到期前不触发、到期时出现在
+// DueTransitions 中并可由 ApplyDue 执行
+func TestOrderStateFlow_Timers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tr := range orderTestTimers {
+		s, want := OrderState{Current: tr.from, EnteredAt: start}, tr.to
+		if tr.reject {
+			s = OrderState{Current: tr.via, Pending: &OrderPendingTransition{From: tr.from, To: tr.to, Fallback: tr.fallback}, EnteredAt: start}
+			want = tr.fallback
+		}
+		match := func(timer OrderTimedTransition) bool {
+			return timer.To == want && timer.After == tr.after
+		}
+
+		if deadline, ok := s.Deadline(); !ok || deadline.After(start.Add(tr.after)) {
+			t.Errorf("%v -> %v: Deadline() = %v, %v; want no later than %v", tr.from, want, deadline, ok, start.Add(tr.after))
+		}
+		if due := s.DueTransitions(start.Add(tr.after - time.Nanosecond)); slices.ContainsFunc(due, match) {
+			t.Errorf("%v -> %v: due before %v", tr.from, want, tr.after)
+		}
+		due := s.DueTransitions(start.Add(tr.after))
+		if !slices.ContainsFunc(due, match) {
+			t.Errorf("%v -> %v: DueTransitions() = %+v, want it due after %v", tr.from, want, due, tr.after)
+			continue
+		}
+		if !match(due[0]) {
+			continue
+		}
+		next, ok, err := s.ApplyDue(start.Add(tr.after))
+		if err != nil || !ok || next.Current != want || !next.EnteredAt.Equal(start.Add(tr.after)) {
+			t.Errorf("%v -> %v: ApplyDue() = %+v, %v, %v", tr.from, want, next, ok, err)
+		}
+	}
+
+	if _, ok := (OrderState{Current: StageOrderPendingPayment}).Deadline(); ok {
+		t.Error("Deadline() without EnteredAt should report false")
+	}
+}
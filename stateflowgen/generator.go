@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/donutnomad/gg"
+	"github.com/donutnomad/gogen/internal/utils"
 	"github.com/donutnomad/gogen/plugin"
 )
 
@@ -82,6 +83,41 @@ func (g *StateFlowGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.Gene
 
 			result.AddDefinition(outputPath, gen)
 
+			// 独立流程图文件（ascii 仅保留代码中的注释）
+			if modelInfo.diagram != "" && modelInfo.diagram != DiagramASCII {
+				content, err := NewFlowGraph(modelInfo.model).Render(modelInfo.diagram)
+				if err != nil {
+					result.AddError(fmt.Errorf("生成 %s 流程图失败: %w", modelInfo.model.Name, err))
+					continue
+				}
+				result.AddFileOutput(diagramOutputPath(outputPath, modelInfo.model.Name, modelInfo.diagram), []byte(content))
+			}
+
+			// 状态机定义导出（schema=json,ts）
+			for _, format := range modelInfo.schema {
+				content, err := NewFlowSchema(modelInfo.model).Render(format)
+				if err != nil {
+					result.AddError(fmt.Errorf("导出 %s 定义失败: %w", modelInfo.model.Name, err))
+					continue
+				}
+				result.AddFileOutput(schemaOutputPath(outputPath, modelInfo.model.Name, format), content)
+			}
+
+			// 数据库约束与允许流转表（sql=mysql|postgres）
+			if modelInfo.model.SQL != "" {
+				result.AddFileOutput(sqlOutputPath(outputPath, modelInfo.model.Name), []byte(modelInfo.model.RenderSQL()))
+			}
+
+			// 测试文件（tests=true）
+			if modelInfo.tests {
+				testGen, err := NewTestCodeGenerator(modelInfo.model, modelInfo.packageName).Generate()
+				if err != nil {
+					result.AddError(fmt.Errorf("生成 %s 测试失败: %w", modelInfo.model.Name, err))
+					continue
+				}
+				result.AddDefinition(testOutputPath(outputPath, modelInfo.model.Name), testGen)
+			}
+
 			if ctx.Verbose {
 				fmt.Printf("[stateflow] 处理 %s -> %s\n", modelInfo.model.Name, outputPath)
 			}
@@ -97,6 +133,9 @@ type modelInfo struct {
 	target      *plugin.AnnotatedTarget
 	ann         *plugin.Annotation
 	packageName string
+	diagram     DiagramFormat
+	schema      []SchemaFormat
+	tests       bool
 }
 
 // parseStateFlowsFromFile 从文件中解析所有 StateFlow 定义
@@ -117,21 +156,33 @@ func (g *StateFlowGenerator) parseStateFlowsFromFile(filePath string, targets []
 		}
 
 		// 查找包含完整注解的注释组
-		commentText := g.findFullComment(file, at.Target.Position, fset)
-		if commentText == "" {
+		cg := g.findFullComment(file, at.Target.Position, fset)
+		if cg == nil {
 			return nil, fmt.Errorf("无法找到 %s 的注释", at.Target.Name)
 		}
+		text, startLine := commentText(fset, cg)
 
 		// 解析 StateFlow 配置和规则
-		config, rules, err := ParseFlowAnnotations(commentText)
+		config, rules, err := ParseFlowAnnotations(text)
 		if err != nil {
 			return nil, fmt.Errorf("解析 StateFlow 注解失败: %w", err)
 		}
+		shiftRuleLines(rules, startLine)
 
 		if config == nil {
 			return nil, fmt.Errorf("未找到 @StateFlow 配置")
 		}
 
+		diagram, err := ParseDiagramFormat(config.Diagram)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := ParseSchemaFormats(config.Schema)
+		if err != nil {
+			return nil, err
+		}
+
 		// 如果没有指定 name，保留为空字符串
 		// 这样生成的类型名称将是 Phase, State, Stage 等，没有前缀
 
@@ -140,20 +191,24 @@ func (g *StateFlowGenerator) parseStateFlowsFromFile(filePath string, targets []
 		if err != nil {
 			return nil, fmt.Errorf("构建状态模型失败: %w", err)
 		}
+		printLintWarnings(filePath, LintModel(model, rules))
 
 		models = append(models, &modelInfo{
 			model:       model,
 			target:      at,
 			ann:         ann,
 			packageName: file.Name.Name,
+			diagram:     diagram,
+			schema:      schema,
+			tests:       config.Tests,
 		})
 	}
 
 	return models, nil
 }
 
-// findFullComment 查找目标位置的完整注释
-func (g *StateFlowGenerator) findFullComment(file *ast.File, pos token.Pos, fset *token.FileSet) string {
+// findFullComment 查找目标位置的完整注释组
+func (g *StateFlowGenerator) findFullComment(file *ast.File, pos token.Pos, fset *token.FileSet) *ast.CommentGroup {
 	targetLine := fset.Position(pos).Line
 
 	// 查找最近的注释组
@@ -174,17 +229,13 @@ func (g *StateFlowGenerator) findFullComment(file *ast.File, pos token.Pos, fset
 		if genDecl, ok := decl.(*ast.GenDecl); ok {
 			if fset.Position(genDecl.Pos()).Line == targetLine || fset.Position(genDecl.Pos()).Line == targetLine+1 {
 				if genDecl.Doc != nil {
-					return genDecl.Doc.Text()
+					return genDecl.Doc
 				}
 			}
 		}
 	}
 
-	if bestComment != nil {
-		return bestComment.Text()
-	}
-
-	return ""
+	return bestComment
 }
 
 // generateCode 生成代码
@@ -193,6 +244,34 @@ func (g *StateFlowGenerator) generateCode(model *StateModel, packageName string)
 	return cg.Generate()
 }
 
+// diagramOutputPath 计算独立流程图文件路径，与生成的 Go 文件位于同一目录
+func diagramOutputPath(goOutput, name string, format DiagramFormat) string {
+	return siblingOutputPath(goOutput, name, format.Ext())
+}
+
+// schemaOutputPath 计算状态机定义导出文件路径，与生成的 Go 文件位于同一目录
+func schemaOutputPath(goOutput, name string, format SchemaFormat) string {
+	return siblingOutputPath(goOutput, name, format.Ext())
+}
+
+// sqlOutputPath 计算 sql=mysql|postgres 生成的 SQL 文件路径，与生成的 Go 文件位于同一目录
+func sqlOutputPath(goOutput, name string) string {
+	return siblingOutputPath(goOutput, name, ".sql")
+}
+
+// testOutputPath 计算 tests=true 生成的测试文件路径，与生成的 Go 文件位于同一目录
+func testOutputPath(goOutput, name string) string {
+	return siblingOutputPath(goOutput, name, "_test.go")
+}
+
+// siblingOutputPath 有 name 时为 <name>_stateflow<suffix>，否则与 Go 文件同名
+func siblingOutputPath(goOutput, name, suffix string) string {
+	if name == "" {
+		return strings.TrimSuffix(goOutput, ".go") + suffix
+	}
+	return filepath.Join(filepath.Dir(goOutput), utils.ToSnakeCase(name)+"_stateflow"+suffix)
+}
+
 // GetOutputPath 计算输出路径
 func GetOutputPath(target *plugin.Target, ann *plugin.Annotation, defaultPattern string, fileConfig *plugin.PackageConfig, pluginName string, cmdDefault string) string {
 	// 优先使用注解参数
@@ -59,12 +59,30 @@ func (g *StateFlowV2Generator) Generate(ctx *plugin.GenerateContext) (*plugin.Ge
 			fileConfig := ctx.GetFileConfig(filePath)
 			outputPath := plugin.GetOutputPath(modelInfo.target.Target, modelInfo.ann, "$FILE_stateflow_v2.go", fileConfig, g.Name(), ctx.DefaultOutput)
 
-			gen, err := g.generateCode(modelInfo.model, modelInfo.packageName)
+			gen, err := g.generateCode(modelInfo.model, modelInfo.packageName, modelInfo.diagram == DiagramASCII)
 			if err != nil {
 				result.AddError(fmt.Errorf("generate %s failed: %w", modelInfo.model.Name, err))
 				continue
 			}
 			result.AddDefinition(outputPath, gen)
+
+			if modelInfo.diagram != "" && modelInfo.diagram != DiagramASCII {
+				content, err := NewFlowGraphV2(modelInfo.model).Render(modelInfo.diagram)
+				if err != nil {
+					result.AddError(fmt.Errorf("render %s diagram failed: %w", modelInfo.model.Name, err))
+					continue
+				}
+				result.AddFileOutput(diagramOutputPath(outputPath, modelInfo.model.Name, modelInfo.diagram), []byte(content))
+			}
+
+			for _, format := range modelInfo.schema {
+				content, err := NewFlowSchemaV2(modelInfo.model).Render(format)
+				if err != nil {
+					result.AddError(fmt.Errorf("export %s schema failed: %w", modelInfo.model.Name, err))
+					continue
+				}
+				result.AddFileOutput(schemaOutputPath(outputPath, modelInfo.model.Name, format), content)
+			}
 		}
 	}
 
@@ -76,6 +94,8 @@ type modelV2Info struct {
 	target      *plugin.AnnotatedTarget
 	ann         *plugin.Annotation
 	packageName string
+	diagram     DiagramFormat
+	schema      []SchemaFormat
 }
 
 func (g *StateFlowV2Generator) parseStateFlowV2FromFile(filePath string, targets []*plugin.AnnotatedTarget) ([]*modelV2Info, error) {
@@ -92,40 +112,57 @@ func (g *StateFlowV2Generator) parseStateFlowV2FromFile(filePath string, targets
 			continue
 		}
 
-		commentText := findFullCommentV2(file, at.Target.Position, fset)
-		if commentText == "" {
+		cg := findFullCommentV2(file, at.Target.Position, fset)
+		if cg == nil {
 			return nil, fmt.Errorf("comment not found for %s", at.Target.Name)
 		}
+		text, startLine := commentText(fset, cg)
 
-		config, rules, err := ParseFlowV2Annotations(commentText)
+		config, rules, err := ParseFlowV2Annotations(text)
 		if err != nil {
 			return nil, err
 		}
+		shiftRuleLines(rules, startLine)
 		if config == nil {
 			return nil, fmt.Errorf("@StateFlowV2 config not found")
 		}
 
+		diagram, err := ParseDiagramFormat(config.Diagram)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := ParseSchemaFormats(config.Schema)
+		if err != nil {
+			return nil, err
+		}
+
 		model, err := BuildStateFlowV2Model(config, rules)
 		if err != nil {
 			return nil, err
 		}
+		printLintWarnings(filePath, LintModelV2(model, rules))
 
 		models = append(models, &modelV2Info{
 			model:       model,
 			target:      at,
 			ann:         ann,
 			packageName: file.Name.Name,
+			diagram:     diagram,
+			schema:      schema,
 		})
 	}
 
 	return models, nil
 }
 
-func (g *StateFlowV2Generator) generateCode(model *StateFlowV2Model, packageName string) (*gg.Generator, error) {
-	return NewCodeGeneratorV2(model, packageName).Generate()
+func (g *StateFlowV2Generator) generateCode(model *StateFlowV2Model, packageName string, asciiDiagram bool) (*gg.Generator, error) {
+	cg := NewCodeGeneratorV2(model, packageName)
+	cg.ASCIIDiagram = asciiDiagram
+	return cg.Generate()
 }
 
-func findFullCommentV2(file *ast.File, pos token.Pos, fset *token.FileSet) string {
+func findFullCommentV2(file *ast.File, pos token.Pos, fset *token.FileSet) *ast.CommentGroup {
 	targetLine := fset.Position(pos).Line
 
 	var bestComment *ast.CommentGroup
@@ -142,14 +179,11 @@ func findFullCommentV2(file *ast.File, pos token.Pos, fset *token.FileSet) strin
 		if genDecl, ok := decl.(*ast.GenDecl); ok {
 			if fset.Position(genDecl.Pos()).Line == targetLine || fset.Position(genDecl.Pos()).Line == targetLine+1 {
 				if genDecl.Doc != nil {
-					return genDecl.Doc.Text()
+					return genDecl.Doc
 				}
 			}
 		}
 	}
 
-	if bestComment != nil {
-		return bestComment.Text()
-	}
-	return ""
+	return bestComment
 }
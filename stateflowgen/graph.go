@@ -0,0 +1,436 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiagramFormat 流程图输出格式
+type DiagramFormat string
+
+const (
+	DiagramASCII    DiagramFormat = "ascii"    // 生成代码顶部的 /* Flowchart: */ 注释
+	DiagramMermaid  DiagramFormat = "mermaid"  // .mmd
+	DiagramDOT      DiagramFormat = "dot"      // Graphviz .dot
+	DiagramPlantUML DiagramFormat = "plantuml" // .puml
+)
+
+// ParseDiagramFormat 解析 diagram 参数，空字符串表示未指定
+func ParseDiagramFormat(s string) (DiagramFormat, error) {
+	switch f := DiagramFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", DiagramASCII, DiagramMermaid, DiagramDOT, DiagramPlantUML:
+		return f, nil
+	case "mmd":
+		return DiagramMermaid, nil
+	case "graphviz", "gv":
+		return DiagramDOT, nil
+	case "puml":
+		return DiagramPlantUML, nil
+	}
+	return "", fmt.Errorf("unsupported diagram format %q (want mermaid, dot, plantuml or ascii)", s)
+}
+
+// Ext 返回独立流程图文件的扩展名
+func (f DiagramFormat) Ext() string {
+	switch f {
+	case DiagramMermaid:
+		return ".mmd"
+	case DiagramDOT:
+		return ".dot"
+	case DiagramPlantUML:
+		return ".puml"
+	default:
+		return ".txt"
+	}
+}
+
+// EdgeKind 流程图中边的类型
+type EdgeKind string
+
+const (
+	EdgeDirect   EdgeKind = "direct"   // 直接流转
+	EdgeApproval EdgeKind = "approval" // 发起审批，进入 via 中间状态
+	EdgeCommit   EdgeKind = "commit"   // 审批通过，前往目标状态
+	EdgeApprove  EdgeKind = "approve"  // 多级审批中本级通过，前往下一级
+	EdgeReject   EdgeKind = "reject"   // 审批拒绝，回退到源状态
+	EdgeElse     EdgeKind = "else"     // 审批拒绝，前往 else 指定的状态
+	EdgeDone     EdgeKind = "done"     // 复合状态所有区域到达终态后的完成流转
+)
+
+// FlowGraph 与输出格式无关的流程图
+// 由 StateModel 或 StateFlowV2Model 构建，再渲染为 mermaid/dot/plantuml/ascii
+type FlowGraph struct {
+	Name  string
+	Init  string // 初始节点 ID
+	Nodes []FlowNode
+	Edges []FlowEdge
+
+	transitions []Transition // ASCII 渲染使用
+}
+
+// FlowNode 流程图节点
+type FlowNode struct {
+	ID    string
+	Label string
+	Via   bool // via 中间状态（每条审批流转一个节点）
+}
+
+// FlowEdge 流程图的边
+type FlowEdge struct {
+	From     string
+	To       string
+	Kind     EdgeKind
+	Optional bool          // 来自 ? 可选审批
+	Wildcard bool          // 来自通配符 (*) 展开
+	Event    string        // on 事件名
+	Guard    string        // if 守卫名
+	After    time.Duration // after 定时（审批边为审批超时）
+	Quorum   int           // 本级审批需要的不同审批人数（quorum N）
+}
+
+// Label 返回边上显示的文字
+func (e FlowEdge) Label() string {
+	var label string
+	switch e.Kind {
+	case EdgeApproval:
+		label = "! approval"
+		if e.Optional {
+			label = "? approval"
+		}
+	case EdgeCommit:
+		label = "commit"
+	case EdgeApprove:
+		label = "approve"
+	case EdgeReject:
+		label = "reject"
+	case EdgeElse:
+		label = "else"
+	case EdgeDone:
+		label = "done"
+	default:
+		if e.Optional {
+			label = "without approval"
+		}
+	}
+	if e.Event != "" {
+		trigger := e.Event
+		if e.Guard != "" {
+			trigger += " [" + e.Guard + "]"
+		}
+		label = strings.TrimSpace(trigger + " " + label)
+	}
+	if e.Quorum > 1 {
+		label = strings.TrimSpace(label + " quorum " + strconv.Itoa(e.Quorum))
+	}
+	if e.After > 0 {
+		label = strings.TrimSpace(label + " after " + formatAfter(e.After))
+	}
+	if e.Wildcard {
+		label = strings.TrimSpace("* " + label)
+	}
+	return label
+}
+
+// NewFlowGraph 从 @StateFlow 模型构建流程图
+func NewFlowGraph(model *StateModel) *FlowGraph {
+	return buildFlowGraph(model.Name, model.InitStage, model.GetAllStages(), model.ViaPhases, model.flowTransitions())
+}
+
+// NewFlowGraphV2 从 @StateFlowV2 模型构建流程图
+func NewFlowGraphV2(model *StateFlowV2Model) *FlowGraph {
+	stages := make([]Stage, 0, len(model.Statuses))
+	for _, status := range model.Statuses {
+		stages = append(stages, Stage{Phase: status})
+	}
+	return buildFlowGraph(model.Name, Stage{Phase: model.InitStatus}, stages, nil, model.stageTransitions())
+}
+
+// stageTransitions 将 V2 流转转换为 Transition，以便复用流程图渲染
+func (m *StateFlowV2Model) stageTransitions() []Transition {
+	transitions := make([]Transition, 0, len(m.Transitions))
+	for _, t := range m.Transitions {
+		trans := Transition{
+			From:             Stage{Phase: t.From},
+			To:               Stage{Phase: t.To},
+			ApprovalRequired: t.ApprovalRequired,
+			ApprovalOptional: t.ApprovalOptional,
+			Self:             t.Self,
+			Line:             t.Line,
+		}
+		if t.ApprovalRequired || t.ApprovalOptional {
+			trans.Via = Stage{Phase: t.Via}
+			trans.Fallback = Stage{Phase: t.Fallback}
+		}
+		transitions = append(transitions, trans)
+	}
+	return transitions
+}
+
+func buildFlowGraph(name string, init Stage, stages []Stage, viaPhases []string, transitions []Transition) *FlowGraph {
+	g := &FlowGraph{Name: name, transitions: transitions}
+
+	// via Phase 只有在作为普通状态出现时才单独成为节点
+	used := make(map[Stage]bool)
+	for _, trans := range transitions {
+		used[trans.From] = true
+		used[trans.To] = true
+		if trans.Fallback.Phase != "" {
+			used[trans.Fallback] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	addStage := func(stage Stage) string {
+		id := stageNodeID(stage)
+		if !seen[id] {
+			seen[id] = true
+			g.Nodes = append(g.Nodes, FlowNode{ID: id, Label: stage.String()})
+		}
+		return id
+	}
+
+	g.Init = addStage(init)
+	for _, stage := range stages {
+		if slices.Contains(viaPhases, stage.Phase) && !used[stage] {
+			continue
+		}
+		addStage(stage)
+	}
+
+	viaNodes := make(map[string]string) // from|to|via -> 节点 ID
+	for _, trans := range transitions {
+		from := addStage(trans.From)
+		to := addStage(trans.To)
+
+		if trans.Completion {
+			g.Edges = append(g.Edges, FlowEdge{From: from, To: to, Kind: EdgeDone})
+			continue
+		}
+		if trans.Via.Phase == "" {
+			g.Edges = append(g.Edges, FlowEdge{From: from, To: to, Kind: EdgeDirect, Wildcard: trans.Wildcard, Event: trans.Event, Guard: trans.Guard, After: trans.After})
+			continue
+		}
+
+		// 多级审批的每一级各自成为一个 via 节点，逐级通过，任一级都可拒绝
+		var vias []string
+		for _, step := range trans.ApprovalSteps() {
+			key := from + "|" + to + "|" + step.String()
+			via, ok := viaNodes[key]
+			if !ok {
+				via = fmt.Sprintf("via_%d", len(viaNodes)+1)
+				viaNodes[key] = via
+				g.Nodes = append(g.Nodes, FlowNode{ID: via, Label: step.String(), Via: true})
+			}
+			vias = append(vias, via)
+		}
+
+		g.Edges = append(g.Edges, FlowEdge{From: from, To: vias[0], Kind: EdgeApproval, Optional: trans.ApprovalOptional, Wildcard: trans.Wildcard, Event: trans.Event, Guard: trans.Guard})
+		for i, via := range vias {
+			next, kind := to, EdgeCommit
+			if i+1 < len(vias) {
+				next, kind = vias[i+1], EdgeApprove
+			}
+			g.Edges = append(g.Edges, FlowEdge{From: via, To: next, Kind: kind, Wildcard: trans.Wildcard, Quorum: trans.Quorum})
+		}
+		if trans.Fallback.Phase != "" {
+			kind := EdgeReject
+			if !trans.Fallback.Equal(trans.From) {
+				kind = EdgeElse
+			}
+			fallback := addStage(trans.Fallback)
+			for _, via := range vias {
+				g.Edges = append(g.Edges, FlowEdge{From: via, To: fallback, Kind: kind, Wildcard: trans.Wildcard, After: trans.After})
+			}
+		}
+		if trans.ApprovalOptional {
+			g.Edges = append(g.Edges, FlowEdge{From: from, To: to, Kind: EdgeDirect, Optional: true, Wildcard: trans.Wildcard, Event: trans.Event, Guard: trans.Guard})
+		}
+	}
+
+	return g
+}
+
+// stageNodeID 生成各格式通用的节点 ID（避免 mermaid 的 end 等保留字）
+func stageNodeID(stage Stage) string {
+	id := "s_" + stage.Phase
+	if stage.Status != "" {
+		id += "_" + stage.Status
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// Render 按指定格式渲染流程图
+func (g *FlowGraph) Render(format DiagramFormat) (string, error) {
+	switch format {
+	case DiagramMermaid:
+		return g.RenderMermaid(), nil
+	case DiagramDOT:
+		return g.RenderDOT(), nil
+	case DiagramPlantUML:
+		return g.RenderPlantUML(), nil
+	case DiagramASCII, "":
+		return newFlowRenderer(g.transitions).Render() + "\n", nil
+	}
+	return "", fmt.Errorf("unsupported diagram format %q", format)
+}
+
+// title 返回流程图标题
+func (g *FlowGraph) title() string {
+	if g.Name == "" {
+		return "StateFlow"
+	}
+	return g.Name
+}
+
+// RenderMermaid 渲染为 mermaid flowchart
+//   - 审批边使用粗箭头 ==>，拒绝/else 使用虚线 -.->
+//   - via 节点为六边形并使用虚线边框
+//   - 通配符展开的边以 * 标注并着色
+func (g *FlowGraph) RenderMermaid() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "---\ntitle: %s\n---\nflowchart LR\n", g.title())
+
+	for _, n := range g.Nodes {
+		if n.Via {
+			fmt.Fprintf(&sb, "    %s{{%q}}\n", n.ID, n.Label)
+		} else {
+			fmt.Fprintf(&sb, "    %s[%q]\n", n.ID, n.Label)
+		}
+	}
+
+	var wildcardLinks []string
+	for i, e := range g.Edges {
+		arrow := "-->"
+		switch e.Kind {
+		case EdgeApproval:
+			arrow = "==>"
+		case EdgeReject, EdgeElse:
+			arrow = "-.->"
+		}
+		if label := e.Label(); label != "" {
+			fmt.Fprintf(&sb, "    %s %s|%q| %s\n", e.From, arrow, label, e.To)
+		} else {
+			fmt.Fprintf(&sb, "    %s %s %s\n", e.From, arrow, e.To)
+		}
+		if e.Wildcard {
+			wildcardLinks = append(wildcardLinks, strconv.Itoa(i))
+		}
+	}
+
+	sb.WriteString("    classDef initial stroke-width:3px\n")
+	sb.WriteString("    classDef via stroke-dasharray:5 5,fill:#fff8dc\n")
+	fmt.Fprintf(&sb, "    class %s initial\n", g.Init)
+	if vias := g.viaNodeIDs(); len(vias) > 0 {
+		fmt.Fprintf(&sb, "    class %s via\n", strings.Join(vias, ","))
+	}
+	if len(wildcardLinks) > 0 {
+		fmt.Fprintf(&sb, "    linkStyle %s stroke:#d9822b,color:#d9822b\n", strings.Join(wildcardLinks, ","))
+	}
+	return sb.String()
+}
+
+// RenderDOT 渲染为 Graphviz dot
+func (g *FlowGraph) RenderDOT() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", g.title())
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=box, style=rounded];\n")
+
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + strconv.Quote(n.Label)}
+		if n.Via {
+			attrs = append(attrs, "shape=hexagon", `style="dashed"`)
+		}
+		if n.ID == g.Init {
+			attrs = append(attrs, "penwidth=2")
+		}
+		fmt.Fprintf(&sb, "    %q [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if label := e.Label(); label != "" {
+			attrs = append(attrs, "label="+strconv.Quote(label))
+		}
+		switch e.Kind {
+		case EdgeApproval:
+			attrs = append(attrs, "style=bold")
+		case EdgeReject:
+			attrs = append(attrs, "style=dashed")
+		case EdgeElse:
+			attrs = append(attrs, "style=dashed", "arrowhead=empty")
+		}
+		if e.Wildcard {
+			attrs = append(attrs, `color="darkorange"`, `fontcolor="darkorange"`)
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&sb, "    %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&sb, "    %q -> %q;\n", e.From, e.To)
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// RenderPlantUML 渲染为 PlantUML 状态图
+func (g *FlowGraph) RenderPlantUML() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@startuml %s\n", g.title())
+	sb.WriteString("hide empty description\n")
+	sb.WriteString("left to right direction\n")
+
+	for _, n := range g.Nodes {
+		if n.Via {
+			fmt.Fprintf(&sb, "state %q as %s <<via>> #line.dashed\n", n.Label, n.ID)
+		} else {
+			fmt.Fprintf(&sb, "state %q as %s\n", n.Label, n.ID)
+		}
+	}
+
+	fmt.Fprintf(&sb, "[*] --> %s\n", g.Init)
+	for _, e := range g.Edges {
+		var styles []string
+		if e.Wildcard {
+			styles = append(styles, "#DarkOrange")
+		}
+		switch e.Kind {
+		case EdgeApproval:
+			styles = append(styles, "bold")
+		case EdgeReject, EdgeElse:
+			styles = append(styles, "dashed")
+		}
+
+		arrow := "-->"
+		if len(styles) > 0 {
+			arrow = "-[" + strings.Join(styles, ",") + "]->"
+		}
+		if label := e.Label(); label != "" {
+			fmt.Fprintf(&sb, "%s %s %s : %s\n", e.From, arrow, e.To, label)
+		} else {
+			fmt.Fprintf(&sb, "%s %s %s\n", e.From, arrow, e.To)
+		}
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func (g *FlowGraph) viaNodeIDs() []string {
+	var ids []string
+	for _, n := range g.Nodes {
+		if n.Via {
+			ids = append(ids, n.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,225 @@
+package stateflowgen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func buildTestModel(t *testing.T, text string) *StateModel {
+	t.Helper()
+	config, rules, err := ParseFlowAnnotations(text)
+	if err != nil {
+		t.Fatalf("ParseFlowAnnotations() error = %v", err)
+	}
+	model, err := BuildModel(config, rules)
+	if err != nil {
+		t.Fatalf("BuildModel() error = %v", err)
+	}
+	return model
+}
+
+func TestFlowGraph_EdgeKinds(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Release", diagram=mermaid)
+@Flow: Development => [ Testing ]
+@Flow: Testing     => [ Production! via Deploying else Rollback, Archived? via Archiving ]
+@Flow: Rollback    => [ Development ]
+`)
+	g := NewFlowGraph(model)
+
+	if g.Init != "s_Development" {
+		t.Errorf("Init = %q, want s_Development", g.Init)
+	}
+
+	var kinds []string
+	for _, e := range g.Edges {
+		kinds = append(kinds, e.From+" "+string(e.Kind)+" "+e.To+" "+e.Label())
+	}
+	want := []string{
+		"s_Development direct s_Testing ",
+		"s_Testing approval via_1 ! approval",
+		"via_1 commit s_Production commit",
+		"via_1 else s_Rollback else",
+		"s_Testing approval via_2 ? approval",
+		"via_2 commit s_Archived commit",
+		"via_2 reject s_Testing reject",
+		"s_Testing direct s_Archived without approval",
+		"s_Rollback direct s_Development ",
+	}
+	if strings.Join(kinds, "\n") != strings.Join(want, "\n") {
+		t.Errorf("edges:\n%s\nwant:\n%s", strings.Join(kinds, "\n"), strings.Join(want, "\n"))
+	}
+
+	// via Phase 只作为 via 节点出现
+	for _, n := range g.Nodes {
+		if n.ID == "s_Deploying" || n.ID == "s_Archiving" {
+			t.Errorf("via phase rendered as regular node: %s", n.ID)
+		}
+	}
+}
+
+func TestFlowGraph_Wildcard(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Machine")
+@Flow: Init            => [ Ready(Running) ]
+@Flow: Ready(Running)  => [ (Stopped) ]
+@Flow: Ready(Stopped)  => [ (Running) ]
+@Flow: Ready(*)        => [ Terminated ]
+`)
+
+	out := NewFlowGraph(model).RenderMermaid()
+	for _, want := range []string{
+		`s_Ready_Running -->|"*"| s_Terminated`,
+		`s_Ready_Stopped -->|"*"| s_Terminated`,
+		"linkStyle 3,4 stroke:#d9822b",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFlowGraph_RenderFormats(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Draft => [ Paid! via Review else Cancelled ]
+@Flow: Paid  => [ Shipped ]
+`)
+	g := NewFlowGraph(model)
+
+	tests := []struct {
+		format DiagramFormat
+		want   []string
+	}{
+		{DiagramMermaid, []string{
+			"flowchart LR",
+			`via_1{{"Review"}}`,
+			`s_Draft ==>|"! approval"| via_1`,
+			`via_1 -.->|"else"| s_Cancelled`,
+			"class s_Draft initial",
+			"class via_1 via",
+		}},
+		{DiagramDOT, []string{
+			`digraph "Order" {`,
+			`"via_1" [label="Review", shape=hexagon, style="dashed"];`,
+			`"s_Draft" -> "via_1" [label="! approval", style=bold];`,
+			`"via_1" -> "s_Cancelled" [label="else", style=dashed, arrowhead=empty];`,
+		}},
+		{DiagramPlantUML, []string{
+			"@startuml Order",
+			`state "Review" as via_1 <<via>> #line.dashed`,
+			"[*] --> s_Draft",
+			"s_Draft -[bold]-> via_1 : ! approval",
+			"via_1 -[dashed]-> s_Cancelled : else",
+			"@enduml",
+		}},
+		{DiagramASCII, []string{
+			"Draft ──▶ Review (via)",
+			"<REJECT> ──▶ Cancelled",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			out, err := g.Render(tt.format)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDiagramFormat(t *testing.T) {
+	tests := map[string]DiagramFormat{
+		"":         "",
+		"mermaid":  DiagramMermaid,
+		"MMD":      DiagramMermaid,
+		"dot":      DiagramDOT,
+		"graphviz": DiagramDOT,
+		"puml":     DiagramPlantUML,
+		"ascii":    DiagramASCII,
+	}
+	for input, want := range tests {
+		got, err := ParseDiagramFormat(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDiagramFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseDiagramFormat("svg"); err == nil {
+		t.Error("ParseDiagramFormat(svg) expected error")
+	}
+}
+
+func TestStateFlowGenerator_DiagramFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package order
+
+// @StateFlow(name="Order", diagram=dot)
+// @Flow: Draft => [ Paid! via Review ]
+// @Flow: Paid  => [ Shipped ]
+const _ = ""
+
+// @StateFlowV2(name="Wallet", output=wallet_state.go, diagram=plantuml)
+// @Flow: initial => [ active? via waiting_approval else rejected ]
+const _ = ""
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "order.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	registry := plugin.NewRegistry()
+	if err := registry.Register(NewStateFlowGenerator()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(NewStateFlowV2Generator()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := plugin.Run(context.Background(), registry, "", tmpDir); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	dot, err := os.ReadFile(filepath.Join(tmpDir, "order_stateflow.dot"))
+	if err != nil {
+		t.Fatalf("ReadFile(order_stateflow.dot) error = %v", err)
+	}
+	if !strings.Contains(string(dot), `"s_Draft" -> "via_1" [label="! approval", style=bold];`) {
+		t.Errorf("dot output missing approval edge:\n%s", dot)
+	}
+
+	puml, err := os.ReadFile(filepath.Join(tmpDir, "wallet_stateflow.puml"))
+	if err != nil {
+		t.Fatalf("ReadFile(wallet_stateflow.puml) error = %v", err)
+	}
+	for _, want := range []string{
+		"s_initial -[bold]-> via_1 : ? approval",
+		"via_1 -[dashed]-> s_rejected : else",
+		"s_initial --> s_active : without approval",
+	} {
+		if !strings.Contains(string(puml), want) {
+			t.Errorf("plantuml output missing %q:\n%s", want, puml)
+		}
+	}
+}
+
+func TestLoadFlowDefinitions(t *testing.T) {
+	defs, err := LoadFlowDefinitions("examples/else_fallback/release.go")
+	if err != nil {
+		t.Fatalf("LoadFlowDefinitions() error = %v", err)
+	}
+	if len(defs) != 1 || defs[0].Name != "Release" || defs[0].Model == nil {
+		t.Fatalf("unexpected definitions: %+v", defs)
+	}
+	if defs[0].Line != 5 {
+		t.Errorf("Line = %d, want 5", defs[0].Line)
+	}
+}
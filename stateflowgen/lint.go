@@ -0,0 +1,364 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LintSeverity 静态分析问题级别
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"   // gogen stateflow lint 返回失败，生成时仅提示
+	LintWarning LintSeverity = "warning" // 仅提示，lint -strict 时返回失败
+)
+
+// 静态分析检查项
+const (
+	LintUnreachable   = "unreachable"    // 无法从初始状态到达
+	LintDeadEnd       = "dead-end"       // 非终态没有出路（仅在声明了 final 时检查）
+	LintFinalOutgoing = "final-outgoing" // final 终态仍有出边
+	LintViaLoop       = "via-loop"       // 审批 via 可能无限循环
+	LintEmptyWildcard = "empty-wildcard" // 通配符未展开出任何流转
+	LintDuplicate     = "duplicate"      // 与之前的流转完全相同
+	LintShadowed      = "shadowed"       // 与之前的流转源/目标相同但属性不同，不会生效
+)
+
+// LintIssue 静态分析发现的问题
+type LintIssue struct {
+	Line     int // 相关 @Flow 所在行，0 表示无法定位
+	Severity LintSeverity
+	Check    string // 检查项
+	Message  string
+}
+
+// String 返回问题描述，如 "line 5: error: state B is unreachable from initial state A (unreachable)"
+func (i LintIssue) String() string {
+	s := fmt.Sprintf("%s: %s (%s)", i.Severity, i.Message, i.Check)
+	if i.Line > 0 {
+		s = fmt.Sprintf("line %d: %s", i.Line, s)
+	}
+	return s
+}
+
+// LintModel 对 @StateFlow 模型做静态分析，返回所有问题（按行号排序）
+func LintModel(model *StateModel, rules []*FlowRule) []LintIssue {
+	// 只作为 via 出现的阶段不是普通状态
+	regular := make(map[Stage]bool)
+	for _, rule := range rules {
+		if !rule.Source.Wildcard && rule.Source.Region == "" {
+			regular[Stage{Phase: rule.Source.Phase, Status: rule.Source.Status}] = true
+		}
+	}
+	for _, trans := range model.flowTransitions() {
+		regular[trans.From] = true
+		regular[trans.To] = true
+		if trans.Fallback.Phase != "" {
+			regular[trans.Fallback] = true
+		}
+	}
+	var stages []Stage
+	for _, stage := range model.GetAllStages() {
+		if regular[stage] || !slices.Contains(model.ViaPhases, stage.Phase) {
+			stages = append(stages, stage)
+		}
+	}
+
+	l := newFlowLinter(model.InitStage, stages, model.Finals, model.flowTransitions(), rules)
+	l.checkEmptyWildcards(rules, model.PhaseStatus)
+	l.checkRegions(model)
+	return l.run()
+}
+
+// LintModelV2 对 @StateFlowV2 模型做静态分析，返回所有问题（按行号排序）
+func LintModelV2(model *StateFlowV2Model, rules []*FlowRule) []LintIssue {
+	stages := make([]Stage, 0, len(model.Statuses))
+	for _, status := range model.Statuses {
+		stages = append(stages, Stage{Phase: status})
+	}
+	finals := make([]Stage, 0, len(model.Finals))
+	for _, status := range model.Finals {
+		finals = append(finals, Stage{Phase: status})
+	}
+
+	l := newFlowLinter(Stage{Phase: model.InitStatus}, stages, finals, model.stageTransitions(), rules)
+	return l.run()
+}
+
+// printLintWarnings 生成时以警告输出全部问题，error 级问题不阻止生成，由 gogen stateflow lint 判定失败
+func printLintWarnings(filePath string, issues []LintIssue) {
+	for _, issue := range issues {
+		fmt.Printf("警告: %s:%d: %s: %s (%s)\n", filePath, issue.Line, issue.Severity, issue.Message, issue.Check)
+	}
+}
+
+// flowLinter V1/V2 共用的图分析
+type flowLinter struct {
+	init        Stage
+	stages      []Stage // 普通阶段（不含只作为 via 出现的阶段）
+	finals      map[Stage]bool
+	transitions []Transition
+	lines       map[Stage]int     // 阶段首次出现的 @Flow 行
+	adj         map[Stage][]Stage // 包含 via 与拒绝回退的邻接表
+	issues      []LintIssue
+}
+
+func newFlowLinter(init Stage, stages, finals []Stage, transitions []Transition, rules []*FlowRule) *flowLinter {
+	l := &flowLinter{
+		init:        init,
+		stages:      stages,
+		finals:      make(map[Stage]bool),
+		transitions: transitions,
+		lines:       make(map[Stage]int),
+		adj:         make(map[Stage][]Stage),
+	}
+	for _, stage := range finals {
+		l.finals[stage] = true
+	}
+
+	for _, rule := range rules {
+		if !rule.Source.Wildcard && rule.Source.Region == "" {
+			l.noteLine(Stage{Phase: rule.Source.Phase, Status: rule.Source.Status}, rule.Line)
+		}
+	}
+	for _, trans := range transitions {
+		l.noteLine(trans.From, trans.Line)
+		l.noteLine(trans.To, trans.Line)
+		if trans.Via.Phase == "" {
+			l.adj[trans.From] = append(l.adj[trans.From], trans.To)
+			continue
+		}
+		l.noteLine(trans.Fallback, trans.Line)
+		if trans.ApprovalOptional {
+			l.adj[trans.From] = append(l.adj[trans.From], trans.To)
+		}
+		// 多级审批：逐级前进，每一级都可以拒绝回退
+		steps := trans.ApprovalSteps()
+		l.adj[trans.From] = append(l.adj[trans.From], steps[0])
+		for i, step := range steps {
+			l.noteLine(step, trans.Line)
+			next := trans.To
+			if i+1 < len(steps) {
+				next = steps[i+1]
+			}
+			l.adj[step] = append(l.adj[step], next, trans.Fallback)
+		}
+	}
+	return l
+}
+
+func (l *flowLinter) noteLine(stage Stage, line int) {
+	if prev, ok := l.lines[stage]; !ok || (line > 0 && (prev == 0 || line < prev)) {
+		l.lines[stage] = line
+	}
+}
+
+func (l *flowLinter) report(line int, severity LintSeverity, check, format string, args ...any) {
+	l.issues = append(l.issues, LintIssue{
+		Line:     line,
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *flowLinter) run() []LintIssue {
+	reachable := l.reachableFrom([]Stage{l.init}, l.adj)
+	l.checkUnreachable(reachable)
+	l.checkFinals(reachable)
+	l.checkViaLoops()
+	l.checkDuplicates()
+
+	slices.SortStableFunc(l.issues, func(a, b LintIssue) int {
+		return a.Line - b.Line
+	})
+	return l.issues
+}
+
+// reachableFrom 从起点出发的 BFS
+func (l *flowLinter) reachableFrom(starts []Stage, adj map[Stage][]Stage) map[Stage]bool {
+	reachable := make(map[Stage]bool)
+	queue := slices.Clone(starts)
+	for _, s := range starts {
+		reachable[s] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[current] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reachable
+}
+
+// checkUnreachable 所有普通阶段必须能从初始状态到达
+func (l *flowLinter) checkUnreachable(reachable map[Stage]bool) {
+	for _, stage := range l.stages {
+		if !reachable[stage] {
+			l.report(l.lines[stage], LintError, LintUnreachable,
+				"state %s is unreachable from initial state %s", stage, l.init)
+		}
+	}
+}
+
+// checkFinals final 终态不能有出边；声明了 final 时，其余状态必须有出路并最终能到达某个终态
+func (l *flowLinter) checkFinals(reachable map[Stage]bool) {
+	if len(l.finals) == 0 {
+		return
+	}
+
+	hasOutgoing := make(map[Stage]bool)
+	for _, trans := range l.transitions {
+		hasOutgoing[trans.From] = true
+		if l.finals[trans.From] {
+			l.report(trans.Line, LintError, LintFinalOutgoing,
+				"final state %s has outgoing transition to %s", trans.From, trans.To)
+		}
+	}
+
+	// 反向 BFS：能到达任一终态的阶段
+	reverse := make(map[Stage][]Stage)
+	for from, targets := range l.adj {
+		for _, to := range targets {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	var finals []Stage
+	for _, stage := range l.stages {
+		if l.finals[stage] {
+			finals = append(finals, stage)
+		}
+	}
+	canFinish := l.reachableFrom(finals, reverse)
+
+	for _, stage := range l.stages {
+		if l.finals[stage] || !reachable[stage] {
+			continue
+		}
+		if !hasOutgoing[stage] {
+			l.report(l.lines[stage], LintWarning, LintDeadEnd,
+				"state %s has no outgoing transitions but is not marked final", stage)
+		} else if !canFinish[stage] {
+			l.report(l.lines[stage], LintWarning, LintDeadEnd,
+				"state %s cannot reach any final state", stage)
+		}
+	}
+}
+
+// checkViaLoops 审批完成或拒绝后落回 via 阶段，而该阶段只能再次发起同一审批时，会无限循环
+func (l *flowLinter) checkViaLoops() {
+	// via 阶段作为普通状态时，是否存在不经过自身审批的出路
+	hasExit := make(map[Stage]bool)
+	for _, trans := range l.transitions {
+		if !trans.Via.Equal(trans.From) || trans.ApprovalOptional {
+			hasExit[trans.From] = true
+		}
+	}
+
+	for _, trans := range l.transitions {
+		if trans.Via.Phase == "" || hasExit[trans.Via] {
+			continue
+		}
+		if trans.To.Equal(trans.Via) || trans.Fallback.Equal(trans.Via) {
+			l.report(trans.Line, LintWarning, LintViaLoop,
+				"approval %s -> %s via %s can resolve into %s, which has no way out except requesting it again",
+				trans.From, trans.To, trans.Via, trans.Via)
+		}
+	}
+}
+
+// checkDuplicates 同一源/目标的流转只有第一条生效
+func (l *flowLinter) checkDuplicates() {
+	type edge struct{ from, to Stage }
+	seen := make(map[edge]Transition)
+	for _, trans := range l.transitions {
+		if trans.Completion {
+			continue
+		}
+		key := edge{trans.From, trans.To}
+		prev, ok := seen[key]
+		if !ok {
+			seen[key] = trans
+			continue
+		}
+		if sameTransition(prev, trans) {
+			l.report(trans.Line, LintWarning, LintDuplicate,
+				"transition %s -> %s duplicates line %d", trans.From, trans.To, prev.Line)
+		} else {
+			l.report(trans.Line, LintError, LintShadowed,
+				"transition %s -> %s is shadowed by line %d and never takes effect", trans.From, trans.To, prev.Line)
+		}
+	}
+}
+
+// checkRegions 区域内子状态必须能从入口到达；终态不能有出边；
+// 复合状态有完成流转时，每个区域都必须声明终态
+func (l *flowLinter) checkRegions(model *StateModel) {
+	for _, region := range model.Regions {
+		lines := make(map[string]int)
+		adj := make(map[Stage][]Stage)
+		for _, trans := range region.Transitions {
+			for _, state := range []string{trans.From, trans.To} {
+				if _, ok := lines[state]; !ok {
+					lines[state] = trans.Line
+				}
+			}
+			adj[Stage{Phase: trans.From}] = append(adj[Stage{Phase: trans.From}], Stage{Phase: trans.To})
+			if slices.Contains(region.Finals, trans.From) {
+				l.report(trans.Line, LintError, LintFinalOutgoing,
+					"final state %s.%s has outgoing transition to %s.%s", region.Name, trans.From, region.Name, trans.To)
+			}
+		}
+
+		reachable := l.reachableFrom([]Stage{{Phase: region.Entry}}, adj)
+		for _, state := range region.States {
+			if !reachable[Stage{Phase: state}] {
+				l.report(lines[state], LintError, LintUnreachable,
+					"state %s.%s is unreachable from entry %s.%s", region.Name, state, region.Name, region.Entry)
+			} else if len(region.Finals) > 0 && len(adj[Stage{Phase: state}]) == 0 && !slices.Contains(region.Finals, state) {
+				l.report(lines[state], LintWarning, LintDeadEnd,
+					"state %s.%s has no outgoing transitions but is not marked final", region.Name, state)
+			}
+		}
+
+		if _, ok := model.CompletionFrom(region.Composite); ok && len(region.Finals) == 0 {
+			l.report(region.Line, LintWarning, LintDeadEnd,
+				"region %s has no final state, so %s never completes", region.Name, region.Composite)
+		}
+	}
+}
+
+// checkEmptyWildcards 通配符规则必须展开出至少一条流转
+func (l *flowLinter) checkEmptyWildcards(rules []*FlowRule, phaseStatus map[string][]string) {
+	for _, rule := range rules {
+		if !rule.Source.Wildcard {
+			continue
+		}
+		if len(phaseStatus[rule.Source.Phase]) == 0 {
+			l.report(rule.Line, LintWarning, LintEmptyWildcard,
+				"wildcard %s(*) matches no status", rule.Source.Phase)
+			continue
+		}
+		if transitions, err := expandRule(rule, phaseStatus); err == nil && len(transitions) == 0 {
+			l.report(rule.Line, LintWarning, LintEmptyWildcard,
+				"wildcard %s(*) produces no transitions", rule.Source.Phase)
+		}
+	}
+}
+
+// sameTransition 两条源/目标相同的流转是否完全一致
+func sameTransition(a, b Transition) bool {
+	return a.ApprovalRequired == b.ApprovalRequired &&
+		a.ApprovalOptional == b.ApprovalOptional &&
+		a.Via.Equal(b.Via) &&
+		a.Fallback.Equal(b.Fallback) &&
+		a.Event == b.Event &&
+		a.Guard == b.Guard &&
+		a.After == b.After &&
+		slices.Equal(a.Steps, b.Steps) &&
+		a.Quorum == b.Quorum
+}
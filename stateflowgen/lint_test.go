@@ -0,0 +1,200 @@
+package stateflowgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func lintText(t *testing.T, text string) []string {
+	t.Helper()
+	config, rules, err := ParseFlowAnnotations(text)
+	if err != nil {
+		t.Fatalf("ParseFlowAnnotations() error = %v", err)
+	}
+	model, err := buildModel(config, rules)
+	if err != nil {
+		t.Fatalf("buildModel() error = %v", err)
+	}
+	var out []string
+	for _, issue := range LintModel(model, rules) {
+		out = append(out, issue.String())
+	}
+	return out
+}
+
+func TestLintModel(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "clean",
+			text: `@StateFlow(name="Order")
+@Flow: Created => [ Paid, Cancelled final ]
+@Flow: Paid    => [ Shipped! via Reviewing ]
+@Flow: Shipped => [ Delivered final ]`,
+		},
+		{
+			name: "unreachable",
+			text: `@StateFlow(name="Server")
+@Flow: Init           => [ Provisioning ]
+@Flow: Ready(Enabled)  => [ (Disabled) ]
+@Flow: Ready(Disabled) => [ (Enabled) ]`,
+			want: []string{
+				"line 3: error: state Ready(Disabled) is unreachable from initial state Init (unreachable)",
+				"line 3: error: state Ready(Enabled) is unreachable from initial state Init (unreachable)",
+			},
+		},
+		{
+			name: "final",
+			text: `@StateFlow(name="Order")
+@Flow: Created   => [ Paid, Lost ]
+@Flow: Paid      => [ Delivered final ]
+@Flow: Delivered => [ Archived ]
+@Flow: Archived  => [ Archived ]`,
+			want: []string{
+				"line 2: warning: state Lost has no outgoing transitions but is not marked final (dead-end)",
+				"line 4: error: final state Delivered has outgoing transition to Archived (final-outgoing)",
+				"line 4: warning: state Archived cannot reach any final state (dead-end)",
+			},
+		},
+		{
+			name: "no final declared",
+			text: `@StateFlow(name="Order")
+@Flow: Created => [ Paid, Lost ]`,
+		},
+		{
+			name: "via loop",
+			text: `@StateFlow(name="Doc")
+@Flow: Draft => [ Published! via Reviewing else Reviewing ]`,
+			want: []string{
+				"line 2: warning: approval Draft -> Published via Reviewing can resolve into Reviewing, which has no way out except requesting it again (via-loop)",
+			},
+		},
+		{
+			name: "empty wildcard",
+			text: `@StateFlow(name="Machine")
+@Flow: Init      => [ Ready(On), Idle ]
+@Flow: Ready(*)  => [ Ready(On) ]
+@Flow: Idle(*)   => [ Init ]`,
+			want: []string{
+				"line 3: warning: wildcard Ready(*) produces no transitions (empty-wildcard)",
+				"line 4: warning: wildcard Idle(*) matches no status (empty-wildcard)",
+			},
+		},
+		{
+			name: "duplicate and shadowed",
+			text: `@StateFlow(name="Order")
+@Flow: Created => [ Paid ]
+@Flow: Paid    => [ Shipped ]
+@Flow: Created => [ Paid ]
+@Flow: Paid    => [ Shipped! via Reviewing ]`,
+			want: []string{
+				"line 4: warning: transition Created -> Paid duplicates line 2 (duplicate)",
+				"line 5: error: transition Paid -> Shipped is shadowed by line 3 and never takes effect (shadowed)",
+			},
+		},
+		{
+			name: "wildcard shadowed by explicit rule",
+			text: `@StateFlow(name="Machine")
+@Flow: Init           => [ Ready(Running) ]
+@Flow: Ready(Running) => [ (Stopped), Terminated! via Stopping ]
+@Flow: Ready(Stopped) => [ (Running) ]
+@Flow: Ready(*)       => [ Terminated ]`,
+			want: []string{
+				"line 5: error: transition Ready(Running) -> Terminated is shadowed by line 3 and never takes effect (shadowed)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lintText(t, tt.text)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestBuildModel_LintErrorsDoNotFail(t *testing.T) {
+	config, rules, err := ParseFlowAnnotations(`@StateFlow(name="Order")
+@Flow: Created   => [ Delivered final ]
+@Flow: Delivered => [ Created ]
+@Flow: Lost      => [ Created ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowAnnotations() error = %v", err)
+	}
+	// 生成时 error 级问题只作为警告输出，由 gogen stateflow lint 判定失败
+	model, err := BuildModel(config, rules)
+	if err != nil {
+		t.Fatalf("BuildModel() error = %v", err)
+	}
+	var got []string
+	for _, issue := range LintModel(model, rules) {
+		got = append(got, issue.String())
+	}
+	for _, want := range []string{"line 3: error: final state Delivered", "line 4: error: state Lost is unreachable"} {
+		if !strings.Contains(strings.Join(got, "\n"), want) {
+			t.Errorf("issues %q missing %q", got, want)
+		}
+	}
+}
+
+func TestLintModelV2(t *testing.T) {
+	config, rules, err := ParseFlowV2Annotations(`@StateFlowV2(name="Wallet")
+@Flow: initial => [ active? via waiting else rejected ]
+@Flow: active  => [ closed final ]
+@Flow: frozen  => [ active ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowV2Annotations() error = %v", err)
+	}
+	model, err := BuildStateFlowV2Model(config, rules)
+	if err != nil {
+		t.Fatalf("BuildStateFlowV2Model() error = %v", err)
+	}
+
+	var got []string
+	for _, issue := range LintModelV2(model, rules) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"line 2: warning: state rejected has no outgoing transitions but is not marked final (dead-end)",
+		"line 4: error: state frozen is unreachable from initial state initial (unreachable)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadFlowDefinitions_IssueLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order.go")
+	source := `package order
+
+//go:generate go run github.com/donutnomad/gogen gen ./...
+
+// 订单状态
+//
+// @StateFlow(name="Order")
+// @Flow: Created => [ Paid ]
+// @Flow: Lost    => [ Paid ]
+const _ = ""
+`
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	defs, err := LoadFlowDefinitions(path)
+	if err != nil {
+		t.Fatalf("LoadFlowDefinitions() error = %v", err)
+	}
+	if len(defs) != 1 || len(defs[0].Issues) != 1 {
+		t.Fatalf("unexpected definitions: %+v", defs)
+	}
+	if issue := defs[0].Issues[0]; issue.Line != 9 || issue.Check != LintUnreachable {
+		t.Errorf("issue = %+v, want unreachable at line 9", issue)
+	}
+}
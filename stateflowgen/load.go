@@ -0,0 +1,110 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// FlowDefinition 源文件中的一个 @StateFlow / @StateFlowV2 定义
+// Model 与 ModelV2 只有一个非空
+type FlowDefinition struct {
+	Name    string            // name 参数，可为空
+	Line    int               // 注释块起始行
+	Diagram DiagramFormat     // diagram 参数
+	Model   *StateModel       // @StateFlow
+	ModelV2 *StateFlowV2Model // @StateFlowV2
+	Issues  []LintIssue       // 静态分析结果，行号为源文件行号
+}
+
+// Graph 构建流程图
+func (d *FlowDefinition) Graph() *FlowGraph {
+	if d.ModelV2 != nil {
+		return NewFlowGraphV2(d.ModelV2)
+	}
+	return NewFlowGraph(d.Model)
+}
+
+// stateFlowV1Regex 匹配 @StateFlow 但不匹配 @StateFlowV2
+var stateFlowV1Regex = regexp.MustCompile(`@StateFlow\b`)
+
+// LoadFlowDefinitions 解析 Go 源文件中所有状态流转定义
+// 不依赖插件扫描，供 gogen stateflow 子命令使用
+// 静态分析问题记录在 Issues 中，不作为错误返回
+func LoadFlowDefinitions(filePath string) ([]*FlowDefinition, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []*FlowDefinition
+	for _, cg := range file.Comments {
+		text, line := commentText(fset, cg)
+
+		switch {
+		case stateFlowV2ConfigRegex.MatchString(text):
+			config, rules, err := ParseFlowV2Annotations(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			shiftRuleLines(rules, line)
+			model, err := BuildStateFlowV2Model(config, rules)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			diagram, err := ParseDiagramFormat(config.Diagram)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			defs = append(defs, &FlowDefinition{Name: config.Name, Line: line, Diagram: diagram, ModelV2: model, Issues: LintModelV2(model, rules)})
+
+		case stateFlowV1Regex.MatchString(text):
+			config, rules, err := ParseFlowAnnotations(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			if config == nil {
+				continue
+			}
+			shiftRuleLines(rules, line)
+			model, err := buildModel(config, rules)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			diagram, err := ParseDiagramFormat(config.Diagram)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+			defs = append(defs, &FlowDefinition{Name: config.Name, Line: line, Diagram: diagram, Model: model, Issues: LintModel(model, rules)})
+		}
+	}
+
+	return defs, nil
+}
+
+// commentText 返回保留原始行结构的注释文本及其起始行号
+// 文本第 i 行对应源文件第 startLine+i-1 行，便于定位 @Flow
+func commentText(fset *token.FileSet, cg *ast.CommentGroup) (text string, startLine int) {
+	startLine = fset.Position(cg.Pos()).Line
+	var b strings.Builder
+	line := startLine
+	for _, c := range cg.List {
+		for l := fset.Position(c.Pos()).Line; line < l; line++ {
+			b.WriteByte('\n')
+		}
+		b.WriteString(c.Text)
+		line += strings.Count(c.Text, "\n")
+	}
+	return b.String(), startLine
+}
+
+// shiftRuleLines 将规则中相对注释文本的行号平移为源文件行号
+func shiftRuleLines(rules []*FlowRule, startLine int) {
+	for _, rule := range rules {
+		rule.Line += startLine - 1
+	}
+}
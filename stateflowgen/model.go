@@ -3,6 +3,7 @@ package stateflowgen
 import (
 	"fmt"
 	"sort"
+	"time"
 )
 
 // StateModel 完整状态模型
@@ -16,6 +17,14 @@ type StateModel struct {
 	Transitions         []Transition        // 展开后的所有流转
 	InitStage           Stage               // 初始阶段
 	ViaPhases           []string            // via 中间状态列表
+	Events              []string            // on 事件名（保持定义顺序）
+	Guards              []string            // if 守卫名（保持定义顺序）
+	History             bool                // 是否生成流转历史（history=true）
+	SQL                 SQLDialect          // 数据库约束的方言（sql=mysql|postgres），为空则不生成
+	Table               string              // SQL 约束作用的表名（table=...）
+	Finals              []Stage             // 标记为 final 的终态（保持定义顺序）
+	Regions             []*Region           // 复合状态的区域（保持声明顺序）
+	Completions         []Transition        // 复合状态的完成流转（所有区域到达终态时自动触发）
 }
 
 // Stage 阶段（Phase + Status）
@@ -39,16 +48,47 @@ func (s Stage) Equal(other Stage) bool {
 
 // Transition 展开后的单条流转
 type Transition struct {
-	From             Stage // 源阶段
-	To               Stage // 目标阶段
-	ApprovalRequired bool  // ! 标记
-	ApprovalOptional bool  // ? 标记
-	Via              Stage // via 中间阶段（审批时）
-	Fallback         Stage // else 拒绝后阶段（为空则等于 From）
+	From             Stage         // 源阶段
+	To               Stage         // 目标阶段
+	ApprovalRequired bool          // ! 标记
+	ApprovalOptional bool          // ? 标记
+	Via              Stage         // via 中间阶段（审批时）
+	Fallback         Stage         // else 拒绝后阶段（为空则等于 From）
+	Wildcard         bool          // 是否由通配符 (*) 展开而来
+	Event            string        // on 事件名
+	Guard            string        // if 守卫名
+	Self             bool          // 是否为 (=) 自我流转
+	Completion       bool          // 是否为复合状态的完成流转（done）
+	After            time.Duration // after 定时（审批流转为审批超时）
+	Steps            []Stage       // 多级审批按顺序的全部中间阶段（第一级即 Via），单级审批为空
+	Quorum           int           // 每一级审批需要的不同审批人数，0 表示单人审批
+	Line             int           // 来源 @Flow 所在行
+}
+
+// ApprovalSteps 返回审批需要依次经过的中间阶段，非审批流转返回 nil
+func (t Transition) ApprovalSteps() []Stage {
+	if len(t.Steps) > 0 {
+		return t.Steps
+	}
+	if t.Via.Phase == "" {
+		return nil
+	}
+	return []Stage{t.Via}
+}
+
+// HasApprovalPolicy 是否为多级或多人审批（via [...] / quorum N）
+func (t Transition) HasApprovalPolicy() bool {
+	return len(t.Steps) > 1 || t.Quorum > 1
 }
 
 // BuildModel 从配置和规则构建状态模型
+// 不可达、被遮蔽等静态分析问题不影响构建，由 LintModel 单独报告
 func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error) {
+	return buildModel(config, rules)
+}
+
+// buildModel 构建状态模型，不做可达性等静态分析
+func buildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error) {
 	if config == nil {
 		return nil, fmt.Errorf("config is nil")
 	}
@@ -59,7 +99,15 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 	model := &StateModel{
 		Name:        config.Name,
 		PhaseStatus: make(map[string][]string),
+		History:     config.History,
+		Table:       config.Table,
+	}
+
+	dialect, err := ParseSQLDialect(config.SQL)
+	if err != nil {
+		return nil, err
 	}
+	model.SQL = dialect
 
 	// 第一遍：收集所有 Phase 和 Status
 	phaseSet := make(map[string]bool)
@@ -68,6 +116,11 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 	viaSet := make(map[string]bool)
 
 	for _, rule := range rules {
+		// 区域内规则由 buildRegions 处理
+		if rule.Source.Region != "" {
+			continue
+		}
+
 		// 处理源状态
 		if rule.Source.Phase != "" {
 			if !phaseSet[rule.Source.Phase] {
@@ -84,6 +137,9 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 
 		// 处理目标状态
 		for _, target := range rule.Targets {
+			if target.Region != "" {
+				return nil, fmt.Errorf("line %d: region state %s.%s can only be targeted within its region", rule.Line, target.Region, target.Phase)
+			}
 			if target.Phase != "" && !target.Self {
 				if !phaseSet[target.Phase] {
 					phaseSet[target.Phase] = true
@@ -101,18 +157,22 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 				statusSet[phase][target.Status] = true
 			}
 
-			// 处理 via 状态
-			if target.Via != "" {
-				if !phaseSet[target.Via] {
-					phaseSet[target.Via] = true
-					phaseOrder = append(phaseOrder, target.Via)
+			// 处理 via 状态（多级审批的每一级都是 via 状态）
+			steps := target.Steps
+			if len(steps) == 0 && target.Via != "" {
+				steps = []StateRef{{Phase: target.Via, Status: target.ViaStatus}}
+			}
+			for _, step := range steps {
+				if !phaseSet[step.Phase] {
+					phaseSet[step.Phase] = true
+					phaseOrder = append(phaseOrder, step.Phase)
 				}
-				viaSet[target.Via] = true
-				if target.ViaStatus != "" {
-					if statusSet[target.Via] == nil {
-						statusSet[target.Via] = make(map[string]bool)
+				viaSet[step.Phase] = true
+				if step.Status != "" {
+					if statusSet[step.Phase] == nil {
+						statusSet[step.Phase] = make(map[string]bool)
 					}
-					statusSet[target.Via][target.ViaStatus] = true
+					statusSet[step.Phase][step.Status] = true
 				}
 			}
 
@@ -130,6 +190,17 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 				}
 			}
 
+			// 收集事件与守卫
+			if target.Guard != "" && target.Event == "" {
+				return nil, fmt.Errorf("guard '%s' requires an event (on ...)", target.Guard)
+			}
+			if target.Event != "" {
+				addOrderedString(&model.Events, target.Event)
+			}
+			if target.Guard != "" {
+				addOrderedString(&model.Guards, target.Guard)
+			}
+
 			// 检查审批标记
 			if target.ApprovalRequired || target.ApprovalOptional {
 				model.HasApproval = true
@@ -164,25 +235,69 @@ func BuildModel(config *StateFlowConfig, rules []*FlowRule) (*StateModel, error)
 
 	// 第二遍：展开通配符并生成 Transitions
 	for _, rule := range rules {
+		if rule.Source.Region != "" {
+			continue
+		}
 		transitions, err := expandRule(rule, model.PhaseStatus)
 		if err != nil {
 			return nil, err
 		}
+		if rule.Source.Done {
+			for i := range transitions {
+				transitions[i].Completion = true
+			}
+			model.Completions = append(model.Completions, transitions...)
+			continue
+		}
 		model.Transitions = append(model.Transitions, transitions...)
 	}
 
-	// 设置初始状态（第一条规则的源状态）
-	if len(rules) > 0 && rules[0].Source.Phase != "" {
-		model.InitStage = Stage{
-			Phase:  rules[0].Source.Phase,
-			Status: rules[0].Source.Status,
+	// 收集 final 终态
+	for _, rule := range rules {
+		if rule.Source.Region != "" {
+			continue
+		}
+		if rule.Source.Final {
+			if rule.Source.Wildcard {
+				return nil, fmt.Errorf("final cannot be used with wildcard source %s(*)", rule.Source.Phase)
+			}
+			addOrderedStage(&model.Finals, Stage{Phase: rule.Source.Phase, Status: rule.Source.Status})
+		}
+		for _, source := range expandSourceStage(rule.Source, model.PhaseStatus) {
+			for _, target := range rule.Targets {
+				if target.Final {
+					addOrderedStage(&model.Finals, resolveTarget(target, source))
+				}
+			}
 		}
 	}
 
+	// 设置初始状态（第一条顶层规则的源状态）
+	for _, rule := range rules {
+		if rule.Source.Region != "" {
+			continue
+		}
+		if rule.Source.Phase != "" {
+			model.InitStage = Stage{
+				Phase:  rule.Source.Phase,
+				Status: rule.Source.Status,
+			}
+		}
+		break
+	}
+
+	// 复合状态与区域
+	if err := buildRegions(model, rules); err != nil {
+		return nil, err
+	}
+
 	// 验证模型
 	if err := validateModel(model); err != nil {
 		return nil, err
 	}
+	if err := validateEvents(model); err != nil {
+		return nil, err
+	}
 
 	return model, nil
 }
@@ -208,18 +323,12 @@ func expandRule(rule *FlowRule, phaseStatus map[string][]string) ([]Transition,
 				}
 				return nil, fmt.Errorf("approval mark '%s' requires 'via' keyword: %s%s", mark, targetDesc, mark)
 			}
+			if (target.Quorum > 0 || len(target.Steps) > 0) && !target.ApprovalRequired && !target.ApprovalOptional {
+				return nil, fmt.Errorf("quorum and multi-step via require an approval mark (! or ?): %s", resolveTarget(target, source))
+			}
 
 			// 计算目标状态
-			toStage := Stage{}
-			if target.Self {
-				toStage = source
-			} else {
-				toStage.Phase = target.Phase
-				if toStage.Phase == "" {
-					toStage.Phase = source.Phase
-				}
-				toStage.Status = target.Status
-			}
+			toStage := resolveTarget(target, source)
 
 			// 跳过自我流转（通配符展开时）
 			if rule.Source.Wildcard && toStage.Equal(source) && !target.Self {
@@ -231,6 +340,16 @@ func expandRule(rule *FlowRule, phaseStatus map[string][]string) ([]Transition,
 				To:               toStage,
 				ApprovalRequired: target.ApprovalRequired,
 				ApprovalOptional: target.ApprovalOptional,
+				Wildcard:         rule.Source.Wildcard,
+				Event:            target.Event,
+				Guard:            target.Guard,
+				Self:             target.Self,
+				After:            target.After,
+				Quorum:           target.Quorum,
+				Line:             rule.Line,
+			}
+			for _, step := range target.Steps {
+				trans.Steps = append(trans.Steps, Stage{Phase: step.Phase, Status: step.Status})
 			}
 
 			// 设置 via 状态
@@ -259,6 +378,28 @@ func expandRule(rule *FlowRule, phaseStatus map[string][]string) ([]Transition,
 	return transitions, nil
 }
 
+// resolveTarget 计算目标引用相对于源阶段的实际阶段
+func resolveTarget(target TargetRef, source Stage) Stage {
+	if target.Self {
+		return source
+	}
+	to := Stage{Phase: target.Phase, Status: target.Status}
+	if to.Phase == "" {
+		to.Phase = source.Phase
+	}
+	return to
+}
+
+// addOrderedStage 按顺序去重追加阶段
+func addOrderedStage(stages *[]Stage, stage Stage) {
+	for _, item := range *stages {
+		if item.Equal(stage) {
+			return
+		}
+	}
+	*stages = append(*stages, stage)
+}
+
 // expandSourceStage 展开源状态，处理通配符
 func expandSourceStage(source StateRef, phaseStatus map[string][]string) []Stage {
 	if !source.Wildcard {
@@ -278,138 +419,60 @@ func expandSourceStage(source StateRef, phaseStatus map[string][]string) []Stage
 	return stages
 }
 
-// validateModel 验证模型的有效性
+// validateModel 验证模型的结构有效性
 func validateModel(model *StateModel) error {
 	if len(model.Phases) == 0 {
 		return fmt.Errorf("no phases defined")
 	}
 
 	// 检查是否有流转（单节点声明时允许无流转）
-	if len(model.Transitions) == 0 && len(model.Phases) > 1 {
+	if len(model.Transitions) == 0 && len(model.Completions) == 0 && len(model.Phases) > 1 {
 		return fmt.Errorf("multiple phases defined but no transitions")
 	}
 
-	// 单节点或无流转时不需要进一步验证
-	if len(model.Transitions) == 0 {
-		return nil
-	}
-
-	// 收集所有边
-	inEdges := make(map[string]bool)  // 有入边的阶段
-	outEdges := make(map[string]bool) // 有出边的阶段
-	viaPhases := make(map[string]bool)
-	fallbackTargets := make(map[string]bool) // else/fallback 目标
-
-	for _, via := range model.ViaPhases {
-		viaPhases[via] = true
-	}
-
-	for _, trans := range model.Transitions {
-		fromKey := trans.From.String()
-		toKey := trans.To.String()
-		outEdges[fromKey] = true
-		inEdges[toKey] = true
-
-		// via 状态也计入
-		if trans.Via.Phase != "" {
-			viaKey := trans.Via.String()
-			inEdges[viaKey] = true
-			outEdges[viaKey] = true
-		}
-
-		// fallback 目标也计入（作为潜在的入边）
-		if trans.Fallback.Phase != "" {
-			fallbackKey := trans.Fallback.String()
-			fallbackTargets[fallbackKey] = true
-		}
-	}
-
-	// 使用 BFS 检测连通性
-	// 从初始状态开始，检查所有节点是否可达
-	reachable := make(map[string]bool)
-
-	// 构建邻接表
-	adjList := make(map[string][]string)
+	// 可达性等图检查由 LintModel 完成
+	return nil
+}
 
+// validateEvents 检查同一阶段上的事件是否唯一确定目标
+func validateEvents(model *StateModel) error {
+	targets := make(map[string]Transition) // from|event -> 流转
 	for _, trans := range model.Transitions {
-		fromKey := trans.From.String()
-		toKey := trans.To.String()
-		adjList[fromKey] = append(adjList[fromKey], toKey)
-
-		if trans.Via.Phase != "" {
-			viaKey := trans.Via.String()
-			adjList[fromKey] = append(adjList[fromKey], viaKey)
-			adjList[viaKey] = append(adjList[viaKey], toKey)
-		}
-
-		if trans.Fallback.Phase != "" {
-			fallbackKey := trans.Fallback.String()
-			adjList[fromKey] = append(adjList[fromKey], fallbackKey)
-		}
-	}
-
-	// BFS 从初始状态开始
-	initKey := model.InitStage.String()
-	queue := []string{initKey}
-	reachable[initKey] = true
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-
-		for _, next := range adjList[current] {
-			if !reachable[next] {
-				reachable[next] = true
-				queue = append(queue, next)
-			}
-		}
-	}
-
-	// 反向 BFS 检查是否有路径到达终态或可从初始状态到达
-	// 简化：只检查是否有节点既不可达也不在图中
-	allNodes := make(map[string]bool)
-	for _, phase := range model.Phases {
-		if viaPhases[phase] {
+		if trans.Event == "" {
 			continue
 		}
-		statuses := model.PhaseStatus[phase]
-		if len(statuses) == 0 {
-			allNodes[phase] = true
-		} else {
-			for _, status := range statuses {
-				allNodes[fmt.Sprintf("%s(%s)", phase, status)] = true
-			}
+		key := trans.From.String() + "|" + trans.Event
+		if prev, ok := targets[key]; ok && !prev.To.Equal(trans.To) {
+			return fmt.Errorf("event %s from %s is ambiguous: %s and %s", trans.Event, trans.From, prev.To, trans.To)
 		}
+		targets[key] = trans
 	}
+	return nil
+}
 
-	// 检查是否有节点无法从初始状态到达
-	for node := range allNodes {
-		hasInEdge := inEdges[node] || fallbackTargets[node]
-		hasOutEdge := outEdges[node]
-		isReachable := reachable[node]
-
-		// 起始态：只有出边，无入边 - 允许（就是初始状态）
-		// 终态：只有入边，无出边，但必须从初始状态可达 - 允许
-		// 中间态：有入边也有出边，但必须从初始状态可达 - 允许
-		// 孤立节点：无入边也无出边 - 错误
-		// 断开的子图：有边但无法从初始状态到达 - 错误
-
-		if !hasInEdge && !hasOutEdge {
-			return fmt.Errorf("isolated node detected: %s", node)
-		}
-
-		// 如果节点有出边但不可达，则是断开的子图
-		if !isReachable && hasOutEdge {
-			return fmt.Errorf("disconnected subgraph detected at: %s (not reachable from initial state)", node)
+// HasTimers 是否定义了任何 after 定时流转
+func (m *StateModel) HasTimers() bool {
+	for _, trans := range m.Transitions {
+		if trans.After > 0 {
+			return true
 		}
+	}
+	return false
+}
 
-		// 如果节点只有入边但不可达（这不应该发生，但以防万一）
-		if !isReachable && hasInEdge && !hasOutEdge {
-			return fmt.Errorf("unreachable terminal state: %s", node)
+// HasApprovalPolicy 是否有多级或多人审批流转
+func (m *StateModel) HasApprovalPolicy() bool {
+	for _, trans := range m.Transitions {
+		if trans.HasApprovalPolicy() {
+			return true
 		}
 	}
+	return false
+}
 
-	return nil
+// HasEvents 是否定义了任何 on 事件
+func (m *StateModel) HasEvents() bool {
+	return len(m.Events) > 0
 }
 
 // GetAllStages 获取所有有效的阶段组合
@@ -1,6 +1,8 @@
 package stateflowgen
 
 import (
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -285,7 +287,7 @@ func TestBuildModel_SingleNode(t *testing.T) {
 	}
 }
 
-func TestBuildModel_IsolatedNodeError(t *testing.T) {
+func TestBuildModel_IsolatedNodeLint(t *testing.T) {
 	config := &StateFlowConfig{Name: "Server"}
 	rules := []*FlowRule{
 		{
@@ -303,9 +305,16 @@ func TestBuildModel_IsolatedNodeError(t *testing.T) {
 		},
 	}
 
-	_, err := BuildModel(config, rules)
-	if err == nil {
-		t.Error("BuildModel() expected error for disconnected subgraph, got nil")
+	// 不连通的子图不阻止生成，由静态分析报告为 error 级问题
+	model, err := BuildModel(config, rules)
+	if err != nil {
+		t.Fatalf("BuildModel() error = %v", err)
+	}
+	issues := LintModel(model, rules)
+	if !slices.ContainsFunc(issues, func(i LintIssue) bool {
+		return i.Severity == LintError && i.Check == LintUnreachable
+	}) {
+		t.Errorf("LintModel() = %v, want unreachable error for disconnected subgraph", issues)
 	}
 }
 
@@ -435,3 +444,84 @@ func TestGetTransitionsFrom(t *testing.T) {
 		t.Errorf("len(transitions) = %v, want 2", len(transitions))
 	}
 }
+
+func TestBuildModel_Events(t *testing.T) {
+	config := &StateFlowConfig{Name: "Order"}
+	rules := []*FlowRule{
+		{Source: StateRef{Phase: "Created"}, Targets: []TargetRef{{Phase: "Paid", Event: "Pay"}, {Phase: "Cancelled", Event: "Cancel"}}},
+		{Source: StateRef{Phase: "Paid"}, Targets: []TargetRef{{Phase: "Shipped", Event: "Ship", Guard: "canShip"}, {Phase: "Cancelled", Event: "Cancel"}}},
+	}
+
+	model, err := BuildModel(config, rules)
+	if err != nil {
+		t.Fatalf("BuildModel() error = %v", err)
+	}
+	if got := strings.Join(model.Events, ","); got != "Pay,Cancel,Ship" {
+		t.Errorf("Events = %s, want Pay,Cancel,Ship", got)
+	}
+	if got := strings.Join(model.Guards, ","); got != "canShip" {
+		t.Errorf("Guards = %s, want canShip", got)
+	}
+	if model.Transitions[2].Guard != "canShip" {
+		t.Errorf("Transitions[2].Guard = %q, want canShip", model.Transitions[2].Guard)
+	}
+}
+
+func TestBuildModel_EventErrors(t *testing.T) {
+	config := &StateFlowConfig{Name: "Order"}
+
+	_, err := BuildModel(config, []*FlowRule{
+		{Source: StateRef{Phase: "Paid"}, Targets: []TargetRef{{Phase: "Shipped", Guard: "canShip"}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires an event") {
+		t.Errorf("guard without event: err = %v", err)
+	}
+
+	_, err = BuildModel(config, []*FlowRule{
+		{Source: StateRef{Phase: "Paid"}, Targets: []TargetRef{{Phase: "Shipped", Event: "Next"}, {Phase: "Cancelled", Event: "Next"}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ambiguous event: err = %v", err)
+	}
+}
+
+func TestBuildModel_ApprovalPolicy(t *testing.T) {
+	model := buildTestModel(t, `
+@StateFlow(name="Payment")
+@Flow: Submitted => [ Approved! via FinanceReview quorum 2, Escalated! via [ManagerReview, DirectorReview] else Submitted ]
+@Flow: Approved  => [ Paid ]
+@Flow: Escalated => [ Paid ]
+`)
+	if !model.HasApprovalPolicy() {
+		t.Fatal("HasApprovalPolicy() = false, want true")
+	}
+	if got := strings.Join(model.ViaPhases, ","); got != "DirectorReview,FinanceReview,ManagerReview" {
+		t.Errorf("ViaPhases = %s", got)
+	}
+	quorum, steps := model.Transitions[0], model.Transitions[1]
+	if quorum.Quorum != 2 || len(quorum.ApprovalSteps()) != 1 || !quorum.HasApprovalPolicy() {
+		t.Errorf("quorum transition = %+v", quorum)
+	}
+	if got := steps.ApprovalSteps(); len(got) != 2 || got[0].Phase != "ManagerReview" || got[1].Phase != "DirectorReview" || !steps.Via.Equal(got[0]) {
+		t.Errorf("ApprovalSteps() = %v, want [ManagerReview DirectorReview]", got)
+	}
+
+	plain := buildTestModel(t, `
+@StateFlow(name="Document")
+@Flow: Draft => [ Published! via Reviewing quorum 1 ]
+`)
+	if plain.HasApprovalPolicy() {
+		t.Error("quorum 1 with a single via should not be an approval policy")
+	}
+
+	config, rules, err := ParseFlowAnnotations(`
+@StateFlow(name="Payment")
+@Flow: Submitted => [ Approved via Review quorum 2 ]
+`)
+	if err != nil {
+		t.Fatalf("ParseFlowAnnotations() error = %v", err)
+	}
+	if _, err := BuildModel(config, rules); err == nil || !strings.Contains(err.Error(), "approval mark") {
+		t.Errorf("quorum without approval mark: err = %v", err)
+	}
+}
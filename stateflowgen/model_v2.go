@@ -12,6 +12,7 @@ type StateFlowV2Model struct {
 	HasOptionalApproval bool
 	Transitions         []TransitionV2
 	InitStatus          string
+	Finals              []string
 }
 
 type TransitionV2 struct {
@@ -21,6 +22,8 @@ type TransitionV2 struct {
 	ApprovalOptional bool
 	Via              string
 	Fallback         string
+	Self             bool
+	Line             int
 }
 
 func BuildStateFlowV2Model(config *StateFlowV2Config, rules []*FlowRule) (*StateFlowV2Model, error) {
@@ -42,15 +45,27 @@ func BuildStateFlowV2Model(config *StateFlowV2Config, rules []*FlowRule) (*State
 	}
 
 	for _, rule := range rules {
+		if rule.Source.Region != "" || rule.Source.Done || len(rule.Regions) > 0 {
+			return nil, fmt.Errorf("StateFlowV2 does not support composite states (regions/done)")
+		}
 		if rule.Source.Phase == "" || rule.Source.Status != "" || rule.Source.Wildcard {
 			return nil, fmt.Errorf("StateFlowV2 source must be a stable status phase: phase=%s status=%s wildcard=%v", rule.Source.Phase, rule.Source.Status, rule.Source.Wildcard)
 		}
 		addOrderedString(&model.Statuses, rule.Source.Phase)
+		if rule.Source.Final {
+			addOrderedString(&model.Finals, rule.Source.Phase)
+		}
 		if model.InitStatus == "" {
 			model.InitStatus = rule.Source.Phase
 		}
 
 		for _, target := range rule.Targets {
+			if target.Region != "" {
+				return nil, fmt.Errorf("StateFlowV2 does not support composite states (regions/done)")
+			}
+			if target.Event != "" || target.Guard != "" || target.After > 0 {
+				return nil, fmt.Errorf("StateFlowV2 does not support events, guards or timers (on/if/after)")
+			}
 			to := target.Phase
 			if target.Self {
 				to = rule.Source.Phase
@@ -59,6 +74,9 @@ func BuildStateFlowV2Model(config *StateFlowV2Config, rules []*FlowRule) (*State
 				return nil, fmt.Errorf("StateFlowV2 target must be a stable status phase")
 			}
 			addOrderedString(&model.Statuses, to)
+			if target.Final {
+				addOrderedString(&model.Finals, to)
+			}
 
 			fallback := rule.Source.Phase
 			if target.Else != "" {
@@ -70,6 +88,9 @@ func BuildStateFlowV2Model(config *StateFlowV2Config, rules []*FlowRule) (*State
 				if target.Via == "" || target.ViaStatus != "" {
 					return nil, fmt.Errorf("StateFlowV2 approval transition requires via stage")
 				}
+				if len(target.Steps) > 0 || target.Quorum > 0 {
+					return nil, fmt.Errorf("StateFlowV2 does not support approval policies (via [...] / quorum)")
+				}
 				addOrderedString(&model.Stages, target.Via)
 				model.HasApproval = true
 			}
@@ -84,6 +105,8 @@ func BuildStateFlowV2Model(config *StateFlowV2Config, rules []*FlowRule) (*State
 				ApprovalOptional: target.ApprovalOptional,
 				Via:              target.Via,
 				Fallback:         fallback,
+				Self:             target.Self,
+				Line:             rule.Line,
 			})
 		}
 	}
@@ -2,20 +2,37 @@ package stateflowgen
 
 import (
 	"fmt"
+	"go/token"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // StateFlowConfig 配置注解解析结果
 type StateFlowConfig struct {
-	Name   string // 类型前缀，如 "Server"
-	Output string // 可选：输出文件路径
+	Name    string // 类型前缀，如 "Server"
+	Output  string // 可选：输出文件路径
+	Diagram string // 可选：流程图格式（mermaid/dot/plantuml/ascii）
+	History bool   // 可选：生成流转记录、历史表与 Replay
+	Tests   bool   // 可选：生成 _stateflow_test.go 测试文件
+	Schema  string // 可选：导出状态机定义（json/ts，逗号分隔）
+	SQL     string // 可选：生成数据库约束与 GORM 钩子（mysql/postgres）
+	Table   string // 可选：SQL 约束作用的表名
 }
 
 // FlowRule 单条流转规则
 type FlowRule struct {
 	Source  StateRef
 	Targets []TargetRef
+	Regions []RegionRef // 复合状态声明的区域（Phase regions [ ... ]）
+	Line    int         // @Flow 所在行（相对于解析的注释文本，调用方可平移为源文件行号）
+}
+
+// RegionRef 复合状态声明中的区域引用
+type RegionRef struct {
+	Name  string // 区域名
+	Entry string // 入口子状态，可为空（默认为该区域第一条规则的源状态）
 }
 
 // StateRef 源状态引用
@@ -23,19 +40,29 @@ type StateRef struct {
 	Phase    string // Phase 名称
 	Status   string // Status 名称，可为空
 	Wildcard bool   // 是否为 * 通配符
+	Final    bool   // 是否标记为 final 终态
+	Region   string // 所属区域（region.SubState 写法），为空表示顶层状态
+	Done     bool   // 是否为复合状态的完成流转（Phase done => [ ... ]）
 }
 
 // TargetRef 目标状态引用（包含审批信息）
 type TargetRef struct {
-	Phase            string // Phase 名称，可为空（纯状态切换时继承源 Phase）
-	Status           string // Status 名称，可为空
-	Self             bool   // 是否为 = 自我流转
-	ApprovalRequired bool   // ! 标记
-	ApprovalOptional bool   // ? 标记
-	Via              string // via 中间状态（Phase 名称）
-	ViaStatus        string // via 中间状态的 Status（可为空）
-	Else             string // else 拒绝后状态（Phase 名称），为空则回退原状态
-	ElseStatus       string // else 拒绝后状态的 Status（可为空）
+	Phase            string        // Phase 名称，可为空（纯状态切换时继承源 Phase）
+	Status           string        // Status 名称，可为空
+	Self             bool          // 是否为 = 自我流转
+	ApprovalRequired bool          // ! 标记
+	ApprovalOptional bool          // ? 标记
+	Via              string        // via 中间状态（Phase 名称）
+	ViaStatus        string        // via 中间状态的 Status（可为空）
+	Else             string        // else 拒绝后状态（Phase 名称），为空则回退原状态
+	ElseStatus       string        // else 拒绝后状态的 Status（可为空）
+	Event            string        // on 事件名（可为空）
+	Guard            string        // if 守卫名（可为空，需配合 on 使用）
+	Final            bool          // 目标是否标记为 final 终态
+	Region           string        // 所属区域（region.SubState 写法），可为空
+	After            time.Duration // after 定时：停留超过该时长后自动流转（审批目标为审批超时自动拒绝）
+	Steps            []StateRef    // via [A, B] 多级审批时按顺序的全部中间状态（Via 为第一级），单级审批为空
+	Quorum           int           // quorum N：每一级审批需要的不同审批人数，0 表示单人审批
 }
 
 // stateFlowConfigRegex 匹配 @StateFlow(name="xxx") 或 @StateFlow() 或 @StateFlow
@@ -82,6 +109,18 @@ func ParseStateFlowConfig(text string) (*StateFlowConfig, error) {
 				config.Name = value
 			case "output":
 				config.Output = value
+			case "diagram":
+				config.Diagram = value
+			case "history":
+				config.History = value == "true"
+			case "tests":
+				config.Tests = value == "true"
+			case "schema":
+				config.Schema = value
+			case "sql":
+				config.SQL = value
+			case "table":
+				config.Table = value
 			}
 		}
 	}
@@ -93,6 +132,9 @@ func ParseStateFlowConfig(text string) (*StateFlowConfig, error) {
 // ParseFlowRule 从文本行中解析 @Flow 规则
 // 格式: @Flow: Source(Status) => [ Target1!, Target2? ]
 // 或: @Flow: Init (无流转，单节点声明)
+// 或: @Flow: Processing regions [ payment, delivery:Packing ] (复合状态声明)
+// 或: @Flow: payment.Unpaid => [ Paid final ] (区域内流转)
+// 或: @Flow: Processing done => [ Completed ] (所有区域到达终态后的完成流转)
 func ParseFlowRule(line string) (*FlowRule, error) {
 	matches := flowRuleRegex.FindStringSubmatch(line)
 	if len(matches) < 2 {
@@ -104,12 +146,25 @@ func ParseFlowRule(line string) (*FlowRule, error) {
 	// 检查是否有 => 符号
 	parts := strings.SplitN(content, "=>", 2)
 
+	// 复合状态声明
+	if len(parts) == 1 {
+		if rule, ok, err := parseRegionsDecl(content); ok || err != nil {
+			return rule, err
+		}
+	}
+
 	// 解析源状态
-	sourceStr := strings.TrimSpace(parts[0])
+	sourceStr, done := cutKeyword(parts[0], "done")
+	sourceStr, final := cutKeyword(sourceStr, "final")
 	source, err := parseStateRef(sourceStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid source state '%s': %w", sourceStr, err)
 	}
+	source.Final = final
+	source.Done = done
+	if done && len(parts) == 1 {
+		return nil, fmt.Errorf("completion transition requires targets: %s", content)
+	}
 
 	rule := &FlowRule{
 		Source: *source,
@@ -122,14 +177,26 @@ func ParseFlowRule(line string) (*FlowRule, error) {
 
 	// 解析目标列表
 	targetsPart := strings.TrimSpace(parts[1])
-	if !strings.HasPrefix(targetsPart, "[") || !strings.HasSuffix(targetsPart, "]") {
+	closeIdx := strings.LastIndex(targetsPart, "]")
+	if !strings.HasPrefix(targetsPart, "[") || closeIdx == -1 {
 		return nil, fmt.Errorf("targets must be enclosed in brackets: %s", targetsPart)
 	}
 
+	// ] 之后的 on/if/after 子句作用于所有目标
+	suffix, ruleAfter, err := cutAfter(targetsPart[closeIdx+1:])
+	if err != nil {
+		return nil, err
+	}
+	suffix, ruleEvent, ruleGuard, err := cutEventClauses(suffix)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(suffix) != "" {
+		return nil, fmt.Errorf("unexpected text after targets: %s", strings.TrimSpace(suffix))
+	}
+
 	// 去除括号
-	targetsPart = strings.TrimPrefix(targetsPart, "[")
-	targetsPart = strings.TrimSuffix(targetsPart, "]")
-	targetsPart = strings.TrimSpace(targetsPart)
+	targetsPart = strings.TrimSpace(targetsPart[1:closeIdx])
 
 	if targetsPart == "" {
 		return nil, fmt.Errorf("empty target list")
@@ -146,6 +213,24 @@ func ParseFlowRule(line string) (*FlowRule, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid target '%s': %w", targetStr, err)
 		}
+		if ruleEvent != "" {
+			if target.Event != "" {
+				return nil, fmt.Errorf("target '%s' already has event %s", targetStr, target.Event)
+			}
+			target.Event = ruleEvent
+		}
+		if ruleGuard != "" {
+			if target.Guard != "" {
+				return nil, fmt.Errorf("target '%s' already has guard %s", targetStr, target.Guard)
+			}
+			target.Guard = ruleGuard
+		}
+		if ruleAfter > 0 {
+			if target.After > 0 {
+				return nil, fmt.Errorf("target '%s' already has after %s", targetStr, target.After)
+			}
+			target.After = ruleAfter
+		}
 		rule.Targets = append(rule.Targets, *target)
 	}
 
@@ -179,10 +264,17 @@ func parseStateRef(s string) (*StateRef, error) {
 		return nil, fmt.Errorf("empty state reference")
 	}
 
+	region, phase, err := cutRegion(ref.Phase)
+	if err != nil {
+		return nil, err
+	}
+	ref.Region, ref.Phase = region, phase
+
 	return ref, nil
 }
 
 // splitTargets 分割目标列表，处理 via/else 关键字
+// 括号与 via [A, B] 方括号内的逗号不作为分隔符
 func splitTargets(s string) ([]string, error) {
 	var targets []string
 	var current strings.Builder
@@ -191,10 +283,10 @@ func splitTargets(s string) ([]string, error) {
 	for i := 0; i < len(s); i++ {
 		c := s[i]
 
-		if c == '(' {
+		if c == '(' || c == '[' {
 			parenDepth++
 			current.WriteByte(c)
-		} else if c == ')' {
+		} else if c == ')' || c == ']' {
 			parenDepth--
 			current.WriteByte(c)
 		} else if c == ',' && parenDepth == 0 {
@@ -218,10 +310,24 @@ func splitTargets(s string) ([]string, error) {
 // 格式: Phase(Status)! via Intermediate else Fallback
 // 或: (Status)! via Intermediate else Fallback
 // 或: (=)? via Intermediate
+// 或: Phase! via [Step1, Step2] (多级审批，按顺序逐级通过)
+// 以上格式均可追加 on Event / if guard / after 30m / quorum 2 子句
 func parseTargetRef(s string) (*TargetRef, error) {
-	s = strings.TrimSpace(s)
+	s, after, err := cutAfter(s)
+	if err != nil {
+		return nil, err
+	}
+	s, quorum, err := cutQuorum(s)
+	if err != nil {
+		return nil, err
+	}
+	s, event, guard, err := cutEventClauses(s)
+	if err != nil {
+		return nil, err
+	}
+	s, final := cutKeyword(s, "final")
 
-	ref := &TargetRef{}
+	ref := &TargetRef{Event: event, Guard: guard, Final: final, After: after, Quorum: quorum}
 
 	// 分割 via 和 else 部分
 	mainPart := s
@@ -278,14 +384,21 @@ func parseTargetRef(s string) (*TargetRef, error) {
 		ref.Phase = mainPart
 	}
 
+	if ref.Region, ref.Phase, err = cutRegion(ref.Phase); err != nil {
+		return nil, err
+	}
+
 	// 解析 via 部分
 	if viaPart != "" {
-		viaRef, err := parseStateRef(viaPart)
+		steps, err := parseViaSteps(viaPart)
 		if err != nil {
-			return nil, fmt.Errorf("invalid via state '%s': %w", viaPart, err)
+			return nil, err
+		}
+		ref.Via = steps[0].Phase
+		ref.ViaStatus = steps[0].Status
+		if len(steps) > 1 {
+			ref.Steps = steps
 		}
-		ref.Via = viaRef.Phase
-		ref.ViaStatus = viaRef.Status
 	}
 
 	// 解析 else 部分
@@ -294,6 +407,9 @@ func parseTargetRef(s string) (*TargetRef, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid else state '%s': %w", elsePart, err)
 		}
+		if elseRef.Region != "" {
+			return nil, fmt.Errorf("else state '%s' cannot be a region state", elsePart)
+		}
 		ref.Else = elseRef.Phase
 		ref.ElseStatus = elseRef.Status
 	}
@@ -301,13 +417,195 @@ func parseTargetRef(s string) (*TargetRef, error) {
 	return ref, nil
 }
 
+// parseViaSteps 解析 via 中间状态，支持单个状态或 [Step1, Step2] 多级列表
+func parseViaSteps(s string) ([]StateRef, error) {
+	items := []string{s}
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unmatched bracket in via '%s'", s)
+		}
+		items = strings.Split(s[1:len(s)-1], ",")
+	}
+
+	var steps []StateRef
+	for _, item := range items {
+		step, err := parseStateRef(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid via state '%s': %w", strings.TrimSpace(item), err)
+		}
+		if step.Region != "" {
+			return nil, fmt.Errorf("via state '%s' cannot be a region state", strings.TrimSpace(item))
+		}
+		for _, prev := range steps {
+			if prev.Phase == step.Phase && prev.Status == step.Status {
+				return nil, fmt.Errorf("via state '%s' is listed more than once", strings.TrimSpace(item))
+			}
+		}
+		steps = append(steps, *step)
+	}
+	return steps, nil
+}
+
+// cutQuorum 从文本中移除 quorum <N> 子句
+func cutQuorum(s string) (rest string, quorum int, err error) {
+	fields := strings.Fields(s)
+	kept := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		if strings.ToLower(fields[i]) != "quorum" {
+			kept = append(kept, fields[i])
+			continue
+		}
+		if quorum > 0 {
+			return "", 0, fmt.Errorf("multiple quorum clauses: %s", strings.TrimSpace(s))
+		}
+		if i+1 >= len(fields) {
+			return "", 0, fmt.Errorf("'quorum' must be followed by a number: %s", strings.TrimSpace(s))
+		}
+		i++
+		if quorum, err = strconv.Atoi(fields[i]); err != nil || quorum <= 0 {
+			return "", 0, fmt.Errorf("invalid quorum '%s' (want a positive integer)", fields[i])
+		}
+	}
+	return strings.Join(kept, " "), quorum, nil
+}
+
+// cutEventClauses 从文本中移除 on <Event> / if <guard> 子句
+// 状态名不含空格，因此按空白分词即可
+func cutEventClauses(s string) (rest, event, guard string, err error) {
+	fields := strings.Fields(s)
+	kept := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		kw := strings.ToLower(fields[i])
+		if kw != "on" && kw != "if" {
+			kept = append(kept, fields[i])
+			continue
+		}
+		if i+1 >= len(fields) || !token.IsIdentifier(fields[i+1]) {
+			return "", "", "", fmt.Errorf("'%s' must be followed by an identifier: %s", kw, strings.TrimSpace(s))
+		}
+		i++
+		if kw == "on" {
+			if event != "" {
+				return "", "", "", fmt.Errorf("multiple events: %s", strings.TrimSpace(s))
+			}
+			event = fields[i]
+		} else {
+			if guard != "" {
+				return "", "", "", fmt.Errorf("multiple guards: %s", strings.TrimSpace(s))
+			}
+			guard = fields[i]
+		}
+	}
+	return strings.Join(kept, " "), event, guard, nil
+}
+
+// cutAfter 从文本中移除 after <duration> 子句
+func cutAfter(s string) (rest string, after time.Duration, err error) {
+	fields := strings.Fields(s)
+	kept := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		if strings.ToLower(fields[i]) != "after" {
+			kept = append(kept, fields[i])
+			continue
+		}
+		if after > 0 {
+			return "", 0, fmt.Errorf("multiple after clauses: %s", strings.TrimSpace(s))
+		}
+		if i+1 >= len(fields) {
+			return "", 0, fmt.Errorf("'after' must be followed by a duration: %s", strings.TrimSpace(s))
+		}
+		i++
+		if after, err = parseAfter(fields[i]); err != nil {
+			return "", 0, err
+		}
+	}
+	return strings.Join(kept, " "), after, nil
+}
+
+// parseAfter 解析 after 时长，支持 time.ParseDuration 格式及整数天数（如 3d）
+func parseAfter(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid after duration '%s' (want e.g. 30m, 72h or 3d)", s)
+	}
+	return d, nil
+}
+
+// formatAfter 格式化 after 时长，去掉多余的零单位，如 30m、72h、1h30m
+func formatAfter(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// cutKeyword 移除末尾的关键字（final/done），返回去除后的文本及是否存在
+func cutKeyword(s, keyword string) (string, bool) {
+	fields := strings.Fields(s)
+	if len(fields) > 1 && fields[len(fields)-1] == keyword {
+		return strings.Join(fields[:len(fields)-1], " "), true
+	}
+	return strings.TrimSpace(s), false
+}
+
+// cutRegion 拆分 region.SubState 写法，无区域前缀时 region 为空
+func cutRegion(s string) (region, phase string, err error) {
+	region, phase, ok := strings.Cut(s, ".")
+	if !ok {
+		return "", s, nil
+	}
+	if !token.IsIdentifier(region) || phase == "" || strings.Contains(phase, ".") {
+		return "", "", fmt.Errorf("invalid region state '%s' (want region.SubState)", s)
+	}
+	return region, phase, nil
+}
+
+// parseRegionsDecl 解析复合状态声明: Phase regions [ region1, region2:Entry ]
+// 不含 regions 关键字时 ok 为 false
+func parseRegionsDecl(content string) (rule *FlowRule, ok bool, err error) {
+	phase, rest, found := strings.Cut(content, " regions ")
+	if !found {
+		return nil, false, nil
+	}
+	phase, rest = strings.TrimSpace(phase), strings.TrimSpace(rest)
+	if !token.IsIdentifier(phase) {
+		return nil, true, fmt.Errorf("composite state must be a plain phase: %s", phase)
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return nil, true, fmt.Errorf("regions must be enclosed in brackets: %s", rest)
+	}
+
+	rule = &FlowRule{Source: StateRef{Phase: phase}}
+	for _, item := range strings.Split(rest[1:len(rest)-1], ",") {
+		name, entry, _ := strings.Cut(strings.TrimSpace(item), ":")
+		name, entry = strings.TrimSpace(name), strings.TrimSpace(entry)
+		if !token.IsIdentifier(name) || (entry != "" && !token.IsIdentifier(entry)) {
+			return nil, true, fmt.Errorf("invalid region '%s' (want name or name:Entry)", strings.TrimSpace(item))
+		}
+		rule.Regions = append(rule.Regions, RegionRef{Name: name, Entry: entry})
+	}
+	return rule, true, nil
+}
+
 // ParseFlowAnnotations 从完整注释文本中解析所有 @StateFlow 和 @Flow 注解
 func ParseFlowAnnotations(text string) (*StateFlowConfig, []*FlowRule, error) {
 	var config *StateFlowConfig
 	var rules []*FlowRule
 
 	lines := strings.Split(text, "\n")
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		line = strings.TrimPrefix(line, "//")
 		line = strings.TrimPrefix(line, "/*")
@@ -337,6 +635,7 @@ func ParseFlowAnnotations(text string) (*StateFlowConfig, []*FlowRule, error) {
 			if err != nil {
 				return nil, nil, err
 			}
+			rule.Line = i + 1
 			rules = append(rules, rule)
 		}
 	}
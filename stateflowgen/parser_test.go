@@ -2,6 +2,7 @@ package stateflowgen
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseStateFlowConfig(t *testing.T) {
@@ -395,3 +396,231 @@ func TestParseFlowRule_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFlowRule_EventsAndGuards(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		events []string
+		guards []string
+		phases []string
+	}{
+		{
+			name:   "rule level event and guard",
+			input:  `@Flow: Paid => [ Shipped ] on Ship if canShip`,
+			events: []string{"Ship"},
+			guards: []string{"canShip"},
+			phases: []string{"Shipped"},
+		},
+		{
+			name:   "target level events",
+			input:  `@Flow: Created => [ Paid on Pay, Cancelled on Cancel if canCancel ]`,
+			events: []string{"Pay", "Cancel"},
+			guards: []string{"", "canCancel"},
+			phases: []string{"Paid", "Cancelled"},
+		},
+		{
+			name:   "with via and else",
+			input:  `@Flow: Testing => [ Production! via Deploying else Rollback on Deploy if canDeploy ]`,
+			events: []string{"Deploy"},
+			guards: []string{"canDeploy"},
+			phases: []string{"Production"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseFlowRule(tt.input)
+			if err != nil {
+				t.Fatalf("ParseFlowRule() error = %v", err)
+			}
+			if len(rule.Targets) != len(tt.events) {
+				t.Fatalf("len(Targets) = %d, want %d", len(rule.Targets), len(tt.events))
+			}
+			for i, target := range rule.Targets {
+				if target.Event != tt.events[i] || target.Guard != tt.guards[i] || target.Phase != tt.phases[i] {
+					t.Errorf("Targets[%d] = %+v, want phase=%s event=%s guard=%s", i, target, tt.phases[i], tt.events[i], tt.guards[i])
+				}
+			}
+		})
+	}
+
+	rule, err := ParseFlowRule(`@Flow: Testing => [ Production! via Deploying else Rollback on Deploy ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if got := rule.Targets[0]; got.Via != "Deploying" || got.Else != "Rollback" || !got.ApprovalRequired {
+		t.Errorf("via/else lost after event clause: %+v", got)
+	}
+}
+
+func TestParseFlowRule_EventErrors(t *testing.T) {
+	for _, input := range []string{
+		`@Flow: Paid => [ Shipped ] on`,
+		`@Flow: Paid => [ Shipped ] if can-ship`,
+		`@Flow: Paid => [ Shipped on Ship ] on Ship`,
+		`@Flow: Paid => [ Shipped ] on Ship on Send`,
+		`@Flow: Paid => [ Shipped ] Ship`,
+	} {
+		if _, err := ParseFlowRule(input); err == nil {
+			t.Errorf("ParseFlowRule(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseFlowRule_Final(t *testing.T) {
+	rule, err := ParseFlowRule(`@Flow: Shipped => [ Delivered final on Deliver, Lost! via Searching final, Returned ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if rule.Source.Final {
+		t.Error("Source.Final = true, want false")
+	}
+	want := []struct {
+		phase string
+		final bool
+	}{{"Delivered", true}, {"Lost", true}, {"Returned", false}}
+	for i, w := range want {
+		target := rule.Targets[i]
+		if target.Phase != w.phase || target.Final != w.final {
+			t.Errorf("target %d = %s final=%v, want %s final=%v", i, target.Phase, target.Final, w.phase, w.final)
+		}
+	}
+	if rule.Targets[0].Event != "Deliver" || rule.Targets[1].Via != "Searching" {
+		t.Errorf("unexpected targets: %+v", rule.Targets)
+	}
+
+	rule, err = ParseFlowRule(`@Flow: Cancelled(Refunded) final`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if !rule.Source.Final || rule.Source.Phase != "Cancelled" || rule.Source.Status != "Refunded" {
+		t.Errorf("Source = %+v, want final Cancelled(Refunded)", rule.Source)
+	}
+}
+
+func TestParseFlowRule_Regions(t *testing.T) {
+	rule, err := ParseFlowRule(`@Flow: Processing regions [ payment, delivery:Packing ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	want := []RegionRef{{Name: "payment"}, {Name: "delivery", Entry: "Packing"}}
+	if rule.Source.Phase != "Processing" || len(rule.Targets) != 0 || len(rule.Regions) != len(want) {
+		t.Fatalf("rule = %+v, want Processing with regions %v", rule, want)
+	}
+	for i, w := range want {
+		if rule.Regions[i] != w {
+			t.Errorf("region %d = %+v, want %+v", i, rule.Regions[i], w)
+		}
+	}
+
+	rule, err = ParseFlowRule(`@Flow: payment.Unpaid => [ payment.Paid final, Failed ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if rule.Source.Region != "payment" || rule.Source.Phase != "Unpaid" {
+		t.Errorf("Source = %+v, want payment.Unpaid", rule.Source)
+	}
+	if target := rule.Targets[0]; target.Region != "payment" || target.Phase != "Paid" || !target.Final {
+		t.Errorf("target 0 = %+v, want final payment.Paid", target)
+	}
+	if target := rule.Targets[1]; target.Region != "" || target.Phase != "Failed" {
+		t.Errorf("target 1 = %+v, want Failed", target)
+	}
+
+	rule, err = ParseFlowRule(`@Flow: Processing done => [ Completed ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if !rule.Source.Done || rule.Source.Phase != "Processing" || rule.Targets[0].Phase != "Completed" {
+		t.Errorf("rule = %+v, want Processing done => Completed", rule)
+	}
+
+	for _, line := range []string{
+		`@Flow: Processing regions [ ]`,
+		`@Flow: Processing regions payment`,
+		`@Flow: Processing(Busy) regions [ payment ]`,
+		`@Flow: Processing done`,
+		`@Flow: A => [ B via payment.Paying ]`,
+		`@Flow: payment. => [ Paid ]`,
+	} {
+		if _, err := ParseFlowRule(line); err == nil {
+			t.Errorf("ParseFlowRule(%q) expected error", line)
+		}
+	}
+}
+
+func TestParseFlowRule_After(t *testing.T) {
+	rule, err := ParseFlowRule(`@Flow: Paid => [ Shipped! via Reviewing after 72h, Expired final after 3d, Cancelled ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	want := []time.Duration{72 * time.Hour, 72 * time.Hour, 0}
+	for i, w := range want {
+		if rule.Targets[i].After != w {
+			t.Errorf("target %d After = %v, want %v", i, rule.Targets[i].After, w)
+		}
+	}
+	if !rule.Targets[1].Final || rule.Targets[0].Via != "Reviewing" {
+		t.Errorf("unexpected targets: %+v", rule.Targets)
+	}
+
+	rule, err = ParseFlowRule(`@Flow: PendingPayment => [ Expired, Cancelled ] after 30m`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	for i, target := range rule.Targets {
+		if target.After != 30*time.Minute {
+			t.Errorf("target %d After = %v, want 30m", i, target.After)
+		}
+	}
+
+	for _, input := range []string{
+		`@Flow: Paid => [ Expired after ]`,
+		`@Flow: Paid => [ Expired after soon ]`,
+		`@Flow: Paid => [ Expired after -1h ]`,
+		`@Flow: Paid => [ Expired after 0d ]`,
+		`@Flow: Paid => [ Expired after 1h after 2h ]`,
+		`@Flow: Paid => [ Expired after 1h ] after 2h`,
+	} {
+		if _, err := ParseFlowRule(input); err == nil {
+			t.Errorf("ParseFlowRule(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseFlowRule_ApprovalPolicy(t *testing.T) {
+	rule, err := ParseFlowRule(`@Flow: Submitted => [ Approved! via FinanceReview quorum 2, Escalated! via [ManagerReview, DirectorReview(Final)] else Draft, Paid ]`)
+	if err != nil {
+		t.Fatalf("ParseFlowRule() error = %v", err)
+	}
+	if len(rule.Targets) != 3 {
+		t.Fatalf("len(Targets) = %d, want 3", len(rule.Targets))
+	}
+	if target := rule.Targets[0]; target.Via != "FinanceReview" || target.Quorum != 2 || target.Steps != nil {
+		t.Errorf("target 0 = %+v, want via FinanceReview quorum 2", target)
+	}
+	target := rule.Targets[1]
+	want := []StateRef{{Phase: "ManagerReview"}, {Phase: "DirectorReview", Status: "Final"}}
+	if target.Via != "ManagerReview" || target.Else != "Draft" || len(target.Steps) != len(want) {
+		t.Fatalf("target 1 = %+v, want via [ManagerReview, DirectorReview(Final)] else Draft", target)
+	}
+	for i, w := range want {
+		if target.Steps[i] != w {
+			t.Errorf("step %d = %+v, want %+v", i, target.Steps[i], w)
+		}
+	}
+
+	for _, input := range []string{
+		`@Flow: Submitted => [ Approved! via Review quorum ]`,
+		`@Flow: Submitted => [ Approved! via Review quorum 0 ]`,
+		`@Flow: Submitted => [ Approved! via Review quorum two ]`,
+		`@Flow: Submitted => [ Approved! via Review quorum 2 quorum 3 ]`,
+		`@Flow: Submitted => [ Approved! via [A, A] ]`,
+		`@Flow: Submitted => [ Approved! via [A, B ]`,
+	} {
+		if _, err := ParseFlowRule(input); err == nil {
+			t.Errorf("ParseFlowRule(%q) expected error", input)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 type StateFlowV2Config struct {
 	Name         string
 	Output       string
+	Diagram      string
+	Schema       string
 	StatusType   string
 	StatusValues map[string]string
 }
@@ -33,6 +35,10 @@ func ParseStateFlowV2Config(text string) (*StateFlowV2Config, error) {
 				config.Name = value
 			case "output":
 				config.Output = value
+			case "diagram":
+				config.Diagram = value
+			case "schema":
+				config.Schema = value
 			case "statustype":
 				config.StatusType = value
 			case "statusvalues":
@@ -52,7 +58,7 @@ func ParseFlowV2Annotations(text string) (*StateFlowV2Config, []*FlowRule, error
 	var rules []*FlowRule
 
 	lines := strings.Split(text, "\n")
-	for _, line := range lines {
+	for i, line := range lines {
 		line = normalizeAnnotationLine(line)
 		if line == "" {
 			continue
@@ -75,6 +81,7 @@ func ParseFlowV2Annotations(text string) (*StateFlowV2Config, []*FlowRule, error
 			if err != nil {
 				return nil, nil, err
 			}
+			rule.Line = i + 1
 			rules = append(rules, rule)
 		}
 	}
@@ -0,0 +1,172 @@
+package stateflowgen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Region 复合状态内的区域
+// 复合状态只有一个区域时即为嵌套子流程，有多个区域时各区域并行（正交）流转
+type Region struct {
+	Name        string             // 区域名，如 "payment"
+	Composite   string             // 所属复合状态（Phase）
+	Entry       string             // 入口子状态，进入复合状态时自动设置
+	States      []string           // 子状态（保持定义顺序）
+	Finals      []string           // 标记为 final 的子状态
+	Transitions []RegionTransition // 区域内流转
+	Line        int                // 声明所在行
+}
+
+// RegionTransition 区域内的单条流转
+type RegionTransition struct {
+	From string
+	To   string
+	Line int
+}
+
+// HasRegions 是否定义了复合状态
+func (m *StateModel) HasRegions() bool {
+	return len(m.Regions) > 0
+}
+
+// CompositePhases 返回所有复合状态（保持声明顺序）
+func (m *StateModel) CompositePhases() []string {
+	var phases []string
+	for _, region := range m.Regions {
+		addOrderedString(&phases, region.Composite)
+	}
+	return phases
+}
+
+// RegionsOf 返回复合状态的所有区域
+func (m *StateModel) RegionsOf(phase string) []*Region {
+	var regions []*Region
+	for _, region := range m.Regions {
+		if region.Composite == phase {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// CompletionFrom 返回复合状态的完成流转，没有时 ok 为 false
+func (m *StateModel) CompletionFrom(phase string) (Transition, bool) {
+	for _, trans := range m.Completions {
+		if trans.From.Phase == phase {
+			return trans, true
+		}
+	}
+	return Transition{}, false
+}
+
+// flowTransitions 返回顶层流转与完成流转，用于流程图与静态分析
+func (m *StateModel) flowTransitions() []Transition {
+	if len(m.Completions) == 0 {
+		return m.Transitions
+	}
+	return append(slices.Clone(m.Transitions), m.Completions...)
+}
+
+// buildRegions 收集复合状态声明与区域内流转，并校验完成流转
+func buildRegions(model *StateModel, rules []*FlowRule) error {
+	regions := make(map[string]*Region)
+	for _, rule := range rules {
+		if len(rule.Regions) == 0 {
+			continue
+		}
+		phase := rule.Source.Phase
+		if len(model.RegionsOf(phase)) > 0 {
+			return fmt.Errorf("line %d: composite state %s is declared more than once", rule.Line, phase)
+		}
+		if len(model.PhaseStatus[phase]) > 0 {
+			return fmt.Errorf("line %d: composite state %s cannot have statuses", rule.Line, phase)
+		}
+		if slices.Contains(model.ViaPhases, phase) {
+			return fmt.Errorf("line %d: composite state %s cannot be used as via", rule.Line, phase)
+		}
+		for _, ref := range rule.Regions {
+			if regions[ref.Name] != nil {
+				return fmt.Errorf("line %d: region %s is already declared in %s", rule.Line, ref.Name, regions[ref.Name].Composite)
+			}
+			if strings.EqualFold(ref.Name, "current") || strings.EqualFold(ref.Name, "pending") {
+				return fmt.Errorf("line %d: region name %s is reserved", rule.Line, ref.Name)
+			}
+			region := &Region{Name: ref.Name, Composite: phase, Entry: ref.Entry, Line: rule.Line}
+			if ref.Entry != "" {
+				region.States = append(region.States, ref.Entry)
+			}
+			regions[ref.Name] = region
+			model.Regions = append(model.Regions, region)
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Source.Region == "" {
+			continue
+		}
+		region := regions[rule.Source.Region]
+		if region == nil {
+			return fmt.Errorf("line %d: region %s is not declared (use @Flow: Phase regions [ %s ])", rule.Line, rule.Source.Region, rule.Source.Region)
+		}
+		if err := addRegionRule(region, rule); err != nil {
+			return fmt.Errorf("line %d: %w", rule.Line, err)
+		}
+	}
+
+	for _, region := range model.Regions {
+		if len(region.States) == 0 {
+			return fmt.Errorf("line %d: region %s has no states", region.Line, region.Name)
+		}
+		if region.Entry == "" {
+			region.Entry = region.States[0]
+		}
+	}
+
+	for _, trans := range model.Completions {
+		if len(model.RegionsOf(trans.From.Phase)) == 0 {
+			return fmt.Errorf("line %d: done requires a composite state, %s has no regions", trans.Line, trans.From.Phase)
+		}
+		if trans.Via.Phase != "" || trans.Event != "" || trans.Self || trans.After > 0 {
+			return fmt.Errorf("line %d: completion transition of %s must be a plain target", trans.Line, trans.From.Phase)
+		}
+	}
+	for i, trans := range model.Completions {
+		for _, prev := range model.Completions[:i] {
+			if prev.From.Equal(trans.From) {
+				return fmt.Errorf("line %d: composite state %s already has a completion transition at line %d", trans.Line, trans.From.Phase, prev.Line)
+			}
+		}
+	}
+	return nil
+}
+
+// addRegionRule 将区域内规则加入区域，目标必须留在同一区域
+func addRegionRule(region *Region, rule *FlowRule) error {
+	source := rule.Source
+	if source.Status != "" || source.Wildcard || source.Done {
+		return fmt.Errorf("region state %s.%s cannot have status, wildcard or done", region.Name, source.Phase)
+	}
+	addOrderedString(&region.States, source.Phase)
+	if source.Final {
+		addOrderedString(&region.Finals, source.Phase)
+	}
+
+	for _, target := range rule.Targets {
+		if target.Region != "" && target.Region != region.Name {
+			return fmt.Errorf("region %s cannot transition to %s.%s", region.Name, target.Region, target.Phase)
+		}
+		if target.Phase == "" || target.Status != "" || target.Self {
+			return fmt.Errorf("region %s target must be a sub-state name", region.Name)
+		}
+		if target.ApprovalRequired || target.ApprovalOptional || target.Via != "" || target.Else != "" || target.Event != "" || target.Guard != "" || target.After > 0 {
+			return fmt.Errorf("region %s does not support approval, events, guards or after", region.Name)
+		}
+		addOrderedString(&region.States, target.Phase)
+		if target.Final {
+			addOrderedString(&region.Finals, target.Phase)
+		}
+		region.Transitions = append(region.Transitions, RegionTransition{From: source.Phase, To: target.Phase, Line: rule.Line})
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package stateflowgen
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const shipmentFlow = `@StateFlow(name="Shipment")
+@Flow: Created    => [ Processing ]
+@Flow: Processing regions [ payment, delivery:Packing ]
+@Flow: payment.Unpaid   => [ Paid final, Failed ]
+@Flow: payment.Failed   => [ Unpaid ]
+@Flow: delivery.Packing => [ Shipped ]
+@Flow: delivery.Shipped => [ Delivered final ]
+@Flow: Processing done  => [ Completed final ]
+@Flow: Processing       => [ Cancelled final ]`
+
+func TestBuildModel_Regions(t *testing.T) {
+	model := buildTestModel(t, shipmentFlow)
+
+	if want := []string{"Created", "Processing", "Completed", "Cancelled"}; !slices.Equal(model.Phases, want) {
+		t.Errorf("Phases = %v, want %v", model.Phases, want)
+	}
+	if len(model.Transitions) != 2 {
+		t.Errorf("Transitions = %+v, want Created -> Processing and Processing -> Cancelled", model.Transitions)
+	}
+	if len(model.Completions) != 1 || !model.Completions[0].Completion || model.Completions[0].To.Phase != "Completed" {
+		t.Errorf("Completions = %+v, want Processing -> Completed", model.Completions)
+	}
+
+	regions := model.RegionsOf("Processing")
+	if len(regions) != 2 {
+		t.Fatalf("RegionsOf(Processing) = %d regions, want 2", len(regions))
+	}
+	payment, delivery := regions[0], regions[1]
+	if payment.Entry != "Unpaid" || !slices.Equal(payment.States, []string{"Unpaid", "Paid", "Failed"}) || !slices.Equal(payment.Finals, []string{"Paid"}) {
+		t.Errorf("payment = %+v", payment)
+	}
+	if delivery.Entry != "Packing" || len(delivery.Transitions) != 2 || !slices.Equal(delivery.Finals, []string{"Delivered"}) {
+		t.Errorf("delivery = %+v", delivery)
+	}
+}
+
+func TestBuildModel_RegionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "undeclared region",
+			text: "@Flow: A => [ B ]\n@Flow: payment.Unpaid => [ Paid ]",
+			want: "region payment is not declared",
+		},
+		{
+			name: "cross region",
+			text: "@Flow: A => [ B ]\n@Flow: B regions [ x, y ]\n@Flow: x.One => [ y.Two ]",
+			want: "region x cannot transition to y.Two",
+		},
+		{
+			name: "top-level target in region",
+			text: "@Flow: A => [ B, x.One ]\n@Flow: B regions [ x ]\n@Flow: x.One => [ Two ]",
+			want: "can only be targeted within its region",
+		},
+		{
+			name: "approval in region",
+			text: "@Flow: A => [ B ]\n@Flow: B regions [ x ]\n@Flow: x.One => [ Two! via Checking ]",
+			want: "does not support approval",
+		},
+		{
+			name: "done without regions",
+			text: "@Flow: A => [ B ]\n@Flow: B done => [ C ]",
+			want: "done requires a composite state",
+		},
+		{
+			name: "composite with status",
+			text: "@Flow: A => [ B(Busy) ]\n@Flow: B regions [ x ]\n@Flow: x.One => [ Two ]",
+			want: "composite state B cannot have statuses",
+		},
+		{
+			name: "duplicate region",
+			text: "@Flow: A => [ B, C ]\n@Flow: B regions [ x ]\n@Flow: C regions [ x ]\n@Flow: x.One => [ Two ]",
+			want: "region x is already declared in B",
+		},
+		{
+			name: "empty region",
+			text: "@Flow: A => [ B ]\n@Flow: B regions [ x ]",
+			want: "region x has no states",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, rules, err := ParseFlowAnnotations(`@StateFlow(name="Test")` + "\n" + tt.text)
+			if err != nil {
+				t.Fatalf("ParseFlowAnnotations() error = %v", err)
+			}
+			_, err = BuildModel(config, rules)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("BuildModel() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintModel_Regions(t *testing.T) {
+	if issues := lintText(t, shipmentFlow); len(issues) != 0 {
+		t.Errorf("LintModel() = %v, want none", issues)
+	}
+
+	got := lintText(t, `@StateFlow(name="Shipment")
+@Flow: Created    => [ Processing ]
+@Flow: Processing regions [ payment, delivery ]
+@Flow: payment.Unpaid   => [ Paid final ]
+@Flow: payment.Paid     => [ Unpaid ]
+@Flow: payment.Refunded => [ Unpaid ]
+@Flow: delivery.Packing => [ Shipped ]
+@Flow: Processing done  => [ Completed final ]`)
+	want := []string{
+		"line 3: warning: region delivery has no final state, so Processing never completes (dead-end)",
+		"line 5: error: final state payment.Paid has outgoing transition to payment.Unpaid (final-outgoing)",
+		"line 6: error: state payment.Refunded is unreachable from entry payment.Unpaid (unreachable)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("LintModel() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
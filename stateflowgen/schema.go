@@ -0,0 +1,287 @@
+package stateflowgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// SchemaFormat 状态机定义的导出格式
+type SchemaFormat string
+
+const (
+	SchemaJSON       SchemaFormat = "json" // <name>_stateflow.json
+	SchemaTypeScript SchemaFormat = "ts"   // <name>_stateflow.ts
+)
+
+// ParseSchemaFormats 解析 schema 参数（逗号分隔，如 "json,ts"），空字符串表示不导出
+func ParseSchemaFormats(s string) ([]SchemaFormat, error) {
+	var formats []SchemaFormat
+	for _, item := range strings.Split(s, ",") {
+		var format SchemaFormat
+		switch strings.ToLower(strings.TrimSpace(item)) {
+		case "":
+			continue
+		case "json":
+			format = SchemaJSON
+		case "ts", "typescript":
+			format = SchemaTypeScript
+		default:
+			return nil, fmt.Errorf("unsupported schema format %q (want json or ts)", strings.TrimSpace(item))
+		}
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// Ext 返回导出文件的扩展名
+func (f SchemaFormat) Ext() string {
+	if f == SchemaTypeScript {
+		return ".ts"
+	}
+	return ".json"
+}
+
+// FlowSchema 展开后的状态机定义，供前端与其他语言的服务共用
+// 阶段以 Stage.String() 作为 ID，如 "Created(Paid)"
+type FlowSchema struct {
+	Name         string              `json:"name"`
+	Init         string              `json:"init"`
+	Phases       []string            `json:"phases"`
+	Statuses     map[string][]string `json:"statuses,omitempty"`     // Phase -> Status 列表
+	StatusValues map[string]any      `json:"statusValues,omitempty"` // V2 状态 -> 状态值
+	Stages       []SchemaStage       `json:"stages"`
+	Events       []string            `json:"events,omitempty"`
+	Regions      []SchemaRegion      `json:"regions,omitempty"`
+	Transitions  []SchemaTransition  `json:"transitions"`
+}
+
+// SchemaStage 阶段
+type SchemaStage struct {
+	ID     string `json:"id"`
+	Phase  string `json:"phase"`
+	Status string `json:"status,omitempty"`
+	Value  any    `json:"value,omitempty"` // V2 的状态值（statusValues）
+	Via    bool   `json:"via,omitempty"`   // 审批中间态
+	Final  bool   `json:"final,omitempty"`
+}
+
+// SchemaTransition 流转
+type SchemaTransition struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Approval string   `json:"approval,omitempty"` // required / optional
+	Via      []string `json:"via,omitempty"`      // 审批中间态，多级审批按顺序排列
+	Quorum   int      `json:"quorum,omitempty"`
+	Fallback string   `json:"fallback,omitempty"`
+	Event    string   `json:"event,omitempty"`
+	Guard    string   `json:"guard,omitempty"`
+	After    string   `json:"after,omitempty"`
+	Done     bool     `json:"done,omitempty"` // 复合状态的完成流转
+}
+
+// SchemaRegion 复合状态的区域
+type SchemaRegion struct {
+	Name        string      `json:"name"`
+	Composite   string      `json:"composite"`
+	Entry       string      `json:"entry"`
+	States      []string    `json:"states"`
+	Finals      []string    `json:"finals,omitempty"`
+	Transitions [][2]string `json:"transitions"`
+}
+
+// NewFlowSchema 从 @StateFlow 模型构建导出定义
+func NewFlowSchema(model *StateModel) *FlowSchema {
+	s := &FlowSchema{
+		Name:        model.Name,
+		Init:        model.InitStage.String(),
+		Phases:      slices.Clone(model.Phases),
+		Events:      slices.Clone(model.Events),
+		Transitions: []SchemaTransition{},
+	}
+
+	if model.HasStatus {
+		s.Statuses = make(map[string][]string)
+		for _, phase := range model.Phases {
+			if statuses := model.PhaseStatus[phase]; len(statuses) > 0 {
+				s.Statuses[phase] = statuses
+			}
+		}
+	}
+
+	for _, stage := range model.GetAllStages() {
+		s.Stages = append(s.Stages, SchemaStage{
+			ID:     stage.String(),
+			Phase:  stage.Phase,
+			Status: stage.Status,
+			Via:    slices.Contains(model.ViaPhases, stage.Phase),
+			Final:  slices.ContainsFunc(model.Finals, stage.Equal),
+		})
+	}
+
+	for _, trans := range model.flowTransitions() {
+		st := SchemaTransition{
+			From:  trans.From.String(),
+			To:    trans.To.String(),
+			Event: trans.Event,
+			Guard: trans.Guard,
+			Done:  trans.Completion,
+		}
+		if trans.After > 0 {
+			st.After = formatAfter(trans.After)
+		}
+		if trans.Via.Phase != "" {
+			st.Approval = "required"
+			if trans.ApprovalOptional {
+				st.Approval = "optional"
+			}
+			for _, step := range trans.ApprovalSteps() {
+				st.Via = append(st.Via, step.String())
+			}
+			if trans.Quorum > 1 {
+				st.Quorum = trans.Quorum
+			}
+			st.Fallback = trans.Fallback.String()
+		}
+		s.Transitions = append(s.Transitions, st)
+	}
+
+	for _, region := range model.Regions {
+		sr := SchemaRegion{
+			Name:        region.Name,
+			Composite:   region.Composite,
+			Entry:       region.Entry,
+			States:      region.States,
+			Finals:      region.Finals,
+			Transitions: [][2]string{},
+		}
+		for _, trans := range region.Transitions {
+			sr.Transitions = append(sr.Transitions, [2]string{trans.From, trans.To})
+		}
+		s.Regions = append(s.Regions, sr)
+	}
+	return s
+}
+
+// NewFlowSchemaV2 从 @StateFlowV2 模型构建导出定义
+// V2 的状态即阶段，状态值取自 statusValues（未配置时为状态名）
+func NewFlowSchemaV2(model *StateFlowV2Model) *FlowSchema {
+	s := &FlowSchema{
+		Name:         model.Name,
+		Init:         model.InitStatus,
+		Phases:       slices.Clone(model.Statuses),
+		StatusValues: make(map[string]any),
+		Transitions:  []SchemaTransition{},
+	}
+
+	for _, status := range model.Statuses {
+		value := statusSchemaValue(model.StatusValues, status)
+		s.StatusValues[status] = value
+		s.Stages = append(s.Stages, SchemaStage{
+			ID:    status,
+			Phase: status,
+			Value: value,
+			Final: slices.Contains(model.Finals, status),
+		})
+	}
+	for _, via := range model.Stages {
+		if via == "none" {
+			continue
+		}
+		s.Stages = append(s.Stages, SchemaStage{ID: via, Phase: via, Via: true})
+	}
+
+	for _, trans := range model.Transitions {
+		st := SchemaTransition{From: trans.From, To: trans.To}
+		if trans.ApprovalRequired || trans.ApprovalOptional {
+			st.Approval = "required"
+			if trans.ApprovalOptional {
+				st.Approval = "optional"
+			}
+			st.Via = []string{trans.Via}
+			st.Fallback = trans.Fallback
+		}
+		s.Transitions = append(s.Transitions, st)
+	}
+	return s
+}
+
+// statusSchemaValue 将 statusValues 中的 Go 字面量转换为 JSON 值
+func statusSchemaValue(values map[string]string, status string) any {
+	literal, ok := values[status]
+	if !ok {
+		return status
+	}
+	if n, err := strconv.ParseInt(literal, 0, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f
+	}
+	if unquoted, err := strconv.Unquote(literal); err == nil {
+		return unquoted
+	}
+	return literal
+}
+
+// JSON 渲染为缩进的 JSON
+func (s *FlowSchema) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// TypeScript 渲染为 TypeScript 模块：阶段联合类型、定义常量与流转查询函数
+func (s *FlowSchema) TypeScript() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := s.Name
+	if name == "" {
+		name = "StateFlow"
+	}
+	stageType := name + "Stage"
+	prefix := string(utils.EString(name).LowerCamelCase())
+	flowConst := prefix + "Flow"
+
+	var ids []string
+	for _, stage := range s.Stages {
+		ids = append(ids, strconv.Quote(stage.ID))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by gogen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&sb, "export type %s = %s;\n\n", stageType, strings.Join(ids, " | "))
+	fmt.Fprintf(&sb, "export const %s = %s as const;\n\n", flowConst, data)
+	fmt.Fprintf(&sb, `/** 从 from 出发的合法目标阶段（去重，保持定义顺序） */
+export function %sValidTransitions(from: %s): %s[] {
+  const targets = %s.transitions.filter((t) => t.from === from).map((t) => t.to as %s);
+  return Array.from(new Set(targets));
+}
+
+/** from -> to 是否为合法流转 */
+export function %sCanTransition(from: %s, to: %s): boolean {
+  return %s.transitions.some((t) => t.from === from && t.to === to);
+}
+`, prefix, stageType, stageType, flowConst, stageType, prefix, stageType, stageType, flowConst)
+	return sb.String(), nil
+}
+
+// Render 按指定格式渲染
+func (s *FlowSchema) Render(format SchemaFormat) ([]byte, error) {
+	if format == SchemaTypeScript {
+		ts, err := s.TypeScript()
+		return []byte(ts), err
+	}
+	return s.JSON()
+}
@@ -0,0 +1,129 @@
+package stateflowgen
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func TestParseSchemaFormats(t *testing.T) {
+	formats, err := ParseSchemaFormats("json, TypeScript, json")
+	if err != nil {
+		t.Fatalf("ParseSchemaFormats() error = %v", err)
+	}
+	if len(formats) != 2 || formats[0] != SchemaJSON || formats[1] != SchemaTypeScript {
+		t.Errorf("formats = %v, want [json ts]", formats)
+	}
+	if formats, err := ParseSchemaFormats(""); err != nil || len(formats) != 0 {
+		t.Errorf("ParseSchemaFormats(\"\") = %v, %v", formats, err)
+	}
+	if _, err := ParseSchemaFormats("yaml"); err == nil {
+		t.Error("ParseSchemaFormats(yaml) expected error")
+	}
+}
+
+func TestNewFlowSchema(t *testing.T) {
+	schema := NewFlowSchema(buildTestModel(t, `
+@StateFlow(name="Order")
+@Flow: Created(Pending) => [ (Paid), Cancelled ]
+@Flow: Created(Paid)    => [ Shipped! via [Review, Audit] quorum 2 else Created(Pending) ]
+@Flow: Shipped          => [ Delivered final after 3d ]
+`))
+
+	if schema.Init != "Created(Pending)" || strings.Join(schema.Statuses["Created"], ",") != "Paid,Pending" {
+		t.Errorf("Init = %s, Statuses = %v", schema.Init, schema.Statuses)
+	}
+	stages := make(map[string]SchemaStage)
+	for _, stage := range schema.Stages {
+		stages[stage.ID] = stage
+	}
+	if !stages["Review"].Via || !stages["Audit"].Via || !stages["Delivered"].Final || stages["Shipped"].Via {
+		t.Errorf("unexpected stages: %+v", schema.Stages)
+	}
+
+	approval := schema.Transitions[2]
+	if approval.From != "Created(Paid)" || approval.Approval != "required" || strings.Join(approval.Via, ",") != "Review,Audit" ||
+		approval.Quorum != 2 || approval.Fallback != "Created(Pending)" {
+		t.Errorf("approval transition = %+v", approval)
+	}
+	if timed := schema.Transitions[3]; timed.After != "72h" {
+		t.Errorf("timed transition = %+v, want after 72h", timed)
+	}
+
+	ts, err := schema.TypeScript()
+	if err != nil {
+		t.Fatalf("TypeScript() error = %v", err)
+	}
+	for _, want := range []string{
+		`export type OrderStage = "Created(Paid)" | "Created(Pending)" | "Cancelled" | "Shipped" | "Review" | "Audit" | "Delivered";`,
+		"export const orderFlow = {",
+		"} as const;",
+		"export function orderValidTransitions(from: OrderStage): OrderStage[] {",
+		"export function orderCanTransition(from: OrderStage, to: OrderStage): boolean {",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("TypeScript output missing %q:\n%s", want, ts)
+		}
+	}
+}
+
+func TestStateFlowGenerator_SchemaFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package order
+
+// @StateFlow(name="Order", schema="json,ts")
+// @Flow: Draft => [ Paid! via Review ]
+// @Flow: Paid  => [ Shipped ]
+const _ = ""
+
+// @StateFlowV2(name="Wallet", output=wallet_state.go, schema=json, statusType=int, statusValues="initial=1,active=2,rejected=3")
+// @Flow: initial => [ active? via waiting_approval else rejected ]
+const _ = ""
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "order.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	registry := plugin.NewRegistry()
+	if err := registry.Register(NewStateFlowGenerator()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(NewStateFlowV2Generator()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := plugin.Run(context.Background(), registry, "", tmpDir); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var order FlowSchema
+	data, err := os.ReadFile(filepath.Join(tmpDir, "order_stateflow.json"))
+	if err != nil {
+		t.Fatalf("ReadFile(order_stateflow.json) error = %v", err)
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(order.Transitions) != 2 || order.Transitions[0].Approval != "required" || order.Transitions[0].Via[0] != "Review" {
+		t.Errorf("order transitions = %+v", order.Transitions)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "order_stateflow.ts")); err != nil {
+		t.Errorf("Stat(order_stateflow.ts) error = %v", err)
+	}
+
+	var wallet FlowSchema
+	data, err = os.ReadFile(filepath.Join(tmpDir, "wallet_stateflow.json"))
+	if err != nil {
+		t.Fatalf("ReadFile(wallet_stateflow.json) error = %v", err)
+	}
+	if err := json.Unmarshal(data, &wallet); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if wallet.StatusValues["active"] != float64(2) || wallet.Transitions[0].Approval != "optional" || wallet.Transitions[0].Fallback != "rejected" {
+		t.Errorf("wallet schema = %+v", wallet)
+	}
+}
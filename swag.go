@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/donutnomad/gogen/swaggen"
+)
+
+// runSwag 处理 swag 子命令
+func runSwag(args []string) {
+	if len(args) == 0 {
+		swagUsage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch args[0] {
+	case "diff":
+		err = swagDiff(args[1:], os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "错误: 未知的 swag 命令 %q\n", args[0])
+		swagUsage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// swagDiff 比较 base 版本与工作区中 swaggen 接口的差异，存在破坏性变更时返回错误
+// 用法: gogen swag diff --base <git-ref> [路径...]
+func swagDiff(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("swag diff", flag.ContinueOnError)
+	base := fs.String("base", "", "对比的 git 版本（分支、标签或提交）")
+
+	// 允许选项出现在路径之后
+	var patterns []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		patterns = append(patterns, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if *base == "" {
+		return fmt.Errorf("用法: gogen swag diff --base <git-ref> [路径...]")
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	ctx := context.Background()
+	baseDoc, err := swaggen.LoadOpenAPIAtRevision(ctx, *base, patterns...)
+	if err != nil {
+		return fmt.Errorf("加载 %s 版本的接口失败: %w", *base, err)
+	}
+	headDoc, err := swaggen.LoadOpenAPI(ctx, patterns...)
+	if err != nil {
+		return fmt.Errorf("加载工作区的接口失败: %w", err)
+	}
+
+	diff := swaggen.DiffOpenAPI(baseDoc, headDoc)
+	if err := diff.Render(stdout); err != nil {
+		return err
+	}
+	if n := len(diff.Breaking()); n > 0 {
+		return fmt.Errorf("发现 %d 个破坏性变更", n)
+	}
+	return nil
+}
+
+func swagUsage() {
+	_, _ = fmt.Fprintf(os.Stderr, `用法:
+  gogen swag diff --base <git-ref> [路径...]
+
+命令:
+  diff      比较 git 版本与工作区中 swaggen 接口的差异
+            破坏性变更：删除或改名的路由、HTTP 方法变更、新增必填参数、删除响应字段、
+            请求/响应字段类型变更、收紧的校验规则、新增认证要求；存在时返回非零退出码
+            新增路由、可选参数与字段、放宽的校验等作为非破坏性变更单独列出
+
+示例:
+  gogen swag diff --base main
+  gogen swag diff --base origin/main ./api/...
+`)
+}
@@ -0,0 +1,577 @@
+package swaggen
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/donutnomad/gogen/plugin"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// 接口加载
+// ============================================================================
+
+// LoadOpenAPI 扫描 patterns 中声明了路由注解的接口，构建合并后的 OpenAPI 文档
+func LoadOpenAPI(ctx context.Context, patterns ...string) (*OpenAPIDoc, error) {
+	scanned, err := plugin.Scan(ctx, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewSwagGenerator()
+	var ifaces []SwaggerInterface
+	for _, at := range scanned.Interfaces {
+		if getFirstAnnotation(at.Annotations, "GET", "POST", "PUT", "PATCH", "DELETE") == nil {
+			continue
+		}
+		iface, err := g.parseInterface(at)
+		if err != nil {
+			return nil, fmt.Errorf("解析接口 %s 失败: %w", at.Target.Name, err)
+		}
+		if iface != nil && len(iface.Methods) > 0 {
+			ifaces = append(ifaces, *iface)
+		}
+	}
+	slices.SortFunc(ifaces, func(a, b SwaggerInterface) int {
+		return strings.Compare(a.FilePath+":"+a.Name, b.FilePath+":"+b.Name)
+	})
+	return BuildOpenAPI(NewTypeLoader(), ifaces, OpenAPIInfo{})
+}
+
+// LoadOpenAPIAtRevision 在 git 版本 ref 上构建 OpenAPI 文档
+// 该版本被导出到临时目录，patterns 按其在仓库中的相对位置映射；该版本中不存在的路径被忽略
+func LoadOpenAPIAtRevision(ctx context.Context, ref string, patterns ...string) (*OpenAPIDoc, error) {
+	root, err := gitOutput(ctx, ".", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, err
+	}
+	if _, err := gitOutput(ctx, root, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("无效的 git 版本 %q", ref)
+	}
+
+	dir, err := os.MkdirTemp("", "gogen-swag-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	archive, err := gitOutputBytes(ctx, root, "archive", "--format=tar", ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := extractTar(bytes.NewReader(archive), dir); err != nil {
+		return nil, fmt.Errorf("导出 %s 失败: %w", ref, err)
+	}
+
+	var mapped []string
+	for _, pattern := range patterns {
+		recursive := strings.HasSuffix(pattern, "/...")
+		pattern = strings.TrimSuffix(pattern, "/...")
+		abs, err := filepath.Abs(pattern)
+		if err != nil {
+			return nil, err
+		}
+		// 工作区中已删除的路径无法解析符号链接，按原路径计算
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s 不在仓库 %s 中", pattern, root)
+		}
+		path := filepath.Join(dir, rel)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if recursive {
+			path += "/..."
+		}
+		mapped = append(mapped, path)
+	}
+	if len(mapped) == 0 {
+		return &OpenAPIDoc{Paths: make(map[string]map[string]*Operation)}, nil
+	}
+	return LoadOpenAPI(ctx, mapped...)
+}
+
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	out, err := gitOutputBytes(ctx, dir, args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+func gitOutputBytes(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// extractTar 将 git archive 的输出解压到 dir
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return fmt.Errorf("非法路径 %s", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, data, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, path); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ============================================================================
+// 变更检测
+// ============================================================================
+
+// APIChange 两个版本之间的一处接口变更
+type APIChange struct {
+	Breaking bool   // 是否为破坏性变更
+	Route    string // 路由，如 GET /api/users/{id}
+	Location string // 变更位置，如 query.page、body.name、response.data.email；路由级变更为空
+	Message  string
+}
+
+func (c APIChange) String() string {
+	if c.Location == "" {
+		return c.Route + ": " + c.Message
+	}
+	return c.Route + " " + c.Location + ": " + c.Message
+}
+
+// APIDiff 两个版本之间的全部接口变更，按路由排序
+type APIDiff struct {
+	Changes []APIChange
+}
+
+// Breaking 返回破坏性变更
+func (d *APIDiff) Breaking() []APIChange {
+	return lo.Filter(d.Changes, func(c APIChange, _ int) bool { return c.Breaking })
+}
+
+// NonBreaking 返回非破坏性变更（新增路由、可选参数与字段、放宽的校验等）
+func (d *APIDiff) NonBreaking() []APIChange {
+	return lo.Filter(d.Changes, func(c APIChange, _ int) bool { return !c.Breaking })
+}
+
+// Render 以文本输出变更，破坏性变更在前
+func (d *APIDiff) Render(w io.Writer) error {
+	if len(d.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "未发现接口变更")
+		return err
+	}
+	var sb strings.Builder
+	for _, group := range []struct {
+		title   string
+		changes []APIChange
+	}{
+		{"破坏性变更", d.Breaking()},
+		{"非破坏性变更", d.NonBreaking()},
+	} {
+		if len(group.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (%d):\n", group.title, len(group.changes))
+		for _, c := range group.changes {
+			fmt.Fprintf(&sb, "  - %s\n", c)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// schemaDir Schema 所在的方向：请求中收紧约束、响应中删除字段属于破坏性变更
+type schemaDir int
+
+const (
+	dirRequest schemaDir = iota
+	dirResponse
+)
+
+// apiRoute 文档中的单个操作
+type apiRoute struct {
+	method string
+	path   string
+	op     *Operation
+}
+
+func (r apiRoute) String() string {
+	return strings.ToUpper(r.method) + " " + r.path
+}
+
+// DiffOpenAPI 比较两个版本的 OpenAPI 文档
+// 路由按 HTTP 方法与路径匹配；匹配不到时按 operationId（方法名）识别路径或 HTTP 方法的变更
+func DiffOpenAPI(base, head *OpenAPIDoc) *APIDiff {
+	d := &apiDiffer{base: base, head: head, diff: &APIDiff{}, visiting: make(map[string]bool)}
+
+	baseRoutes, headRoutes := collectRoutes(base), collectRoutes(head)
+	baseKeys := make(map[string]bool, len(baseRoutes))
+	for _, r := range baseRoutes {
+		baseKeys[r.String()] = true
+	}
+	headByKey := make(map[string]apiRoute, len(headRoutes))
+	headByID := make(map[string]apiRoute, len(headRoutes))
+	for _, r := range headRoutes {
+		headByKey[r.String()] = r
+		if !baseKeys[r.String()] {
+			headByID[r.op.OperationID] = r
+		}
+	}
+
+	matched := make(map[string]bool)
+	for _, b := range baseRoutes {
+		if h, ok := headByKey[b.String()]; ok {
+			matched[h.String()] = true
+			d.compareOperation(h.String(), b.op, h.op)
+			continue
+		}
+		h, ok := headByID[b.op.OperationID]
+		if !ok || matched[h.String()] {
+			d.add(true, b.String(), "", "路由已删除")
+			continue
+		}
+		matched[h.String()] = true
+		if b.method != h.method {
+			d.add(true, b.String(), "", fmt.Sprintf("HTTP 方法变更为 %s", strings.ToUpper(h.method)))
+		}
+		if b.path != h.path {
+			d.add(true, b.String(), "", fmt.Sprintf("路径变更为 %s", h.path))
+		}
+		d.compareOperation(h.String(), b.op, h.op)
+	}
+	for _, h := range headRoutes {
+		if !matched[h.String()] {
+			d.add(false, h.String(), "", "新增路由")
+		}
+	}
+
+	slices.SortStableFunc(d.diff.Changes, func(a, b APIChange) int {
+		return strings.Compare(a.Route, b.Route)
+	})
+	return d.diff
+}
+
+// collectRoutes 按路径、HTTP 方法排序返回文档中的所有操作
+func collectRoutes(doc *OpenAPIDoc) []apiRoute {
+	var routes []apiRoute
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			routes = append(routes, apiRoute{method: method, path: path, op: op})
+		}
+	}
+	slices.SortFunc(routes, func(a, b apiRoute) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return routes
+}
+
+type apiDiffer struct {
+	base, head *OpenAPIDoc
+	diff       *APIDiff
+	visiting   map[string]bool // 正在比较的组件对，避免递归类型无限展开
+}
+
+func (d *apiDiffer) add(breaking bool, route, loc, msg string) {
+	d.diff.Changes = append(d.diff.Changes, APIChange{Breaking: breaking, Route: route, Location: loc, Message: msg})
+}
+
+// compareOperation 比较同一路由的认证、参数、请求体与成功响应
+func (d *apiDiffer) compareOperation(route string, b, h *Operation) {
+	baseSecurity, headSecurity := securityNames(b), securityNames(h)
+	for _, name := range headSecurity {
+		if !slices.Contains(baseSecurity, name) {
+			d.add(true, route, "", fmt.Sprintf("新增认证要求 %s", name))
+		}
+	}
+	for _, name := range baseSecurity {
+		if !slices.Contains(headSecurity, name) {
+			d.add(false, route, "", fmt.Sprintf("移除认证要求 %s", name))
+		}
+	}
+
+	d.compareParameters(route, b.Parameters, h.Parameters)
+	d.compareRequestBody(route, b.RequestBody, h.RequestBody)
+
+	for _, status := range sortedKeys(h.Responses) {
+		if _, ok := b.Responses[status]; !ok {
+			d.add(false, route, "", fmt.Sprintf("新增 %s 响应", status))
+		}
+	}
+	if b.Responses["200"] != nil && h.Responses["200"] != nil {
+		d.compareContent(route, "response", dirResponse, b.Responses["200"].Content, h.Responses["200"].Content)
+	}
+}
+
+func securityNames(op *Operation) []string {
+	var names []string
+	for _, item := range op.Security {
+		names = append(names, sortedKeys(item)...)
+	}
+	return names
+}
+
+// compareParameters 比较 path / query / header 参数；新增必填参数与参数变为必填属于破坏性变更
+func (d *apiDiffer) compareParameters(route string, base, head []*OpenAPIParameter) {
+	key := func(p *OpenAPIParameter) string { return p.In + "." + p.Name }
+	baseByKey := lo.KeyBy(base, key)
+	headByKey := lo.KeyBy(head, key)
+
+	for _, b := range base {
+		loc := key(b)
+		h, ok := headByKey[loc]
+		if !ok {
+			d.add(false, route, loc, "参数已删除")
+			continue
+		}
+		switch {
+		case h.Required && !b.Required:
+			d.add(true, route, loc, "参数变为必填")
+		case !h.Required && b.Required:
+			d.add(false, route, loc, "参数变为可选")
+		}
+		d.compareSchema(route, loc, dirRequest, b.Schema, h.Schema)
+	}
+	for _, h := range head {
+		if _, ok := baseByKey[key(h)]; ok {
+			continue
+		}
+		if h.Required {
+			d.add(true, route, key(h), "新增必填参数")
+		} else {
+			d.add(false, route, key(h), "新增可选参数")
+		}
+	}
+}
+
+func (d *apiDiffer) compareRequestBody(route string, b, h *RequestBody) {
+	switch {
+	case b == nil && h == nil:
+	case b == nil:
+		d.add(h.Required, route, "body", "新增请求体")
+	case h == nil:
+		d.add(false, route, "body", "请求体已删除")
+	default:
+		d.compareContent(route, "body", dirRequest, b.Content, h.Content)
+	}
+}
+
+// compareContent 比较请求体或响应的各 MIME 类型；旧版本中的 MIME 类型不再支持属于破坏性变更
+func (d *apiDiffer) compareContent(route, loc string, dir schemaDir, base, head map[string]*MediaType) {
+	if len(base) > 0 && len(head) == 0 && dir == dirResponse {
+		d.add(true, route, loc, "响应体已删除")
+		return
+	}
+	if len(base) == 0 && len(head) > 0 && dir == dirResponse {
+		d.add(false, route, loc, "新增响应体")
+		return
+	}
+	for _, ct := range sortedKeys(base) {
+		h, ok := head[ct]
+		if !ok {
+			d.add(true, route, loc, fmt.Sprintf("不再支持 %s，当前为 %s", ct, strings.Join(sortedKeys(head), ", ")))
+			continue
+		}
+		d.compareSchema(route, loc, dir, base[ct].Schema, h.Schema)
+	}
+}
+
+// resolveSchema 解析 $ref 引用的组件
+func resolveSchema(doc *OpenAPIDoc, s *Schema) *Schema {
+	if s == nil || s.Ref == "" || doc.Components == nil {
+		return s
+	}
+	if target, ok := doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]; ok {
+		return target
+	}
+	return s
+}
+
+// compareSchema 递归比较 Schema：类型变更、字段增删、必填与校验约束的变化
+func (d *apiDiffer) compareSchema(route, loc string, dir schemaDir, b, h *Schema) {
+	if b == nil || h == nil {
+		return
+	}
+	if b.Ref != "" || h.Ref != "" {
+		pair := fmt.Sprintf("%s|%s|%d", b.Ref, h.Ref, dir)
+		if d.visiting[pair] {
+			return
+		}
+		d.visiting[pair] = true
+		defer delete(d.visiting, pair)
+	}
+	b, h = resolveSchema(d.base, b), resolveSchema(d.head, h)
+
+	if bt, ht := schemaTypeName(b), schemaTypeName(h); bt != ht {
+		d.add(true, route, loc, fmt.Sprintf("类型由 %s 变为 %s", bt, ht))
+		return
+	}
+
+	if b.Items != nil && h.Items != nil {
+		d.compareSchema(route, loc+"[]", dir, b.Items, h.Items)
+	}
+	if b.AdditionalProperties != nil && h.AdditionalProperties != nil {
+		d.compareSchema(route, loc+"{}", dir, b.AdditionalProperties, h.AdditionalProperties)
+	}
+	d.compareProperties(route, loc, dir, b, h)
+	d.compareEnum(route, loc, dir, b.Enum, h.Enum)
+	if dir == dirRequest {
+		d.compareConstraints(route, loc, b, h)
+	}
+}
+
+// compareProperties 请求中新增必填字段、字段变为必填属于破坏性变更；响应中删除字段属于破坏性变更
+func (d *apiDiffer) compareProperties(route, loc string, dir schemaDir, b, h *Schema) {
+	for _, name := range sortedKeys(b.Properties) {
+		fieldLoc := loc + "." + name
+		hp, ok := h.Properties[name]
+		if !ok {
+			d.add(dir == dirResponse, route, fieldLoc, "字段已删除")
+			continue
+		}
+		if dir == dirRequest {
+			bRequired, hRequired := slices.Contains(b.Required, name), slices.Contains(h.Required, name)
+			switch {
+			case hRequired && !bRequired:
+				d.add(true, route, fieldLoc, "字段变为必填")
+			case !hRequired && bRequired:
+				d.add(false, route, fieldLoc, "字段变为可选")
+			}
+		}
+		d.compareSchema(route, fieldLoc, dir, b.Properties[name], hp)
+	}
+	for _, name := range sortedKeys(h.Properties) {
+		if _, ok := b.Properties[name]; ok {
+			continue
+		}
+		fieldLoc := loc + "." + name
+		switch {
+		case dir == dirRequest && slices.Contains(h.Required, name):
+			d.add(true, route, fieldLoc, "新增必填字段")
+		case dir == dirRequest:
+			d.add(false, route, fieldLoc, "新增可选字段")
+		default:
+			d.add(false, route, fieldLoc, "新增字段")
+		}
+	}
+}
+
+// compareEnum 请求中可选值减少或新增取值限制属于破坏性变更
+func (d *apiDiffer) compareEnum(route, loc string, dir schemaDir, base, head []any) {
+	baseValues := lo.Map(base, func(v any, _ int) string { return fmt.Sprint(v) })
+	headValues := lo.Map(head, func(v any, _ int) string { return fmt.Sprint(v) })
+	if len(headValues) == 0 {
+		if len(baseValues) > 0 {
+			d.add(false, route, loc, "取消可选值限制")
+		}
+		return
+	}
+	if len(baseValues) == 0 {
+		d.add(dir == dirRequest, route, loc, fmt.Sprintf("限制可选值为 %s", strings.Join(headValues, ", ")))
+		return
+	}
+	if removed, _ := lo.Difference(baseValues, headValues); len(removed) > 0 {
+		d.add(dir == dirRequest, route, loc, fmt.Sprintf("删除可选值 %s", strings.Join(removed, ", ")))
+	}
+	if added, _ := lo.Difference(headValues, baseValues); len(added) > 0 {
+		d.add(false, route, loc, fmt.Sprintf("新增可选值 %s", strings.Join(added, ", ")))
+	}
+}
+
+// compareConstraints 比较请求中的校验约束：下限提高、上限降低、新增或修改 pattern 属于破坏性变更
+func (d *apiDiffer) compareConstraints(route, loc string, b, h *Schema) {
+	compareBound(d, route, loc, "最小值", b.Minimum, h.Minimum, true)
+	compareBound(d, route, loc, "最大值", b.Maximum, h.Maximum, false)
+	compareBound(d, route, loc, "最小长度", b.MinLength, h.MinLength, true)
+	compareBound(d, route, loc, "最大长度", b.MaxLength, h.MaxLength, false)
+	compareBound(d, route, loc, "最少元素数", b.MinItems, h.MinItems, true)
+	compareBound(d, route, loc, "最多元素数", b.MaxItems, h.MaxItems, false)
+
+	switch {
+	case b.Pattern == h.Pattern:
+	case h.Pattern == "":
+		d.add(false, route, loc, fmt.Sprintf("移除 pattern %s", b.Pattern))
+	case b.Pattern == "":
+		d.add(true, route, loc, fmt.Sprintf("新增 pattern %s", h.Pattern))
+	default:
+		d.add(true, route, loc, fmt.Sprintf("pattern 由 %s 变为 %s", b.Pattern, h.Pattern))
+	}
+}
+
+// compareBound 比较上下限约束；lower 为下限时数值提高为收紧，上限时数值降低为收紧
+func compareBound[T int | float64](d *apiDiffer, route, loc, label string, base, head *T, lower bool) {
+	if base == nil && head == nil || base != nil && head != nil && *base == *head {
+		return
+	}
+	show := func(v *T) string {
+		if v == nil {
+			return "无"
+		}
+		return fmt.Sprint(*v)
+	}
+	tightened := head != nil && (base == nil || (lower && *head > *base) || (!lower && *head < *base))
+	d.add(tightened, route, loc, fmt.Sprintf("%s由 %s 变为 %s", label, show(base), show(head)))
+}
+
+// schemaTypeName 用于比较的类型名：type 与 format，未声明类型时为 any
+func schemaTypeName(s *Schema) string {
+	name := s.Type
+	if name == "" {
+		name = "any"
+	}
+	if s.Format != "" {
+		name += "(" + s.Format + ")"
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
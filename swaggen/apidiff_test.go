@@ -0,0 +1,145 @@
+package swaggen
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func loadDiffDoc(t *testing.T, dir string) *OpenAPIDoc {
+	t.Helper()
+	doc, err := LoadOpenAPI(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func changeStrings(changes []APIChange) []string {
+	var out []string
+	for _, c := range changes {
+		out = append(out, c.String())
+	}
+	return out
+}
+
+func TestDiffOpenAPI(t *testing.T) {
+	diff := DiffOpenAPI(loadDiffDoc(t, "testdata/apidiff/base"), loadDiffDoc(t, "testdata/apidiff/head"))
+
+	breaking := changeStrings(diff.Breaking())
+	for _, want := range []string{
+		// 删除与变更的路由
+		"DELETE /api/users/{id}: 路由已删除",
+		"GET /api/users/{id}/avatar: 路径变更为 /api/users/{id}/photo",
+		"PUT /api/users/{id}/name: HTTP 方法变更为 PATCH",
+		// 新增必填参数、认证要求
+		"GET /api/users query.page: 参数变为必填",
+		"GET /api/users: 新增认证要求 Bearer",
+		// 响应字段删除、请求字段类型变更与收紧的校验
+		"GET /api/users/{id} response.email: 字段已删除",
+		"GET /api/users response[].email: 字段已删除",
+		"POST /api/users body.age: 类型由 integer(int64) 变为 string",
+		"POST /api/users body.name: 最大长度由 32 变为 16",
+		"POST /api/users body.email: 新增必填字段",
+	} {
+		if !slices.Contains(breaking, want) {
+			t.Errorf("missing breaking change %q, got:\n%s", want, strings.Join(breaking, "\n"))
+		}
+	}
+
+	nonBreaking := changeStrings(diff.NonBreaking())
+	for _, want := range []string{
+		"GET /api/users/search: 新增路由",
+		"GET /api/users query.status: 新增可选参数",
+		"GET /api/users/{id} response.nickname: 新增字段",
+		"GET /api/users/{id} response.role: 新增可选值 guest",
+		"GET /api/users/{id}/photo query.size: 最大值由 100 变为 200",
+		"POST /api/users body.note: 字段已删除",
+	} {
+		if !slices.Contains(nonBreaking, want) {
+			t.Errorf("missing non-breaking change %q, got:\n%s", want, strings.Join(nonBreaking, "\n"))
+		}
+	}
+
+	var out strings.Builder
+	if err := diff.Render(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "破坏性变更 (") || !strings.Contains(out.String(), "\n非破坏性变更 (") {
+		t.Errorf("unexpected render output:\n%s", out.String())
+	}
+}
+
+func TestDiffOpenAPIUnchanged(t *testing.T) {
+	diff := DiffOpenAPI(loadDiffDoc(t, "testdata/apidiff/base"), loadDiffDoc(t, "testdata/apidiff/base"))
+	if len(diff.Changes) > 0 {
+		t.Fatalf("expected no changes, got:\n%s", strings.Join(changeStrings(diff.Changes), "\n"))
+	}
+	var out strings.Builder
+	if err := diff.Render(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "未发现接口变更\n" {
+		t.Errorf("unexpected render output: %q", out.String())
+	}
+}
+
+func TestLoadOpenAPIAtRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	copyAPI := func(src string) {
+		t.Helper()
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "api", "api.go"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(repo, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/diffrepo\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	copyAPI("testdata/apidiff/base/api.go")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+	copyAPI("testdata/apidiff/head/api.go")
+
+	t.Chdir(repo)
+	ctx := context.Background()
+	base, err := LoadOpenAPIAtRevision(ctx, "HEAD", "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := LoadOpenAPI(ctx, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaking := changeStrings(DiffOpenAPI(base, head).Breaking())
+	if !slices.Contains(breaking, "DELETE /api/users/{id}: 路由已删除") {
+		t.Errorf("expected removed route, got:\n%s", strings.Join(breaking, "\n"))
+	}
+
+	if _, err := LoadOpenAPIAtRevision(ctx, "no-such-ref", "./..."); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
@@ -0,0 +1,727 @@
+package swaggen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+)
+
+// ============================================================================
+// HTTP 框架后端
+// ============================================================================
+
+const (
+	BackendGin     = "gin"
+	BackendNetHTTP = "nethttp"
+	BackendChi     = "chi"
+	BackendEcho    = "echo"
+)
+
+// Backend 描述绑定代码所面向的 HTTP 框架：处理器签名、参数提取、请求绑定、响应与路由注册
+type Backend interface {
+	// Name 后端名称，与 @SwagBackend 的取值一致
+	Name() string
+	// Imports 生成代码需要的导入路径
+	Imports() []string
+	// RouterType BindXxx / BindAll 接收的路由类型，须同时接受框架的根路由与分组
+	RouterType(wrapperName string) string
+	// MiddlewareType 中间件类型，PreHandlers 与 @MID 方法均返回该类型的切片
+	MiddlewareType() string
+	// HandlerSignature 处理器方法的参数与返回值，如 (ctx *gin.Context)
+	HandlerSignature() string
+	// RoutePath 将 Swagger 路径 {param} 转换为框架路由格式
+	RoutePath(path string) string
+	// PathParam 读取路径参数的表达式
+	PathParam(name string) string
+	// Header 读取请求头的表达式
+	Header(name string) string
+	// Query 读取查询参数的表达式
+	Query(name string) string
+	// RequestContext 获取 context.Context 的表达式
+	RequestContext() string
+	// Bind 绑定请求参数的语句，kind 为 JSON / FORM / QUERY，失败时直接返回
+	Bind(varName, kind string) string
+	// ValidatingBind 绑定时自带 binding 标签校验的框架（gin）返回绑定请求结构体的表达式（结果为 error），
+	// 请求结构体声明了校验规则时改用它绑定，由 bindingInvalid 将失败转换为校验失败字段；其余后端返回空字符串
+	ValidatingBind(varName, kind string) string
+	// Respond 输出响应的语句，typeArgs 为泛型实参（如 [string]），可为空
+	Respond(typeArgs, args string) string
+	// RouteHandlers 声明每条路由初始中间件 handlers 的语句
+	RouteHandlers(methodCommentLiteral, interfaceCommentLiteral string) string
+	// BindMethod 生成通用的 bind 方法
+	BindMethod(wrapperName string) string
+	// HelperFunctions 需要使用方实现的辅助函数示例
+	HelperFunctions() string
+	// OnError 生成 onError 方法：以 errorResponse 的结果输出错误响应（声明了 @ERRORS 时）
+	OnError(wrapperName string) string
+	// HandleError 处理器中 err != nil 时的语句
+	HandleError() string
+	// OnInvalid 生成 onInvalid 方法：以 invalidResponse 的结果输出参数校验失败响应
+	OnInvalid(wrapperName, fieldErrorName string) string
+	// HandleInvalid 处理器中存在校验失败字段（invalid 切片非空）时的语句
+	HandleInvalid() string
+	// OnDenied 生成 onDenied 方法：以 deniedResponse 的结果输出权限检查或限流未通过的响应
+	OnDenied(wrapperName string) string
+	// HandleDenied 处理器中权限检查或限流返回错误时的语句
+	HandleDenied() string
+	// ErrorImports onError / onInvalid / onDenied 需要的额外导入
+	ErrorImports() []string
+	// Writer 处理器中底层 http.ResponseWriter 的表达式
+	Writer() string
+	// Request 处理器中底层 *http.Request 的表达式
+	Request() string
+	// RespondError 未声明 @ERRORS 时提前输出 err 并结束处理器的语句
+	RespondError() string
+	// Finish 直接写入 ResponseWriter 的语句（SSE / 文件下载），补全处理器的返回
+	Finish(stmt string) string
+}
+
+var backends = map[string]Backend{
+	BackendGin:     ginBackend{},
+	BackendNetHTTP: netHTTPBackend{},
+	BackendChi:     chiBackend{},
+	BackendEcho:    echoBackend{},
+}
+
+// lookupBackend 按名称查找后端，名称为空时返回 gin
+func lookupBackend(name string) (Backend, error) {
+	if name == "" {
+		name = BackendGin
+	}
+	b, ok := backends[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("不支持的 @SwagBackend(%s)，可选值: gin, nethttp, chi, echo", name)
+	}
+	return b, nil
+}
+
+// backendOf 返回接口使用的后端，未知名称回退到 gin（名称已在 checkBackend 中校验）
+func backendOf(iface SwaggerInterface) Backend {
+	b, err := lookupBackend(iface.CommonDef.GetBackend())
+	if err != nil {
+		return ginBackend{}
+	}
+	return b
+}
+
+// checkBackend 校验接口的后端名称，以及非 gin 后端下方法未使用 *gin.Context 参数
+func checkBackend(iface SwaggerInterface) error {
+	b, err := lookupBackend(iface.CommonDef.GetBackend())
+	if err != nil {
+		return err
+	}
+	if b.Name() == BackendGin {
+		return nil
+	}
+	for _, method := range iface.Methods {
+		for _, param := range method.Parameters {
+			if param.Type.FullName == GinContextType {
+				return fmt.Errorf("方法 %s 的参数 %s 为 *gin.Context，无法用于 %s 后端", method.Name, param.Name, b.Name())
+			}
+		}
+	}
+	return nil
+}
+
+// backendImports 按 标准库 / 第三方 分组生成导入行
+func backendImports(paths []string) []string {
+	var std, third []string
+	for _, p := range paths {
+		first, _, _ := strings.Cut(p, "/")
+		if strings.Contains(first, ".") {
+			third = append(third, p)
+		} else {
+			std = append(std, p)
+		}
+	}
+	var lines []string
+	for _, p := range std {
+		lines = append(lines, fmt.Sprintf(`	"%s"`, p))
+	}
+	if len(std) > 0 && len(third) > 0 {
+		lines = append(lines, "")
+	}
+	for _, p := range third {
+		lines = append(lines, fmt.Sprintf(`	"%s"`, p))
+	}
+	return lines
+}
+
+// collectBackendImports 收集接口集合中各后端所需的导入，保持首次出现的顺序
+func collectBackendImports(interfaces []SwaggerInterface) []string {
+	var paths []string
+	for _, iface := range interfaces {
+		for _, p := range backendOf(iface).Imports() {
+			if !slices.Contains(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		paths = ginBackend{}.Imports()
+	}
+	return paths
+}
+
+// ----------------------------------------------------------------------------
+// gin
+// ----------------------------------------------------------------------------
+
+type ginBackend struct{}
+
+func (ginBackend) Name() string                 { return BackendGin }
+func (ginBackend) Imports() []string            { return []string{"strings", "github.com/gin-gonic/gin"} }
+func (ginBackend) RouterType(string) string     { return "gin.IRoutes" }
+func (ginBackend) MiddlewareType() string       { return "gin.HandlerFunc" }
+func (ginBackend) HandlerSignature() string     { return "(ctx *gin.Context)" }
+func (ginBackend) RoutePath(path string) string { return convertPathToGinFormat(path) }
+func (ginBackend) PathParam(name string) string { return fmt.Sprintf(`ctx.Param("%s")`, name) }
+func (ginBackend) Header(name string) string    { return fmt.Sprintf(`ctx.GetHeader("%s")`, name) }
+func (ginBackend) Query(name string) string     { return fmt.Sprintf(`ctx.Query("%s")`, name) }
+func (ginBackend) RequestContext() string       { return "ctx.Request.Context()" }
+
+func (ginBackend) Bind(varName, kind string) string {
+	return fmt.Sprintf(`if !onGinBind(ctx, &%s, "%s") {
+			return
+		}`, varName, kind)
+}
+
+func (ginBackend) ValidatingBind(varName, kind string) string {
+	method := "ShouldBind"
+	switch kind {
+	case "JSON":
+		method = "ShouldBindJSON"
+	case "QUERY":
+		method = "ShouldBindQuery"
+	}
+	return fmt.Sprintf("ctx.%s(&%s)", method, varName)
+}
+
+func (ginBackend) Respond(typeArgs, args string) string {
+	return fmt.Sprintf("onGinResponse%s(ctx, %s)", typeArgs, args)
+}
+
+func (ginBackend) RouteHandlers(methodCommentLiteral, interfaceCommentLiteral string) string {
+	return fmt.Sprintf(`var handlers = []gin.HandlerFunc{
+		func(c *gin.Context) {
+			c.Set("gormgen:methodcomment", %s)
+			c.Set("gormgen:interfacecomment", %s)
+		},
+	}`, methodCommentLiteral, interfaceCommentLiteral)
+}
+
+func (ginBackend) BindMethod(wrapperName string) string {
+	template := `
+func (a *{{.WrapperName}}) bind(router gin.IRoutes, method, path string, preHandlers, innerHandlers []gin.HandlerFunc, f gin.HandlerFunc) {
+    var basePath string
+    if v, ok := router.(interface {
+        BasePath() string
+    }); ok {
+        basePath = v.BasePath()
+    }
+    handlers := make([]gin.HandlerFunc, 0, len(preHandlers)+len(innerHandlers)+1)
+    handlers = append(handlers, preHandlers...)
+    handlers = append(handlers, innerHandlers...)
+    handlers = append(handlers, f)
+    router.Handle(method, strings.TrimPrefix(path, basePath), handlers...)
+}
+`
+	return strings.TrimSpace(utils.MustExecuteTemplate(map[string]any{"WrapperName": wrapperName}, template))
+}
+
+func (ginBackend) OnError(wrapperName string) string {
+	return ginWriteMethod(wrapperName, "onError", "err error", "a.errorResponse(err)")
+}
+
+func (ginBackend) HandleError() string {
+	return `a.onError(ctx, err)
+            return`
+}
+
+func (ginBackend) OnInvalid(wrapperName, fieldErrorName string) string {
+	return ginWriteMethod(wrapperName, "onInvalid", "fields []"+fieldErrorName, "a.invalidResponse(fields)")
+}
+
+func (ginBackend) HandleInvalid() string {
+	return `a.onInvalid(ctx, invalid)
+            return`
+}
+
+// ginWriteMethod 生成以 (status, body) 输出 JSON 并中止处理链的方法
+func ginWriteMethod(wrapperName, name, param, response string) string {
+	template := `
+func (a *{{.WrapperName}}) {{.Name}}(ctx *gin.Context, {{.Param}}) {
+    status, body := {{.Response}}
+    ctx.AbortWithStatusJSON(status, body)
+}
+`
+	data := map[string]any{"WrapperName": wrapperName, "Name": name, "Param": param, "Response": response}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+func (ginBackend) OnDenied(wrapperName string) string {
+	return ginWriteMethod(wrapperName, "onDenied", "status int, err error", "a.deniedResponse(status, err)")
+}
+
+func (ginBackend) HandleDenied() string {
+	return `a.onDenied(ctx, status, err)
+            return`
+}
+
+func (ginBackend) ErrorImports() []string { return nil }
+
+func (ginBackend) Writer() string  { return "ctx.Writer" }
+func (ginBackend) Request() string { return "ctx.Request" }
+
+func (ginBackend) RespondError() string {
+	return `onGinResponse[string](ctx, "", err)
+            return`
+}
+
+func (ginBackend) Finish(stmt string) string { return stmt }
+
+func (ginBackend) HelperFunctions() string {
+	return `
+func onGinBind(c *gin.Context, val any, typ string) bool {
+    switch typ {
+    case "JSON":
+        if err := c.ShouldBindJSON(val); err != nil {
+            c.JSON(400, gin.H{"error": err.Error()})
+            return false
+        }
+    case "FORM":
+        if err := c.ShouldBind(val); err != nil {
+            c.JSON(400, gin.H{"error": err.Error()})
+            return false
+        }
+    case "QUERY":
+        if err := c.ShouldBindQuery(val); err != nil {
+            c.JSON(400, gin.H{"error": err.Error()})
+            return false
+        }
+    default:
+        if err := c.ShouldBind(val); err != nil {
+            c.JSON(400, gin.H{"error": err.Error()})
+            return false
+        }
+    }
+    return true
+}
+
+func onGinResponse[T any](c *gin.Context, data any, err error) {
+    c.JSON(200, data)
+}
+
+func onGinBindErr(c *gin.Context, err error) {
+    c.JSON(500, gin.H{"error": err.Error()})
+}`
+}
+
+// ----------------------------------------------------------------------------
+// net/http（Go 1.22+ ServeMux 路由模式）
+// ----------------------------------------------------------------------------
+
+type netHTTPBackend struct{}
+
+func (netHTTPBackend) Name() string             { return BackendNetHTTP }
+func (netHTTPBackend) Imports() []string        { return []string{"net/http"} }
+func (netHTTPBackend) RouterType(string) string { return "*http.ServeMux" }
+func (netHTTPBackend) MiddlewareType() string   { return "func(http.Handler) http.Handler" }
+func (netHTTPBackend) HandlerSignature() string { return "(w http.ResponseWriter, r *http.Request)" }
+
+// RoutePath ServeMux 与 Swagger 同样使用 {param}，根路径需写作 /{$} 才能精确匹配
+func (netHTTPBackend) RoutePath(path string) string {
+	if path == "/" {
+		return "/{$}"
+	}
+	return path
+}
+
+func (netHTTPBackend) PathParam(name string) string { return fmt.Sprintf(`r.PathValue("%s")`, name) }
+func (netHTTPBackend) Header(name string) string    { return fmt.Sprintf(`r.Header.Get("%s")`, name) }
+func (netHTTPBackend) Query(name string) string     { return httpQuery(name) }
+func (netHTTPBackend) RequestContext() string       { return "r.Context()" }
+func (netHTTPBackend) Bind(varName, kind string) string {
+	return httpBind(varName, kind)
+}
+func (netHTTPBackend) ValidatingBind(string, string) string { return "" }
+func (netHTTPBackend) Respond(typeArgs, args string) string {
+	return httpRespond(typeArgs, args)
+}
+func (netHTTPBackend) RouteHandlers(string, string) string {
+	return "var handlers []func(http.Handler) http.Handler"
+}
+
+func (netHTTPBackend) BindMethod(wrapperName string) string {
+	template := `
+func (a *{{.WrapperName}}) bind(router *http.ServeMux, method, path string, preHandlers, innerHandlers []func(http.Handler) http.Handler, f http.HandlerFunc) {
+    var h http.Handler = f
+    for i := len(innerHandlers) - 1; i >= 0; i-- {
+        h = innerHandlers[i](h)
+    }
+    for i := len(preHandlers) - 1; i >= 0; i-- {
+        h = preHandlers[i](h)
+    }
+    router.Handle(method+" "+path, h)
+}
+`
+	return strings.TrimSpace(utils.MustExecuteTemplate(map[string]any{"WrapperName": wrapperName}, template))
+}
+
+func (netHTTPBackend) HelperFunctions() string           { return httpHelperFunctions }
+func (netHTTPBackend) OnError(wrapperName string) string { return httpOnError(wrapperName) }
+func (netHTTPBackend) HandleError() string               { return httpHandleError }
+func (netHTTPBackend) OnInvalid(wrapperName, fieldErrorName string) string {
+	return httpOnInvalid(wrapperName, fieldErrorName)
+}
+func (netHTTPBackend) HandleInvalid() string              { return httpHandleInvalid }
+func (netHTTPBackend) OnDenied(wrapperName string) string { return httpOnDenied(wrapperName) }
+func (netHTTPBackend) HandleDenied() string               { return httpHandleDenied }
+func (netHTTPBackend) ErrorImports() []string             { return []string{`"encoding/json"`} }
+
+func (netHTTPBackend) Writer() string            { return "w" }
+func (netHTTPBackend) Request() string           { return "r" }
+func (netHTTPBackend) RespondError() string      { return httpRespondError }
+func (netHTTPBackend) Finish(stmt string) string { return stmt }
+
+// ----------------------------------------------------------------------------
+// chi
+// ----------------------------------------------------------------------------
+
+type chiBackend struct{}
+
+func (chiBackend) Name() string                 { return BackendChi }
+func (chiBackend) Imports() []string            { return []string{"net/http", "github.com/go-chi/chi/v5"} }
+func (chiBackend) RouterType(string) string     { return "chi.Router" }
+func (chiBackend) MiddlewareType() string       { return "func(http.Handler) http.Handler" }
+func (chiBackend) HandlerSignature() string     { return "(w http.ResponseWriter, r *http.Request)" }
+func (chiBackend) RoutePath(path string) string { return path }
+func (chiBackend) PathParam(name string) string { return fmt.Sprintf(`chi.URLParam(r, "%s")`, name) }
+func (chiBackend) Header(name string) string    { return fmt.Sprintf(`r.Header.Get("%s")`, name) }
+func (chiBackend) Query(name string) string     { return httpQuery(name) }
+func (chiBackend) RequestContext() string       { return "r.Context()" }
+func (chiBackend) Bind(varName, kind string) string {
+	return httpBind(varName, kind)
+}
+func (chiBackend) ValidatingBind(string, string) string { return "" }
+func (chiBackend) Respond(typeArgs, args string) string {
+	return httpRespond(typeArgs, args)
+}
+func (chiBackend) RouteHandlers(string, string) string {
+	return "var handlers []func(http.Handler) http.Handler"
+}
+
+func (chiBackend) BindMethod(wrapperName string) string {
+	template := `
+func (a *{{.WrapperName}}) bind(router chi.Router, method, path string, preHandlers, innerHandlers []func(http.Handler) http.Handler, f http.HandlerFunc) {
+    router.With(preHandlers...).With(innerHandlers...).Method(method, path, f)
+}
+`
+	return strings.TrimSpace(utils.MustExecuteTemplate(map[string]any{"WrapperName": wrapperName}, template))
+}
+
+func (chiBackend) HelperFunctions() string           { return httpHelperFunctions }
+func (chiBackend) OnError(wrapperName string) string { return httpOnError(wrapperName) }
+func (chiBackend) HandleError() string               { return httpHandleError }
+func (chiBackend) OnInvalid(wrapperName, fieldErrorName string) string {
+	return httpOnInvalid(wrapperName, fieldErrorName)
+}
+func (chiBackend) HandleInvalid() string              { return httpHandleInvalid }
+func (chiBackend) OnDenied(wrapperName string) string { return httpOnDenied(wrapperName) }
+func (chiBackend) HandleDenied() string               { return httpHandleDenied }
+func (chiBackend) ErrorImports() []string             { return []string{`"encoding/json"`} }
+
+func (chiBackend) Writer() string            { return "w" }
+func (chiBackend) Request() string           { return "r" }
+func (chiBackend) RespondError() string      { return httpRespondError }
+func (chiBackend) Finish(stmt string) string { return stmt }
+
+// httpBind net/http 与 chi 共用的绑定语句
+func httpBind(varName, kind string) string {
+	return fmt.Sprintf(`if !onHTTPBind(w, r, &%s, "%s") {
+			return
+		}`, varName, kind)
+}
+
+// httpRespond net/http 与 chi 共用的响应语句
+func httpRespond(typeArgs, args string) string {
+	return fmt.Sprintf("onHTTPResponse%s(w, r, %s)", typeArgs, args)
+}
+
+// httpQuery net/http 与 chi 共用的查询参数读取表达式
+func httpQuery(name string) string {
+	return fmt.Sprintf(`r.URL.Query().Get("%s")`, name)
+}
+
+// httpOnError net/http 与 chi 共用的 onError 方法
+func httpOnError(wrapperName string) string {
+	return httpWriteMethod(wrapperName, "onError", "err error", "a.errorResponse(err)")
+}
+
+const httpHandleError = `a.onError(w, err)
+            return`
+
+const httpRespondError = `onHTTPResponse[string](w, r, "", err)
+            return`
+
+// httpOnInvalid net/http 与 chi 共用的 onInvalid 方法
+func httpOnInvalid(wrapperName, fieldErrorName string) string {
+	return httpWriteMethod(wrapperName, "onInvalid", "fields []"+fieldErrorName, "a.invalidResponse(fields)")
+}
+
+const httpHandleInvalid = `a.onInvalid(w, invalid)
+            return`
+
+// httpOnDenied net/http 与 chi 共用的 onDenied 方法
+func httpOnDenied(wrapperName string) string {
+	return httpWriteMethod(wrapperName, "onDenied", "status int, err error", "a.deniedResponse(status, err)")
+}
+
+const httpHandleDenied = `a.onDenied(w, status, err)
+            return`
+
+// httpWriteMethod 生成以 (status, body) 输出 JSON 的方法
+func httpWriteMethod(wrapperName, name, param, response string) string {
+	template := `
+func (a *{{.WrapperName}}) {{.Name}}(w http.ResponseWriter, {{.Param}}) {
+    status, body := {{.Response}}
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    _ = json.NewEncoder(w).Encode(body)
+}
+`
+	data := map[string]any{"WrapperName": wrapperName, "Name": name, "Param": param, "Response": response}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+const httpHelperFunctions = `
+func onHTTPBind(w http.ResponseWriter, r *http.Request, val any, typ string) bool {
+    var err error
+    switch typ {
+    case "JSON":
+        err = json.NewDecoder(r.Body).Decode(val)
+    case "QUERY":
+        err = decodeFormValues(r.URL.Query(), val)
+    default:
+        if err = r.ParseForm(); err == nil {
+            err = decodeFormValues(r.Form, val)
+        }
+    }
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return false
+    }
+    return true
+}
+
+func onHTTPResponse[T any](w http.ResponseWriter, r *http.Request, data any, err error) {
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    _ = json.NewEncoder(w).Encode(data)
+}
+` + formDecodeHelperFunctions
+
+// formDecodeHelperFunctions net/http 与 echo 示例共用的表单解码：按 form 标签填充 FORM / QUERY 参数的结构体
+const formDecodeHelperFunctions = `
+// decodeFormValues 按 form 标签（缺省为字段名）将 values 填充到 val 指向的结构体，支持基础类型及其切片
+func decodeFormValues(values url.Values, val any) error {
+    v := reflect.ValueOf(val).Elem()
+    for i := 0; i < v.NumField(); i++ {
+        field := v.Type().Field(i)
+        name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+        if name == "-" || !field.IsExported() {
+            continue
+        }
+        if name == "" {
+            name = field.Name
+        }
+        items, ok := values[name]
+        if !ok || len(items) == 0 {
+            continue
+        }
+        fv := v.Field(i)
+        if fv.Kind() != reflect.Slice {
+            items = items[:1]
+        } else {
+            fv.Set(reflect.MakeSlice(fv.Type(), len(items), len(items)))
+        }
+        for j, item := range items {
+            target := fv
+            if fv.Kind() == reflect.Slice {
+                target = fv.Index(j)
+            }
+            if err := setFormValue(target, item); err != nil {
+                return fmt.Errorf("%s: %w", name, err)
+            }
+        }
+    }
+    return nil
+}
+
+func setFormValue(v reflect.Value, s string) error {
+    switch v.Kind() {
+    case reflect.String:
+        v.SetString(s)
+    case reflect.Bool:
+        b, err := strconv.ParseBool(s)
+        if err != nil {
+            return err
+        }
+        v.SetBool(b)
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+        if err != nil {
+            return err
+        }
+        v.SetInt(n)
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+        if err != nil {
+            return err
+        }
+        v.SetUint(n)
+    case reflect.Float32, reflect.Float64:
+        n, err := strconv.ParseFloat(s, v.Type().Bits())
+        if err != nil {
+            return err
+        }
+        v.SetFloat(n)
+    default:
+        return fmt.Errorf("不支持的类型 %s", v.Type())
+    }
+    return nil
+}`
+
+// ----------------------------------------------------------------------------
+// echo
+// ----------------------------------------------------------------------------
+
+type echoBackend struct{}
+
+func (echoBackend) Name() string                 { return BackendEcho }
+func (echoBackend) Imports() []string            { return []string{"github.com/labstack/echo/v4"} }
+func (echoBackend) MiddlewareType() string       { return "echo.MiddlewareFunc" }
+func (echoBackend) HandlerSignature() string     { return "(c echo.Context) error" }
+func (echoBackend) RoutePath(path string) string { return convertPathToGinFormat(path) }
+func (echoBackend) PathParam(name string) string { return fmt.Sprintf(`c.Param("%s")`, name) }
+func (echoBackend) Header(name string) string {
+	return fmt.Sprintf(`c.Request().Header.Get("%s")`, name)
+}
+func (echoBackend) Query(name string) string { return fmt.Sprintf(`c.QueryParam("%s")`, name) }
+func (echoBackend) RequestContext() string   { return "c.Request().Context()" }
+
+func (echoBackend) Bind(varName, kind string) string {
+	return fmt.Sprintf(`if err := onEchoBind(c, &%s, "%s"); err != nil {
+			return err
+		}`, varName, kind)
+}
+
+func (echoBackend) ValidatingBind(string, string) string { return "" }
+
+func (echoBackend) Respond(typeArgs, args string) string {
+	return fmt.Sprintf("return onEchoResponse%s(c, %s)", typeArgs, args)
+}
+
+func (echoBackend) RouteHandlers(string, string) string {
+	return "var handlers []echo.MiddlewareFunc"
+}
+
+// RouterType echo 没有 *echo.Echo 与 *echo.Group 共同实现的接口，按包装结构体生成一个，如 StockAPIWrap -> StockAPIRouter
+func (echoBackend) RouterType(wrapperName string) string {
+	return strings.TrimSuffix(wrapperName, "Wrap") + "Router"
+}
+
+func (b echoBackend) BindMethod(wrapperName string) string {
+	template := `
+// {{.RouterType}} 注册路由的目标，*echo.Echo 与 *echo.Group 均满足
+type {{.RouterType}} interface {
+    Add(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+}
+
+func (a *{{.WrapperName}}) bind(router {{.RouterType}}, method, path string, preHandlers, innerHandlers []echo.MiddlewareFunc, f echo.HandlerFunc) {
+    middlewares := make([]echo.MiddlewareFunc, 0, len(preHandlers)+len(innerHandlers))
+    middlewares = append(middlewares, preHandlers...)
+    middlewares = append(middlewares, innerHandlers...)
+    router.Add(method, path, f, middlewares...)
+}
+`
+	data := map[string]any{"WrapperName": wrapperName, "RouterType": b.RouterType(wrapperName)}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+func (echoBackend) OnError(wrapperName string) string {
+	return echoWriteMethod(wrapperName, "onError", "err error", "a.errorResponse(err)")
+}
+
+func (echoBackend) HandleError() string { return "return a.onError(c, err)" }
+
+func (echoBackend) OnInvalid(wrapperName, fieldErrorName string) string {
+	return echoWriteMethod(wrapperName, "onInvalid", "fields []"+fieldErrorName, "a.invalidResponse(fields)")
+}
+
+func (echoBackend) HandleInvalid() string { return "return a.onInvalid(c, invalid)" }
+
+func (echoBackend) OnDenied(wrapperName string) string {
+	return echoWriteMethod(wrapperName, "onDenied", "status int, err error", "a.deniedResponse(status, err)")
+}
+
+func (echoBackend) HandleDenied() string { return "return a.onDenied(c, status, err)" }
+
+// echoWriteMethod 生成以 (status, body) 输出 JSON 的方法
+func echoWriteMethod(wrapperName, name, param, response string) string {
+	template := `
+func (a *{{.WrapperName}}) {{.Name}}(c echo.Context, {{.Param}}) error {
+    status, body := {{.Response}}
+    return c.JSON(status, body)
+}
+`
+	data := map[string]any{"WrapperName": wrapperName, "Name": name, "Param": param, "Response": response}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+func (echoBackend) ErrorImports() []string { return nil }
+
+func (echoBackend) Writer() string  { return "c.Response()" }
+func (echoBackend) Request() string { return "c.Request()" }
+
+func (echoBackend) RespondError() string { return `return onEchoResponse[string](c, "", err)` }
+
+func (echoBackend) Finish(stmt string) string {
+	return stmt + `
+        return nil`
+}
+
+func (echoBackend) HelperFunctions() string {
+	return `
+func onEchoBind(c echo.Context, val any, typ string) error {
+    var err error
+    switch typ {
+    case "JSON":
+        err = json.NewDecoder(c.Request().Body).Decode(val)
+    case "QUERY":
+        err = decodeFormValues(c.QueryParams(), val)
+    default:
+        var values url.Values
+        if values, err = c.FormParams(); err == nil {
+            err = decodeFormValues(values, val)
+        }
+    }
+    if err != nil {
+        return echo.NewHTTPError(400, err.Error())
+    }
+    return nil
+}
+
+func onEchoResponse[T any](c echo.Context, data any, err error) error {
+    if err != nil {
+        return err
+    }
+    return c.JSON(200, data)
+}
+` + formDecodeHelperFunctions
+}
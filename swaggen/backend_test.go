@@ -0,0 +1,138 @@
+package swaggen
+
+import (
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+// generateBackendCode 以包级默认 nethttp 后端生成 testdata/backend 中的接口
+func generateBackendCode(t *testing.T, names ...string) (string, *plugin.GenerateResult) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/backend/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := []*plugin.AnnotatedTarget{{
+		Target: &plugin.Target{
+			Kind:        plugin.TargetComment,
+			Name:        "SwagBackend",
+			PackageName: "backend",
+			FilePath:    filePath,
+		},
+		Annotations: []*plugin.Annotation{{Name: "SwagBackend", Raw: "@SwagBackend(nethttp)"}},
+	}}
+	for _, name := range names {
+		targets = append(targets, interfaceTarget(t, filePath, name))
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: targets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")])
+	if code != "" {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+		}
+	}
+	return code, result
+}
+
+func TestBackendNetHTTP(t *testing.T) {
+	code, result := generateBackendCode(t, "IOrderAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		`"net/http"`,
+		"PreHandlers() []func(http.Handler) http.Handler",
+		"Auth() []func(http.Handler) http.Handler",
+		`router.Handle(method+" "+path, h)`,
+		"func (a *OrderAPIWrap) GetOrder(w http.ResponseWriter, r *http.Request) {",
+		`id := cast.ToInt64(r.PathValue("id"))`,
+		`tenant := r.Header.Get("tenant")`,
+		"a.inner.GetOrder(r.Context(), id, tenant)",
+		"onHTTPResponse[Order](w, r, result, err)",
+		`if !onHTTPBind(w, r, &req, "JSON") {`,
+		"func (a *OrderAPIWrap) BindGetOrder(router *http.ServeMux, preHandlers ...func(http.Handler) http.Handler) {",
+		"handlers = append(handlers, a.handler.Auth()...)",
+		`a.bind(router, "GET", "/api/orders/{id}", preHandlers, handlers, a.GetOrder)`,
+		"func (a *OrderAPIWrap) BindAll(router *http.ServeMux, preHandlers ...func(http.Handler) http.Handler) {",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"gin.", "gormgen:methodcomment"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("nethttp code should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestBackendOverridesPackageDefault(t *testing.T) {
+	code, result := generateBackendCode(t, "IStockAPI", "IPingAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		// echo
+		`"github.com/labstack/echo/v4"`,
+		"func (a *StockAPIWrap) ListStocks(c echo.Context) error {",
+		`if err := onEchoBind(c, &req, "QUERY"); err != nil {`,
+		"return onEchoResponse[[]Order](c, result, err)",
+		`id := cast.ToInt64(c.Param("id"))`,
+		`a.bind(router, "DELETE", "/stocks/:id", preHandlers, handlers, a.DeleteStock)`,
+		// *echo.Echo 与 *echo.Group 均可注册
+		"type StockAPIRouter interface {\n    Add(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route\n}",
+		"func (a *StockAPIWrap) BindAll(router StockAPIRouter, preHandlers ...echo.MiddlewareFunc) {",
+		"router.Add(method, path, f, middlewares...)",
+		// chi
+		`"github.com/go-chi/chi/v5"`,
+		"func (a *PingAPIWrap) Ping(w http.ResponseWriter, r *http.Request) {",
+		"router.With(preHandlers...).With(innerHandlers...).Method(method, path, f)",
+		`a.bind(router, "GET", "/", preHandlers, handlers, a.Ping)`,
+		// 每个后端各输出一份辅助函数示例
+		"//func onEchoBind(c echo.Context, val any, typ string) error {",
+		"//func onHTTPBind(w http.ResponseWriter, r *http.Request, val any, typ string) bool {",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	// 示例按 typ 将 FORM / QUERY 参数解码到 val，共用的解码函数只输出一次
+	for _, want := range []string{
+		"//        err = decodeFormValues(c.QueryParams(), val)",
+		"//            err = decodeFormValues(r.Form, val)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	if n := strings.Count(code, "//func decodeFormValues("); n != 1 {
+		t.Errorf("decodeFormValues emitted %d times, want 1", n)
+	}
+}
+
+func TestBackendRejectsGinContext(t *testing.T) {
+	_, result := generateBackendCode(t, "IGinOnlyAPI")
+	if !result.HasErrors() || !strings.Contains(result.Errors[0].Error(), "*gin.Context") {
+		t.Fatalf("expected *gin.Context error, got %v", result.Errors)
+	}
+}
+
+func TestNetHTTPRootPattern(t *testing.T) {
+	if got := (netHTTPBackend{}).RoutePath("/"); got != "/{$}" {
+		t.Errorf("root pattern = %q", got)
+	}
+	if _, err := lookupBackend("fasthttp"); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
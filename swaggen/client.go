@@ -0,0 +1,680 @@
+package swaggen
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// HTTP 客户端生成器
+// ============================================================================
+
+// ClientGenerator 为声明了 @CLIENT 的接口生成基于 net/http 的客户端
+// 参数与返回值的编码方式由 go/types 推导，查询参数和表单字段静态展开，不依赖反射
+type ClientGenerator struct {
+	loader  *TypeLoader
+	imports map[string]string // 导入路径 -> 代码中使用的包名
+	pkgs    map[string]string // 导入路径 -> 包的实际名称
+}
+
+// NewClientGenerator 创建客户端生成器
+func NewClientGenerator(loader *TypeLoader) *ClientGenerator {
+	return &ClientGenerator{
+		loader:  loader,
+		imports: make(map[string]string),
+		pkgs:    make(map[string]string),
+	}
+}
+
+// Imports 返回生成代码所需的导入声明（不含引号外的缩进），按路径排序
+func (g *ClientGenerator) Imports() []string {
+	var lines []string
+	for path, name := range g.imports {
+		if name == g.pkgs[path] {
+			lines = append(lines, fmt.Sprintf("%q", path))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s %q", name, path))
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// use 登记标准库导入
+func (g *ClientGenerator) use(paths ...string) {
+	for _, path := range paths {
+		name := path[strings.LastIndex(path, "/")+1:]
+		g.imports[path] = name
+		g.pkgs[path] = name
+	}
+}
+
+// clientMethod 单个方法生成时的上下文
+type clientMethod struct {
+	iface  SwaggerInterface
+	method SwaggerMethod
+	sig    *types.Signature
+	names  []string // 参数在生成代码中的名称
+	lines  []string
+	zero   string // 出错返回时 err 之前的返回值，如 "result, "
+	hasErr bool   // 是否已声明 err
+	mpfd   bool
+	files  bool // 是否有 @FILE 参数（已声明 files）
+}
+
+// check 添加返回 (value, err) 的语句及错误检查
+func (m *clientMethod) check(stmt string) {
+	m.lines = append(m.lines, stmt, "if err != nil {", "\treturn "+m.zero+"err", "}")
+	m.hasErr = true
+}
+
+// Generate 生成接口的客户端代码
+func (g *ClientGenerator) Generate(iface SwaggerInterface) (string, error) {
+	pkg, err := g.loader.Load(iface.FilePath)
+	if err != nil {
+		return "", err
+	}
+	qual := g.qualifier(iface, pkg.Types)
+	clientName := iface.GetClientName()
+	g.use("bytes", "context", "encoding/json", "encoding/xml", "fmt", "io", "net/http", "net/url", "strings")
+
+	var methods []string
+	var needMultipart bool
+	for _, method := range iface.Methods {
+		if method.Def.IsRemoved() {
+			continue
+		}
+		sig, err := g.loader.Signature(iface, method.Name)
+		if err != nil {
+			return "", err
+		}
+		code, mpfd, err := g.generateMethod(iface, method, sig, qual)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", iface.Name, method.Name, err)
+		}
+		needMultipart = needMultipart || mpfd
+		methods = append(methods, code)
+	}
+
+	data := map[string]any{
+		"Client":    clientName,
+		"Interface": iface.Name,
+		"Multipart": needMultipart,
+	}
+	if needMultipart {
+		g.use("mime/multipart")
+	}
+	parts := []string{strings.TrimSpace(utils.MustExecuteTemplate(data, clientTemplate))}
+	parts = append(parts, methods...)
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// qualifier 返回类型限定函数：接口所在包不加前缀，其他包沿用源文件中的导入别名
+func (g *ClientGenerator) qualifier(iface SwaggerInterface, local *types.Package) types.Qualifier {
+	return func(p *types.Package) string {
+		if p.Path() == local.Path() {
+			return ""
+		}
+		name := p.Name()
+		for _, imp := range iface.Imports {
+			if imp.Path == p.Path() && imp.Alias != "" && imp.Alias != "_" && imp.Alias != "." {
+				name = imp.Alias
+				break
+			}
+		}
+		g.imports[p.Path()] = name
+		g.pkgs[p.Path()] = p.Name()
+		return name
+	}
+}
+
+// generateMethod 生成单个方法的客户端实现
+func (g *ClientGenerator) generateMethod(iface SwaggerInterface, method SwaggerMethod, sig *types.Signature, qual types.Qualifier) (string, bool, error) {
+	if sig.Params().Len() != len(method.Parameters) {
+		return "", false, fmt.Errorf("参数数量与类型信息不一致")
+	}
+	var resultType types.Type
+	switch results := sig.Results(); {
+	case method.Response == ResponseDownload:
+	case results.Len() == 1 && isErrorType(results.At(0).Type()):
+	case results.Len() == 2 && isErrorType(results.At(1).Type()):
+		resultType = results.At(0).Type()
+	default:
+		return "", false, fmt.Errorf("客户端要求方法返回 error 或 (T, error)")
+	}
+	m := &clientMethod{iface: iface, method: method, sig: sig}
+	m.names = clientParamNames(sig)
+
+	// 方法签名
+	var params []string
+	ctxExpr := "context.Background()"
+	for i := 0; i < sig.Params().Len(); i++ {
+		typ := sig.Params().At(i).Type()
+		params = append(params, m.names[i]+" "+types.TypeString(typ, qual))
+		switch {
+		case isContextType(typ):
+			ctxExpr = m.names[i]
+		case isGinContextType(typ):
+			m.lines = append(m.lines,
+				"var reqCtx context.Context = context.Background()",
+				fmt.Sprintf("if %s != nil {", m.names[i]),
+				fmt.Sprintf("\treqCtx = %s", m.names[i]),
+				"}")
+			ctxExpr = "reqCtx"
+		}
+	}
+	signature := fmt.Sprintf("func (c *%s) %s(%s)", iface.GetClientName(), method.Name, strings.Join(params, ", "))
+	switch {
+	case method.Response == ResponseDownload:
+		signature += fmt.Sprintf(" (%s, string, error)", types.TypeString(sig.Results().At(0).Type(), qual))
+		m.zero = `nil, "", `
+	case method.Response.IsSSE():
+		signature += fmt.Sprintf(" (%s, error)", types.TypeString(resultType, qual))
+		m.zero = "nil, "
+	case resultType != nil:
+		signature += fmt.Sprintf(" (%s, error)", types.TypeString(resultType, qual))
+		m.lines = append(m.lines, fmt.Sprintf("var result %s", types.TypeString(resultType, qual)))
+		m.zero = "result, "
+	default:
+		signature += " error"
+	}
+
+	// @FILE 参数先汇总，由请求体编码时一并写入 multipart 表单
+	for i, param := range method.Parameters {
+		if param.Source != ParamSourceFile {
+			continue
+		}
+		if !m.files {
+			m.lines = append(m.lines, "files := map[string][]*multipart.FileHeader{}")
+			m.files = true
+			g.use("mime/multipart")
+		}
+		if _, ok := sig.Params().At(i).Type().(*types.Slice); ok {
+			m.lines = append(m.lines, fmt.Sprintf("files[%q] = %s", param.ExternalName(), m.names[i]))
+		} else {
+			m.lines = append(m.lines,
+				fmt.Sprintf("if %s != nil {", m.names[i]),
+				fmt.Sprintf("	files[%q] = []*multipart.FileHeader{%s}", param.ExternalName(), m.names[i]),
+				"}")
+		}
+	}
+
+	path := g.buildPath(m)
+	query, header, body, contentType := "nil", "nil", "nil", `""`
+	for i, param := range method.Parameters {
+		typ := sig.Params().At(i).Type()
+		name := m.names[i]
+		switch {
+		case isContextType(typ) || isGinContextType(typ):
+		case param.Source == ParamSourceFile:
+		case param.Source == "path":
+		case param.Source == "header":
+			if header == "nil" {
+				m.lines = append(m.lines, "header := http.Header{}")
+				header = "header"
+			}
+			m.lines = append(m.lines, fmt.Sprintf("header.Set(%q, %s)", param.ExternalName(), g.formatValue(name, typ)))
+		case param.Source == "query":
+			if query == "nil" {
+				m.lines = append(m.lines, "query := url.Values{}")
+				query = "query"
+			}
+			m.lines = append(m.lines, fmt.Sprintf("query.Set(%q, %s)", param.ExternalName(), g.formatValue(name, typ)))
+		case i != method.BodyIndex():
+		case method.GetHTTPMethod() == "GET":
+			if query == "nil" {
+				m.lines = append(m.lines, "query := url.Values{}")
+				query = "query"
+			}
+			if _, ok := derefType(typ).Underlying().(*types.Struct); ok {
+				g.encodeValues(m, "query", name, typ, "")
+			} else {
+				m.lines = append(m.lines, fmt.Sprintf("query.Set(%q, %s)", param.Name, g.formatValue(name, typ)))
+			}
+		default:
+			body, contentType = g.encodeBody(m, acceptType(iface, method), name, typ)
+		}
+	}
+	if m.files && body == "nil" {
+		m.check(fmt.Sprintf("body, contentType, err := %s(nil, files)", transferName("EncodeMultipart")))
+		body, contentType = "body", "contentType"
+	}
+
+	args := fmt.Sprintf("%s, %q, %s, %s, %s, %s, %s, %q",
+		ctxExpr, method.GetHTTPMethod(), path, query, header, body, contentType, mimeType(produceType(iface, method)))
+	switch {
+	case method.Response != ResponseDefault:
+		// 流式响应与文件下载直接交出响应体，由调用方读取并关闭
+		m.check(fmt.Sprintf("resp, err %s c.do(%s)", lo.Ternary(m.hasErr, "=", ":="), args))
+		switch method.Response {
+		case ResponseSSE:
+			m.lines = append(m.lines, fmt.Sprintf("return %s[%s](resp.Body), nil", transferName("ReadSSE"), types.TypeString(streamElemType(resultType), qual)))
+		case ResponseSSEChan:
+			m.lines = append(m.lines, fmt.Sprintf("return %s[%s](%s, resp.Body), nil", transferName("ReadSSEChan"), types.TypeString(streamElemType(resultType), qual), ctxExpr))
+		case ResponseDownload:
+			m.lines = append(m.lines, fmt.Sprintf("return resp.Body, %s(resp.Header), nil", transferName("Filename")))
+		}
+	case resultType != nil:
+		m.lines = append(m.lines, lo.Ternary(m.hasErr, "err = ", "err := ")+fmt.Sprintf("c.call(%s, &result)", args), "return result, err")
+	default:
+		m.lines = append(m.lines, "return "+fmt.Sprintf("c.call(%s, nil)", args))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// %s %s %s\n", method.Name, method.GetHTTPMethod(), clientFullPath(iface, method))
+	sb.WriteString(signature + " {\n")
+	for _, line := range m.lines {
+		sb.WriteString("\t" + line + "\n")
+	}
+	sb.WriteString("}")
+	return sb.String(), m.mpfd, nil
+}
+
+// buildPath 生成请求路径表达式：路径参数经 url.PathEscape 拼入，使用方法的第一个路由
+func (g *ClientGenerator) buildPath(m *clientMethod) string {
+	fullPath := clientFullPath(m.iface, m.method)
+	var parts []string
+	for {
+		open := strings.Index(fullPath, "{")
+		if open == -1 {
+			break
+		}
+		end := strings.Index(fullPath[open:], "}")
+		if end == -1 {
+			break
+		}
+		end += open
+		if open > 0 {
+			parts = append(parts, fmt.Sprintf("%q", fullPath[:open]))
+		}
+		name := fullPath[open+1 : end]
+		expr := fmt.Sprintf("%q", "{"+name+"}")
+		for i, param := range m.method.Parameters {
+			if param.Source == "path" && (param.PathName == name || param.Alias == name || param.Name == name) {
+				expr = fmt.Sprintf("url.PathEscape(%s)", g.formatValue(m.names[i], m.sig.Params().At(i).Type()))
+				break
+			}
+		}
+		parts = append(parts, expr)
+		fullPath = fullPath[end+1:]
+	}
+	if fullPath != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", fullPath))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// encodeBody 生成请求体编码代码，返回 body 与 Content-Type 表达式
+func (g *ClientGenerator) encodeBody(m *clientMethod, accept, name string, typ types.Type) (string, string) {
+	contentType := mimeType(accept)
+	switch accept {
+	case "json", "json-api":
+		m.check(fmt.Sprintf("data, err := json.Marshal(%s)", name))
+		return "bytes.NewReader(data)", fmt.Sprintf("%q", contentType)
+	case "xml":
+		m.check(fmt.Sprintf("data, err := xml.Marshal(%s)", name))
+		return "bytes.NewReader(data)", fmt.Sprintf("%q", contentType)
+	case "x-www-form-urlencoded", "mpfd":
+		if _, ok := derefType(typ).Underlying().(*types.Struct); !ok {
+			break
+		}
+		m.lines = append(m.lines, "form := url.Values{}")
+		g.encodeValues(m, "form", name, typ, "")
+		if accept == "x-www-form-urlencoded" {
+			return "strings.NewReader(form.Encode())", fmt.Sprintf("%q", contentType)
+		}
+		if m.files {
+			m.check(fmt.Sprintf("body, contentType, err := %s(form, files)", transferName("EncodeMultipart")))
+			return "body", "contentType"
+		}
+		m.mpfd = true
+		m.check("body, contentType, err := c.encodeMultipart(form)")
+		return "body", "contentType"
+	}
+
+	// 其他 MIME 类型：字符串、字节切片与 io.Reader 原样发送，其余按 JSON 编码
+	switch {
+	case implementsReader(typ):
+		return name, fmt.Sprintf("%q", contentType)
+	case isStringType(typ):
+		return fmt.Sprintf("strings.NewReader(string(%s))", name), fmt.Sprintf("%q", contentType)
+	case isByteSlice(typ):
+		return fmt.Sprintf("bytes.NewReader(%s)", name), fmt.Sprintf("%q", contentType)
+	}
+	m.check(fmt.Sprintf("data, err := json.Marshal(%s)", name))
+	return "bytes.NewReader(data)", fmt.Sprintf("%q", contentType)
+}
+
+// encodeValues 将结构体字段按 form 标签写入 url.Values（与 gin 的 form 绑定一致）
+// 零值字段不写入；未打标签的嵌入或结构体字段被展开
+func (g *ClientGenerator) encodeValues(m *clientMethod, values, expr string, typ types.Type, indent string) {
+	if _, ok := typ.(*types.Pointer); ok {
+		m.lines = append(m.lines, fmt.Sprintf("%sif %s != nil {", indent, expr))
+		closing := indent + "}"
+		defer func() { m.lines = append(m.lines, closing) }()
+		indent += "\t"
+	}
+	st, ok := derefType(typ).Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		key, _ := parseTagName(reflect.StructTag(st.Tag(i)).Get("form"))
+		if key == "-" || !field.Exported() && !field.Embedded() {
+			continue
+		}
+		fieldExpr := expr + "." + field.Name()
+		fieldType := field.Type()
+
+		if key == "" && isPlainStruct(fieldType) {
+			g.encodeValues(m, values, fieldExpr, fieldType, indent)
+			continue
+		}
+		if !field.Exported() {
+			continue
+		}
+		if key == "" {
+			key = field.Name()
+		}
+		m.lines = append(m.lines, g.encodeField(values, key, fieldExpr, fieldType, indent)...)
+	}
+}
+
+// encodeField 单个字段的写入语句
+func (g *ClientGenerator) encodeField(values, key, expr string, typ types.Type, indent string) []string {
+	switch t := typ.(type) {
+	case *types.Pointer:
+		return []string{
+			fmt.Sprintf("%sif %s != nil {", indent, expr),
+			fmt.Sprintf("%s\t%s.Set(%q, %s)", indent, values, key, g.formatValue("*"+expr, t.Elem())),
+			indent + "}",
+		}
+	case *types.Slice:
+		if !isByte(t.Elem()) {
+			return []string{
+				fmt.Sprintf("%sfor _, v := range %s {", indent, expr),
+				fmt.Sprintf("%s\t%s.Add(%q, %s)", indent, values, key, g.formatValue("v", t.Elem())),
+				indent + "}",
+			}
+		}
+	}
+	cond := zeroCheck(expr, typ)
+	if cond == "" {
+		return []string{fmt.Sprintf("%s%s.Set(%q, %s)", indent, values, key, g.formatValue(expr, typ))}
+	}
+	return []string{
+		fmt.Sprintf("%sif %s {", indent, cond),
+		fmt.Sprintf("%s\t%s.Set(%q, %s)", indent, values, key, g.formatValue(expr, typ)),
+		indent + "}",
+	}
+}
+
+// formatValue 返回将值转换为字符串的表达式
+func (g *ClientGenerator) formatValue(expr string, typ types.Type) string {
+	if isTimeType(typ) {
+		g.use("time")
+		return expr + ".Format(time.RFC3339)"
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return fmt.Sprintf("fmt.Sprint(%s)", expr)
+	}
+	named := types.Unalias(typ) != types.Typ[basic.Kind()]
+	conv := func(target string) string {
+		if !named && basic.Name() == target {
+			return expr
+		}
+		return target + "(" + expr + ")"
+	}
+	switch info := basic.Info(); {
+	case info&types.IsString != 0:
+		if !named {
+			return expr
+		}
+		return "string(" + expr + ")"
+	case info&types.IsBoolean != 0:
+		g.use("strconv")
+		return "strconv.FormatBool(" + conv("bool") + ")"
+	case info&types.IsUnsigned != 0:
+		g.use("strconv")
+		return "strconv.FormatUint(" + conv("uint64") + ", 10)"
+	case info&types.IsInteger != 0:
+		g.use("strconv")
+		return "strconv.FormatInt(" + conv("int64") + ", 10)"
+	case info&types.IsFloat != 0:
+		g.use("strconv")
+		return "strconv.FormatFloat(" + conv("float64") + ", 'f', -1, 64)"
+	}
+	return fmt.Sprintf("fmt.Sprint(%s)", expr)
+}
+
+// clientFullPath 方法第一个路由的完整路径（含 @PREFIX）
+func clientFullPath(iface SwaggerInterface, method SwaggerMethod) string {
+	var first string
+	if paths := method.GetPaths(); len(paths) > 0 {
+		first = paths[0]
+	}
+	fullPath := strings.TrimRight(iface.CommonDef.GetPrefix()+first, "/")
+	if fullPath == "" {
+		fullPath = "/"
+	}
+	return fullPath
+}
+
+// clientReservedNames 生成代码中使用的局部变量与包名，参数重名时追加 Param 后缀
+var clientReservedNames = []string{
+	"c", "result", "err", "data", "query", "header", "form", "body", "contentType", "reqCtx", "v", "files", "resp",
+	"bytes", "context", "json", "xml", "fmt", "io", "http", "url", "strings", "strconv", "time", "multipart", "iter",
+}
+
+// clientParamNames 生成代码中的参数名
+func clientParamNames(sig *types.Signature) []string {
+	names := make([]string, sig.Params().Len())
+	for i := range names {
+		name := sig.Params().At(i).Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		if slices.Contains(clientReservedNames, name) {
+			name += "Param"
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// zeroCheck 非零值判断表达式，无法判断时返回空字符串
+func zeroCheck(expr string, typ types.Type) string {
+	if isTimeType(typ) {
+		return "!" + expr + ".IsZero()"
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	switch info := basic.Info(); {
+	case info&types.IsString != 0:
+		return expr + ` != ""`
+	case info&types.IsBoolean != 0:
+		return expr
+	case info&types.IsNumeric != 0:
+		return expr + " != 0"
+	}
+	return ""
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func isGinContextType(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" && named.Obj().Name() == "Context"
+}
+
+func isTimeType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time"
+}
+
+// isPlainStruct 可展开的结构体字段（time.Time 等实现了文本编码的类型除外）
+func isPlainStruct(t types.Type) bool {
+	t = derefType(t)
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return !isTimeType(t) && !hasMethod(t, "MarshalText")
+}
+
+func isStringType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+func isByteSlice(t types.Type) bool {
+	s, ok := t.Underlying().(*types.Slice)
+	return ok && isByte(s.Elem())
+}
+
+// implementsReader 类型是否实现 io.Reader
+func implementsReader(t types.Type) bool {
+	if iface, ok := t.Underlying().(*types.Interface); ok {
+		for i := 0; i < iface.NumMethods(); i++ {
+			if iface.Method(i).Name() == "Read" {
+				return true
+			}
+		}
+		return false
+	}
+	return hasMethod(t, "Read")
+}
+
+// clientTemplate 客户端结构体与公共方法
+const clientTemplate = `
+// {{.Client}} 基于 net/http 实现 {{.Interface}} 的客户端
+type {{.Client}} struct {
+	BaseURL     string                          // 服务地址，如 http://localhost:8080
+	HTTPClient  *http.Client                    // 为 nil 时使用 http.DefaultClient
+	Middlewares []func(req *http.Request) error // 请求发送前依次调用，可用于设置认证头、追踪信息等
+}
+
+var _ {{.Interface}} = (*{{.Client}})(nil)
+
+// New{{.Client}} 创建客户端
+func New{{.Client}}(baseURL string, middlewares ...func(req *http.Request) error) *{{.Client}} {
+	return &{{.Client}}{
+		BaseURL:     baseURL,
+		Middlewares: middlewares,
+	}
+}
+
+// {{.Client}}Error 服务端返回的非 2xx 响应
+type {{.Client}}Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *{{.Client}}Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), bytes.TrimSpace(e.Body))
+}
+
+// call 发送请求并按 accept 解码响应体到 out（为 nil 时丢弃响应体）
+func (c *{{.Client}}) call(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, contentType, accept string, out any) error {
+	resp, err := c.do(ctx, method, path, query, header, body, contentType, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch out := out.(type) {
+	case nil:
+		_, err = io.Copy(io.Discard, resp.Body)
+	case *[]byte:
+		*out, err = io.ReadAll(resp.Body)
+	case *string:
+		var data []byte
+		data, err = io.ReadAll(resp.Body)
+		*out = string(data)
+	default:
+		if strings.Contains(accept, "xml") {
+			err = xml.NewDecoder(resp.Body).Decode(out)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(out)
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	return err
+}
+
+// do 发送请求，非 2xx 响应读取响应体后返回 {{.Client}}Error；成功时由调用方关闭 resp.Body
+func (c *{{.Client}}) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, contentType, accept string) (*http.Response, error) {
+	target := strings.TrimRight(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", accept)
+	for _, middleware := range c.Middlewares {
+		if err := middleware(req); err != nil {
+			return nil, err
+		}
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, &{{.Client}}Error{StatusCode: resp.StatusCode, Body: data}
+	}
+	return resp, nil
+}
+{{- if .Multipart}}
+
+// encodeMultipart 将表单字段编码为 multipart/form-data
+func (c *{{.Client}}) encodeMultipart(form url.Values) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for key, values := range form {
+		for _, value := range values {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+{{- end}}
+`
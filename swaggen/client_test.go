@@ -0,0 +1,113 @@
+package swaggen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateTestClient(t *testing.T) (string, []string) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/client/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IItemAPI"))
+	if err != nil {
+		t.Fatalf("parseInterface failed: %v", err)
+	}
+	gen := NewClientGenerator(NewTypeLoader())
+	code, err := gen.Generate(*iface)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	src := "package client\n\nimport (\n" + strings.Join(gen.Imports(), "\n") + "\n)\n\n" + code
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("generated client is not valid Go: %v\n%s", err, src)
+	}
+	return code, gen.Imports()
+}
+
+func TestClientGenerate(t *testing.T) {
+	code, imports := generateTestClient(t)
+
+	wants := []string{
+		"type ItemAPIClient struct {",
+		"var _ IItemAPI = (*ItemAPIClient)(nil)",
+		// 路径参数与请求头
+		`func (c *ItemAPIClient) GetItem(ctx context.Context, id int64, token string) (Item, error) {`,
+		`header.Set("token", token)`,
+		`c.call(ctx, "GET", "/api/items/" + url.PathEscape(strconv.FormatInt(id, 10)), nil, header, nil, "", "application/json", &result)`,
+		// 查询参数结构体：嵌入字段展开、零值跳过、指针与切片
+		`if req.Page.Page != 0 {`,
+		`query.Set("page", strconv.FormatInt(int64(req.Page.Page), 10))`,
+		`query.Set("level", strconv.FormatInt(int64(req.Level), 10))`,
+		`query.Set("active", strconv.FormatBool(*req.Active))`,
+		`query.Add("ids", strconv.FormatInt(v, 10))`,
+		`query.Set("since", req.Since.Format(time.RFC3339))`,
+		// JSON 请求体
+		`data, err := json.Marshal(req)`,
+		`func (c *ItemAPIClient) CreateItem(ctx context.Context, req CreateReq) (*Item, error) {`,
+		`err = c.call(ctx, "POST", "/api/items", nil, nil, bytes.NewReader(data), "application/json", "application/json", &result)`,
+		// 表单请求体与 *gin.Context
+		`func (c *ItemAPIClient) UpdateForm(ctx *gin.Context, itemId int64, req FormReq) error {`,
+		`var reqCtx context.Context = context.Background()`,
+		`form.Set("count", strconv.FormatUint(uint64(req.Count), 10))`,
+		`return c.call(reqCtx, "PUT", "/api/items/" + url.PathEscape(strconv.FormatInt(itemId, 10)) + "/form", nil, nil, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", "application/json", nil)`,
+		// multipart 与纯文本响应
+		`if req != nil {`,
+		`body, contentType, err := c.encodeMultipart(form)`,
+		`"text/plain", &result)`,
+		"func (c *ItemAPIClient) encodeMultipart(form url.Values) (io.Reader, string, error) {",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated client missing %q", want)
+		}
+	}
+	if strings.Contains(code, "Ignored") {
+		t.Errorf(`form:"-" field should be skipped`)
+	}
+
+	for _, want := range []string{`"github.com/gin-gonic/gin"`, `"mime/multipart"`, `"strconv"`, `"time"`} {
+		found := false
+		for _, imp := range imports {
+			found = found || imp == want
+		}
+		if !found {
+			t.Errorf("imports missing %s: %v", want, imports)
+		}
+	}
+}
+
+func TestClientDocComment(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/client/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, "IItemAPI")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")]
+	file, err := parser.ParseFile(token.NewFileSet(), "api_swagger.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+	}
+	// Gin 代码末尾的辅助函数模板注释不能成为客户端类型的文档
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE && gen.Specs[0].(*ast.TypeSpec).Name.Name == "ItemAPIClient" {
+			if doc := gen.Doc.Text(); doc != "ItemAPIClient 基于 net/http 实现 IItemAPI 的客户端\n" {
+				t.Errorf("ItemAPIClient doc = %q", doc)
+			}
+			return
+		}
+	}
+	t.Fatal("ItemAPIClient not generated")
+}
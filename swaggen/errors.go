@@ -0,0 +1,285 @@
+package swaggen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	"github.com/donutnomad/gogen/internal/xast"
+	"github.com/donutnomad/gogen/plugin"
+	parsers "github.com/donutnomad/gogen/swaggen/parser"
+	"github.com/samber/lo"
+	"golang.org/x/tools/go/packages"
+)
+
+// ============================================================================
+// @ERRORS 与 codegen @Code 注册表
+// ============================================================================
+
+// errorComponent OpenAPI 中统一错误响应的组件名
+const errorComponent = "ErrorResponse"
+
+// ErrorCode 通过 @ERRORS 声明的错误及其 @Code 定义
+type ErrorCode struct {
+	Ref     string          // 源码中的引用，如 errs.ErrUserNotFound
+	Name    string          // 变量名
+	Import  xast.ImportInfo // 错误所在包（即 codegen 生成 GetCode / GetHttpCode 的包），与接口同包时 Path 为空
+	Code    int             // 业务错误码
+	HTTP    int             // HTTP 状态码
+	Message string          // errors.New 等构造时的字面量消息，无法推断时为空
+}
+
+// declaredErrors 返回方法声明的错误引用：接口级 @ERRORS 在前，方法级在后，去重
+func declaredErrors(iface SwaggerInterface, method SwaggerMethod) []string {
+	var refs []string
+	for _, v := range CollectDef[*parsers.Errors](iface.CommonDef, method.Def) {
+		for _, ref := range v.Value {
+			if !slices.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// ErrorCodes 解析方法通过 @ERRORS 声明的错误
+func (l *TypeLoader) ErrorCodes(iface SwaggerInterface, method SwaggerMethod) ([]ErrorCode, error) {
+	var out []ErrorCode
+	for _, ref := range declaredErrors(iface, method) {
+		code, err := l.resolveError(iface, ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", iface.Name, method.Name, err)
+		}
+		out = append(out, code)
+	}
+	return out, nil
+}
+
+// ErrorRegistries 返回接口中所有 @ERRORS 引用所在的包（按首次出现顺序），未声明 @ERRORS 时为空
+func (l *TypeLoader) ErrorRegistries(iface SwaggerInterface) ([]xast.ImportInfo, error) {
+	var out []xast.ImportInfo
+	for _, method := range iface.Methods {
+		if method.Def.IsRemoved() {
+			continue
+		}
+		codes, err := l.ErrorCodes(iface, method)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range codes {
+			if !slices.Contains(out, code.Import) {
+				out = append(out, code.Import)
+			}
+		}
+	}
+	return out, nil
+}
+
+// resolveError 解析 Name、alias.Name 或 导入路径.Name 形式的错误引用，要求其为标注了 @Code 的 error 变量
+func (l *TypeLoader) resolveError(iface SwaggerInterface, ref string) (ErrorCode, error) {
+	result := ErrorCode{Ref: ref, Name: ref}
+	var pkg *packages.Package
+	var err error
+	if idx := strings.LastIndex(ref, "."); idx > 0 && strings.Contains(ref[:idx], "/") {
+		// 完整导入路径: github.com/x/errs.ErrNotFound，源文件无需导入该包
+		result.Name = ref[idx+1:]
+		pkg, err = l.LoadPath(ref[:idx], filepath.Dir(iface.FilePath))
+		if err == nil {
+			result.Import = xast.ImportInfo{Path: ref[:idx], Alias: pkg.Name}
+		}
+	} else if alias, name, ok := strings.Cut(ref, "."); ok {
+		imp := iface.Imports.Find(alias)
+		if imp == nil {
+			return result, fmt.Errorf("@ERRORS(%s): 未找到包 %s 的导入", ref, alias)
+		}
+		result.Name = name
+		result.Import = xast.ImportInfo{Path: strings.Trim(imp.Path, `"`), Alias: imp.Alias}
+		pkg, err = l.LoadPath(result.Import.Path, filepath.Dir(iface.FilePath))
+	} else {
+		pkg, err = l.Load(iface.FilePath)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(result.Name).(*types.Var)
+	errType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	if !ok || !types.Implements(obj.Type(), errType) {
+		return result, fmt.Errorf("@ERRORS(%s): %s 不是包 %s 中的 error 变量", ref, result.Name, pkg.PkgPath)
+	}
+
+	spec, doc := findValueSpec(pkg, obj.Pos())
+	ann := plugin.GetAnnotation(plugin.ParseAnnotations(doc), "Code")
+	if ann == nil {
+		return result, fmt.Errorf("@ERRORS(%s): %s 未标注 @Code", ref, result.Name)
+	}
+	if result.Code, err = strconv.Atoi(ann.Params["code"]); err != nil {
+		return result, fmt.Errorf("@ERRORS(%s): 无效的 @Code code=%q", ref, ann.Params["code"])
+	}
+	result.HTTP = 500
+	if v := ann.Params["http"]; v != "" {
+		if result.HTTP, err = strconv.Atoi(v); err != nil {
+			return result, fmt.Errorf("@ERRORS(%s): 无效的 @Code http=%q", ref, v)
+		}
+	}
+	if spec != nil {
+		result.Message = literalMessage(spec, result.Name)
+	}
+	return result, nil
+}
+
+// LoadPath 按导入路径加载包，dir 为解析导入路径所用的目录
+func (l *TypeLoader) LoadPath(importPath, dir string) (*packages.Package, error) {
+	key := "import:" + importPath
+	if pkg, ok := l.pkgs[key]; ok {
+		return pkg, nil
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, fmt.Errorf("加载包 %s 失败: %w", importPath, err)
+	}
+	if len(pkgs) == 0 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("加载包 %s 失败: 未找到包", importPath)
+	}
+	l.pkgs[key] = pkgs[0]
+	return pkgs[0], nil
+}
+
+// findValueSpec 查找声明位置为 pos 的变量，返回其 ValueSpec 与注释（单行声明时使用 GenDecl 的注释）
+func findValueSpec(pkg *packages.Package, pos token.Pos) (*ast.ValueSpec, string) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, s := range genDecl.Specs {
+				spec := s.(*ast.ValueSpec)
+				if !slices.ContainsFunc(spec.Names, func(n *ast.Ident) bool { return n.Pos() == pos }) {
+					continue
+				}
+				if spec.Doc != nil {
+					return spec, spec.Doc.Text()
+				}
+				if genDecl.Doc != nil && !genDecl.Lparen.IsValid() {
+					return spec, genDecl.Doc.Text()
+				}
+				return spec, ""
+			}
+		}
+	}
+	return nil, ""
+}
+
+// literalMessage 提取 errors.New("...") / fmt.Errorf("...") 的字符串字面量
+func literalMessage(spec *ast.ValueSpec, name string) string {
+	for i, ident := range spec.Names {
+		if ident.Name != name || i >= len(spec.Values) {
+			continue
+		}
+		call, ok := spec.Values[i].(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return ""
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if s, err := strconv.Unquote(lit.Value); err == nil {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// generateErrorResponse 生成 errorResponse 方法：依次查询各 @Code 注册表（内部以 errors.Is 匹配）；
+// 未注册的错误按 500 处理，消息固定为 Internal Server Error，避免内部错误细节泄露给客户端
+func generateErrorResponse(wrapperName string, registries []xast.ImportInfo) string {
+	var lookups []string
+	for _, reg := range registries {
+		qualifier := ""
+		if reg.Path != "" {
+			qualifier = reg.GetBase() + "."
+		}
+		lookups = append(lookups, fmt.Sprintf(`if c, ok := %[1]sGetCode(err); ok {
+        code, message = c, err.Error()
+        if s, _ := %[1]sGetHttpCode(err); s != 0 {
+            status = s
+        }
+    }`, qualifier))
+	}
+
+	template := `
+// errorResponse 通过 @Code 注册表解析错误的 HTTP 状态码与业务码，返回统一的错误响应
+func (a *{{.WrapperName}}) errorResponse(err error) (int, any) {
+    status, code, message := 500, 500, http.StatusText(http.StatusInternalServerError)
+    {{.Lookups}}
+    return status, struct {
+        Code    int    ` + "`json:\"code\"`" + `
+        Message string ` + "`json:\"message\"`" + `
+    }{Code: code, Message: message}
+}
+`
+	data := map[string]any{
+		"WrapperName": wrapperName,
+		"Lookups":     strings.Join(lookups, " else "),
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// errorImports 返回 errorResponse 引用的 net/http 与注册表包的导入行
+func errorImports(interfaces []SwaggerInterface) []string {
+	var out []string
+	for _, iface := range interfaces {
+		if len(iface.ErrorRegistries) == 0 {
+			continue
+		}
+		out = append(out, `"net/http"`)
+		out = append(out, backendOf(iface).ErrorImports()...)
+		for _, reg := range iface.ErrorRegistries {
+			if reg.Path != "" {
+				out = append(out, reg.String())
+			}
+		}
+	}
+	return lo.Uniq(out)
+}
+
+// errorResponseSchema 统一错误响应的 Schema
+func errorResponseSchema() *Schema {
+	s := &Schema{Type: "object"}
+	s.setProperty("code", &Schema{Type: "integer", Description: "业务错误码"}, true)
+	s.setProperty("message", &Schema{Type: "string", Description: "错误信息"}, true)
+	return s
+}
+
+// errorResponses 将声明的错误按 HTTP 状态码分组为 OpenAPI 响应
+func errorResponses(codes []ErrorCode) map[string]*Response {
+	out := make(map[string]*Response)
+	for _, code := range codes {
+		status := strconv.Itoa(code.HTTP)
+		line := fmt.Sprintf("%s (%d)", code.Name, code.Code)
+		if code.Message != "" {
+			line += ": " + code.Message
+		}
+		if resp, ok := out[status]; ok {
+			resp.Description += "\n" + line
+			continue
+		}
+		out[status] = &Response{
+			Description: line,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: &Schema{Ref: "#/components/schemas/" + errorComponent}},
+			},
+		}
+	}
+	return out
+}
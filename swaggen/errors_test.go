@@ -0,0 +1,109 @@
+package swaggen
+
+import (
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateErrorsCode(t *testing.T, names ...string) (string, *plugin.GenerateResult) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/errors/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var targets []*plugin.AnnotatedTarget
+	for _, name := range names {
+		targets = append(targets, interfaceTarget(t, filePath, name))
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: targets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")])
+	if code != "" {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+		}
+	}
+	return code, result
+}
+
+func TestErrorsHandler(t *testing.T) {
+	code, result := generateErrorsCode(t, "IOrderAPI", "IAuditAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		// 接口级错误在同包，方法级错误在 errs 包：依次查询两个注册表
+		"func (a *OrderAPIWrap) errorResponse(err error) (int, any) {",
+		"if c, ok := GetCode(err); ok {",
+		"} else if c, ok := errs.GetCode(err); ok {",
+		"if s, _ := errs.GetHttpCode(err); s != 0 {",
+		// 未注册的错误不输出 err.Error()
+		"status, code, message := 500, 500, http.StatusText(http.StatusInternalServerError)",
+		"code, message = c, err.Error()",
+		"}{Code: code, Message: message}",
+		// gin
+		"func (a *OrderAPIWrap) onError(ctx *gin.Context, err error) {",
+		"ctx.AbortWithStatusJSON(status, body)",
+		"a.onError(ctx, err)",
+		// net/http，完整导入路径引用
+		"func (a *AuditAPIWrap) onError(w http.ResponseWriter, err error) {",
+		"w.WriteHeader(status)",
+		"a.onError(w, err)",
+		`"encoding/json"`,
+		`"github.com/donutnomad/gogen/swaggen/testdata/errors/errs"`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	if strings.Count(code, "a.onError(ctx, err)")+strings.Count(code, "a.onError(w, err)") != 3 {
+		t.Errorf("every handler should check err:\n%s", code)
+	}
+}
+
+func TestErrorsOpenAPI(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/errors/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IOrderAPI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := BuildOpenAPI(NewTypeLoader(), []SwaggerInterface{*iface}, OpenAPIInfo{Title: "t", Version: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := doc.Paths["/orders/{id}"]["get"]
+	if got := keys(get.Responses); strings.Join(got, ",") != "200,400,404,409" {
+		t.Fatalf("GetOrder responses = %v", got)
+	}
+	if got := get.Responses["404"].Description; got != "ErrNotFound (11001): not found" {
+		t.Errorf("404 description = %q", got)
+	}
+	if got := get.Responses["409"].Content["application/json"].Schema.Ref; got != "#/components/schemas/ErrorResponse" {
+		t.Errorf("error schema ref = %q", got)
+	}
+	if got := keys(doc.Paths["/orders/{id}"]["delete"].Responses); strings.Join(got, ",") != "200,409" {
+		t.Errorf("DeleteOrder responses = %v", got)
+	}
+	if s := doc.Components.Schemas["ErrorResponse"]; s == nil || strings.Join(s.Required, ",") != "code,message" {
+		t.Errorf("ErrorResponse component = %+v", s)
+	}
+}
+
+func TestErrorsRequireCode(t *testing.T) {
+	_, result := generateErrorsCode(t, "IPlainAPI")
+	if !result.HasErrors() || !strings.Contains(result.Errors[0].Error(), "未标注 @Code") {
+		t.Fatalf("expected @Code error, got %v", result.Errors)
+	}
+}
@@ -15,6 +15,8 @@ type CreateUserReq struct {
 
 // @TAG(用户管理)
 // @SECURITY(Bearer)
+// @OPENAPI(openapi.yaml; title=User API; version=1.0.0)
+// @CLIENT
 type IUserAPI interface {
 	// 获取用户
 	// @GET(/api/v1/user/{id})
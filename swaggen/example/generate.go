@@ -2,6 +2,15 @@
 package example
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -88,7 +97,12 @@ func (a *UserAPIWrap) DeleteUser(ctx *gin.Context) {
 }
 
 func (a *UserAPIWrap) BindGetUser(router gin.IRoutes, preHandlers ...gin.HandlerFunc) {
-	var handlers []gin.HandlerFunc
+	var handlers = []gin.HandlerFunc{
+		func(c *gin.Context) {
+			c.Set("gormgen:methodcomment", "// 获取用户\n// @GET(/api/v1/user/{id})")
+			c.Set("gormgen:interfacecomment", "// @TAG(用户管理)\n// @SECURITY(Bearer)\n// @OPENAPI(openapi.yaml; title=User API; version=1.0.0)\n// @CLIENT")
+		},
+	}
 	if a.handler != nil {
 		handlers = append(handlers, a.handler.PreHandlers()...)
 	}
@@ -96,7 +110,12 @@ func (a *UserAPIWrap) BindGetUser(router gin.IRoutes, preHandlers ...gin.Handler
 }
 
 func (a *UserAPIWrap) BindCreateUser(router gin.IRoutes, preHandlers ...gin.HandlerFunc) {
-	var handlers []gin.HandlerFunc
+	var handlers = []gin.HandlerFunc{
+		func(c *gin.Context) {
+			c.Set("gormgen:methodcomment", "// 创建用户\n// @POST(/)")
+			c.Set("gormgen:interfacecomment", "// @TAG(用户管理)\n// @SECURITY(Bearer)\n// @OPENAPI(openapi.yaml; title=User API; version=1.0.0)\n// @CLIENT")
+		},
+	}
 	if a.handler != nil {
 		handlers = append(handlers, a.handler.PreHandlers()...)
 	}
@@ -104,7 +123,12 @@ func (a *UserAPIWrap) BindCreateUser(router gin.IRoutes, preHandlers ...gin.Hand
 }
 
 func (a *UserAPIWrap) BindDeleteUser(router gin.IRoutes, preHandlers ...gin.HandlerFunc) {
-	var handlers []gin.HandlerFunc
+	var handlers = []gin.HandlerFunc{
+		func(c *gin.Context) {
+			c.Set("gormgen:methodcomment", "// 删除用户\n// @DELETE(/api/v1/user/{id})")
+			c.Set("gormgen:interfacecomment", "// @TAG(用户管理)\n// @SECURITY(Bearer)\n// @OPENAPI(openapi.yaml; title=User API; version=1.0.0)\n// @CLIENT")
+		},
+	}
 	if a.handler != nil {
 		handlers = append(handlers, a.handler.PreHandlers()...)
 	}
@@ -117,6 +141,29 @@ func (a *UserAPIWrap) BindAll(router gin.IRoutes, preHandlers ...gin.HandlerFunc
 	a.BindDeleteUser(router, preHandlers...)
 }
 
+// UserAPIRoute IUserAPI 注册的一条路由
+type UserAPIRoute struct {
+	Method      string   // HTTP 方法，如 GET
+	Path        string   // 完整路径，路径参数写作 {id}
+	Handler     string   // 处理方法，形如 IUserAPI.GetUser
+	Middlewares []string // @MID 声明的中间件
+	Tags        []string // @TAG 声明的标签
+	Permissions []string // @PERM 声明的权限
+}
+
+func (r UserAPIRoute) String() string {
+	return r.Method + " " + r.Path
+}
+
+// Routes 返回 IUserAPI 注册的路由（已拼接 @PREFIX），可用于文档、权限初始化与测试
+func (a *UserAPIWrap) Routes() []UserAPIRoute {
+	return []UserAPIRoute{
+		{Method: "GET", Path: "/api/v1/user/{id}", Handler: "IUserAPI.GetUser", Tags: []string{"用户管理"}},
+		{Method: "POST", Path: "/", Handler: "IUserAPI.CreateUser", Tags: []string{"用户管理"}},
+		{Method: "DELETE", Path: "/api/v1/user/{id}", Handler: "IUserAPI.DeleteUser", Tags: []string{"用户管理"}},
+	}
+}
+
 //
 //func onGinBind(c *gin.Context, val any, typ string) bool {
 //    switch typ {
@@ -151,3 +198,122 @@ func (a *UserAPIWrap) BindAll(router gin.IRoutes, preHandlers ...gin.HandlerFunc
 //func onGinBindErr(c *gin.Context, err error) {
 //    c.JSON(500, gin.H{"error": err.Error()})
 //}
+
+// UserAPIClient 基于 net/http 实现 IUserAPI 的客户端
+type UserAPIClient struct {
+	BaseURL     string                          // 服务地址，如 http://localhost:8080
+	HTTPClient  *http.Client                    // 为 nil 时使用 http.DefaultClient
+	Middlewares []func(req *http.Request) error // 请求发送前依次调用，可用于设置认证头、追踪信息等
+}
+
+var _ IUserAPI = (*UserAPIClient)(nil)
+
+// NewUserAPIClient 创建客户端
+func NewUserAPIClient(baseURL string, middlewares ...func(req *http.Request) error) *UserAPIClient {
+	return &UserAPIClient{
+		BaseURL:     baseURL,
+		Middlewares: middlewares,
+	}
+}
+
+// UserAPIClientError 服务端返回的非 2xx 响应
+type UserAPIClientError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *UserAPIClientError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), bytes.TrimSpace(e.Body))
+}
+
+// call 发送请求并按 accept 解码响应体到 out（为 nil 时丢弃响应体）
+func (c *UserAPIClient) call(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, contentType, accept string, out any) error {
+	resp, err := c.do(ctx, method, path, query, header, body, contentType, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch out := out.(type) {
+	case nil:
+		_, err = io.Copy(io.Discard, resp.Body)
+	case *[]byte:
+		*out, err = io.ReadAll(resp.Body)
+	case *string:
+		var data []byte
+		data, err = io.ReadAll(resp.Body)
+		*out = string(data)
+	default:
+		if strings.Contains(accept, "xml") {
+			err = xml.NewDecoder(resp.Body).Decode(out)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(out)
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	return err
+}
+
+// do 发送请求，非 2xx 响应读取响应体后返回 UserAPIClientError；成功时由调用方关闭 resp.Body
+func (c *UserAPIClient) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, contentType, accept string) (*http.Response, error) {
+	target := strings.TrimRight(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", accept)
+	for _, middleware := range c.Middlewares {
+		if err := middleware(req); err != nil {
+			return nil, err
+		}
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, &UserAPIClientError{StatusCode: resp.StatusCode, Body: data}
+	}
+	return resp, nil
+}
+
+// GetUser GET /api/v1/user/{id}
+func (c *UserAPIClient) GetUser(ctx context.Context, id int64) (UserResponse, error) {
+	var result UserResponse
+	err := c.call(ctx, "GET", "/api/v1/user/"+url.PathEscape(strconv.FormatInt(id, 10)), nil, nil, nil, "", "application/json", &result)
+	return result, err
+}
+
+// CreateUser POST /
+func (c *UserAPIClient) CreateUser(ctx context.Context, req CreateUserReq) (UserResponse, error) {
+	var result UserResponse
+	data, err := json.Marshal(req)
+	if err != nil {
+		return result, err
+	}
+	err = c.call(ctx, "POST", "/", nil, nil, bytes.NewReader(data), "application/json", "application/json", &result)
+	return result, err
+}
+
+// DeleteUser DELETE /api/v1/user/{id}
+func (c *UserAPIClient) DeleteUser(ctx context.Context, id int64) error {
+	return c.call(ctx, "DELETE", "/api/v1/user/"+url.PathEscape(strconv.FormatInt(id, 10)), nil, nil, nil, "", "application/json", nil)
+}
@@ -49,6 +49,13 @@ func newTagParserSafe() (*parsers.Parser, error) {
 		parsers.ExcludeFromBindAll{},
 		parsers.Raw{},
 		parsers.Prefix{},
+		parsers.OpenAPI{},
+		parsers.TS{},
+		parsers.Client{},
+		parsers.SwagBackend{},
+		parsers.Errors{},
+		parsers.Perm{},
+		parsers.RateLimit{},
 	)
 
 	return parser, err
@@ -91,6 +98,11 @@ func mergeDefs[T any](ifaceDefs, methodDefs []parsers.Definition, f func(item pa
 	post(methodTags)
 }
 
+// securityApplies 判断 @SECURITY 是否作用于方法：指定 include 时仅作用于列出的方法，否则作用于 exclude 之外的方法
+func securityApplies(v *parsers.Security, method string) bool {
+	return scopeApplies(v.Include, v.Exclude, method)
+}
+
 // generateMethodComments 生成单个方法的 Swagger 注释
 func (g *SwaggerGenerator) generateMethodComments(method SwaggerMethod, iface SwaggerInterface) []string {
 	var lines []string
@@ -133,52 +145,23 @@ func (g *SwaggerGenerator) generateMethodComments(method SwaggerMethod, iface Sw
 	})
 
 	if method.GetHTTPMethod() != "GET" {
-		mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
-			return DefSlice{item}.GetAcceptType()
-		}, func(i []string) {
-			var ret = "json"
-			if len(i) > 0 {
-				ret = i[0]
-			}
-			lines = append(lines, fmt.Sprintf("// @Accept %s", ret))
-		})
+		lines = append(lines, fmt.Sprintf("// @Accept %s", acceptType(iface, method)))
 	}
-
-	mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
-		return DefSlice{item}.GetContentType()
-	}, func(i []string) {
-		var ret = "json"
-		if len(i) > 0 {
-			ret = i[0]
-		}
-		lines = append(lines, fmt.Sprintf("// @Produce %s", ret))
-	})
+	lines = append(lines, fmt.Sprintf("// @Produce %s", produceType(iface, method)))
 
 	mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
 		v, ok := item.(*parsers.Security)
 		if !ok {
 			return "", false
 		}
-		ok = false
-		if len(v.Include) > 0 {
-			if lo.Contains(v.Include, method.Name) {
-				ok = true
-			}
-		} else if len(v.Exclude) > 0 {
-			if !lo.Contains(v.Include, method.Name) {
-				ok = true
-			}
-		} else {
-			ok = true
-		}
-		return v.Value, ok
+		return v.Value, securityApplies(v, method.Name)
 	}, func(i []string) {
 		if len(i) > 0 {
 			lines = append(lines, fmt.Sprintf("// @Security %s", strings.Join(i, ",")))
 		}
 	})
 
-	paramLines := g.generateParameterComments(method, method.Parameters, iface.CommonDef, method.Def)
+	paramLines := g.generateParameterComments(iface, method)
 	lines = append(lines, paramLines...)
 
 	for _, md := range CollectDef[*parsers.Raw](method.Def) {
@@ -186,36 +169,39 @@ func (g *SwaggerGenerator) generateMethodComments(method SwaggerMethod, iface Sw
 	}
 
 	successLine := g.generateSuccessComment(method.ResponseType)
+	if method.Response == ResponseDownload {
+		successLine = "// @Success 200 {file} file"
+	}
 	lines = append(lines, successLine)
 
 	prefix := iface.CommonDef.GetPrefix()
 
 	for _, pathRouter := range method.GetPaths() {
-		fullPath := prefix + pathRouter
-		fullPath = strings.TrimRight(fullPath, "/")
-		if fullPath == "" {
-			fullPath = "/"
-		}
-		lines = append(lines, fmt.Sprintf("// @Router %s [%s]", fullPath, strings.ToLower(method.GetHTTPMethod())))
+		lines = append(lines, fmt.Sprintf("// @Router %s [%s]", fullRoutePath(prefix, pathRouter), strings.ToLower(method.GetHTTPMethod())))
 	}
 
 	return lines
 }
 
 // generateParameterComments 生成参数注释
-func (g *SwaggerGenerator) generateParameterComments(method SwaggerMethod, parameters []Parameter, ifaceDef, def DefSlice) []string {
+func (g *SwaggerGenerator) generateParameterComments(iface SwaggerInterface, method SwaggerMethod) []string {
 	var lines []string
 
-	for i, param := range parameters {
+	for i, param := range method.Parameters {
 		if param.Type.FullName == GinContextType || param.Type.TypeName == "Context" {
 			continue
 		}
-		if param.Source == "path" {
-		} else if param.Source == "header" {
-		} else if i == len(parameters)-1 {
+		if param.Source == ParamSourceFile {
+			lines = append(lines, fmt.Sprintf("// @Param %s formData %s %s \"%s\"", param.ExternalName(),
+				lo.Ternary(param.Type.IsSlice, "[]file", "file"), lo.Ternary(param.Required, "true", "false"),
+				lo.Ternary(param.Comment == "", param.Name, param.Comment)))
+			continue
+		}
+		if param.Source == "path" || param.Source == "header" || param.Source == "query" {
+		} else if i == method.BodyIndex() {
 			if method.GetHTTPMethod() == "GET" {
 				param.Source = "query"
-			} else if v, _ := slices.Concat(def, ifaceDef).GetAcceptType(); v == "json" {
+			} else if acceptType(iface, method) == "json" {
 				param.Source = "body"
 			} else {
 				param.Source = "formData"
@@ -226,7 +212,7 @@ func (g *SwaggerGenerator) generateParameterComments(method SwaggerMethod, param
 		lines = append(lines, paramLine)
 	}
 
-	var allSlice = slices.Concat(ifaceDef, def)
+	var allSlice = slices.Concat(iface.CommonDef, method.Def)
 	var headerMap = make(map[string]*parsers.Header)
 	var headerNames []string
 	for _, param := range allSlice {
@@ -257,8 +243,28 @@ func (g *SwaggerGenerator) generateParameterComment(param Parameter) string {
 		return fmt.Sprintf("// @Param %s body %s %s \"%s\"", param.Name, param.Type.FullName, required, description)
 	}
 
-	n := lo.Ternary(len(param.PathName) > 0, param.PathName, param.Name)
-	return fmt.Sprintf("// @Param %s %s %s %s \"%s\"", n, param.Source, paramType, required, description)
+	line := fmt.Sprintf("// @Param %s %s %s %s \"%s\"", param.ExternalName(), param.Source, paramType, required, description)
+	return strings.TrimRight(line+" "+swagRuleAttributes(param), " ")
+}
+
+// swagRuleAttributes 将参数注解的校验规则转换为 swag 的属性，如 minimum(1) Enums(a,b)
+func swagRuleAttributes(param Parameter) string {
+	var attrs []string
+	format := func(n float64) string { return strconv.FormatFloat(n, 'f', -1, 64) }
+	lower, upper := "minimum", "maximum"
+	if paramKind(param) == kindString {
+		lower, upper = "minlength", "maxlength"
+	}
+	if r := param.Rules; r.Min != nil {
+		attrs = append(attrs, fmt.Sprintf("%s(%s)", lower, format(*r.Min)))
+	}
+	if r := param.Rules; r.Max != nil {
+		attrs = append(attrs, fmt.Sprintf("%s(%s)", upper, format(*r.Max)))
+	}
+	if len(param.Rules.Enum) > 0 {
+		attrs = append(attrs, fmt.Sprintf("Enums(%s)", strings.Join(param.Rules.Enum, ",")))
+	}
+	return strings.Join(attrs, " ")
 }
 
 // generateSuccessComment 生成成功响应注释
@@ -322,16 +328,19 @@ func (g *SwaggerGenerator) GenerateFileHeader(packageName string) string {
 	return strings.Join(lines, "\n")
 }
 
-// GenerateImports 生成导入声明
-func (g *SwaggerGenerator) GenerateImports() string {
-	var imports []string
-	imports = append(imports, `	"strings"`)
-	imports = append(imports, "")
-	imports = append(imports, `	"github.com/gin-gonic/gin"`)
+// GenerateImports 生成导入声明，extra 为附加的导入（如客户端代码所需的包）
+func (g *SwaggerGenerator) GenerateImports(extra ...string) string {
+	paths := collectBackendImports(g.collection.Interfaces)
+	imports := backendImports(paths)
 
 	if g.needsCastImport() {
 		imports = append(imports, `	"github.com/spf13/cast"`)
 	}
+	for _, item := range slices.Concat(errorImports(g.collection.Interfaces), validationImports(g.collection.Interfaces), guardImports(g.collection.Interfaces), extra) {
+		if !slices.Contains(paths, strings.Trim(item, `"`)) && !slices.Contains(imports, "	"+item) {
+			imports = append(imports, "	"+item)
+		}
+	}
 
 	return "import (\n" + strings.Join(imports, "\n") + "\n)"
 }
@@ -371,14 +380,14 @@ func convertPathToGinFormat(path string) string {
 	return re.ReplaceAllString(path, ":$1")
 }
 
-// NewGinGenerator 创建 Gin 生成器
+// NewGinGenerator 创建路由绑定代码生成器，各接口按 @SwagBackend 选择 HTTP 框架后端（默认 gin）
 func NewGinGenerator(collection *InterfaceCollection) *GinGenerator {
 	return &GinGenerator{
 		collection: collection,
 	}
 }
 
-// GenerateGinCode 生成 Gin 绑定代码
+// GenerateGinCode 生成路由绑定代码
 func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCode, code string) {
 	// 按接口名排序，确保生成代码顺序稳定
 	sortedInterfaces := make([]SwaggerInterface, len(g.collection.Interfaces))
@@ -392,6 +401,7 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 	var handlerInterface []string
 
 	for _, iface := range sortedInterfaces {
+		backend := backendOf(iface)
 		var middlewareCount int
 		var middlewareMap = make(map[string][]*parsers.MiddleWare)
 		var handlerItfName = fmt.Sprintf("%sHandler", iface.Name)
@@ -408,6 +418,25 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 		parts = append(parts, bindMethodCode)
 		parts = append(parts, "")
 
+		if len(iface.ErrorRegistries) > 0 {
+			parts = append(parts, generateErrorResponse(iface.GetWrapperName(), iface.ErrorRegistries), "")
+			parts = append(parts, backend.OnError(iface.GetWrapperName()), "")
+		}
+		if needsAuthorizer(iface) || needsLimiter(iface) {
+			parts = append(parts, generateGuardTypes(iface), "")
+			parts = append(parts, backend.OnDenied(iface.GetWrapperName()), "")
+		}
+		if hasValidation(iface) {
+			parts = append(parts, generateFieldErrorType(iface), "")
+			parts = append(parts, backend.OnInvalid(iface.GetWrapperName(), iface.GetFieldErrorName()), "")
+			if needsBindingInvalid(iface) {
+				parts = append(parts, generateBindingInvalid(iface), "")
+			}
+			if vars := generatePatternVars(iface); vars != "" {
+				parts = append(parts, vars, "")
+			}
+		}
+
 		for _, method := range iface.Methods {
 			methodKey := fmt.Sprintf("%s.%s", iface.Name, method.Name)
 			if v, ok := comments[methodKey]; ok {
@@ -424,7 +453,7 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 			parts = append(parts, "")
 		}
 
-		template := fmt.Sprintf("func (a *%s) BindAll(router gin.IRoutes, preHandlers ...gin.HandlerFunc) {", iface.GetWrapperName())
+		template := fmt.Sprintf("func (a *%s) BindAll(router %s, preHandlers ...%s) {", iface.GetWrapperName(), backend.RouterType(iface.GetWrapperName()), backend.MiddlewareType())
 		parts = append(parts, template)
 		for _, method := range iface.Methods {
 			if method.Def.IsRemoved() || method.Def.IsExcludeFromBindAll() {
@@ -434,6 +463,10 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 		}
 		parts = append(parts, "}")
 		parts = append(parts, "")
+		parts = append(parts, generateRoutesMethod(iface), "")
+		if needsAuthorizer(iface) {
+			parts = append(parts, generatePermissionsMethod(iface), "")
+		}
 
 		if len(middlewareMap) > 0 {
 			var items = lo.Uniq(lo.Flatten(lo.Map(lo.Flatten(maps.Values(middlewareMap)), func(item *parsers.MiddleWare, index int) []string {
@@ -442,9 +475,9 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 			sort.Strings(items)
 
 			handlerInterface = append(handlerInterface, fmt.Sprintf("type %s interface {", handlerItfName))
-			handlerInterface = append(handlerInterface, fmt.Sprintf("%s() []gin.HandlerFunc", "PreHandlers"))
+			handlerInterface = append(handlerInterface, fmt.Sprintf("%s() []%s", "PreHandlers", backend.MiddlewareType()))
 			for _, key := range items {
-				handlerInterface = append(handlerInterface, fmt.Sprintf("%s() []gin.HandlerFunc", key))
+				handlerInterface = append(handlerInterface, fmt.Sprintf("%s() []%s", key, backend.MiddlewareType()))
 			}
 			handlerInterface = append(handlerInterface, "}")
 			handlerInterface = append(handlerInterface, "\n")
@@ -456,101 +489,49 @@ func (g *GinGenerator) GenerateGinCode(comments map[string]string) (constructCod
 	return strings.Join(constructorParts, "\n\n"), strings.Join(slices.Concat(handlerInterface, parts), "\n")
 }
 
-// generateWrapperStruct 生成包装结构体
+// generateWrapperStruct 生成包装结构体；声明了 @PERM / @RATELIMIT 时额外持有 Authorizer / Limiter
 func (g *GinGenerator) generateWrapperStruct(iface SwaggerInterface, handlerItfName string) (string, string) {
-	wrapperName := iface.GetWrapperName()
-	constructorName := fmt.Sprintf("New%s", wrapperName)
-
-	if len(handlerItfName) == 0 {
-		template1 := `
-func {{.ConstructorName}}(inner {{.InterfaceName}}) *{{.WrapperName}} {
-    return &{{.WrapperName}}{
-        inner: inner,
-    }
-}
-`
-
-		data1 := map[string]any{
-			"ConstructorName": constructorName,
-			"WrapperName":     wrapperName,
-			"InterfaceName":   iface.Name,
-		}
-		constructorResult := utils.MustExecuteTemplate(data1, template1)
-
-		template := `
-type {{.WrapperName}} struct {
-    inner {{.InterfaceName}}
-}
-`
-		data := map[string]any{
-			"WrapperName":   wrapperName,
-			"InterfaceName": iface.Name,
-		}
-
-		result := utils.MustExecuteTemplate(data, template)
-		return strings.TrimSpace(constructorResult), strings.TrimSpace(result)
+	type field struct{ Name, Type string }
+	fields := []field{{"inner", iface.Name}}
+	if len(handlerItfName) > 0 {
+		fields = append(fields, field{"handler", handlerItfName})
+	}
+	if needsAuthorizer(iface) {
+		fields = append(fields, field{"authorizer", iface.GetAuthorizerName()})
+	}
+	if needsLimiter(iface) {
+		fields = append(fields, field{"limiter", iface.GetLimiterName()})
 	}
 
 	template1 := `
-func {{.ConstructorName}}(inner {{.InterfaceName}}, handler {{.HandlerName}}) *{{.WrapperName}} {
+func {{.ConstructorName}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Name}} {{$f.Type}}{{end}}) *{{.WrapperName}} {
     return &{{.WrapperName}}{
-        inner: inner,
-        handler: handler,
+        {{- range .Fields}}
+        {{.Name}}: {{.Name}},
+        {{- end}}
     }
 }
 `
-
-	data1 := map[string]any{
-		"ConstructorName": constructorName,
-		"WrapperName":     wrapperName,
-		"InterfaceName":   iface.Name,
-		"HandlerName":     handlerItfName,
-	}
-
-	constructorResult := utils.MustExecuteTemplate(data1, template1)
-
 	template := `
 type {{.WrapperName}} struct {
-    inner {{.InterfaceName}}
-    handler {{.HandlerName}}
+    {{- range .Fields}}
+    {{.Name}} {{.Type}}
+    {{- end}}
 }
 `
 	data := map[string]any{
-		"WrapperName":   wrapperName,
-		"InterfaceName": iface.Name,
-		"HandlerName":   handlerItfName,
+		"ConstructorName": fmt.Sprintf("New%s", iface.GetWrapperName()),
+		"WrapperName":     iface.GetWrapperName(),
+		"Fields":          fields,
 	}
-
+	constructorResult := utils.MustExecuteTemplate(data, template1)
 	result := utils.MustExecuteTemplate(data, template)
 	return strings.TrimSpace(constructorResult), strings.TrimSpace(result)
 }
 
 // generateBindMethod 生成通用的 bind 方法
 func (g *GinGenerator) generateBindMethod(iface SwaggerInterface) string {
-	wrapperName := iface.GetWrapperName()
-
-	template := `
-func (a *{{.WrapperName}}) bind(router gin.IRoutes, method, path string, preHandlers, innerHandlers []gin.HandlerFunc, f gin.HandlerFunc) {
-    var basePath string
-    if v, ok := router.(interface {
-        BasePath() string
-    }); ok {
-        basePath = v.BasePath()
-    }
-    handlers := make([]gin.HandlerFunc, 0, len(preHandlers)+len(innerHandlers)+1)
-    handlers = append(handlers, preHandlers...)
-    handlers = append(handlers, innerHandlers...)
-    handlers = append(handlers, f)
-    router.Handle(method, strings.TrimPrefix(path, basePath), handlers...)
-}
-`
-
-	data := map[string]any{
-		"WrapperName": wrapperName,
-	}
-
-	result := utils.MustExecuteTemplate(data, template)
-	return strings.TrimSpace(result)
+	return backendOf(iface).BindMethod(iface.GetWrapperName())
 }
 
 // generateHandlerMethod 生成处理器方法
@@ -558,29 +539,24 @@ func (g *GinGenerator) generateHandlerMethod(iface SwaggerInterface, method Swag
 	wrapperName := iface.GetWrapperName()
 	handlerMethodName := method.Name
 
-	paramBindingCode := g.generateParameterBinding(iface, method)
-	methodCallCode := g.generateMethodCall(method)
+	backend := backendOf(iface)
+	// 权限检查与限流先于参数绑定，拒绝的请求不读取请求体
+	body := lo.Compact([]string{
+		generateGuardChecks(backend, iface, method),
+		g.generateParameterBinding(iface, method),
+		g.generateMethodCall(backend, iface, method),
+	})
 
-	var template string
-	if paramBindingCode == "" {
-		template = `
-func (a *{{.WrapperName}}) {{.HandlerMethodName}}(ctx *gin.Context) {
-{{.MethodCall}}
-}
-`
-	} else {
-		template = `
-func (a *{{.WrapperName}}) {{.HandlerMethodName}}(ctx *gin.Context) {
-{{.ParameterBinding}}
-{{.MethodCall}}
+	template := `
+func (a *{{.WrapperName}}) {{.HandlerMethodName}}{{.Signature}} {
+{{.Body}}
 }
 `
-	}
 	data := map[string]any{
 		"WrapperName":       wrapperName,
 		"HandlerMethodName": handlerMethodName,
-		"ParameterBinding":  paramBindingCode,
-		"MethodCall":        methodCallCode,
+		"Signature":         backend.HandlerSignature(),
+		"Body":              strings.Join(body, "\n"),
 	}
 	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
 }
@@ -594,23 +570,15 @@ func (g *GinGenerator) generateMethodBinding(iface SwaggerInterface, method Swag
 	methodCommentLiteral := strconv.Quote(strings.Join(method.RawComments, "\n"))
 	interfaceCommentLiteral := strconv.Quote(strings.Join(iface.RawComments, "\n"))
 
-	ginPaths := lo.Map(method.GetPaths(), func(item string, index int) string {
-		fullPath := prefix + convertPathToGinFormat(item)
-		fullPath = strings.TrimRight(fullPath, "/")
-		if fullPath == "" {
-			fullPath = "/"
-		}
-		return fullPath
+	backend := backendOf(iface)
+
+	routePaths := lo.Map(method.GetPaths(), func(item string, index int) string {
+		return backend.RoutePath(fullRoutePath(prefix, item))
 	})
 
 	template := `
-func (a *{{.WrapperName}}) {{.BindMethodName}}(router gin.IRoutes, preHandlers ...gin.HandlerFunc) { {{- range .GinPath}}
-	var handlers = []gin.HandlerFunc{
-		func(c *gin.Context) {
-			c.Set("gormgen:methodcomment", {{$.MethodCommentLiteral}})
-			c.Set("gormgen:interfacecomment", {{$.InterfaceCommentLiteral}})
-		},
-	}
+func (a *{{.WrapperName}}) {{.BindMethodName}}(router {{.RouterType}}, preHandlers ...{{.MiddlewareType}}) { {{- range .RoutePaths}}
+	{{$.RouteHandlers}}
 	if a.handler != nil {
 		handlers = append(handlers, a.handler.PreHandlers()...)
 		{{range $.Handlers}}handlers = append(handlers, a.handler.{{.}}()...)
@@ -626,18 +594,25 @@ func (a *{{.WrapperName}}) {{.BindMethodName}}(router gin.IRoutes, preHandlers .
 		"Handlers": lo.Uniq(lo.Flatten(lo.Map(middlewares, func(item *parsers.MiddleWare, index int) []string {
 			return item.Value
 		}))),
-		"HTTPMethod":              method.GetHTTPMethod(),
-		"GinPath":                 ginPaths,
-		"HandlerMethodName":       handlerMethodName,
-		"MethodCommentLiteral":    methodCommentLiteral,
-		"InterfaceCommentLiteral": interfaceCommentLiteral,
+		"HTTPMethod":        method.GetHTTPMethod(),
+		"RoutePaths":        routePaths,
+		"HandlerMethodName": handlerMethodName,
+		"RouterType":        backend.RouterType(iface.GetWrapperName()),
+		"MiddlewareType":    backend.MiddlewareType(),
+		"RouteHandlers":     backend.RouteHandlers(methodCommentLiteral, interfaceCommentLiteral),
 	}
 	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
 }
 
-// generateParameterBinding 生成参数绑定代码
+// generateParameterBinding 生成参数绑定代码；声明了校验规则时收集未通过校验的字段，统一以 400 响应
 func (g *GinGenerator) generateParameterBinding(iface SwaggerInterface, method SwaggerMethod) string {
 	var lines []string
+	backend := backendOf(iface)
+	validate := methodHasValidation(method)
+	v := validator{typeName: iface.GetFieldErrorName()}
+	if validate {
+		lines = append(lines, fmt.Sprintf("var invalid []%s", v.typeName))
+	}
 
 	for i, param := range method.Parameters {
 		if param.Type.FullName == GinContextType ||
@@ -645,24 +620,57 @@ func (g *GinGenerator) generateParameterBinding(iface SwaggerInterface, method S
 			strings.Contains(param.Type.FullName, "context.Context") {
 			continue
 		}
-		if param.Source == "path" {
-			lines = append(lines, g.generatePathParamBinding(param))
+		if param.Source == ParamSourceFile {
+			lines = append(lines, v.file(param, backend.Request()))
 			continue
-		} else if param.Source == "header" {
-			lines = append(lines, g.generateHeaderParamBinding(param))
+		}
+		var raw string
+		switch param.Source {
+		case "path":
+			raw = backend.PathParam(lo.Ternary(param.Alias != "", param.Alias, param.Name))
+		case "header":
+			raw = backend.Header(param.ExternalName())
+		case "query":
+			raw = backend.Query(param.ExternalName())
+		}
+		if raw != "" {
+			switch {
+			case !param.Rules.IsZero():
+				lines = append(lines, v.param(param, raw, patternVar(iface, method, param)))
+			case param.Source == "header":
+				lines = append(lines, fmt.Sprintf(`%s := %s`, param.Name, raw))
+			default:
+				lines = append(lines, g.generateTypedParamBinding(param, raw))
+			}
 			continue
 		}
 
-		if i == len(method.Parameters)-1 {
+		if i == method.BodyIndex() {
+			kind, in := "FORM", "formData"
 			if method.GetHTTPMethod() == "GET" {
-				lines = append(lines, g.generateQueryParamBinding(param))
-			} else if v, _ := slices.Concat(method.Def, iface.CommonDef).GetAcceptType(); v == "json" {
-				lines = append(lines, g.generateBodyParamBinding(param))
+				kind, in = "QUERY", "query"
+			} else if acceptType(iface, method) == "json" {
+				kind, in = "JSON", "body"
+			}
+			var checks []string
+			for _, f := range method.FieldRules {
+				if code := v.field(param.Name, in, f); code != "" {
+					checks = append(checks, code)
+				}
+			}
+			if bind := backend.ValidatingBind(param.Name, kind); bind != "" && len(method.FieldRules) > 0 {
+				// 框架自带的 binding 校验同样以结构化的 400 输出，绑定成功后再执行生成的校验
+				lines = append(lines, fmt.Sprintf("var %s %s", param.Name, param.Type.FullName))
+				lines = append(lines, ifBlock("err := "+bind+"; err != nil", []string{v.binding(in, method.FieldRules)}, checks))
 			} else {
-				lines = append(lines, g.generateFormParamBinding(param))
+				lines = append(lines, g.generateRequestBinding(backend, param, kind))
+				lines = append(lines, checks...)
 			}
 		}
 	}
+	if validate {
+		lines = append(lines, fmt.Sprintf("if len(invalid) > 0 {\n%s\n}", backend.HandleInvalid()))
+	}
 
 	for i, line := range lines {
 		if line != "" && !strings.HasPrefix(line, "        ") {
@@ -711,66 +719,21 @@ func (g *GinGenerator) generateTypedParamBinding(param Parameter, paramValue str
 	}
 }
 
-// generatePathParamBinding 生成路径参数绑定
-func (g *GinGenerator) generatePathParamBinding(param Parameter) string {
-	paramNameInPath := param.Name
-	if param.Alias != "" {
-		paramNameInPath = param.Alias
-	}
-	paramValue := fmt.Sprintf(`ctx.Param("%s")`, paramNameInPath)
-	return g.generateTypedParamBinding(param, paramValue)
-}
-
-// generateQueryParamBinding 生成query参数绑定
-func (g *GinGenerator) generateQueryParamBinding(param Parameter) string {
-	varName := param.Name
-	typeName := param.Type.FullName
-
-	s := fmt.Sprintf(`var %s %s
-        if !onGinBind(ctx, &%s, "QUERY") {
-			return
-		}`, varName, typeName, varName)
-	return s
-}
-
-// generateFormParamBinding 生成表单参数绑定
-func (g *GinGenerator) generateFormParamBinding(param Parameter) string {
-	varName := param.Name
-	typeName := param.Type.FullName
-
-	s := fmt.Sprintf(`var %s %s
-        if !onGinBind(ctx, &%s, "FORM") {
-			return
-		}`, varName, typeName, varName)
-	return s
-}
-
-// generateBodyParamBinding 生成 body 参数绑定
-func (g *GinGenerator) generateBodyParamBinding(param Parameter) string {
-	varName := param.Name
-	typeName := param.Type.FullName
-
-	s := fmt.Sprintf(`var %s %s
-        if !onGinBind(ctx, &%s, "JSON") {
-			return
-		}`, varName, typeName, varName)
-	return s
-}
-
-// generateHeaderParamBinding 生成头部参数绑定
-func (g *GinGenerator) generateHeaderParamBinding(param Parameter) string {
-	return fmt.Sprintf(`%s := ctx.GetHeader("%s")`, param.Name, param.Name)
+// generateRequestBinding 生成 query / 表单 / body 参数绑定，kind 为 QUERY / FORM / JSON
+func (g *GinGenerator) generateRequestBinding(backend Backend, param Parameter, kind string) string {
+	return fmt.Sprintf(`var %s %s
+        %s`, param.Name, param.Type.FullName, backend.Bind(param.Name, kind))
 }
 
 // generateMethodCall 生成方法调用代码
-func (g *GinGenerator) generateMethodCall(method SwaggerMethod) string {
+func (g *GinGenerator) generateMethodCall(backend Backend, iface SwaggerInterface, method SwaggerMethod) string {
 	var args []string
 
 	// 按照接口定义的顺序添加参数
 	for _, param := range method.Parameters {
 		// 如果是标准库的 context.Context 类型
 		if strings.Contains(param.Type.FullName, "context.Context") {
-			args = append(args, "ctx.Request.Context()")
+			args = append(args, backend.RequestContext())
 			continue
 		}
 		// 如果是 gin.Context 类型
@@ -784,7 +747,7 @@ func (g *GinGenerator) generateMethodCall(method SwaggerMethod) string {
 
 	methodCall := fmt.Sprintf("a.inner.%s(%s)", method.Name, strings.Join(args, ", "))
 
-	responseCode := g.generateResponseHandling(method, methodCall, "ctx")
+	responseCode := g.generateResponseHandling(backend, iface, method, methodCall)
 
 	return "        " + responseCode
 }
@@ -802,23 +765,36 @@ func (g *GinGenerator) methodNeedsContext(method SwaggerMethod) bool {
 }
 
 // generateResponseHandling 生成响应处理代码
-func (g *GinGenerator) generateResponseHandling(method SwaggerMethod, methodCall, receiverName string) string {
+func (g *GinGenerator) generateResponseHandling(backend Backend, iface SwaggerInterface, method SwaggerMethod, methodCall string) string {
+	// 声明了 @ERRORS 时，非 nil 错误经 @Code 注册表映射后由 onError 输出
+	var errorCheck string
+	if len(iface.ErrorRegistries) > 0 {
+		errorCheck = fmt.Sprintf(`if err != nil {
+            %s
+        }
+        `, backend.HandleError())
+	}
+
+	if method.Response != ResponseDefault {
+		return generateStreamResponse(backend, iface, method, methodCall)
+	}
+
 	if method.ResponseType.FullName == "" {
 		return fmt.Sprintf(`%s
-        onGinResponse[string](%s, "", nil)`, methodCall, receiverName)
+        %s`, methodCall, backend.Respond("[string]", `"", nil`))
 	}
 
 	if g.isErrorType(method.ResponseType) {
 		return fmt.Sprintf(`err := %s
-        onGinResponse[string](%s, "", err)`, methodCall, receiverName)
+        %s%s`, methodCall, errorCheck, backend.Respond("[string]", `"", err`))
 	}
 
 	if *version < 2 {
 		return fmt.Sprintf(`var result %s = %s
-        onGinResponse(%s, result)`, method.ResponseType.FullName, methodCall, receiverName)
+        %s`, method.ResponseType.FullName, methodCall, backend.Respond("", "result"))
 	}
 	return fmt.Sprintf(`result, err := %s
-        onGinResponse[%s](%s, result, err)`, methodCall, method.ResponseType.FullName, receiverName)
+        %s%s`, methodCall, errorCheck, backend.Respond("["+method.ResponseType.FullName+"]", "result, err"))
 }
 
 // isErrorType 检查是否是错误类型
@@ -828,7 +804,7 @@ func (g *GinGenerator) isErrorType(typeInfo TypeInfo) bool {
 		strings.HasSuffix(typeInfo.TypeName, "Error")
 }
 
-// GenerateComplete 生成完整的 Gin 绑定代码
+// GenerateComplete 生成完整的路由绑定代码
 func (g *GinGenerator) GenerateComplete(comments map[string]string) string {
 	var parts []string
 
@@ -840,8 +816,7 @@ func (g *GinGenerator) GenerateComplete(comments map[string]string) string {
 		parts = append(parts, ginCode)
 	}
 
-	helperFunctions := g.generateHelperFunctions()
-	if helperFunctions != "" {
+	for _, helperFunctions := range g.generateHelperFunctions() {
 		helperFunctions = strings.Join(lo.Map(strings.Split(helperFunctions, "\n"), func(item string, _ int) string {
 			return "//" + item
 		}), "\n")
@@ -851,40 +826,29 @@ func (g *GinGenerator) GenerateComplete(comments map[string]string) string {
 	return strings.Join(parts, "\n\n")
 }
 
-// generateHelperFunctions 生成辅助函数
-func (g *GinGenerator) generateHelperFunctions() string {
-	return `
-func onGinBind(c *gin.Context, val any, typ string) bool {
-    switch typ {
-    case "JSON":
-        if err := c.ShouldBindJSON(val); err != nil {
-            c.JSON(400, gin.H{"error": err.Error()})
-            return false
-        }
-    case "FORM":
-        if err := c.ShouldBind(val); err != nil {
-            c.JSON(400, gin.H{"error": err.Error()})
-            return false
-        }
-    case "QUERY":
-        if err := c.ShouldBindQuery(val); err != nil {
-            c.JSON(400, gin.H{"error": err.Error()})
-            return false
-        }
-    default:
-        if err := c.ShouldBind(val); err != nil {
-            c.JSON(400, gin.H{"error": err.Error()})
-            return false
-        }
-    }
-    return true
-}
-
-func onGinResponse[T any](c *gin.Context, data any, err error) {
-    c.JSON(200, data)
-}
-
-func onGinBindErr(c *gin.Context, err error) {
-    c.JSON(500, gin.H{"error": err.Error()})
-}`
+// generateHelperFunctions 生成各后端需要使用方实现的辅助函数示例
+func (g *GinGenerator) generateHelperFunctions() []string {
+	var names []string
+	var out []string
+	var formDecode bool
+	for _, iface := range g.collection.Interfaces {
+		backend := backendOf(iface)
+		if slices.Contains(names, backend.Name()) {
+			continue
+		}
+		names = append(names, backend.Name())
+		helpers := backend.HelperFunctions()
+		// net/http、chi 与 echo 共用的表单解码只输出一次
+		if strings.HasSuffix(helpers, formDecodeHelperFunctions) {
+			if formDecode {
+				helpers = strings.TrimSuffix(helpers, formDecodeHelperFunctions)
+			}
+			formDecode = true
+		}
+		out = append(out, helpers)
+	}
+	if len(out) == 0 {
+		out = append(out, ginBackend{}.HelperFunctions())
+	}
+	return out
 }
@@ -0,0 +1,270 @@
+package swaggen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	"github.com/donutnomad/gogen/swaggen/guard"
+	parsers "github.com/donutnomad/gogen/swaggen/parser"
+	"github.com/donutnomad/gogen/swaggen/routes"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// 权限（@PERM）与限流（@RATELIMIT）
+// ============================================================================
+
+// scopeApplies 判断接口级注释是否作用于方法：指定 include 时仅作用于列出的方法，否则作用于 exclude 之外的方法
+func scopeApplies(include, exclude []string, method string) bool {
+	if len(include) > 0 {
+		return lo.Contains(include, method)
+	}
+	return !lo.Contains(exclude, method)
+}
+
+// methodPerms 返回方法需要的权限：方法级 @PERM 覆盖接口级，多个 @PERM 须同时满足
+func methodPerms(iface SwaggerInterface, method SwaggerMethod) []string {
+	var out []string
+	mergeDefs[[]string](iface.CommonDef, method.Def, func(item parsers.Definition) ([]string, bool) {
+		v, ok := item.(*parsers.Perm)
+		if !ok {
+			return nil, false
+		}
+		return v.Value, scopeApplies(v.Include, v.Exclude, method.Name)
+	}, func(i [][]string) {
+		out = lo.Uniq(lo.Flatten(i))
+	})
+	return out
+}
+
+// methodRates 返回方法的限流规则：方法级 @RATELIMIT 覆盖接口级，多条规则须同时满足
+func methodRates(iface SwaggerInterface, method SwaggerMethod) ([]guard.Rate, error) {
+	var out []guard.Rate
+	var errs []error
+	mergeDefs[*parsers.RateLimit](iface.CommonDef, method.Def, func(item parsers.Definition) (*parsers.RateLimit, bool) {
+		v, ok := item.(*parsers.RateLimit)
+		if !ok {
+			return nil, false
+		}
+		return v, scopeApplies(v.Include, v.Exclude, method.Name)
+	}, func(i []*parsers.RateLimit) {
+		for _, v := range i {
+			rate, err := guard.ParseRate(v.Value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("@RATELIMIT: %w", err))
+				continue
+			}
+			rate.Key = v.Key
+			out = append(out, rate)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return out, nil
+}
+
+// checkGuards 校验方法的 @RATELIMIT 频率
+func checkGuards(iface SwaggerInterface, method SwaggerMethod) error {
+	_, err := methodRates(iface, method)
+	return err
+}
+
+// needsAuthorizer 是否有方法声明了 @PERM，此时包装结构体需要 Authorizer
+func needsAuthorizer(iface SwaggerInterface) bool {
+	return slices.ContainsFunc(iface.Methods, func(m SwaggerMethod) bool {
+		return len(methodPerms(iface, m)) > 0
+	})
+}
+
+// needsLimiter 是否有方法声明了 @RATELIMIT，此时包装结构体需要 Limiter
+func needsLimiter(iface SwaggerInterface) bool {
+	return slices.ContainsFunc(iface.Methods, func(m SwaggerMethod) bool {
+		rates, _ := methodRates(iface, m)
+		return len(rates) > 0
+	})
+}
+
+// guardImports 返回权限检查与限流代码需要的导入行
+func guardImports(interfaces []SwaggerInterface) []string {
+	var out []string
+	for _, iface := range interfaces {
+		limiter := needsLimiter(iface)
+		if !limiter && !needsAuthorizer(iface) {
+			continue
+		}
+		out = append(out, `"errors"`, `"net/http"`)
+		out = append(out, backendOf(iface).ErrorImports()...)
+		if limiter {
+			out = append(out, `"time"`)
+		}
+	}
+	return lo.Uniq(out)
+}
+
+// generateGuardTypes 生成包装结构体依赖的 Authorizer / Limiter 接口及检查方法；
+// 接口按包装结构体命名、只使用标准库类型，生成代码不依赖 gogen 的包，guard 包的实现可直接传入
+func generateGuardTypes(iface SwaggerInterface) string {
+	template := `
+{{- if .Authorizer}}
+// {{.AuthorizerName}} 检查请求是否具备 perms 中的全部权限，handler 为处理方法（形如 {{.InterfaceName}}.Method）；
+// 返回的错误实现 StatusCode() int 时按其状态码输出，否则以 403 输出
+type {{.AuthorizerName}} interface {
+    Authorize(r *http.Request, handler string, perms []string) error
+}
+
+// authorize 经 authorizer 检查权限，返回拒绝时的状态码；未配置 authorizer 时拒绝请求（500），避免权限检查失效
+func (a *{{.WrapperName}}) authorize(r *http.Request, handler string, perms ...string) (int, error) {
+    if a.authorizer == nil {
+        return http.StatusInternalServerError, errors.New("未配置 {{.AuthorizerName}}")
+    }
+    return a.deniedStatus(a.authorizer.Authorize(r, handler, perms), http.StatusForbidden)
+}
+{{end}}
+{{- if .Limiter}}
+// {{.LimiterName}} 判断请求是否未超过每 per 时间 limit 次，key 为 @RATELIMIT 的计数维度（如 user / ip），handler 为处理方法；
+// 返回的错误实现 StatusCode() int 时按其状态码输出，否则以 429 输出
+type {{.LimiterName}} interface {
+    Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error
+}
+
+// allow 经 limiter 检查限流，返回拒绝时的状态码；未配置 limiter 时拒绝请求（500）
+func (a *{{.WrapperName}}) allow(r *http.Request, handler string, limit int, per time.Duration, key string) (int, error) {
+    if a.limiter == nil {
+        return http.StatusInternalServerError, errors.New("未配置 {{.LimiterName}}")
+    }
+    return a.deniedStatus(a.limiter.Allow(r, handler, limit, per, key), http.StatusTooManyRequests)
+}
+{{end}}
+// deniedStatus 返回拒绝响应的状态码：err 实现 StatusCode() int 时使用其状态码，否则为 status
+func (a *{{.WrapperName}}) deniedStatus(err error, status int) (int, error) {
+    var coded interface{ StatusCode() int }
+    if errors.As(err, &coded) {
+        status = coded.StatusCode()
+    }
+    return status, err
+}
+
+// deniedResponse 返回权限检查或限流未通过的响应，结构与 @ERRORS 的错误响应一致
+func (a *{{.WrapperName}}) deniedResponse(status int, err error) (int, any) {
+    return status, struct {
+        Code    int    ` + "`json:\"code\"`" + `
+        Message string ` + "`json:\"message\"`" + `
+    }{Code: status, Message: err.Error()}
+}
+`
+	data := map[string]any{
+		"InterfaceName":  iface.Name,
+		"WrapperName":    iface.GetWrapperName(),
+		"AuthorizerName": iface.GetAuthorizerName(),
+		"LimiterName":    iface.GetLimiterName(),
+		"Authorizer":     needsAuthorizer(iface),
+		"Limiter":        needsLimiter(iface),
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// generateGuardChecks 生成处理器开头的权限检查与限流代码，未通过时由 onDenied 输出
+func generateGuardChecks(backend Backend, iface SwaggerInterface, method SwaggerMethod) string {
+	handler := strconv.Quote(iface.Name + "." + method.Name)
+	var lines []string
+	if perms := methodPerms(iface, method); len(perms) > 0 {
+		args := lo.Map(perms, func(item string, _ int) string { return strconv.Quote(item) })
+		lines = append(lines, fmt.Sprintf(`if status, err := a.authorize(%s, %s, %s); err != nil {
+            %s
+        }`, backend.Request(), handler, strings.Join(args, ", "), backend.HandleDenied()))
+	}
+	rates, _ := methodRates(iface, method)
+	for _, rate := range rates {
+		lines = append(lines, fmt.Sprintf(`if status, err := a.allow(%s, %s, %s); err != nil {
+            %s
+        }`, backend.Request(), handler, rateArgs(rate), backend.HandleDenied()))
+	}
+	for i, line := range lines {
+		lines[i] = "        " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rateArgs 生成 allow 的 limit, per, key 实参，如 10, time.Second, "user"
+func rateArgs(rate guard.Rate) string {
+	per := fmt.Sprintf("time.Duration(%d)", rate.Per)
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{{time.Hour, "time.Hour"}, {time.Minute, "time.Minute"}, {time.Second, "time.Second"}, {time.Millisecond, "time.Millisecond"}} {
+		if rate.Per%unit.d == 0 {
+			per = unit.name
+			if n := rate.Per / unit.d; n != 1 {
+				per = fmt.Sprintf("%d * %s", n, unit.name)
+			}
+			break
+		}
+	}
+	return fmt.Sprintf("%d, %s, %s", rate.Limit, per, strconv.Quote(rate.Key))
+}
+
+// permission 权限目录中的一项：权限及需要它的路由（形如 POST /api/orders）
+type permission struct {
+	name   string
+	routes []string
+}
+
+// permissionCatalog 按路由的 @PERM 汇总权限目录，按权限名排序
+func permissionCatalog(rs []routes.Route) []permission {
+	index := make(map[string][]string)
+	for _, r := range rs {
+		for _, perm := range r.Permissions {
+			index[perm] = append(index[perm], r.String())
+		}
+	}
+	out := make([]permission, 0, len(index))
+	for name, rs := range index {
+		out = append(out, permission{name: name, routes: rs})
+	}
+	slices.SortFunc(out, func(a, b permission) int { return strings.Compare(a.name, b.name) })
+	return out
+}
+
+// generatePermissionsMethod 生成权限目录条目类型与 Permissions 方法，列出每个权限及需要它的路由
+func generatePermissionsMethod(iface SwaggerInterface) string {
+	var items []string
+	for _, perm := range permissionCatalog(interfaceRoutes(iface)) {
+		items = append(items, fmt.Sprintf("{Name: %s, Routes: %#v},", strconv.Quote(perm.name), perm.routes))
+	}
+
+	template := `
+// {{.TypeName}} {{.InterfaceName}} 权限目录中的一项
+type {{.TypeName}} struct {
+    Name   string   // @PERM 声明的权限
+    Routes []string // 需要该权限的路由，形如 POST /api/orders
+}
+
+// Permissions 返回 {{.InterfaceName}} 的权限目录：每个 @PERM 权限及需要它的路由，可用于初始化权限数据
+func (a *{{.WrapperName}}) Permissions() []{{.TypeName}} {
+    return []{{.TypeName}}{
+        {{- range .Items}}
+        {{.}}
+        {{- end}}
+    }
+}
+`
+	data := map[string]any{
+		"InterfaceName": iface.Name,
+		"WrapperName":   iface.GetWrapperName(),
+		"TypeName":      iface.GetPermissionName(),
+		"Items":         items,
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// rateLimitExtensions 返回 OpenAPI 操作的 x-ratelimit 扩展
+func rateLimitExtensions(rates []guard.Rate) []RateLimitExtension {
+	return lo.Map(rates, func(item guard.Rate, _ int) RateLimitExtension {
+		return RateLimitExtension{Rate: item.String(), Key: item.Key}
+	})
+}
@@ -0,0 +1,192 @@
+// Package guard 提供 swaggen 生成的 Authorizer / Limiter 接口的常用实现
+//
+// 方法或接口声明 @PERM / @RATELIMIT 后，生成的包装结构体的构造函数额外接收按接口命名的 Authorizer / Limiter
+// （如 OrderAPIAuthorizer），处理器在绑定参数、调用业务实现之前先检查。生成的接口只使用标准库类型，
+// 生成代码不导入本包；AuthorizerFunc、LimiterFunc 与 MemoryLimiter 满足这些接口，可直接传入，也可自行实现。
+package guard
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// 权限
+// ============================================================================
+
+// AuthorizerFunc 以函数实现生成的 Authorizer 接口：检查请求是否具备 perms 中的全部权限，
+// handler 为处理方法（形如 IOrderAPI.CreateOrder）；返回 *Error 时按其状态码输出，其他非 nil 错误以 403 输出
+type AuthorizerFunc func(r *http.Request, handler string, perms []string) error
+
+func (f AuthorizerFunc) Authorize(r *http.Request, handler string, perms []string) error {
+	return f(r, handler, perms)
+}
+
+// ============================================================================
+// 限流
+// ============================================================================
+
+// Rate 限流规则：按 Key 分别计数，每 Per 时间内最多 Limit 次请求
+type Rate struct {
+	Limit int
+	Per   time.Duration
+	Key   string // @RATELIMIT 的 key，如 user / ip，由 Limiter 解释；为空时按处理方法全局计数
+}
+
+// String 返回 @RATELIMIT 的写法，如 10/s、100/10m
+func (r Rate) String() string {
+	var unit string
+	switch r.Per {
+	case time.Second:
+		unit = "s"
+	case time.Minute:
+		unit = "m"
+	case time.Hour:
+		unit = "h"
+	default:
+		unit = r.Per.String()
+	}
+	return strconv.Itoa(r.Limit) + "/" + unit
+}
+
+// ParseRate 解析 @RATELIMIT 的频率，形如 10/s、100/m、1000/h，单位前可带倍数（如 5/10s）
+func ParseRate(s string) (Rate, error) {
+	count, per, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("无效的频率 %q，应形如 10/s", s)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || limit <= 0 {
+		return Rate{}, fmt.Errorf("无效的频率 %q：次数须为正整数", s)
+	}
+	per = strings.TrimSpace(per)
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("无效的频率 %q：时间单位须为 s / m / h 等", s)
+	}
+	return Rate{Limit: limit, Per: d}, nil
+}
+
+// LimiterFunc 以函数实现生成的 Limiter 接口：判断请求是否未超过每 per 时间 limit 次，
+// key 为 @RATELIMIT 的计数维度，handler 为处理方法；返回 *Error 时按其状态码输出，其他非 nil 错误以 429 输出
+type LimiterFunc func(r *http.Request, handler string, limit int, per time.Duration, key string) error
+
+func (f LimiterFunc) Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error {
+	return f(r, handler, limit, per, key)
+}
+
+// KeyFunc 将 @RATELIMIT 的 key 解析为计数维度的值，如 key 为 user 时返回当前用户 ID
+type KeyFunc func(r *http.Request, key string) string
+
+// MemoryLimiter 进程内的令牌桶限流器：每个 (handler, key 值) 一个桶，容量为 Limit，每 Per 补满
+type MemoryLimiter struct {
+	keyFunc KeyFunc
+	now     func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   Rate
+}
+
+// maxBuckets 桶数超过该值时清理已补满的桶
+const maxBuckets = 10000
+
+// NewMemoryLimiter 创建进程内限流器；key 为空时按 handler 全局计数，
+// 否则以 keyFunc 的结果计数，keyFunc 为 nil 或返回空字符串（如 key 为 user 而请求未登录）时按客户端地址计数，
+// 不会让不同请求方共用一个桶
+func NewMemoryLimiter(keyFunc KeyFunc) *MemoryLimiter {
+	return &MemoryLimiter{keyFunc: keyFunc, now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (l *MemoryLimiter) Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error {
+	rate := Rate{Limit: limit, Per: per, Key: key}
+	id := handler + "|" + rate.String() + "|" + l.key(r, rate.Key)
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[id]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.sweep(now)
+		}
+		b = &bucket{tokens: float64(rate.Limit), last: now, rate: rate}
+		l.buckets[id] = b
+	}
+	b.refill(now)
+	if b.tokens < 1 {
+		return ErrTooManyRequests
+	}
+	b.tokens--
+	return nil
+}
+
+// key 返回计数维度的值：key 为空时全局计数，无法解析时回退为客户端地址
+func (l *MemoryLimiter) key(r *http.Request, key string) string {
+	if key == "" {
+		return ""
+	}
+	if l.keyFunc != nil {
+		if v := l.keyFunc(r, key); v != "" {
+			return key + "=" + v
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip=" + host
+}
+
+// sweep 删除已补满的桶，它们与新建的桶等价
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for id, b := range l.buckets {
+		if b.refill(now); b.tokens >= float64(b.rate.Limit) {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(b.rate.Limit), b.tokens+elapsed.Seconds()/b.rate.Per.Seconds()*float64(b.rate.Limit))
+		b.last = now
+	}
+}
+
+// ============================================================================
+// 拒绝响应
+// ============================================================================
+
+// Error 带 HTTP 状态码的拒绝原因
+type Error struct {
+	Status  int
+	Message string
+	Err     error // 原始错误，可为 nil
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// StatusCode 返回响应的状态码，生成的处理器据此输出拒绝响应
+func (e *Error) StatusCode() int { return e.Status }
+
+var (
+	ErrUnauthorized    = &Error{Status: http.StatusUnauthorized, Message: "unauthorized"}
+	ErrForbidden       = &Error{Status: http.StatusForbidden, Message: "forbidden"}
+	ErrTooManyRequests = &Error{Status: http.StatusTooManyRequests, Message: "too many requests"}
+)
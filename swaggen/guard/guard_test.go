@@ -0,0 +1,154 @@
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Rate
+	}{
+		{"10/s", Rate{Limit: 10, Per: time.Second}},
+		{" 100 / m ", Rate{Limit: 100, Per: time.Minute}},
+		{"1000/h", Rate{Limit: 1000, Per: time.Hour}},
+		{"5/10s", Rate{Limit: 5, Per: 10 * time.Second}},
+	} {
+		got, err := ParseRate(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"10", "0/s", "x/s", "10/", "10/day", "10/-1s"} {
+		if _, err := ParseRate(in); err == nil {
+			t.Errorf("ParseRate(%q) expected error", in)
+		}
+	}
+	if s := (Rate{Limit: 5, Per: 10 * time.Second}).String(); s != "5/10s" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	var coded interface{ StatusCode() int }
+	err := fmt.Errorf("wrapped: %w", ErrUnauthorized)
+	if !errors.As(err, &coded) || coded.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("StatusCode() not found through %v", err)
+	}
+
+	var gotPerms []string
+	a := AuthorizerFunc(func(r *http.Request, handler string, perms []string) error {
+		gotPerms = perms
+		if r.Header.Get("X-Role") != "admin" {
+			return ErrForbidden
+		}
+		return nil
+	})
+	r := httptest.NewRequest("GET", "/", nil)
+	if err := a.Authorize(withRole(r, "admin"), "IOrderAPI.Get", []string{"order:read", "order:write"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !slices.Equal(gotPerms, []string{"order:read", "order:write"}) {
+		t.Errorf("perms = %v", gotPerms)
+	}
+	if err := a.Authorize(withRole(r, "guest"), "IOrderAPI.Get", nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("guest error = %v", err)
+	}
+}
+
+func withRole(r *http.Request, role string) *http.Request {
+	r = r.Clone(r.Context())
+	r.Header.Set("X-Role", role)
+	return r
+}
+
+// 与生成的 Authorizer / Limiter 接口方法一致
+var (
+	_ interface {
+		Authorize(r *http.Request, handler string, perms []string) error
+	} = AuthorizerFunc(nil)
+	_ interface {
+		Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error
+	} = (*MemoryLimiter)(nil)
+	_ interface {
+		Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error
+	} = LimiterFunc(nil)
+)
+
+func allow(l *MemoryLimiter, r *http.Request, handler string, rate Rate) error {
+	return l.Allow(r, handler, rate.Limit, rate.Per, rate.Key)
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewMemoryLimiter(func(r *http.Request, key string) string {
+		if key == "user" {
+			return r.Header.Get("X-User")
+		}
+		return ""
+	})
+	l.now = func() time.Time { return now }
+
+	rate := Rate{Limit: 2, Per: time.Second, Key: "user"}
+	alice := httptest.NewRequest("GET", "/", nil)
+	alice.Header.Set("X-User", "alice")
+	bob := httptest.NewRequest("GET", "/", nil)
+	bob.Header.Set("X-User", "bob")
+
+	for i := range 2 {
+		if err := allow(l, alice, "IOrderAPI.Get", rate); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if err := allow(l, alice, "IOrderAPI.Get", rate); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("third request error = %v", err)
+	}
+	if err := allow(l, bob, "IOrderAPI.Get", rate); err != nil {
+		t.Errorf("other key limited: %v", err)
+	}
+	if err := allow(l, alice, "IOrderAPI.List", rate); err != nil {
+		t.Errorf("other handler limited: %v", err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if err := allow(l, alice, "IOrderAPI.Get", rate); err != nil {
+		t.Errorf("token not refilled: %v", err)
+	}
+	if err := allow(l, alice, "IOrderAPI.Get", rate); err == nil {
+		t.Error("expected limit after refill")
+	}
+
+	// 无法解析 user 的请求按客户端地址计数，不共用一个全局桶
+	anon1 := httptest.NewRequest("GET", "/", nil)
+	anon1.RemoteAddr = "10.0.0.1:1234"
+	anon2 := httptest.NewRequest("GET", "/", nil)
+	anon2.RemoteAddr = "10.0.0.2:1234"
+	for i := range 2 {
+		if err := allow(l, anon1, "IOrderAPI.Get", rate); err != nil {
+			t.Fatalf("anonymous request %d: %v", i, err)
+		}
+	}
+	if err := allow(l, anon1, "IOrderAPI.Get", rate); err == nil {
+		t.Error("expected anonymous client to be limited")
+	}
+	if err := allow(l, anon2, "IOrderAPI.Get", rate); err != nil {
+		t.Errorf("other anonymous client limited: %v", err)
+	}
+	nilKey := NewMemoryLimiter(nil)
+	nilKey.now = l.now
+	for i := range 2 {
+		if err := allow(nilKey, anon1, "IOrderAPI.Get", rate); err != nil {
+			t.Fatalf("nil keyFunc request %d: %v", i, err)
+		}
+	}
+	if err := allow(nilKey, anon2, "IOrderAPI.Get", rate); err != nil {
+		t.Errorf("nil keyFunc shares a bucket across clients: %v", err)
+	}
+
+}
@@ -0,0 +1,171 @@
+package swaggen
+
+import (
+	"encoding/json"
+	"go/format"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateGuardCode(t *testing.T, name string) (string, *plugin.GenerateResult) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/guard/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, name)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")])
+	if code != "" {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+		}
+	}
+	return code, result
+}
+
+func TestGuardHandler(t *testing.T) {
+	code, result := generateGuardCode(t, "IOrderAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		`"errors"`,
+		`"time"`,
+		// 构造函数按需接收 Authorizer / Limiter
+		"func NewOrderAPIWrap(inner IOrderAPI, handler IOrderAPIHandler, authorizer OrderAPIAuthorizer, limiter OrderAPILimiter) *OrderAPIWrap {",
+		// 接口按包装结构体生成，只使用标准库类型
+		"type OrderAPIAuthorizer interface {\n    Authorize(r *http.Request, handler string, perms []string) error\n}",
+		"type OrderAPILimiter interface {\n    Allow(r *http.Request, handler string, limit int, per time.Duration, key string) error\n}",
+		"func (a *OrderAPIWrap) onDenied(ctx *gin.Context, status int, err error) {\n    status, body := a.deniedResponse(status, err)\n    ctx.AbortWithStatusJSON(status, body)",
+		// 接口级规则；检查先于参数绑定
+		`if status, err := a.authorize(ctx.Request, "IOrderAPI.GetOrder", "order:read"); err != nil {
+            a.onDenied(ctx, status, err)
+            return
+        }
+        if status, err := a.allow(ctx.Request, "IOrderAPI.GetOrder", 100, time.Minute, "ip"); err != nil {
+            a.onDenied(ctx, status, err)
+            return
+        }
+        id := cast.ToInt64(ctx.Param("id"))`,
+		// 方法级规则覆盖接口级，多条限流规则逐条检查
+		`a.authorize(ctx.Request, "IOrderAPI.CreateOrder", "order:write", "order:read")`,
+		`a.allow(ctx.Request, "IOrderAPI.CreateOrder", 10, time.Second, "user")`,
+		`a.allow(ctx.Request, "IOrderAPI.CreateOrder", 1000, time.Hour, "user")`,
+		// 路由表与权限目录
+		`{Method: "POST", Path: "/api/orders", Handler: "IOrderAPI.CreateOrder", Permissions: []string{"order:write", "order:read"}},`,
+		`{Method: "GET", Path: "/api/orders/health", Handler: "IOrderAPI.Health"},`,
+		`{Name: "order:read", Routes: []string{"GET /api/orders/{id}", "POST /api/orders"}},`,
+		`{Name: "order:write", Routes: []string{"POST /api/orders"}},`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+	// exclude=Health 的方法不做检查；方法级 @RATELIMIT 覆盖接口级，按 ip 的规则只作用于 GetOrder
+	if strings.Contains(code, "gogen/swaggen/guard") {
+		t.Errorf("generated code should not import the guard package\n%s", code)
+	}
+	if strings.Contains(code, `"IOrderAPI.Health", `) {
+		t.Errorf("excluded method should not be guarded\n%s", code)
+	}
+	if n := strings.Count(code, `, "ip")`); n != 1 {
+		t.Errorf("interface-level @RATELIMIT applied %d times, want 1\n%s", n, code)
+	}
+}
+
+func TestGuardBackends(t *testing.T) {
+	code, result := generateGuardCode(t, "IAdminAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	for _, want := range []string{
+		"func NewAdminAPIWrap(inner IAdminAPI, handler IAdminAPIHandler, authorizer AdminAPIAuthorizer) *AdminAPIWrap {",
+		`"encoding/json"`,
+		"func (a *AdminAPIWrap) onDenied(w http.ResponseWriter, status int, err error) {",
+		`if status, err := a.authorize(r, "IAdminAPI.Purge", "admin"); err != nil {
+            a.onDenied(w, status, err)
+            return
+        }`,
+		"type AdminAPIPermission struct {",
+		"func (a *AdminAPIWrap) Permissions() []AdminAPIPermission {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+	// include=Purge 仅作用于 Purge；未声明 @RATELIMIT 时不需要 Limiter
+	for _, unwanted := range []string{`"IAdminAPI.Status", "admin"`, "AdminAPILimiter", "a.allow(", `"time"`} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("unexpected %q\n%s", unwanted, code)
+		}
+	}
+
+	code, result = generateGuardCode(t, "IEchoAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	for _, want := range []string{
+		"func NewEchoAPIWrap(inner IEchoAPI, handler IEchoAPIHandler, limiter EchoAPILimiter) *EchoAPIWrap {",
+		`if status, err := a.allow(c.Request(), "IEchoAPI.Echo", 5, 10 * time.Second, ""); err != nil {
+            return a.onDenied(c, status, err)
+        }`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+	// 没有 @PERM 时不生成权限目录
+	if strings.Contains(code, "Permissions()") {
+		t.Errorf("unexpected permission catalogue\n%s", code)
+	}
+}
+
+func TestGuardInvalidRate(t *testing.T) {
+	_, result := generateGuardCode(t, "IInvalidRateAPI")
+	if !result.HasErrors() || !strings.Contains(result.Errors[0].Error(), `无效的频率 "10/day"`) {
+		t.Fatalf("expected invalid rate error, got %v", result.Errors)
+	}
+}
+
+func TestGuardOpenAPIExtensions(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/guard/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IOrderAPI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := BuildOpenAPI(NewTypeLoader(), []SwaggerInterface{*iface}, OpenAPIInfo{Title: "Order API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := doc.Paths["/api/orders"]["post"]
+	if !slices.Equal(op.Permissions, []string{"order:write", "order:read"}) {
+		t.Errorf("x-permissions = %v", op.Permissions)
+	}
+	if want := []RateLimitExtension{{Rate: "10/s", Key: "user"}, {Rate: "1000/h", Key: "user"}}; !slices.Equal(op.RateLimits, want) {
+		t.Errorf("x-ratelimit = %v, want %v", op.RateLimits, want)
+	}
+	if health := doc.Paths["/api/orders/health"]["get"]; len(health.Permissions) > 0 || len(health.RateLimits) > 0 {
+		t.Errorf("excluded operation has extensions: %v %v", health.Permissions, health.RateLimits)
+	}
+
+	data, err := json.Marshal(doc.Paths["/api/orders/{id}"]["get"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"x-permissions":["order:read"],"x-ratelimit":[{"rate":"100/m","key":"ip"}]`; !strings.Contains(string(data), want) {
+		t.Errorf("missing %s in %s", want, data)
+	}
+}
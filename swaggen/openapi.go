@@ -0,0 +1,488 @@
+package swaggen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	parsers "github.com/donutnomad/gogen/swaggen/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// OpenAPI 文档结构
+// ============================================================================
+
+// OpenAPIDoc OpenAPI 3.1 文档
+type OpenAPIDoc struct {
+	OpenAPI    string                           `json:"openapi" yaml:"openapi"`
+	Info       OpenAPIInfo                      `json:"info" yaml:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths" yaml:"paths"`
+	Components *OpenAPIComponents               `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// OpenAPIInfo 文档信息
+type OpenAPIInfo struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// OpenAPIComponents 可复用组件
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// Operation 单个路由的操作
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string                `json:"operationId" yaml:"operationId"`
+	Parameters  []*OpenAPIParameter   `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses" yaml:"responses"`
+	Security    []map[string][]string `json:"security,omitempty" yaml:"security,omitempty"`
+	Permissions []string              `json:"x-permissions,omitempty" yaml:"x-permissions,omitempty"` // @PERM 声明的权限
+	RateLimits  []RateLimitExtension  `json:"x-ratelimit,omitempty" yaml:"x-ratelimit,omitempty"`     // @RATELIMIT 声明的限流规则
+}
+
+// RateLimitExtension x-ratelimit 扩展中的一条限流规则
+type RateLimitExtension struct {
+	Rate string `json:"rate" yaml:"rate"`                   // 频率，如 10/s
+	Key  string `json:"key,omitempty" yaml:"key,omitempty"` // 计数维度，如 user
+}
+
+// OpenAPIParameter path / query / header 参数
+type OpenAPIParameter struct {
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                     `json:"description" yaml:"description"`
+	Headers     map[string]*ResponseHeader `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     map[string]*MediaType      `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// ResponseHeader 响应头
+type ResponseHeader struct {
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+}
+
+// MediaType 某一 MIME 类型的内容
+type MediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type   string `json:"type" yaml:"type"`
+	Scheme string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	In     string `json:"in,omitempty" yaml:"in,omitempty"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Render 按文件扩展名输出 JSON（.json）或 YAML（.yaml / .yml）
+func (d *OpenAPIDoc) Render(path string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ".yaml", ".yml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(d); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("不支持的 OpenAPI 文件格式: %s（应为 .json / .yaml / .yml）", path)
+}
+
+// ============================================================================
+// 文档构建
+// ============================================================================
+
+// mimeAliases @MIME / @MIME-REQ 别名对应的 MIME 类型
+var mimeAliases = map[string]string{
+	"json":                  "application/json",
+	"xml":                   "text/xml",
+	"plain":                 "text/plain",
+	"html":                  "text/html",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"json-api":              "application/vnd.api+json",
+	"json-stream":           "application/x-json-stream",
+	"octet-stream":          "application/octet-stream",
+	"png":                   "image/png",
+	"jpeg":                  "image/jpeg",
+	"gif":                   "image/gif",
+	"event-stream":          "text/event-stream",
+}
+
+// mimeType 将别名转换为 MIME 类型，已是完整 MIME 类型时原样返回
+func mimeType(alias string) string {
+	if v, ok := mimeAliases[alias]; ok {
+		return v
+	}
+	return alias
+}
+
+// OpenAPIBuilder 从解析出的接口构建 OpenAPI 文档，请求/响应的 Schema 由 Go 类型推导
+type OpenAPIBuilder struct {
+	loader  *TypeLoader
+	schemas *SchemaBuilder
+	doc     *OpenAPIDoc
+	opIDs   map[string]int
+}
+
+// BuildOpenAPI 构建包含 ifaces 中所有未移除方法的 OpenAPI 文档
+func BuildOpenAPI(loader *TypeLoader, ifaces []SwaggerInterface, info OpenAPIInfo) (*OpenAPIDoc, error) {
+	for _, iface := range ifaces {
+		if _, err := loader.Load(iface.FilePath); err != nil {
+			return nil, err
+		}
+	}
+	b := &OpenAPIBuilder{
+		loader:  loader,
+		schemas: NewSchemaBuilder(loader.fieldDocs()),
+		doc: &OpenAPIDoc{
+			OpenAPI: "3.1.0",
+			Info:    info,
+			Paths:   make(map[string]map[string]*Operation),
+		},
+		opIDs: make(map[string]int),
+	}
+
+	// 方法名在多个接口中重复时，operationId 带上接口名
+	for _, iface := range ifaces {
+		for _, method := range iface.Methods {
+			if !method.Def.IsRemoved() {
+				b.opIDs[method.Name]++
+			}
+		}
+	}
+
+	components := &OpenAPIComponents{SecuritySchemes: make(map[string]*SecurityScheme)}
+	for _, iface := range ifaces {
+		for _, v := range CollectDef[*parsers.Security](iface.CommonDef) {
+			components.SecuritySchemes[v.Value] = securityScheme(v)
+		}
+		for _, method := range iface.Methods {
+			if method.Def.IsRemoved() {
+				continue
+			}
+			for _, v := range CollectDef[*parsers.Security](method.Def) {
+				components.SecuritySchemes[v.Value] = securityScheme(v)
+			}
+			if err := b.addMethod(iface, method); err != nil {
+				return nil, err
+			}
+		}
+	}
+	components.Schemas = b.schemas.Components
+	if len(components.Schemas) > 0 || len(components.SecuritySchemes) > 0 {
+		b.doc.Components = components
+	}
+	return b.doc, nil
+}
+
+// addMethod 为方法的每个路由添加操作
+func (b *OpenAPIBuilder) addMethod(iface SwaggerInterface, method SwaggerMethod) error {
+	sig, err := b.loader.Signature(iface, method.Name)
+	if err != nil {
+		return err
+	}
+	if sig.Params().Len() != len(method.Parameters) {
+		return fmt.Errorf("%s.%s: 参数数量与类型信息不一致", iface.Name, method.Name)
+	}
+
+	op := &Operation{
+		Summary:     method.Summary,
+		Description: method.Description,
+		OperationID: method.Name,
+		Responses:   b.responses(iface, method, sig),
+	}
+	if b.opIDs[method.Name] > 1 {
+		op.OperationID = iface.Name + "." + method.Name
+	}
+
+	// @ERRORS 声明的错误按 HTTP 状态码列为错误响应
+	codes, err := b.loader.ErrorCodes(iface, method)
+	if err != nil {
+		return err
+	}
+	if len(codes) > 0 {
+		b.schemas.Components[errorComponent] = errorResponseSchema()
+		for status, resp := range errorResponses(codes) {
+			op.Responses[status] = resp
+		}
+	}
+
+	// 声明了校验规则的方法增加 400 校验失败响应
+	fieldRules, err := b.loader.FieldRules(iface, method)
+	if err != nil {
+		return err
+	}
+	method.FieldRules = fieldRules
+	if methodHasValidation(method) {
+		b.schemas.Components[validationComponent] = validationErrorSchema()
+		resp := op.Responses["400"]
+		if resp == nil {
+			resp = &Response{Description: "参数校验失败"}
+			op.Responses["400"] = resp
+		} else {
+			resp.Description += "\n参数校验失败"
+		}
+		resp.Content = map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/" + validationComponent}},
+		}
+	}
+	if op.Summary == "" {
+		op.Summary = method.Name
+	}
+
+	mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
+		v, ok := item.(*parsers.Tag)
+		if !ok {
+			return "", false
+		}
+		return v.Value, true
+	}, func(i []string) {
+		op.Tags = i
+	})
+
+	mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
+		v, ok := item.(*parsers.Security)
+		if !ok {
+			return "", false
+		}
+		return v.Value, securityApplies(v, method.Name)
+	}, func(i []string) {
+		for _, name := range i {
+			op.Security = append(op.Security, map[string][]string{name: {}})
+		}
+	})
+
+	op.Permissions = methodPerms(iface, method)
+	rates, err := methodRates(iface, method)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", iface.Name, method.Name, err)
+	}
+	op.RateLimits = rateLimitExtensions(rates)
+
+	b.addParameters(op, iface, method, sig)
+
+	httpMethod := strings.ToLower(method.GetHTTPMethod())
+	prefix := iface.CommonDef.GetPrefix()
+	for i, pathRouter := range method.GetPaths() {
+		fullPath := fullRoutePath(prefix, pathRouter)
+		current := op
+		if i > 0 {
+			copied := *op
+			copied.OperationID = op.OperationID + strconv.Itoa(i+1)
+			current = &copied
+		}
+		if b.doc.Paths[fullPath] == nil {
+			b.doc.Paths[fullPath] = make(map[string]*Operation)
+		}
+		if _, ok := b.doc.Paths[fullPath][httpMethod]; ok {
+			return fmt.Errorf("%s.%s: 路由 %s %s 重复定义", iface.Name, method.Name, method.GetHTTPMethod(), fullPath)
+		}
+		b.doc.Paths[fullPath][httpMethod] = current
+	}
+	return nil
+}
+
+// addParameters 处理方法参数：path / header / query 参数，最后一个非 @FILE 参数作为 GET 的查询参数或其他方法的请求体
+// 参数注解声明的校验规则写入参数 Schema 的 minimum / maxLength / pattern / enum 等约束
+func (b *OpenAPIBuilder) addParameters(op *Operation, iface SwaggerInterface, method SwaggerMethod, sig *types.Signature) {
+	for i, param := range method.Parameters {
+		if param.Type.FullName == GinContextType || param.Type.TypeName == "Context" {
+			continue
+		}
+		typ := sig.Params().At(i).Type()
+
+		switch {
+		case param.Source == "path":
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{
+				Name:        param.ExternalName(),
+				In:          "path",
+				Description: param.Comment,
+				Required:    true,
+				Schema:      applyRules(b.schemas.Schema(typ), param.Rules),
+			})
+		case param.Source == "header" || param.Source == "query":
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{
+				Name:        param.ExternalName(),
+				In:          param.Source,
+				Description: param.Comment,
+				Required:    param.Required,
+				Schema:      applyRules(b.schemas.Schema(typ), param.Rules),
+			})
+		case param.Source == ParamSourceFile:
+		case i != method.BodyIndex():
+		case method.GetHTTPMethod() == "GET":
+			if _, ok := derefType(typ).Underlying().(*types.Struct); !ok {
+				op.Parameters = append(op.Parameters, &OpenAPIParameter{
+					Name:        param.Name,
+					In:          "query",
+					Description: param.Comment,
+					Required:    param.Required,
+					Schema:      b.schemas.Schema(typ),
+				})
+				continue
+			}
+			names, schemas, required := b.schemas.QueryFields(typ)
+			for j, name := range names {
+				op.Parameters = append(op.Parameters, &OpenAPIParameter{
+					Name:        name,
+					In:          "query",
+					Description: schemas[j].Description,
+					Required:    required[j],
+					Schema:      schemas[j],
+				})
+			}
+		default:
+			accept := acceptType(iface, method)
+			schema := b.schemas.FormSchema(typ)
+			if accept == "json" {
+				schema = b.schemas.Schema(typ)
+			}
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{mimeType(accept): {Schema: schema}},
+			}
+		}
+	}
+
+	b.addFileFields(op, method, sig)
+
+	var headers []*parsers.Header
+	for _, v := range CollectDef[*parsers.Header](iface.CommonDef, method.Def) {
+		idx := slices.IndexFunc(headers, func(h *parsers.Header) bool { return h.Value == v.Value })
+		if idx >= 0 {
+			headers[idx] = v
+		} else {
+			headers = append(headers, v)
+		}
+	}
+	for _, v := range headers {
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name:        v.Value,
+			In:          "header",
+			Description: v.Description,
+			Required:    v.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+}
+
+// addFileFields 将 @FILE 参数作为二进制字段并入 multipart/form-data 请求体
+func (b *OpenAPIBuilder) addFileFields(op *Operation, method SwaggerMethod, sig *types.Signature) {
+	if !method.hasFileParams() {
+		return
+	}
+	contentType := mimeType("mpfd")
+	if op.RequestBody == nil {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{contentType: {Schema: &Schema{Type: "object"}}}}
+	}
+	body := op.RequestBody.Content[contentType]
+	for i, param := range method.Parameters {
+		if param.Source != ParamSourceFile {
+			continue
+		}
+		schema := b.schemas.Schema(sig.Params().At(i).Type())
+		if param.Comment != "" {
+			copied := *schema
+			copied.Description = param.Comment
+			schema = &copied
+		}
+		body.Schema.setProperty(param.ExternalName(), schema, param.Required)
+	}
+}
+
+// responses 成功响应：第一个非 error 返回值作为响应体；SSE 的 Schema 为单条事件的数据，文件下载为二进制内容
+func (b *OpenAPIBuilder) responses(iface SwaggerInterface, method SwaggerMethod, sig *types.Signature) map[string]*Response {
+	resp := &Response{Description: "OK"}
+	results := sig.Results()
+	contentType := mimeType(produceType(iface, method))
+	switch {
+	case method.Response.IsSSE():
+		resp.Description = "Server-Sent Events，每条事件的 data 为以下 Schema 的 JSON（字符串原样输出）"
+		resp.Content = map[string]*MediaType{
+			contentType: {Schema: b.schemas.Schema(streamElemType(results.At(0).Type()))},
+		}
+	case method.Response == ResponseDownload:
+		resp.Description = "文件下载"
+		resp.Headers = map[string]*ResponseHeader{
+			"Content-Disposition": {Description: "attachment; filename=...", Schema: &Schema{Type: "string"}},
+		}
+		schema := binarySchema()
+		schema.ContentMediaType = contentType
+		resp.Content = map[string]*MediaType{contentType: {Schema: schema}}
+	case results.Len() > 0 && !isErrorType(results.At(0).Type()):
+		resp.Content = map[string]*MediaType{
+			contentType: {Schema: b.schemas.Schema(results.At(0).Type())},
+		}
+	}
+	return map[string]*Response{"200": resp}
+}
+
+// securityScheme 由 @SECURITY 推导认证方式：未指定 type 时按名称推断
+func securityScheme(v *parsers.Security) *SecurityScheme {
+	scheme := &SecurityScheme{Type: v.Type, Scheme: v.Scheme, In: v.In, Name: v.Param}
+	if scheme.Type == "" {
+		switch name := strings.ToLower(v.Value); {
+		case scheme.Scheme != "" || strings.Contains(name, "bearer"):
+			scheme.Type = "http"
+		case strings.Contains(name, "basic"):
+			scheme.Type, scheme.Scheme = "http", "basic"
+		default:
+			scheme.Type = "apiKey"
+		}
+	}
+	switch scheme.Type {
+	case "http":
+		if scheme.Scheme == "" {
+			scheme.Scheme = "bearer"
+		}
+		scheme.In, scheme.Name = "", ""
+	case "apiKey":
+		if scheme.In == "" {
+			scheme.In = "header"
+		}
+		if scheme.Name == "" {
+			scheme.Name = "Authorization"
+		}
+	}
+	return scheme
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,299 @@
+package swaggen
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+// interfaceTarget 从源文件构造接口目标
+func interfaceTarget(t *testing.T, filePath, name string) *plugin.AnnotatedTarget {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == name {
+				return &plugin.AnnotatedTarget{
+					Target: &plugin.Target{
+						Kind:        plugin.TargetInterface,
+						Name:        name,
+						PackageName: file.Name.Name,
+						FilePath:    filePath,
+						Node:        typeSpec,
+					},
+					Annotations: []*plugin.Annotation{{Name: "GET"}},
+				}
+			}
+		}
+	}
+	t.Fatalf("interface %s not found in %s", name, filePath)
+	return nil
+}
+
+func buildTestOpenAPI(t *testing.T) *OpenAPIDoc {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/openapi/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IUserAPI"))
+	if err != nil {
+		t.Fatalf("parseInterface failed: %v", err)
+	}
+	doc, err := BuildOpenAPI(NewTypeLoader(), []SwaggerInterface{*iface}, OpenAPIInfo{Title: "User API", Version: "2.0.0"})
+	if err != nil {
+		t.Fatalf("BuildOpenAPI failed: %v", err)
+	}
+	return doc
+}
+
+func findParam(op *Operation, in, name string) *OpenAPIParameter {
+	for _, p := range op.Parameters {
+		if p.In == in && p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestBuildOpenAPIComponents(t *testing.T) {
+	doc := buildTestOpenAPI(t)
+	schemas := doc.Components.Schemas
+
+	resp, ok := schemas["openapi.BaseResponse-openapi_UserInfo"]
+	if !ok {
+		t.Fatalf("generic component missing, got %v", keys(schemas))
+	}
+	if got := resp.Properties["data"].Ref; got != "#/components/schemas/openapi.UserInfo" {
+		t.Errorf("data ref = %q", got)
+	}
+	list := schemas["openapi.BaseResponse-array_openapi_UserInfo"]
+	if list == nil || list.Properties["data"].Type != "array" || list.Properties["data"].Items.Ref == "" {
+		t.Errorf("slice generic component mismatch: %+v", list)
+	}
+
+	user := schemas["openapi.UserInfo"]
+	wantOrder := []string{"created_at", "updated_at", "id", "name", "status", "tags"}
+	if !slices.Equal(user.PropertyNames(), wantOrder) {
+		t.Errorf("UserInfo properties = %v, want %v", user.PropertyNames(), wantOrder)
+	}
+	if user.Properties["created_at"].Format != "date-time" {
+		t.Errorf("embedded time field not flattened: %+v", user.Properties["created_at"])
+	}
+	if user.Properties["updated_at"].Description != "最后更新时间" {
+		t.Errorf("field doc missing: %+v", user.Properties["updated_at"])
+	}
+	if user.Properties["id"].Type != "string" {
+		t.Errorf(",string option ignored: %+v", user.Properties["id"])
+	}
+	if user.Properties["name"].Description != "用户名" {
+		t.Errorf("line comment missing: %+v", user.Properties["name"])
+	}
+	if got := user.Properties["status"].Enum; len(got) != 2 || got[0] != "active" || got[1] != "disabled" {
+		t.Errorf("enum = %v", got)
+	}
+
+	create := schemas["openapi.CreateUserReq"]
+	if !slices.Equal(create.Required, []string{"name"}) {
+		t.Errorf("required = %v", create.Required)
+	}
+}
+
+// checkType 类型检查源码，返回包中的具名类型
+func checkType(t *testing.T, src, name string) types.Type {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "types.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("example", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg.Scope().Lookup(name).Type()
+}
+
+// fieldPrecedenceSrc 嵌入结构体在外层同名字段之前声明
+const fieldPrecedenceSrc = `package example
+
+type Base struct {
+	ID   string ` + "`json:\"id\" form:\"id\" binding:\"required,min=3\"`" + `
+	Note string
+}
+
+type Left struct {
+	Dup  int
+	Kept int ` + "`json:\"Tagged\"`" + `
+}
+
+type Right struct {
+	Dup    string
+	Tagged string
+}
+
+type Req struct {
+	Base
+	Left
+	*Right
+	ID int64 ` + "`json:\"id\" form:\"id\" binding:\"min=1\"`" + `
+}
+`
+
+func TestSchemaFieldPrecedence(t *testing.T) {
+	b := NewSchemaBuilder(nil)
+	b.Schema(checkType(t, fieldPrecedenceSrc, "Req"))
+	req := b.Components["example.Req"]
+
+	// 外层字段覆盖嵌入字段；同层级同名字段中带标签的生效，无法区分的被忽略
+	if want := []string{"Note", "Tagged", "id"}; !slices.Equal(req.PropertyNames(), want) {
+		t.Errorf("properties = %v, want %v", req.PropertyNames(), want)
+	}
+	if got := req.Properties["id"]; got.Type != "integer" || got.MinLength != nil {
+		t.Errorf("id = %+v, want outer int64 field", got)
+	}
+	if got := req.Properties["Tagged"]; got.Type != "integer" {
+		t.Errorf("Tagged = %+v, want Left.Kept", got)
+	}
+	if len(req.Required) > 0 {
+		t.Errorf("required = %v, shadowed field rules leaked", req.Required)
+	}
+}
+
+func TestBuildOpenAPIOperations(t *testing.T) {
+	doc := buildTestOpenAPI(t)
+
+	get := doc.Paths["/api/v1/users/{id}"]["get"]
+	if get == nil {
+		t.Fatalf("paths = %v", keys(doc.Paths))
+	}
+	if p := findParam(get, "path", "id"); p == nil || !p.Required || p.Schema.Type != "integer" {
+		t.Errorf("path param mismatch: %+v", p)
+	}
+	if p := findParam(get, "header", "X-Tenant"); p == nil || !p.Required || p.Description != "租户" {
+		t.Errorf("header param mismatch: %+v", p)
+	}
+	if !slices.Equal(get.Tags, []string{"用户管理"}) {
+		t.Errorf("tags = %v", get.Tags)
+	}
+	if len(get.Security) != 1 || get.Security[0]["Bearer"] == nil {
+		t.Errorf("security = %v", get.Security)
+	}
+	if got := get.Responses["200"].Content["application/json"].Schema.Ref; got != "#/components/schemas/openapi.BaseResponse-openapi_UserInfo" {
+		t.Errorf("response ref = %q", got)
+	}
+
+	list := doc.Paths["/api/v1/users"]["get"]
+	if p := findParam(list, "query", "page"); p == nil || !p.Required {
+		t.Errorf("query param page mismatch: %+v", p)
+	}
+	if p := findParam(list, "query", "keyword"); p == nil || p.Required {
+		t.Errorf("query param keyword mismatch: %+v", p)
+	}
+	if len(list.Security) != 0 {
+		t.Errorf("excluded method has security: %v", list.Security)
+	}
+
+	create := doc.Paths["/api/v1/users"]["post"]
+	if got := create.RequestBody.Content["application/json"].Schema.Ref; got != "#/components/schemas/openapi.CreateUserReq" {
+		t.Errorf("json body ref = %q", got)
+	}
+
+	upload := doc.Paths["/api/v1/users/{id}/upload"]["post"]
+	form := upload.RequestBody.Content["application/x-www-form-urlencoded"]
+	if form == nil || !slices.Equal(form.Schema.PropertyNames(), []string{"title"}) || !slices.Equal(form.Schema.Required, []string{"title"}) {
+		t.Errorf("form body mismatch: %+v", upload.RequestBody)
+	}
+	if upload.Responses["200"].Content != nil {
+		t.Errorf("error-only method should have no response body")
+	}
+	if len(upload.Security) != 1 || upload.Security[0]["ApiKey"] == nil {
+		t.Errorf("method security should override interface security: %v", upload.Security)
+	}
+
+	schemes := doc.Components.SecuritySchemes
+	if s := schemes["Bearer"]; s.Type != "http" || s.Scheme != "bearer" {
+		t.Errorf("Bearer scheme = %+v", s)
+	}
+	if s := schemes["ApiKey"]; s.Type != "apiKey" || s.In != "header" || s.Name != "X-API-Key" {
+		t.Errorf("ApiKey scheme = %+v", s)
+	}
+}
+
+func TestOpenAPIRender(t *testing.T) {
+	doc := buildTestOpenAPI(t)
+
+	data, err := doc.Render("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if decoded["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v", decoded["openapi"])
+	}
+
+	data, err = doc.Render("openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"openapi: 3.1.0", "title: User API", "$ref: '#/components/schemas/openapi.UserInfo'"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("yaml missing %q", want)
+		}
+	}
+
+	if _, err := doc.Render("openapi.txt"); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
+
+func TestGenerateOpenAPIOutput(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/openapi/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{
+		Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, "IUserAPI")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	data, ok := result.FileOutputs[filepath.Join(filepath.Dir(filePath), "openapi.yaml")]
+	if !ok {
+		t.Fatalf("openapi.yaml not generated, outputs: %v", keys(result.FileOutputs))
+	}
+	if !strings.Contains(string(data), "version: 2.0.0") {
+		t.Errorf("info version missing:\n%s", data)
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	slices.Sort(out)
+	return out
+}
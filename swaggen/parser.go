@@ -244,27 +244,56 @@ func unregisteredTagName(err error) (string, bool) {
 }
 
 // ParseParameterAnnotations 解析参数注释
-func (p *AnnotationParser) ParseParameterAnnotations(paramName string, tag string) Parameter {
+// @PARAM(别名; 规则) 路径参数、@QUERY(名称; 规则) 查询参数、@HEADER(名称; 规则) 请求头参数，规则见 parseRules；
+// @FILE(字段名; required=false) multipart 上传文件，仅支持 required
+func (p *AnnotationParser) ParseParameterAnnotations(paramName string, tag string) (Parameter, error) {
 	param := Parameter{
 		Name:     paramName,
 		Required: true,
 	}
-	line := tag
+	name, content, hasArgs := strings.Cut(strings.TrimPrefix(tag, "@"), "(")
 
-	switch {
-	case strings.HasPrefix(line, "@PARAM"):
+	switch name {
+	case "PARAM":
 		param.Source = "path"
-		if aliasRegex := regexp.MustCompile(`@PARAM\s*\(([^)]+)\)`); aliasRegex.MatchString(line) {
-			matches := aliasRegex.FindStringSubmatch(line)
-			if len(matches) == 2 {
-				param.Alias = matches[1]
-			}
-		}
-	case strings.HasPrefix(line, "@HEADER"):
+	case "QUERY":
+		param.Source = "query"
+		param.Required = false
+	case "HEADER":
 		param.Source = "header"
+	case "FILE":
+		param.Source = ParamSourceFile
+	default:
+		return param, nil
+	}
+	if !hasArgs {
+		return param, nil
 	}
 
-	return param
+	end := strings.LastIndex(content, ")")
+	if end == -1 {
+		return param, fmt.Errorf("参数 %s 的注解 %s 缺少 ')'", paramName, tag)
+	}
+	value, rules, required, err := parseRules(content[:end])
+	if err != nil {
+		return param, fmt.Errorf("参数 %s 的注解 %s: %w", paramName, tag, err)
+	}
+	param.Alias = value
+	if required != nil {
+		param.Required = *required
+	}
+	if param.Source == ParamSourceFile {
+		if !rules.IsZero() {
+			return param, fmt.Errorf("参数 %s 的注解 %s: @FILE 仅支持 required", paramName, tag)
+		}
+		return param, nil
+	}
+	// 声明了任意规则时才生成校验代码，此时必填性沿用参数的 Required
+	if required != nil || !rules.IsZero() {
+		rules.Required = param.Required
+	}
+	param.Rules = rules
+	return param, nil
 }
 
 // extractPathParameters 从路径中提取参数
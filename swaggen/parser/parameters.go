@@ -43,6 +43,30 @@ func splitTopLevel(input string) []string {
 	return result
 }
 
+// cutAnnotation 从注释内容中切出 @TAG(...) 形式的标签（按括号配对），返回标签与其后的内容
+func cutAnnotation(content string) (tag, rest string, ok bool) {
+	if !strings.HasPrefix(content, "@") {
+		return "", "", false
+	}
+	open := strings.IndexAny(content, "( \t\n")
+	if open == -1 || content[open] != '(' {
+		return "", "", false
+	}
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return content[:i+1], content[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
 // ParseParameters 解析函数参数定义字符串
 // 支持 // TAG name type, /* TAG */ name type, 以及 name, anotherName type 等格式
 // 输入示例: (c *gin.Context, date, filename string)
@@ -85,7 +109,11 @@ func ParseParameters(rawInput string) ([]Parameter, error) {
 		} else if strings.HasPrefix(part, "//") {
 			commentContent := strings.TrimSpace(strings.TrimPrefix(part, "//"))
 			fields := strings.Fields(commentContent)
-			if len(fields) >= 2 { // 至少要有 TAG 和 name/type
+			if tag, rest, ok := cutAnnotation(commentContent); ok {
+				// 带括号的标签内可以有空格，如 // @HEADER(X-Tenant; pattern=^[a-z]+$)
+				currentTag = tag
+				definitionPart = strings.Join(strings.Fields(rest), " ")
+			} else if len(fields) >= 2 { // 至少要有 TAG 和 name/type
 				currentTag = fields[0]
 				definitionPart = strings.Join(fields[1:], " ")
 			} else {
@@ -109,3 +109,26 @@ func TestParseParameters(t *testing.T) {
 		}
 	}
 }
+
+func TestParseParametersAnnotationArgs(t *testing.T) {
+	input := `(
+		ctx context.Context,
+		// @HEADER(X-Tenant; pattern=^[a-z]{2,8}$)
+		tenant string,
+		// @QUERY(expand; enum=a,b)
+		expand string,
+	)`
+	params, err := ParseParameters(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 3 {
+		t.Fatalf("got %d params: %+v", len(params), params)
+	}
+	if params[1].Tag != "@HEADER(X-Tenant; pattern=^[a-z]{2,8}$)" || params[1].FullName() != "tenant string" {
+		t.Errorf("tenant = %+v", params[1])
+	}
+	if params[2].Tag != "@QUERY(expand; enum=a,b)" || params[2].FullName() != "expand string" {
+		t.Errorf("expand = %+v", params[2])
+	}
+}
@@ -20,6 +20,11 @@ const (
 	// 例如: @HEADER(X-Api-Key;true;这是一个描述)
 	// 值会按顺序填充到结构体的字段中
 	ModePositional
+
+	// ModeNamedComma 与 ModeNamed 相同，但内容不含分号时也可用逗号分隔
+	// 例如: @RATELIMIT(10/s, key=user, exclude=A,B)
+	// 逗号后的片段不含 = 时并入前一个参数（列表值）
+	ModeNamedComma
 )
 
 // Definition 是所有可解析标签结构体必须实现的接口
@@ -118,6 +123,8 @@ func (p *Parser) Parse(line string) (any, error) {
 		err = p.fillStructFromNamed(newStructElem, content)
 	case ModePositional:
 		err = p.fillStructFromPositional(newStructElem, content)
+	case ModeNamedComma:
+		err = p.fillStructFromNamed(newStructElem, commaToSemicolon(content))
 	default:
 		err = fmt.Errorf("unknown parsing mode")
 	}
@@ -178,6 +185,22 @@ func (p *Parser) fillStructFromNamed(structElem reflect.Value, content string) e
 	return nil
 }
 
+// commaToSemicolon 将逗号分隔的具名参数转换为分号分隔；内容已含分号时原样返回
+func commaToSemicolon(content string) string {
+	if strings.Contains(content, ";") {
+		return content
+	}
+	var parts []string
+	for _, item := range strings.Split(content, ",") {
+		if len(parts) > 0 && !strings.Contains(item, "=") {
+			parts[len(parts)-1] += "," + item
+			continue
+		}
+		parts = append(parts, item)
+	}
+	return strings.Join(parts, ";")
+}
+
 // fillStructFromPositional 处理位置参数
 func (p *Parser) fillStructFromPositional(structElem reflect.Value, content string) error {
 	parts := strings.Split(content, ";")
@@ -235,7 +258,7 @@ func setFieldFromString(field reflect.Value, fieldType reflect.StructField, valu
 				if delimiter == " " {
 					values = strings.Fields(value)
 				} else {
-					values = strings.Split(value, delimiter)
+					values = splitTrim(value, delimiter)
 				}
 			}
 			field.Set(reflect.ValueOf(values))
@@ -302,7 +325,7 @@ func (p *Parser) fillStruct(structElem reflect.Value, args map[string]string) er
 					if delimiter == " " {
 						values = strings.Fields(argValue)
 					} else {
-						values = strings.Split(argValue, delimiter)
+						values = splitTrim(argValue, delimiter)
 					}
 				}
 				field.Set(reflect.ValueOf(values))
@@ -354,7 +377,22 @@ func parseSgTag(tag string) map[string]string {
 		if len(kv) > 1 {
 			value = kv[1]
 		}
+		// delimiter=, 中的逗号会被上面的 Split 切掉
+		if key == "delimiter" && value == "" {
+			value = ","
+		}
 		result[key] = value
 	}
 	return result
 }
+
+// splitTrim 按分隔符切分并去除各项首尾空白，忽略空项
+func splitTrim(s, sep string) []string {
+	var values []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -11,11 +11,41 @@ type Security struct {
 	Value   string   `sg:"required"`
 	Exclude []string `sg:"delimiter=,"` // 支持,分割和默认支持空格分割
 	Include []string `sg:"delimiter=,"`
+	// OpenAPI securityScheme，均可省略：名称含 bearer/basic 时为 http 认证，否则为 Authorization 请求头中的 apiKey
+	Type   string // http / apiKey / oauth2 / openIdConnect
+	Scheme string // type=http 时的 bearer / basic
+	In     string // type=apiKey 时的 header / query / cookie
+	Param  string // type=apiKey 时的参数名
 }
 
 func (s Security) Name() string    { return "SECURITY" }
 func (s Security) Mode() ParseMode { return ModeNamed }
 
+// Perm 方法需要的权限（接口级别作用于所有方法），exclude / include 与 @SECURITY 相同
+// 例如: @PERM(order:write)、@PERM(order:read, order:export; exclude=Health)
+// 一个注解中的多个权限、以及多个 @PERM 注解须同时满足
+type Perm struct {
+	Value   []string `sg:"required,delimiter=,"`
+	Exclude []string `sg:"delimiter=,"`
+	Include []string `sg:"delimiter=,"`
+}
+
+func (s Perm) Name() string    { return "PERM" }
+func (s Perm) Mode() ParseMode { return ModeNamedComma }
+
+// RateLimit 限流规则（接口级别作用于所有方法），exclude / include 与 @SECURITY 相同
+// 例如: @RATELIMIT(10/s, key=user)、@RATELIMIT(100/m; key=ip; exclude=Health)
+// 速率为 次数/单位，单位为 s / m / h；key 为限流维度，由 Limiter 解释，默认 global
+type RateLimit struct {
+	Value   string `sg:"required"`
+	Key     string
+	Exclude []string `sg:"delimiter=,"`
+	Include []string `sg:"delimiter=,"`
+}
+
+func (s RateLimit) Name() string    { return "RATELIMIT" }
+func (s RateLimit) Mode() ParseMode { return ModeNamedComma }
+
 type Header struct {
 	Value       string `sg:"required"`
 	Required    bool   `sg:"required"`
@@ -25,6 +55,54 @@ type Header struct {
 func (s Header) Name() string    { return "HEADER" }
 func (s Header) Mode() ParseMode { return ModeNamed }
 
+// OpenAPI 输出 OpenAPI 3.1 文档（接口级别）
+// 例如: @OPENAPI(openapi.yaml; title=User API; version=1.0.0)
+// 路径相对于源文件目录，扩展名决定格式（.json / .yaml / .yml），指向同一路径的接口合并为一份文档
+type OpenAPI struct {
+	Value   string `sg:"required"`
+	Title   string
+	Version string
+}
+
+func (s OpenAPI) Name() string    { return "OPENAPI" }
+func (s OpenAPI) Mode() ParseMode { return ModeNamed }
+
+// TS 输出 TypeScript 类型定义与基于 fetch 的客户端（接口级别）
+// 例如: @TS(../web/src/api/user.ts)
+// 路径相对于源文件目录，指向同一路径的接口合并为一个文件
+type TS struct {
+	Value string `sg:"required"`
+}
+
+func (s TS) Name() string    { return "TS" }
+func (s TS) Mode() ParseMode { return ModeNamed }
+
+// Client 生成基于 net/http 的类型化客户端（接口级别）
+// 例如: 在 IUserAPI 上添加 @CLIENT，生成实现 IUserAPI 的 UserAPIClient
+type Client struct{}
+
+func (s Client) Name() string    { return "CLIENT" }
+func (s Client) Mode() ParseMode { return ModeNamed }
+
+// SwagBackend 指定绑定代码面向的 HTTP 框架（接口级别），可选 gin（默认）/ nethttp / chi / echo
+// 例如: @SwagBackend(nethttp)；包级默认值可通过 //go:gogen @SwagBackend(nethttp) 设置
+type SwagBackend struct {
+	Value string `sg:"required"`
+}
+
+func (s SwagBackend) Name() string    { return "SwagBackend" }
+func (s SwagBackend) Mode() ParseMode { return ModeNamed }
+
+// Errors 声明方法可能返回的错误（接口级别作用于所有方法）
+// 例如: @ERRORS(ErrUserNotFound, errs.ErrInvalidInput, github.com/x/errs.ErrForbidden)
+// 引用须为标注了 codegen @Code 的 error 变量；声明后处理器通过 @Code 注册表将错误映射为 HTTP 状态码与统一的错误响应
+type Errors struct {
+	Value []string `sg:"required,delimiter=,"`
+}
+
+func (s Errors) Name() string    { return "ERRORS" }
+func (s Errors) Mode() ParseMode { return ModeNamed }
+
 /////////////////////////////// 响应 /////////////////////////////////////
 
 type JSON struct {
@@ -2,6 +2,7 @@ package parsers
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -50,3 +51,53 @@ func TestTag(t *testing.T) {
 		}
 	}
 }
+
+func TestSecurityListDelimiter(t *testing.T) {
+	parser := NewParser()
+	if err := parser.Register(Security{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.Parse("@SECURITY(ApiKeyAuth; exclude=A, B; include=C)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := result.(*Security)
+	if !slices.Equal(got.Exclude, []string{"A", "B"}) || !slices.Equal(got.Include, []string{"C"}) {
+		t.Fatalf("exclude=%q include=%q", got.Exclude, got.Include)
+	}
+}
+
+func TestNamedCommaMode(t *testing.T) {
+	parser := NewParser()
+	if err := parser.Register(Perm{}, RateLimit{}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := parser.Parse("@RATELIMIT(10/s, key=user, exclude=A,B)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := result.(*RateLimit)
+	if limit.Value != "10/s" || limit.Key != "user" || !slices.Equal(limit.Exclude, []string{"A", "B"}) {
+		t.Fatalf("got %#v", limit)
+	}
+
+	// 含分号时按 ModeNamed 解析，逗号保留在值中
+	result, err = parser.Parse("@PERM(order:read, order:export; include=List)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm := result.(*Perm)
+	if !slices.Equal(perm.Value, []string{"order:read", "order:export"}) || !slices.Equal(perm.Include, []string{"List"}) {
+		t.Fatalf("got %#v", perm)
+	}
+
+	result, err = parser.Parse("@PERM(order:read, order:write, exclude=Health)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm = result.(*Perm)
+	if !slices.Equal(perm.Value, []string{"order:read", "order:write"}) || !slices.Equal(perm.Exclude, []string{"Health"}) {
+		t.Fatalf("got %#v", perm)
+	}
+}
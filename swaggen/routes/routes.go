@@ -0,0 +1,188 @@
+// Package routes 描述 swaggen 解析出的路由表
+//
+// swaggen 生成时以 Route 汇总各接口注册的路由（已拼接 @PREFIX，一个方法声明多个路径时逐条列出），
+// 并以 Conflicts 按各 HTTP 框架的匹配规则检查多组路由能否同时注册。
+// 生成代码不依赖本包：包装结构体的 Routes() 返回生成在输出文件中的同名字段类型（如 UserAPIRoute）。
+package routes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 路由框架，与 @SwagBackend 的取值一致
+const (
+	Gin     = "gin"
+	NetHTTP = "nethttp"
+	Chi     = "chi"
+	Echo    = "echo"
+)
+
+// Route 一条 HTTP 路由
+type Route struct {
+	Method      string   // HTTP 方法，如 GET
+	Path        string   // 完整路径，路径参数写作 {id}
+	Handler     string   // 处理方法，形如 IUserAPI.GetUser
+	Middlewares []string // @MID 声明的中间件
+	Tags        []string // @TAG 声明的标签
+	Permissions []string // @PERM 声明的权限
+}
+
+func (r Route) String() string {
+	return r.Method + " " + r.Path
+}
+
+// Conflict 两条无法同时注册的路由
+type Conflict struct {
+	A, B   Route
+	Reason string
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("%s (%s) 与 %s (%s) 冲突: %s", c.A, c.A.Handler, c.B, c.B.Handler, c.Reason)
+}
+
+// Conflicts 按 router 的匹配规则返回 rs 中无法同时注册的路由对：
+// 所有框架下同一方法的重复路径（参数名不同也视为重复）均为冲突；
+// gin 下同一位置的参数名不同、或通配符 *name 与其他路由重叠时冲突；
+// net/http 下两个模式可匹配同一请求且无法判定哪个更具体时冲突（如 /a/{x}/b 与 /a/c/{y}）
+func Conflicts(router string, rs []Route) []Conflict {
+	var out []Conflict
+	for i := range rs {
+		for j := i + 1; j < len(rs); j++ {
+			if !strings.EqualFold(rs[i].Method, rs[j].Method) {
+				continue
+			}
+			if reason := conflict(router, parse(rs[i].Path), parse(rs[j].Path)); reason != "" {
+				out = append(out, Conflict{A: rs[i], B: rs[j], Reason: reason})
+			}
+		}
+	}
+	return out
+}
+
+type segmentKind int
+
+const (
+	static segmentKind = iota
+	param
+	catchAll
+	exactEnd // net/http 的 {$}，仅匹配以 / 结尾的路径本身
+)
+
+// segment 路径中的一段，参数支持 {id} 与 :id，通配符支持 *path 与 {path...}
+type segment struct {
+	kind segmentKind
+	name string // 静态段为原文，参数与通配符为参数名
+}
+
+func (s segment) String() string {
+	switch s.kind {
+	case param:
+		return "{" + s.name + "}"
+	case catchAll:
+		return "*" + s.name
+	case exactEnd:
+		return "{$}"
+	}
+	return s.name
+}
+
+func parse(path string) []segment {
+	var out []segment
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case part == "{$}":
+			out = append(out, segment{kind: exactEnd})
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "...}"):
+			out = append(out, segment{kind: catchAll, name: part[1 : len(part)-4]})
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			out = append(out, segment{kind: param, name: part[1 : len(part)-1]})
+		case strings.HasPrefix(part, ":"):
+			out = append(out, segment{kind: param, name: part[1:]})
+		case strings.HasPrefix(part, "*"):
+			out = append(out, segment{kind: catchAll, name: part[1:]})
+		default:
+			out = append(out, segment{kind: static, name: part})
+		}
+	}
+	return out
+}
+
+func conflict(router string, a, b []segment) string {
+	if sameShape(a, b) {
+		return "路由重复"
+	}
+	switch router {
+	case Gin:
+		return ginConflict(a, b)
+	case NetHTTP:
+		return muxConflict(a, b)
+	}
+	// chi 与 echo 按 静态 > 参数 > 通配符 的优先级匹配，仅重复注册会静默覆盖
+	return ""
+}
+
+// sameShape 两个路径是否匹配完全相同的请求集合
+func sameShape(a, b []segment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].kind != b[i].kind || (a[i].kind == static && a[i].name != b[i].name) {
+			return false
+		}
+	}
+	return true
+}
+
+// ginConflict gin（>= 1.8）允许静态段与参数段共存，但同一位置的参数必须同名，通配符不能与其他路由共享该位置
+func ginConflict(a, b []segment) string {
+	for i := 0; i < min(len(a), len(b)); i++ {
+		x, y := a[i], b[i]
+		switch {
+		case x.kind == catchAll || y.kind == catchAll:
+			return fmt.Sprintf("gin 中通配符 %s 不能与 %s 共存", x, y)
+		case x.kind == param && y.kind == param:
+			if x.name != y.name {
+				return fmt.Sprintf("gin 中同一位置的路径参数 :%s 与 :%s 名称不同", x.name, y.name)
+			}
+		case x.kind != y.kind || x.name != y.name:
+			return ""
+		}
+	}
+	return ""
+}
+
+// muxConflict net/http ServeMux 要求重叠的两个模式中必须有一个更具体
+func muxConflict(a, b []segment) string {
+	if len(a) != len(b) {
+		return ""
+	}
+	var aMore, bMore bool
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.kind == catchAll || y.kind == catchAll {
+			return ""
+		}
+		switch {
+		case x.kind == exactEnd || y.kind == exactEnd:
+			// {$} 只匹配路径本身，与其他任何段都不重叠
+			if x.kind != y.kind {
+				return ""
+			}
+		case x.kind == static && y.kind == static:
+			if x.name != y.name {
+				return ""
+			}
+		case x.kind == static:
+			aMore = true
+		case y.kind == static:
+			bMore = true
+		}
+	}
+	if aMore && bMore {
+		return "net/http ServeMux 无法判定哪个模式更具体"
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package routes
+
+import "testing"
+
+func TestConflicts(t *testing.T) {
+	tests := []struct {
+		router string
+		a, b   string
+		want   string
+	}{
+		{Gin, "/user/{id}", "/user/me", ""},
+		{Gin, "/user/{id}", "/user/{id}/profile", ""},
+		{Gin, "/user/{id}", "/user/{name}/profile", "gin 中同一位置的路径参数 :id 与 :name 名称不同"},
+		{Gin, "/user/{id}", "/user/:name", "路由重复"},
+		{Gin, "/files/*path", "/files/{id}", "gin 中通配符 *path 不能与 {id} 共存"},
+		{Gin, "/files/*path", "/files/x", "gin 中通配符 *path 不能与 x 共存"},
+		{Gin, "/files/*path", "/files", ""},
+		{Gin, "/a/{x}/b", "/a/c/{y}", ""},
+		{NetHTTP, "/a/{x}/b", "/a/c/{y}", "net/http ServeMux 无法判定哪个模式更具体"},
+		{NetHTTP, "/user/{id}", "/user/me", ""},
+		{NetHTTP, "/user/{id}", "/user/{name}/profile", ""},
+		{NetHTTP, "/", "/{$}", ""},
+		{NetHTTP, "/{$}", "/{$}", "路由重复"},
+		{NetHTTP, "/a/{$}", "/a/{x}", ""},
+		{Chi, "/user/{id}", "/user/{name}", "路由重复"},
+		{Chi, "/user/{id}", "/user/{name}/profile", ""},
+		{Echo, "/user/:id", "/user/{name}", "路由重复"},
+	}
+	for _, tt := range tests {
+		rs := []Route{{Method: "GET", Path: tt.a}, {Method: "get", Path: tt.b}, {Method: "POST", Path: tt.b}}
+		got := Conflicts(tt.router, rs)
+		switch {
+		case tt.want == "" && len(got) != 0:
+			t.Errorf("%s %s vs %s: unexpected conflict %v", tt.router, tt.a, tt.b, got)
+		case tt.want != "" && (len(got) != 1 || got[0].Reason != tt.want):
+			t.Errorf("%s %s vs %s: got %v, want %q", tt.router, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
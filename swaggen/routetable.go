@@ -0,0 +1,172 @@
+package swaggen
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	parsers "github.com/donutnomad/gogen/swaggen/parser"
+	"github.com/donutnomad/gogen/swaggen/routes"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// 路由表：生成 Routes() 并检测跨接口的路由冲突
+// ============================================================================
+
+// fullRoutePath 拼接 @PREFIX 与方法路径，去掉末尾的 /，结果为空时为根路径
+func fullRoutePath(prefix, path string) string {
+	fullPath := strings.TrimRight(prefix+path, "/")
+	if fullPath == "" {
+		return "/"
+	}
+	return fullPath
+}
+
+// interfaceRoutes 返回接口注册的路由（跳过 @Removed 的方法），路径为 Swagger 格式
+func interfaceRoutes(iface SwaggerInterface) []routes.Route {
+	var out []routes.Route
+	prefix := iface.CommonDef.GetPrefix()
+	for _, method := range iface.Methods {
+		if method.Def.IsRemoved() {
+			continue
+		}
+		middlewares := lo.Uniq(lo.FlatMap(CollectDef[*parsers.MiddleWare](iface.CommonDef, method.Def), func(item *parsers.MiddleWare, _ int) []string {
+			return item.Value
+		}))
+		var tags []string
+		mergeDefs[string](iface.CommonDef, method.Def, func(item parsers.Definition) (string, bool) {
+			v, ok := item.(*parsers.Tag)
+			if !ok {
+				return "", false
+			}
+			return v.Value, true
+		}, func(i []string) {
+			tags = i
+		})
+		for _, path := range method.GetPaths() {
+			out = append(out, routes.Route{
+				Method:      method.GetHTTPMethod(),
+				Path:        fullRoutePath(prefix, path),
+				Handler:     iface.Name + "." + method.Name,
+				Middlewares: middlewares,
+				Tags:        tags,
+				Permissions: methodPerms(iface, method),
+			})
+		}
+	}
+	return out
+}
+
+// generateRoutesMethod 生成路由表条目类型与 Routes 方法，列出包装结构体注册的全部路由；
+// 类型按接口命名并生成在输出文件中，生成代码不依赖 gogen 的包
+func generateRoutesMethod(iface SwaggerInterface) string {
+	var items []string
+	for _, r := range interfaceRoutes(iface) {
+		fields := []string{
+			"Method: " + strconv.Quote(r.Method),
+			"Path: " + strconv.Quote(r.Path),
+			"Handler: " + strconv.Quote(r.Handler),
+		}
+		if len(r.Middlewares) > 0 {
+			fields = append(fields, fmt.Sprintf("Middlewares: %#v", r.Middlewares))
+		}
+		if len(r.Tags) > 0 {
+			fields = append(fields, fmt.Sprintf("Tags: %#v", r.Tags))
+		}
+		if len(r.Permissions) > 0 {
+			fields = append(fields, fmt.Sprintf("Permissions: %#v", r.Permissions))
+		}
+		items = append(items, "{"+strings.Join(fields, ", ")+"},")
+	}
+
+	template := `
+// {{.TypeName}} {{.InterfaceName}} 注册的一条路由
+type {{.TypeName}} struct {
+    Method      string   // HTTP 方法，如 GET
+    Path        string   // 完整路径，路径参数写作 {id}
+    Handler     string   // 处理方法，形如 IUserAPI.GetUser
+    Middlewares []string // @MID 声明的中间件
+    Tags        []string // @TAG 声明的标签
+    Permissions []string // @PERM 声明的权限
+}
+
+func (r {{.TypeName}}) String() string {
+    return r.Method + " " + r.Path
+}
+
+// Routes 返回 {{.InterfaceName}} 注册的路由（已拼接 @PREFIX），可用于文档、权限初始化与测试
+func (a *{{.WrapperName}}) Routes() []{{.TypeName}} {
+    return []{{.TypeName}}{
+        {{- range .Items}}
+        {{.}}
+        {{- end}}
+    }
+}
+`
+	data := map[string]any{
+		"InterfaceName": iface.Name,
+		"WrapperName":   iface.GetWrapperName(),
+		"TypeName":      iface.GetRouteName(),
+		"Items":         items,
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// routeOwner 路由所属的接口源文件，用于冲突报错
+type routeOwner struct {
+	route routes.Route
+	file  string
+}
+
+// checkRouteConflicts 汇总本次生成的所有接口的路由，按包与后端分别检测冲突：
+// 同一包中同一后端的接口通常挂载在同一路由器上，不同包（如各服务、v1 / v2 分组）各自注册，互不影响
+func checkRouteConflicts(fileTargets map[string][]*swagTargetInfo) []error {
+	var ifaces []*SwaggerInterface
+	for _, targets := range fileTargets {
+		for _, t := range targets {
+			ifaces = append(ifaces, t.iface)
+		}
+	}
+	slices.SortFunc(ifaces, func(a, b *SwaggerInterface) int {
+		if c := strings.Compare(a.FilePath, b.FilePath); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	type group struct{ dir, backend string }
+	byGroup := make(map[group][]routeOwner)
+	var groups []group
+	for _, iface := range ifaces {
+		key := group{dir: filepath.Dir(iface.FilePath), backend: backendOf(*iface).Name()}
+		if _, ok := byGroup[key]; !ok {
+			groups = append(groups, key)
+		}
+		for _, r := range interfaceRoutes(*iface) {
+			byGroup[key] = append(byGroup[key], routeOwner{route: r, file: iface.FilePath})
+		}
+	}
+
+	var errs []error
+	for _, key := range groups {
+		owners := byGroup[key]
+		files := make(map[string]string, len(owners))
+		for _, o := range owners {
+			files[routeKey(o.route)] = filepath.Base(o.file)
+		}
+		rs := lo.Map(owners, func(o routeOwner, _ int) routes.Route { return o.route })
+		for _, c := range routes.Conflicts(key.backend, rs) {
+			errs = append(errs, fmt.Errorf("路由冲突: %s (%s, %s) 与 %s (%s, %s): %s",
+				c.A, c.A.Handler, files[routeKey(c.A)], c.B, c.B.Handler, files[routeKey(c.B)], c.Reason))
+		}
+	}
+	return errs
+}
+
+func routeKey(r routes.Route) string {
+	return r.Handler + " " + r.Method + " " + r.Path
+}
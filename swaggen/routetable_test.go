@@ -0,0 +1,101 @@
+package swaggen
+
+import (
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateRoutes(t *testing.T, targets map[string][]string) *plugin.GenerateResult {
+	t.Helper()
+	var list []*plugin.AnnotatedTarget
+	for file, names := range targets {
+		filePath, err := filepath.Abs(filepath.Join("testdata/routes", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			list = append(list, interfaceTarget(t, filePath, name))
+		}
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: list})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestRoutesMethod(t *testing.T) {
+	result := generateRoutes(t, map[string][]string{"user.go": {"IUserAPI"}})
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	dir, _ := filepath.Abs("testdata/routes")
+	code := string(result.RawOutputs[filepath.Join(dir, "user_swagger.go")])
+	if _, err := format.Source([]byte(code)); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+	}
+
+	wants := []string{
+		"type UserAPIRoute struct {",
+		"func (r UserAPIRoute) String() string {",
+		"func (a *UserAPIWrap) Routes() []UserAPIRoute {",
+		`{Method: "GET", Path: "/api/user/{id}", Handler: "IUserAPI.GetUser", Middlewares: []string{"Auth", "Audit"}, Tags: []string{"用户"}},`,
+		`{Method: "GET", Path: "/api/member/{id}", Handler: "IUserAPI.GetUser", Middlewares: []string{"Auth", "Audit"}, Tags: []string{"用户"}},`,
+		`{Method: "GET", Path: "/api/user/me", Handler: "IUserAPI.Me", Tags: []string{"用户"}},`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, `Handler: "IUserAPI.DeleteUser"`) {
+		t.Errorf("@Removed method should not be listed\n%s", code)
+	}
+	if strings.Contains(code, "github.com/donutnomad/gogen") {
+		t.Errorf("generated code should not import gogen packages\n%s", code)
+	}
+}
+
+func TestRouteConflictsPerPackage(t *testing.T) {
+	// 不同包的接口各自挂载在自己的路由器上，相同或冲突的路径不报错
+	result := generateRoutes(t, map[string][]string{
+		"user.go":    {"IUserAPI"},
+		"v2/user.go": {"IUserAPI"},
+	})
+	if result.HasErrors() {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestRouteConflicts(t *testing.T) {
+	result := generateRoutes(t, map[string][]string{
+		"user.go":    {"IUserAPI"},
+		"profile.go": {"IProfileAPI", "IMemberAPI", "IFileAPI", "IDocAPI"},
+	})
+
+	var msgs []string
+	for _, err := range result.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	wants := []string{
+		"路由冲突: GET /api/user/{name}/profile (IProfileAPI.GetProfile, profile.go) 与 GET /api/user/{id} (IUserAPI.GetUser, user.go): gin 中同一位置的路径参数 :name 与 :id 名称不同",
+		"路由冲突: GET /api/member/{memberId} (IMemberAPI.GetMember, profile.go) 与 GET /api/member/{id} (IUserAPI.GetUser, user.go): 路由重复",
+		"路由冲突: GET /files/{dir}/raw (IFileAPI.Raw, profile.go) 与 GET /files/latest/{name} (IFileAPI.Latest, profile.go): net/http ServeMux 无法判定哪个模式更具体",
+	}
+	if len(msgs) != len(wants) {
+		t.Fatalf("got %d errors, want %d:\n%s", len(msgs), len(wants), strings.Join(msgs, "\n"))
+	}
+	for _, want := range wants {
+		found := false
+		for _, msg := range msgs {
+			found = found || msg == want
+		}
+		if !found {
+			t.Errorf("missing error %q, got:\n%s", want, strings.Join(msgs, "\n"))
+		}
+	}
+}
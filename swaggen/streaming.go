@@ -0,0 +1,309 @@
+package swaggen
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/donutnomad/gogen/internal/xast"
+)
+
+// ============================================================================
+// 流式响应（SSE）、文件下载与 @FILE 上传
+// ============================================================================
+
+// transferSource transfer 包的源码，改写后生成到使用流式响应与文件传输的包中
+//
+//go:embed transfer/transfer.go
+var transferSource []byte
+
+// transferPrefix 生成到包中的 transfer 标识符前缀
+const transferPrefix = "transfer"
+
+// transferName transfer 包标识符在生成代码中的名称，如 SSE -> transferSSE、startSSE -> transferStartSSE
+func transferName(name string) string {
+	return transferPrefix + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// ResponseMode 处理器输出成功响应的方式
+type ResponseMode int
+
+const (
+	ResponseDefault  ResponseMode = iota // 交给 onXxxResponse 输出
+	ResponseSSE                          // (iter.Seq[T], error)：以 Server-Sent Events 逐条输出
+	ResponseSSEChan                      // (<-chan T, error)：以 Server-Sent Events 逐条输出，通道关闭时结束
+	ResponseDownload                     // (io.Reader / io.ReadCloser, 文件名, error)：以附件下载输出
+)
+
+// IsSSE 是否以 Server-Sent Events 输出
+func (m ResponseMode) IsSSE() bool {
+	return m == ResponseSSE || m == ResponseSSEChan
+}
+
+// parseResponseMode 按返回值推断响应方式；SSE 返回事件元素的类型，下载返回 reader 的类型
+func parseResponseMode(results *ast.FieldList, imports xast.ImportInfoSlice, typeParser *ReturnTypeParser) (ResponseMode, TypeInfo, error) {
+	var list []ast.Expr
+	for _, field := range results.List {
+		for range max(len(field.Names), 1) {
+			list = append(list, field.Type)
+		}
+	}
+	if len(list) == 0 {
+		return ResponseDefault, TypeInfo{}, nil
+	}
+	isIdent := func(expr ast.Expr, name string) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == name
+	}
+	endsWithError := isIdent(list[len(list)-1], "error")
+
+	first := list[0]
+	if index, ok := first.(*ast.IndexExpr); ok && isPackageType(index.X, imports, "iter", "Seq") {
+		if len(list) != 2 || !endsWithError {
+			return 0, TypeInfo{}, fmt.Errorf("以 SSE 输出时须返回 (iter.Seq[T], error)")
+		}
+		return ResponseSSE, typeParser.ParseReturnType(index.Index), nil
+	}
+	if ch, ok := first.(*ast.ChanType); ok && ch.Dir == ast.RECV {
+		if len(list) != 2 || !endsWithError {
+			return 0, TypeInfo{}, fmt.Errorf("以 SSE 输出时须返回 (<-chan T, error)")
+		}
+		return ResponseSSEChan, typeParser.ParseReturnType(ch.Value), nil
+	}
+	if isPackageType(first, imports, "io", "Reader") || isPackageType(first, imports, "io", "ReadCloser") {
+		if len(list) == 3 {
+			if !isIdent(list[1], "string") || !endsWithError {
+				return 0, TypeInfo{}, fmt.Errorf("以文件下载输出时须返回 (io.Reader, string, error)，第二个返回值为文件名")
+			}
+			return ResponseDownload, typeParser.ParseReturnType(first), nil
+		}
+	}
+	return ResponseDefault, typeParser.ParseReturnType(first), nil
+}
+
+// isPackageType 表达式是否为导入路径 pkgPath 中的类型 name
+func isPackageType(expr ast.Expr, imports xast.ImportInfoSlice, pkgPath, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	imp := imports.Find(ident.Name)
+	return imp != nil && strings.Trim(imp.Path, `"`) == pkgPath
+}
+
+// isFileHeaderType 参数类型是否为 *multipart.FileHeader 或 []*multipart.FileHeader
+func isFileHeaderType(t TypeInfo) bool {
+	return t.TypeName == "FileHeader" && strings.Trim(t.Package, `"`) == "mime/multipart" &&
+		strings.HasPrefix(strings.TrimPrefix(t.FullName, "[]"), "*")
+}
+
+// hasFileParams 方法是否有 @FILE 参数
+func (s SwaggerMethod) hasFileParams() bool {
+	return slices.ContainsFunc(s.Parameters, func(p Parameter) bool { return p.Source == ParamSourceFile })
+}
+
+// checkFileParams 校验 @FILE 参数：类型须为文件头，请求须为 multipart/form-data
+func checkFileParams(iface SwaggerInterface, method SwaggerMethod) error {
+	if !method.hasFileParams() {
+		return nil
+	}
+	for _, param := range method.Parameters {
+		if param.Source == ParamSourceFile && !isFileHeaderType(param.Type) {
+			return fmt.Errorf("@FILE 参数 %s 的类型须为 *multipart.FileHeader 或 []*multipart.FileHeader", param.Name)
+		}
+	}
+	if method.GetHTTPMethod() == "GET" {
+		return fmt.Errorf("GET 方法不支持 @FILE 参数")
+	}
+	if accept := acceptType(iface, method); accept != "mpfd" {
+		return fmt.Errorf("@FILE 参数要求 multipart/form-data 请求，不能与请求类型 %s 同时使用", accept)
+	}
+	return nil
+}
+
+// acceptType 请求体类型（@JSON-REQ / @FORM-REQ / @MIME-REQ 的别名），方法级优先于接口级；
+// 未声明时有 @FILE 参数的方法为 mpfd，其余为 json
+func acceptType(iface SwaggerInterface, method SwaggerMethod) string {
+	if accept, ok := slices.Concat(method.Def, iface.CommonDef).GetAcceptType(); ok {
+		return accept
+	}
+	if method.hasFileParams() {
+		return "mpfd"
+	}
+	return "json"
+}
+
+// produceType 响应类型（@JSON / @MIME 的别名），方法级优先于接口级；
+// 未声明时 SSE 为 event-stream，下载为 octet-stream，其余为 json
+func produceType(iface SwaggerInterface, method SwaggerMethod) string {
+	if produce, ok := slices.Concat(method.Def, iface.CommonDef).GetContentType(); ok {
+		return produce
+	}
+	switch {
+	case method.Response.IsSSE():
+		return "event-stream"
+	case method.Response == ResponseDownload:
+		return "octet-stream"
+	}
+	return "json"
+}
+
+// needsTransfer 接口是否使用了 SSE、文件下载或 @FILE 参数
+func needsTransfer(iface SwaggerInterface) bool {
+	return slices.ContainsFunc(iface.Methods, func(m SwaggerMethod) bool {
+		return !m.Def.IsRemoved() && (m.Response != ResponseDefault || m.hasFileParams())
+	})
+}
+
+// transferHelpers 将 transfer 包的源码改写为包内声明：包级标识符按 transferName 重命名，
+// 去掉包注释与导入，返回代码与所需的导入行。生成代码因此只依赖标准库，每个包生成一次
+var transferHelpers = sync.OnceValues(func() (string, []string) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "transfer.go", transferSource, parser.ParseComments)
+	if err != nil {
+		panic(fmt.Sprintf("解析 transfer 源码失败: %v", err))
+	}
+
+	renamed := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			renamed[d.Name.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						renamed[ident.Name] = true
+					}
+				case *ast.TypeSpec:
+					renamed[s.Name.Name] = true
+				}
+			}
+		}
+	}
+	// 选择器的字段名（如 fh.Filename）不是包级标识符，不重命名
+	selected := make(map[*ast.Ident]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			selected[sel.Sel] = true
+		}
+		return true
+	})
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && renamed[ident.Name] && !selected[ident] {
+			ident.Name = transferName(ident.Name)
+		}
+		return true
+	})
+	// 文档注释以声明名开头，随之改名
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			name, _, _ := strings.Cut(strings.TrimPrefix(c.Text, "// "), " ")
+			if renamed[name] {
+				c.Text = "// " + transferName(strings.TrimPrefix(c.Text, "// "))
+			}
+		}
+	}
+
+	var imports []string
+	for _, imp := range file.Imports {
+		imports = append(imports, imp.Path.Value)
+	}
+	file.Doc = nil
+	file.Decls = slices.DeleteFunc(file.Decls, func(decl ast.Decl) bool {
+		gen, ok := decl.(*ast.GenDecl)
+		return ok && gen.Tok == token.IMPORT
+	})
+	file.Comments = slices.DeleteFunc(file.Comments, func(group *ast.CommentGroup) bool {
+		return group.Pos() < file.Name.End()
+	})
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		panic(fmt.Sprintf("输出 transfer 源码失败: %v", err))
+	}
+	_, code, _ := strings.Cut(buf.String(), "\n")
+	return strings.TrimSpace(code), imports
+})
+
+// generateTransferHelpers 返回生成到包中的 transfer 声明及其导入行
+func generateTransferHelpers() (string, []string) {
+	code, imports := transferHelpers()
+	return "// ============================================================================\n" +
+		"// 流式响应与文件传输（由 swaggen/transfer 生成，每个包一次）\n" +
+		"// ============================================================================\n\n" + code, imports
+}
+
+// generateStreamResponse 生成 SSE / 文件下载的响应代码：方法返回错误时按普通错误输出，否则直接写入 ResponseWriter
+func generateStreamResponse(backend Backend, iface SwaggerInterface, method SwaggerMethod, methodCall string) string {
+	fail := backend.RespondError()
+	if len(iface.ErrorRegistries) > 0 {
+		fail = backend.HandleError()
+	}
+
+	results, stmt := "result, err", ""
+	switch method.Response {
+	case ResponseSSE:
+		stmt = fmt.Sprintf("%s(%s, %s, result)", transferName("SSE"), backend.Writer(), backend.Request())
+	case ResponseSSEChan:
+		stmt = fmt.Sprintf("%s(%s, %s, result)", transferName("SSEChan"), backend.Writer(), backend.Request())
+	case ResponseDownload:
+		results = "result, downloadName, err"
+		contentType := ""
+		if produce, ok := slices.Concat(method.Def, iface.CommonDef).GetContentType(); ok {
+			contentType = mimeType(produce)
+		}
+		stmt = fmt.Sprintf("%s(%s, result, downloadName, %q)", transferName("Download"), backend.Writer(), contentType)
+	}
+	return fmt.Sprintf(`%s := %s
+        if err != nil {
+            %s
+        }
+        %s`, results, methodCall, fail, backend.Finish(stmt))
+}
+
+// file 生成 @FILE 参数的读取语句：请求体无法解析与必填文件缺失记录为失败字段
+func (v validator) file(param Parameter, request string) string {
+	field := param.ExternalName()
+	fn, missing := "FormFile", param.Name+" == nil"
+	if strings.HasPrefix(param.Type.FullName, "[]") {
+		fn, missing = "FormFiles", "len("+param.Name+") == 0"
+	}
+	code := fmt.Sprintf("%s, fileErr := %s(%s, %q)\n", param.Name, transferName(fn), request, field)
+	parseErr := []string{fmt.Sprintf("invalid = append(invalid, %s{Field: %q, In: %q, Rule: %q, Message: fileErr.Error()})", v.typeName, field, "formData", "multipart")}
+	if !param.Required {
+		return code + ifBlock("fileErr != nil", parseErr, nil)
+	}
+	return code + ifBlock("fileErr != nil", parseErr, nil) + " else " + ifBlock(missing, []string{v.fail(field, "formData", "required", "is required")}, nil)
+}
+
+// streamElemType SSE 返回值的事件元素类型（iter.Seq[T] / <-chan T 中的 T）
+func streamElemType(t types.Type) types.Type {
+	switch t := types.Unalias(t).(type) {
+	case *types.Chan:
+		return t.Elem()
+	case *types.Named:
+		if args := t.TypeArgs(); args.Len() == 1 {
+			return args.At(0)
+		}
+	}
+	return t
+}
+
+// binarySchema 文件内容的 Schema
+func binarySchema() *Schema {
+	return &Schema{Type: "string", Format: "binary", ContentMediaType: "application/octet-stream"}
+}
@@ -0,0 +1,248 @@
+package swaggen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateStreamCode(t *testing.T, name string) (string, *plugin.GenerateResult) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/stream/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, name)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")])
+	if code != "" {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+		}
+	}
+	return code, result
+}
+
+func TestStreamHandler(t *testing.T) {
+	code, result := generateStreamCode(t, "IStreamAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		`"bufio"`,
+		`"mime/multipart"`,
+		// SSE：出错时按普通错误响应，否则逐条输出事件
+		"// @Produce event-stream",
+		"// @Success 200 {object} Tick",
+		"result, err := a.inner.Ticks(r.Context(), count)",
+		`onHTTPResponse[string](w, r, "", err)`,
+		"transferSSE(w, r, result)",
+		"transferSSEChan(w, r, result)",
+		// 文件下载
+		"// @Produce png",
+		"// @Success 200 {file} file",
+		"result, downloadName, err := a.inner.Download(r.Context(), name)",
+		`transferDownload(w, result, downloadName, "image/png")`,
+		// @FILE 上传
+		"// @Accept mpfd",
+		`// @Param avatar formData file true "avatar"`,
+		`// @Param docs formData []file true "docs"`,
+		`// @Param cover formData file false "cover"`,
+		`avatar, fileErr := transferFormFile(r, "avatar")`,
+		`invalid = append(invalid, StreamAPIFieldError{Field: "avatar", In: "formData", Rule: "multipart", Message: fileErr.Error()})`,
+		`} else if avatar == nil {`,
+		`docs, fileErr := transferFormFiles(r, "docs")`,
+		`} else if len(docs) == 0 {`,
+		`if !onHTTPBind(w, r, &req, "FORM") {`,
+		"result, err := a.inner.UploadDocs(r.Context(), req, docs, cover)",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+	// transfer 声明生成在包内，生成代码不依赖 gogen 运行时包
+	if strings.Contains(code, "github.com/donutnomad/gogen") {
+		t.Errorf("generated code should not import gogen packages\n%s", code)
+	}
+	if strings.Count(code, "func transferSSE[T any](") != 1 {
+		t.Errorf("transfer helpers should be generated once\n%s", code)
+	}
+	if strings.Contains(code, `} else if cover == nil {`) {
+		t.Errorf("optional file should not be required\n%s", code)
+	}
+}
+
+func TestStreamClient(t *testing.T) {
+	code, _ := generateStreamCode(t, "IStreamAPI")
+
+	wants := []string{
+		"func (c *StreamAPIClient) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, contentType, accept string) (*http.Response, error) {",
+		// SSE 与下载直接交出响应体
+		`resp, err := c.do(ctx, "GET", "/api/ticks", query, nil, nil, "", "text/event-stream")`,
+		"return transferReadSSE[Tick](resp.Body), nil",
+		"return transferReadSSEChan[string](ctx, resp.Body), nil",
+		"func (c *StreamAPIClient) Download(ctx context.Context, name string) (io.ReadCloser, string, error) {",
+		`return nil, "", err`,
+		"return resp.Body, transferFilename(resp.Header), nil",
+		// 文件与表单字段一并编码为 multipart
+		`files["avatar"] = []*multipart.FileHeader{avatar}`,
+		"body, contentType, err := transferEncodeMultipart(nil, files)",
+		`files["docs"] = docs`,
+		`form.Set("title", req.Title)`,
+		"body, contentType, err := transferEncodeMultipart(form, files)",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated client missing %q\n%s", want, code)
+		}
+	}
+}
+
+func TestStreamRejectsInvalidSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"IBadSeqAPI", "以 SSE 输出时须返回 (iter.Seq[T], error)"},
+		{"IBadFileAPI", "GET 方法不支持 @FILE 参数"},
+		{"IBadFileRuleAPI", "@FILE 仅支持 required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, result := generateStreamCode(t, tt.name)
+			if !result.HasErrors() || !strings.Contains(result.Errors[0].Error(), tt.want) {
+				t.Fatalf("expected %q, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestStreamOpenAPI(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/stream/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IStreamAPI"))
+	if err != nil {
+		t.Fatalf("parseInterface failed: %v", err)
+	}
+	doc, err := BuildOpenAPI(NewTypeLoader(), []SwaggerInterface{*iface}, OpenAPIInfo{Title: "Stream API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("BuildOpenAPI failed: %v", err)
+	}
+
+	// SSE：Schema 为单条事件的数据
+	ticks := doc.Paths["/api/ticks"]["get"].Responses["200"].Content["text/event-stream"]
+	if ticks == nil || ticks.Schema.Ref != "#/components/schemas/stream.Tick" {
+		t.Errorf("ticks response = %+v", doc.Paths["/api/ticks"]["get"].Responses["200"])
+	}
+
+	// 下载：二进制内容与 Content-Disposition 响应头
+	download := doc.Paths["/api/files/{name}"]["get"].Responses["200"]
+	if png := download.Content["image/png"]; png == nil || png.Schema.Format != "binary" {
+		t.Errorf("download content = %v", keys(download.Content))
+	}
+	if download.Headers["Content-Disposition"] == nil {
+		t.Error("download response missing Content-Disposition header")
+	}
+
+	// 上传：文件字段与表单字段合并到 multipart/form-data 请求体
+	body := doc.Paths["/api/docs"]["post"].RequestBody.Content["multipart/form-data"].Schema
+	if got := keys(body.Properties); !slices.Equal(got, []string{"cover", "docs", "title"}) {
+		t.Errorf("properties = %v", got)
+	}
+	if docs := body.Properties["docs"]; docs.Type != "array" || docs.Items.Format != "binary" {
+		t.Errorf("docs schema = %+v", docs)
+	}
+	if !slices.Equal(body.Required, []string{"docs"}) {
+		t.Errorf("required = %v", body.Required)
+	}
+	avatar := doc.Paths["/api/avatar"]["post"].RequestBody.Content["multipart/form-data"].Schema
+	if avatar.Properties["avatar"].Format != "binary" || !slices.Equal(avatar.Required, []string{"avatar"}) {
+		t.Errorf("avatar body = %+v", avatar)
+	}
+	if doc.Paths["/api/avatar"]["post"].Responses["400"] == nil {
+		t.Error("upload should document the 400 validation response")
+	}
+}
+
+// TestTransferHelpers 改写后的 transfer 声明可以独立编译，包级标识符均带 transfer 前缀
+func TestTransferHelpers(t *testing.T) {
+	code, imports := generateTransferHelpers()
+	src := "package api\n\nimport (\n" + strings.Join(imports, "\n") + "\n)\n\n" + code
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "transfer.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v\n%s", err, src)
+	}
+	if _, err := (&types.Config{Importer: importer.ForCompiler(fset, "source", nil)}).Check("api", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("type check: %v\n%s", err, src)
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && !strings.HasPrefix(fn.Name.Name, "transfer") {
+			t.Errorf("func %s is not prefixed", fn.Name.Name)
+		}
+	}
+	for _, want := range []string{
+		"// transferSSE 将 seq 中的值",
+		"flush := transferStartSSE(w)",
+		"r.ParseMultipartForm(transferMaxMemory)",
+		"part, err := writer.CreatePart(transferFileHeader(field, fh.Filename, fh.Header.Get(\"Content-Type\")))",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("transfer helpers missing %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "package transfer") || strings.Contains(code, "Package transfer") {
+		t.Errorf("package clause should be stripped\n%s", code)
+	}
+}
+
+// TestTransferHelpersOncePerPackage 包内已声明 transfer 函数时不再生成
+func TestTransferHelpersOncePerPackage(t *testing.T) {
+	// 目录位于模块内，便于加载包的类型信息
+	dir, err := os.MkdirTemp(".", "_transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	if dir, err = filepath.Abs(dir); err != nil {
+		t.Fatal(err)
+	}
+	src, err := os.ReadFile("testdata/stream/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(dir, "api.go")
+	if err := os.WriteFile(filePath, src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, _ := generateTransferHelpers()
+	existing := "package stream\n\n" + code
+	if err := os.WriteFile(filepath.Join(dir, "transfer.go"), []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, "IStreamAPI")}})
+	if err != nil || result.HasErrors() {
+		t.Fatalf("Generate: %v %v", err, result.Errors)
+	}
+	out := string(result.RawOutputs[filepath.Join(dir, "api_swagger.go")])
+	if !strings.Contains(out, "transferSSE(w, r, result)") || strings.Contains(out, "func transferSSE[") {
+		t.Errorf("existing transfer helpers should be reused\n%s", out)
+	}
+}
@@ -1,11 +1,13 @@
 package swaggen
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -31,9 +33,9 @@ func NewSwagGenerator() *SwagGenerator {
 	gen := &SwagGenerator{
 		BaseGenerator: *plugin.NewBaseGeneratorWithParamsStruct(
 			generatorName,
-			// HTTP 方法注解作为触发器
-			[]string{"GET", "POST", "PUT", "PATCH", "DELETE"},
-			[]plugin.TargetKind{plugin.TargetInterface},
+			// HTTP 方法注解作为触发器；SwagBackend 同时支持 //go:gogen 包级默认值
+			[]string{"GET", "POST", "PUT", "PATCH", "DELETE", "SwagBackend"},
+			[]plugin.TargetKind{plugin.TargetInterface, plugin.TargetComment},
 			SwagParams{},
 		),
 	}
@@ -49,6 +51,7 @@ func (g *SwagGenerator) AnnotationFormats() []string {
 		"PUT(path)",
 		"PATCH(path)",
 		"DELETE(path)",
+		"SwagBackend(gin|nethttp|chi|echo)",
 	}
 }
 
@@ -64,6 +67,14 @@ func (g *SwagGenerator) ExtraHelp() string {
       @SECURITY(name)         - 安全认证，支持 exclude/include 参数
       @HEADER(name,required,desc) - 公共请求头
       @PREFIX(path)           - 路由前缀
+      @OPENAPI(path;title=;version=) - 输出 OpenAPI 3.1 文档 (.json/.yaml)
+      @TS(path)               - 输出 TypeScript 类型定义与 fetch 客户端 (.ts)
+      @CLIENT                 - 生成实现该接口的 net/http 客户端
+      @ERRORS(Err1, pkg.Err2) - 可能返回的 @Code 错误，映射 HTTP 状态码并列入 OpenAPI
+      @PERM(order:write)      - 所需权限，处理器经生成的 Authorizer 接口检查，支持 exclude/include 参数，亦可用于方法
+      @RATELIMIT(10/s, key=user) - 限流规则，处理器经生成的 Limiter 接口检查，支持 exclude/include 参数，亦可用于方法
+      @SwagBackend(name)      - 绑定代码的 HTTP 框架: gin(默认)/nethttp/chi/echo
+                                //go:gogen @SwagBackend(name) 设置包级默认值
     辅助注解 (方法级别):
       @JSON                   - 响应类型为 JSON
       @MIME(type)             - 自定义响应 MIME 类型
@@ -74,12 +85,18 @@ func (g *SwagGenerator) ExtraHelp() string {
       @Removed                - 从生成中移除此方法
       @ExcludeFromBindAll     - 从 BindAll 中排除
       @Raw(text)              - 原始 Swagger 注释
+    返回值:
+      (iter.Seq[T], error) / (<-chan T, error)   - 以 Server-Sent Events 输出
+      (io.ReadCloser, string, error)            - 以附件下载输出，第二个返回值为文件名
     辅助注解 (参数级别):
       @PARAM                  - 路径参数，可指定别名 @PARAM(alias)
-      @QUERY                  - 查询参数
+      @QUERY                  - 查询参数，可指定名称 @QUERY(name)
       @BODY                   - 请求体参数
       @FORM                   - 表单参数
-      @HEADER                 - 请求头参数
+      @HEADER                 - 请求头参数，可指定名称 @HEADER(X-Name)
+                                以上三者可追加校验规则: @PARAM(min=1) @QUERY(required) @HEADER(X-Tenant; pattern=^[a-z]+$)
+                                规则: required, min, max, len, pattern, enum=a,b
+      @FILE                   - 上传文件，类型为 *multipart.FileHeader 或 []*multipart.FileHeader，可指定字段名 @FILE(avatar; required=false)
     示例:
       // @TAG(用户管理)
       // @SECURITY(Bearer)
@@ -107,6 +124,12 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 	// 用于去重的 map，key: 文件路径+接口名
 	processedInterfaces := make(map[string]bool)
 
+	// 包级 //go:gogen @SwagBackend(...) 默认后端，key: 包目录
+	defaultBackends, err := g.parseDefaultBackends(ctx.Targets)
+	if err != nil {
+		result.AddError(err)
+	}
+
 	for _, at := range ctx.Targets {
 		// 确保是接口类型
 		if at.Target.Kind != plugin.TargetInterface {
@@ -122,7 +145,7 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 
 		// 获取输出路径
 		fileConfig := ctx.GetFileConfig(at.Target.FilePath)
-		ann := getFirstAnnotation(at.Annotations, "GET", "POST", "PUT", "PATCH", "DELETE")
+		ann := getFirstAnnotation(at.Annotations, "GET", "POST", "PUT", "PATCH", "DELETE", "SwagBackend")
 		outputPath := plugin.GetOutputPath(at.Target, ann, "$FILE_swagger.go", fileConfig, g.Name(), ctx.DefaultOutput)
 
 		// 解析接口
@@ -135,6 +158,15 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 		if swaggerInterface == nil || len(swaggerInterface.Methods) == 0 {
 			continue
 		}
+		if swaggerInterface.CommonDef.GetBackend() == "" {
+			if def, ok := defaultBackends[filepath.Dir(at.Target.FilePath)]; ok {
+				swaggerInterface.CommonDef = append(swaggerInterface.CommonDef, def)
+			}
+		}
+		if err := checkBackend(*swaggerInterface); err != nil {
+			result.AddError(fmt.Errorf("接口 %s: %w", at.Target.Name, err))
+			continue
+		}
 
 		fileTargets[outputPath] = append(fileTargets[outputPath], &swagTargetInfo{
 			iface:  swaggerInterface,
@@ -155,6 +187,16 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 	}
 	slices.Sort(outputPaths)
 
+	// 汇总所有接口的路由表，检测重复与框架无法同时注册的路由
+	for _, err := range checkRouteConflicts(fileTargets) {
+		result.AddError(err)
+	}
+
+	// transfer 声明是包级的：每个包只在第一个需要的输出文件中生成一次，
+	// 包内其他文件（如另一次运行的输出）已声明时不再生成
+	transferDirs := make(map[string]bool)
+
+	loader := NewTypeLoader()
 	for _, outputPath := range outputPaths {
 		targets := fileTargets[outputPath]
 		// 按接口名称排序，确保同一文件中不同接口的顺序一致
@@ -162,7 +204,15 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 			return strings.Compare(a.target.Target.Name, b.target.Target.Name)
 		})
 
-		code, err := g.generateCode(targets)
+		withTransfer := false
+		if dir := filepath.Dir(outputPath); !transferDirs[dir] && slices.ContainsFunc(targets, func(t *swagTargetInfo) bool {
+			return needsTransfer(*t.iface)
+		}) {
+			withTransfer = !xast.PackageDecls(dir, outputPaths...)[transferName("SSE")]
+			transferDirs[dir] = true
+		}
+
+		code, err := g.generateCode(targets, loader, withTransfer)
 		if err != nil {
 			result.AddError(fmt.Errorf("生成 %s 失败: %w", outputPath, err))
 			continue
@@ -170,9 +220,146 @@ func (g *SwagGenerator) Generate(ctx *plugin.GenerateContext) (*plugin.GenerateR
 		result.AddRawOutput(outputPath, []byte(code))
 	}
 
+	g.generateOpenAPI(result, fileTargets, loader)
+	g.generateTS(result, fileTargets, loader)
+
 	return result, nil
 }
 
+// generateOpenAPI 为声明了 @OPENAPI 的接口输出 OpenAPI 文档，指向同一路径的接口合并为一份
+func (g *SwagGenerator) generateOpenAPI(result *plugin.GenerateResult, fileTargets map[string][]*swagTargetInfo, loader *TypeLoader) {
+	docTargets := make(map[string][]*swagTargetInfo)
+	for _, targets := range fileTargets {
+		for _, t := range targets {
+			def := t.iface.CommonDef.GetOpenAPI()
+			if def == nil {
+				continue
+			}
+			docPath := def.Value
+			if !filepath.IsAbs(docPath) {
+				docPath = filepath.Join(filepath.Dir(t.iface.FilePath), docPath)
+			}
+			docTargets[docPath] = append(docTargets[docPath], t)
+		}
+	}
+
+	docPaths := make([]string, 0, len(docTargets))
+	for docPath := range docTargets {
+		docPaths = append(docPaths, docPath)
+	}
+	slices.Sort(docPaths)
+
+	for _, docPath := range docPaths {
+		targets := docTargets[docPath]
+		slices.SortFunc(targets, func(a, b *swagTargetInfo) int {
+			return strings.Compare(a.iface.Name, b.iface.Name)
+		})
+
+		info := OpenAPIInfo{Title: targets[0].iface.PackagePath, Version: "1.0.0"}
+		var interfaces []SwaggerInterface
+		for _, t := range targets {
+			def := t.iface.CommonDef.GetOpenAPI()
+			if def.Title != "" {
+				info.Title = def.Title
+			}
+			if def.Version != "" {
+				info.Version = def.Version
+			}
+			interfaces = append(interfaces, *t.iface)
+		}
+
+		doc, err := BuildOpenAPI(loader, interfaces, info)
+		if err != nil {
+			result.AddError(fmt.Errorf("生成 %s 失败: %w", docPath, err))
+			continue
+		}
+		data, err := doc.Render(docPath)
+		if err != nil {
+			result.AddError(fmt.Errorf("生成 %s 失败: %w", docPath, err))
+			continue
+		}
+		result.AddFileOutput(docPath, data)
+	}
+}
+
+// generateTS 为声明了 @TS 的接口输出 TypeScript 类型与客户端，指向同一路径的接口合并为一个文件
+func (g *SwagGenerator) generateTS(result *plugin.GenerateResult, fileTargets map[string][]*swagTargetInfo, loader *TypeLoader) {
+	tsTargets := make(map[string][]*swagTargetInfo)
+	for _, targets := range fileTargets {
+		for _, t := range targets {
+			def := t.iface.CommonDef.GetTS()
+			if def == nil {
+				continue
+			}
+			tsPath := def.Value
+			if !filepath.IsAbs(tsPath) {
+				tsPath = filepath.Join(filepath.Dir(t.iface.FilePath), tsPath)
+			}
+			tsTargets[tsPath] = append(tsTargets[tsPath], t)
+		}
+	}
+
+	tsPaths := make([]string, 0, len(tsTargets))
+	for tsPath := range tsTargets {
+		tsPaths = append(tsPaths, tsPath)
+	}
+	slices.Sort(tsPaths)
+
+	for _, tsPath := range tsPaths {
+		targets := tsTargets[tsPath]
+		slices.SortFunc(targets, func(a, b *swagTargetInfo) int {
+			return strings.Compare(a.iface.Name, b.iface.Name)
+		})
+
+		interfaces := make([]SwaggerInterface, 0, len(targets))
+		for _, t := range targets {
+			interfaces = append(interfaces, *t.iface)
+		}
+
+		code, err := NewTSGenerator(loader).Generate(interfaces)
+		if err != nil {
+			result.AddError(fmt.Errorf("生成 %s 失败: %w", tsPath, err))
+			continue
+		}
+		result.AddFileOutput(tsPath, []byte(code))
+	}
+}
+
+// parseDefaultBackends 解析 //go:gogen @SwagBackend(...) 独立注解，返回 包目录 -> 默认后端
+func (g *SwagGenerator) parseDefaultBackends(targets []*plugin.AnnotatedTarget) (map[string]*parsers.SwagBackend, error) {
+	out := make(map[string]*parsers.SwagBackend)
+	tagsParser, err := newTagParserSafe()
+	if err != nil {
+		return out, fmt.Errorf("创建标签解析器失败: %w", err)
+	}
+	var errs []error
+	for _, at := range targets {
+		if at.Target.Kind != plugin.TargetComment || len(at.Annotations) == 0 {
+			continue
+		}
+		parsed, err := tagsParser.Parse(at.Annotations[0].Raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: 解析 %s 失败: %w", at.Target.FilePath, at.Annotations[0].Raw, err))
+			continue
+		}
+		def, ok := parsed.(*parsers.SwagBackend)
+		if !ok {
+			continue
+		}
+		if _, err := lookupBackend(def.Value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", at.Target.FilePath, err))
+			continue
+		}
+		dir := filepath.Dir(at.Target.FilePath)
+		if prev, ok := out[dir]; ok && !strings.EqualFold(prev.Value, def.Value) {
+			errs = append(errs, fmt.Errorf("%s: 包内 @SwagBackend 默认值冲突: %s / %s", dir, prev.Value, def.Value))
+			continue
+		}
+		out[dir] = def
+	}
+	return out, errors.Join(errs...)
+}
+
 // swagTargetInfo 存储单个接口的处理信息
 type swagTargetInfo struct {
 	iface  *SwaggerInterface
@@ -247,6 +434,7 @@ func (g *SwagGenerator) parseInterface(at *plugin.AnnotatedTarget) (*SwaggerInte
 		Name:        at.Target.Name,
 		PackagePath: at.Target.PackageName,
 		Imports:     imports,
+		FilePath:    at.Target.FilePath,
 		Methods:     []SwaggerMethod{},
 	}
 
@@ -299,15 +487,29 @@ func (g *SwagGenerator) parseInterface(at *plugin.AnnotatedTarget) (*SwaggerInte
 		// 解析参数
 		if funcType.Params != nil {
 			paramAnnotations, _ := parsers.ParseParameters(getParamsContent(fileBs, fset, funcType))
-			allParams := extractBaseParameters(funcType.Params.List, paramAnnotations, typeParser, annotationParser)
+			allParams, err := extractBaseParameters(funcType.Params.List, paramAnnotations, typeParser, annotationParser)
+			if err != nil {
+				return nil, fmt.Errorf("解析方法 %s 失败: %w", field.Names[0].Name, err)
+			}
 			mapPathParameters(swaggerMethod, allParams)
 			swaggerMethod.Parameters = allParams
 		}
 
-		// 解析返回类型
-		if funcType.Results != nil && len(funcType.Results.List) > 0 {
-			firstResult := funcType.Results.List[0]
-			swaggerMethod.ResponseType = typeParser.ParseReturnType(firstResult.Type)
+		if err := checkFileParams(*swaggerInterface, *swaggerMethod); err != nil {
+			return nil, fmt.Errorf("解析方法 %s 失败: %w", field.Names[0].Name, err)
+		}
+		if err := checkGuards(*swaggerInterface, *swaggerMethod); err != nil {
+			return nil, fmt.Errorf("解析方法 %s 失败: %w", field.Names[0].Name, err)
+		}
+
+		// 解析返回类型：iter.Seq[T] / <-chan T 以 SSE 输出，(io.Reader, 文件名, error) 以附件下载输出
+		if funcType.Results != nil {
+			mode, responseType, err := parseResponseMode(funcType.Results, imports, typeParser)
+			if err != nil {
+				return nil, fmt.Errorf("解析方法 %s 失败: %w", field.Names[0].Name, err)
+			}
+			swaggerMethod.Response = mode
+			swaggerMethod.ResponseType = responseType
 		}
 
 		swaggerInterface.Methods = append(swaggerInterface.Methods, *swaggerMethod)
@@ -317,15 +519,27 @@ func (g *SwagGenerator) parseInterface(at *plugin.AnnotatedTarget) (*SwaggerInte
 }
 
 // generateCode 生成完整代码
-func (g *SwagGenerator) generateCode(targets []*swagTargetInfo) (string, error) {
+func (g *SwagGenerator) generateCode(targets []*swagTargetInfo, loader *TypeLoader, withTransfer bool) (string, error) {
 	if len(targets) == 0 {
 		return "", fmt.Errorf("没有目标需要生成")
 	}
 
-	// 收集所有接口
+	// 收集所有接口，并解析 @ERRORS 引用的 @Code 注册表与请求结构体的校验规则
 	var interfaces []SwaggerInterface
 	for _, t := range targets {
-		interfaces = append(interfaces, *t.iface)
+		iface := *t.iface
+		registries, err := loader.ErrorRegistries(iface)
+		if err != nil {
+			return "", err
+		}
+		iface.ErrorRegistries = registries
+		iface.Methods = slices.Clone(iface.Methods)
+		for i, method := range iface.Methods {
+			if iface.Methods[i].FieldRules, err = loader.FieldRules(iface, method); err != nil {
+				return "", err
+			}
+		}
+		interfaces = append(interfaces, iface)
 	}
 
 	// 获取包名
@@ -347,8 +561,31 @@ func (g *SwagGenerator) generateCode(targets []*swagTargetInfo) (string, error)
 	header := swaggerGen.GenerateFileHeader(packageName)
 	parts = append(parts, header)
 
+	// HTTP 客户端（@CLIENT）
+	clientGen := NewClientGenerator(loader)
+	var clientParts []string
+	for _, iface := range interfaces {
+		if !iface.CommonDef.HasClient() {
+			continue
+		}
+		code, err := clientGen.Generate(iface)
+		if err != nil {
+			return "", fmt.Errorf("生成 %s 客户端失败: %w", iface.Name, err)
+		}
+		clientParts = append(clientParts, code)
+	}
+
+	// 流式响应与文件传输的包级声明
+	extraImports := clientGen.Imports()
+	var transferCode string
+	if withTransfer {
+		code, imports := generateTransferHelpers()
+		transferCode = code
+		extraImports = append(extraImports, imports...)
+	}
+
 	// 导入声明
-	imports := swaggerGen.GenerateImports()
+	imports := swaggerGen.GenerateImports(extraImports...)
 	if imports != "" {
 		parts = append(parts, imports, "")
 	}
@@ -361,6 +598,14 @@ func (g *SwagGenerator) generateCode(targets []*swagTargetInfo) (string, error)
 	if ginCode != "" {
 		parts = append(parts, ginCode)
 	}
+	// 空行隔开 Gin 代码末尾的辅助函数模板注释，避免其成为客户端类型的文档
+	if len(clientParts) > 0 {
+		parts = append(parts, "")
+		parts = append(parts, clientParts...)
+	}
+	if transferCode != "" {
+		parts = append(parts, "", transferCode)
+	}
 
 	return strings.Join(parts, "\n"), nil
 }
@@ -407,7 +652,7 @@ func getParamsContent(fileBs []byte, fset *token.FileSet, funcType *ast.FuncType
 	return getContent(fileBs, start, end)
 }
 
-func extractBaseParameters(fields []*ast.Field, paramAnnotations []parsers.Parameter, typeParser *ReturnTypeParser, annotationParser *AnnotationParser) []Parameter {
+func extractBaseParameters(fields []*ast.Field, paramAnnotations []parsers.Parameter, typeParser *ReturnTypeParser, annotationParser *AnnotationParser) ([]Parameter, error) {
 	var allParams []Parameter
 
 	var expandedFields []*ast.Field
@@ -428,13 +673,23 @@ func extractBaseParameters(fields []*ast.Field, paramAnnotations []parsers.Param
 
 		if i < len(paramAnnotations) {
 			annotation := paramAnnotations[i]
-			parameter := annotationParser.ParseParameterAnnotations(annotation.Name, annotation.Tag)
+			parameter, err := annotationParser.ParseParameterAnnotations(annotation.Name, annotation.Tag)
+			if err != nil {
+				return nil, err
+			}
 			parameter.Type = paramType
+			// @QUERY 标注的结构体仍按查询参数结构体整体绑定
+			if parameter.Source == "query" && paramKind(parameter) == "" && parameter.Rules.IsZero() {
+				parameter.Source = ""
+			}
+			if err := checkParamRules(parameter); err != nil {
+				return nil, err
+			}
 			allParams = append(allParams, parameter)
 		}
 	}
 
-	return allParams
+	return allParams, nil
 }
 
 func mapPathParameters(swaggerMethod *SwaggerMethod, allParams []Parameter) {
@@ -535,9 +790,9 @@ func (g *SwaggerGenerator2) GenerateFileHeader(packageName string) string {
 }
 
 // GenerateImports 生成导入声明
-func (g *SwaggerGenerator2) GenerateImports() string {
+func (g *SwaggerGenerator2) GenerateImports(extra ...string) string {
 	sg := &SwaggerGenerator{collection: g.collection, tagsParser: g.tagsParser}
-	return sg.GenerateImports()
+	return sg.GenerateImports(extra...)
 }
 
 // getFirstAnnotation 获取第一个匹配的注解
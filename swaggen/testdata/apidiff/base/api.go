@@ -0,0 +1,55 @@
+package api
+
+import "context"
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+type User struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  Role   `json:"role"`
+}
+
+type ListReq struct {
+	Page    int    `form:"page"`
+	Keyword string `form:"keyword"`
+}
+
+type CreateReq struct {
+	Name string `json:"name" binding:"max=32"`
+	Age  int    `json:"age"`
+	Note string `json:"note"`
+}
+
+// @PREFIX(/api)
+// @SECURITY(Bearer; exclude=ListUsers)
+type IUserAPI interface {
+	// @GET(/users/{id})
+	GetUser(ctx context.Context, id int64) (User, error)
+
+	// @GET(/users)
+	ListUsers(ctx context.Context, req ListReq) ([]User, error)
+
+	// @POST(/users)
+	CreateUser(ctx context.Context, req CreateReq) (User, error)
+
+	// @PUT(/users/{id}/name)
+	Rename(ctx context.Context, id int64, req CreateReq) error
+
+	// @GET(/users/{id}/avatar)
+	Avatar(
+		ctx context.Context,
+		id int64,
+		// @QUERY(max=100)
+		size int,
+	) ([]byte, error)
+
+	// @DELETE(/users/{id})
+	DeleteUser(ctx context.Context, id int64) error
+}
@@ -0,0 +1,57 @@
+package api
+
+import "context"
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+	RoleGuest Role = "guest"
+)
+
+type User struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	Role     Role   `json:"role"`
+}
+
+type ListReq struct {
+	Page    int    `form:"page" binding:"required"`
+	Keyword string `form:"keyword"`
+	Status  string `form:"status"`
+}
+
+type CreateReq struct {
+	Name  string `json:"name" binding:"max=16"`
+	Age   string `json:"age"`
+	Email string `json:"email" binding:"required"`
+}
+
+// @PREFIX(/api)
+// @SECURITY(Bearer)
+type IUserAPI interface {
+	// @GET(/users/{id})
+	GetUser(ctx context.Context, id int64) (User, error)
+
+	// @GET(/users)
+	ListUsers(ctx context.Context, req ListReq) ([]User, error)
+
+	// @POST(/users)
+	CreateUser(ctx context.Context, req CreateReq) (User, error)
+
+	// @PATCH(/users/{id}/name)
+	Rename(ctx context.Context, id int64, req CreateReq) error
+
+	// @GET(/users/{id}/photo)
+	Avatar(
+		ctx context.Context,
+		id int64,
+		// @QUERY(max=200)
+		size int,
+	) ([]byte, error)
+
+	// @GET(/users/search)
+	Search(ctx context.Context, q string) ([]User, error)
+}
@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:gogen @SwagBackend(nethttp)
+
+type Order struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type CreateOrderReq struct {
+	Title string `json:"title"`
+}
+
+type ListReq struct {
+	Page int `form:"page"`
+}
+
+// @PREFIX(/api)
+type IOrderAPI interface {
+	// @GET(/orders/{id})
+	// @MID(Auth)
+	GetOrder(
+		ctx context.Context,
+		id int64,
+		// @HEADER
+		tenant string,
+	) (Order, error)
+
+	// @POST(/orders)
+	// @JSON-REQ
+	CreateOrder(ctx context.Context, req CreateOrderReq) (Order, error)
+}
+
+// @SwagBackend(echo)
+type IStockAPI interface {
+	// @GET(/stocks)
+	ListStocks(ctx context.Context, req ListReq) ([]Order, error)
+
+	// @DELETE(/stocks/{id})
+	DeleteStock(ctx context.Context, id int64) error
+}
+
+// @SwagBackend(chi)
+type IPingAPI interface {
+	// @GET(/)
+	Ping(ctx context.Context) (string, error)
+}
+
+// @SwagBackend(nethttp)
+type IGinOnlyAPI interface {
+	// @GET(/raw)
+	Raw(ctx *gin.Context) error
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Level int
+
+type Page struct {
+	Page int `form:"page"`
+	Size int `form:"size"`
+}
+
+// ListReq 查询参数
+type ListReq struct {
+	Page
+	Keyword string    `form:"keyword"`
+	Level   Level     `form:"level"`
+	Active  *bool     `form:"active"`
+	IDs     []int64   `form:"ids"`
+	Since   time.Time `form:"since"`
+	Ignored string    `form:"-"`
+}
+
+type Item struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateReq struct {
+	Name string `json:"name"`
+}
+
+type FormReq struct {
+	Title string `form:"title"`
+	Count uint   `form:"count"`
+}
+
+// @CLIENT
+// @PREFIX(/api)
+type IItemAPI interface {
+	// @GET(/items/{id})
+	GetItem(
+		ctx context.Context,
+		// @PARAM
+		id int64,
+		// @HEADER
+		token string,
+	) (Item, error)
+
+	// @GET(/items)
+	ListItems(ctx context.Context, req ListReq) ([]Item, error)
+
+	// @POST(/items)
+	CreateItem(ctx context.Context, req CreateReq) (*Item, error)
+
+	// @PUT(/items/{itemId}/form)
+	// @FORM-REQ
+	UpdateForm(ctx *gin.Context, itemId int64, req FormReq) error
+
+	// @POST(/items/upload)
+	// @MIME-REQ(mpfd)
+	// @MIME(plain)
+	Upload(ctx context.Context, req *FormReq) (string, error)
+}
@@ -0,0 +1,41 @@
+package errorsapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/donutnomad/gogen/swaggen/testdata/errors/errs"
+)
+
+// ErrConflict
+// @Code(code=20001,http=409)
+var ErrConflict = errors.New("conflict")
+
+var _ = errs.ErrInvalid
+
+type Order struct {
+	ID int64 `json:"id"`
+}
+
+// @ERRORS(ErrConflict)
+type IOrderAPI interface {
+	// @GET(/orders/{id})
+	// @ERRORS(errs.ErrNotFound, errs.ErrInvalid)
+	GetOrder(ctx context.Context, id int64) (Order, error)
+
+	// @DELETE(/orders/{id})
+	DeleteOrder(ctx context.Context, id int64) error
+}
+
+// @SwagBackend(nethttp)
+type IAuditAPI interface {
+	// @GET(/audits/{id})
+	// @ERRORS(github.com/donutnomad/gogen/swaggen/testdata/errors/errs.ErrNotFound)
+	GetAudit(ctx context.Context, id int64) (Order, error)
+}
+
+type IPlainAPI interface {
+	// @GET(/plain)
+	// @ERRORS(errs.ErrPlain)
+	Plain(ctx context.Context) error
+}
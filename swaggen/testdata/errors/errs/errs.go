@@ -0,0 +1,14 @@
+package errs
+
+import "errors"
+
+// ErrNotFound 资源不存在
+// @Code(code=11001,http=404,grpc=NotFound)
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalid
+// @Code(code=11002,http=400)
+var ErrInvalid = errors.New("invalid input")
+
+// ErrPlain 没有错误码注解
+var ErrPlain = errors.New("plain")
@@ -0,0 +1,52 @@
+package guard
+
+import "context"
+
+type Order struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type CreateOrderReq struct {
+	Title string `json:"title"`
+}
+
+// @PREFIX(/api/orders)
+// @PERM(order:read; exclude=Health)
+// @RATELIMIT(100/m, key=ip, exclude=Health)
+type IOrderAPI interface {
+	// @GET(/{id})
+	GetOrder(ctx context.Context, id int64) (Order, error)
+
+	// @POST(/)
+	// @PERM(order:write, order:read)
+	// @RATELIMIT(10/s, key=user)
+	// @RATELIMIT(1000/h, key=user)
+	CreateOrder(ctx context.Context, req CreateOrderReq) (Order, error)
+
+	// @GET(/health)
+	Health(ctx context.Context) (string, error)
+}
+
+// @SwagBackend(nethttp)
+// @PERM(admin; include=Purge)
+type IAdminAPI interface {
+	// @DELETE(/api/admin/cache)
+	Purge(ctx context.Context) error
+
+	// @GET(/api/admin/status)
+	Status(ctx context.Context) (string, error)
+}
+
+// @SwagBackend(echo)
+// @RATELIMIT(5/10s)
+type IEchoAPI interface {
+	// @GET(/api/echo)
+	Echo(ctx context.Context, msg string) (string, error)
+}
+
+type IInvalidRateAPI interface {
+	// @GET(/api/invalid)
+	// @RATELIMIT(10/day)
+	Invalid(ctx context.Context) (string, error)
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"context"
+	"time"
+)
+
+// BaseResponse 通用响应
+type BaseResponse[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+	Data T      `json:"data"`
+}
+
+// UserStatus 用户状态
+type UserStatus string
+
+const (
+	UserStatusActive   UserStatus = "active"
+	UserStatusDisabled UserStatus = "disabled"
+)
+
+// Audit 审计字段
+type Audit struct {
+	CreatedAt time.Time `json:"created_at"`
+	// 最后更新时间
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// UserInfo 用户信息
+type UserInfo struct {
+	Audit
+	ID     int64      `json:"id,string"`
+	Name   string     `json:"name"` // 用户名
+	Status UserStatus `json:"status"`
+	Tags   []string   `json:"tags,omitempty"`
+	secret string
+}
+
+// ListUserReq 查询参数
+type ListUserReq struct {
+	Page    int    `form:"page" binding:"required"`
+	Keyword string `form:"keyword"`
+}
+
+// CreateUserReq 创建用户请求
+type CreateUserReq struct {
+	Name   string     `json:"name" binding:"required"`
+	Status UserStatus `json:"status"`
+}
+
+// UploadReq 表单请求
+type UploadReq struct {
+	Title string `form:"title" binding:"required"`
+}
+
+// @TAG(用户管理)
+// @SECURITY(Bearer; exclude=ListUsers)
+// @HEADER(X-Tenant; required=true; description=租户)
+// @PREFIX(/api/v1)
+// @OPENAPI(openapi.yaml; title=User API; version=2.0.0)
+type IUserAPI interface {
+	// GetUser 获取用户
+	// @GET(/users/{id})
+	GetUser(ctx context.Context, id int64) (BaseResponse[UserInfo], error)
+
+	// ListUsers 用户列表
+	// @GET(/users)
+	ListUsers(ctx context.Context, req ListUserReq) (BaseResponse[[]UserInfo], error)
+
+	// CreateUser 创建用户
+	// @POST(/users)
+	CreateUser(ctx context.Context, req CreateUserReq) (BaseResponse[UserInfo], error)
+
+	// Upload 上传
+	// @POST(/users/{id}/upload)
+	// @FORM-REQ
+	// @SECURITY(ApiKey; type=apiKey; in=header; param=X-API-Key)
+	Upload(ctx context.Context, id int64, req UploadReq) error
+}
@@ -0,0 +1,34 @@
+package routes
+
+import "context"
+
+type Profile struct {
+	Bio string `json:"bio"`
+}
+
+// IProfileAPI 与 IUserAPI 的 /api/user/{id} 在同一位置使用了不同的参数名
+type IProfileAPI interface {
+	// @GET(/api/user/{name}/profile)
+	GetProfile(ctx context.Context, name string) (Profile, error)
+}
+
+// IMemberAPI 与 IUserAPI 的第二个路由重复
+type IMemberAPI interface {
+	// @GET(/api/member/{memberId})
+	GetMember(ctx context.Context, memberId int64) (User, error)
+}
+
+// @SwagBackend(nethttp)
+type IFileAPI interface {
+	// @GET(/files/{dir}/raw)
+	Raw(ctx context.Context, dir string) (Profile, error)
+
+	// @GET(/files/latest/{name})
+	Latest(ctx context.Context, name string) (Profile, error)
+}
+
+// @SwagBackend(nethttp)
+type IDocAPI interface {
+	// @GET(/api/user/{name}/profile)
+	GetDoc(ctx context.Context, name string) (Profile, error)
+}
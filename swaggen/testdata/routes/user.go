@@ -0,0 +1,24 @@
+package routes
+
+import "context"
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// @PREFIX(/api)
+// @TAG(用户)
+type IUserAPI interface {
+	// @GET(/user/{id})
+	// @GET(/member/{id})
+	// @MID(Auth Audit)
+	GetUser(ctx context.Context, id int64) (User, error)
+
+	// @GET(/user/me)
+	Me(ctx context.Context) (User, error)
+
+	// @DELETE(/user/{id})
+	// @Removed
+	DeleteUser(ctx context.Context, id int64) error
+}
@@ -0,0 +1,17 @@
+package v2
+
+import "context"
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// @PREFIX(/api)
+type IUserAPI interface {
+	// @GET(/user/{name})
+	GetUser(ctx context.Context, name string) (User, error)
+
+	// @GET(/user/me)
+	Me(ctx context.Context) (User, error)
+}
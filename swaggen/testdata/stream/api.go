@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"iter"
+	"mime/multipart"
+)
+
+type Tick struct {
+	N int `json:"n"`
+}
+
+type UploadReq struct {
+	Title string `form:"title"`
+}
+
+// @CLIENT
+// @SwagBackend(nethttp)
+// @PREFIX(/api)
+type IStreamAPI interface {
+	// @GET(/ticks)
+	Ticks(ctx context.Context, count int) (iter.Seq[Tick], error)
+
+	// @GET(/logs)
+	Logs(ctx context.Context) (<-chan string, error)
+
+	// @GET(/files/{name})
+	// @MIME(png)
+	Download(ctx context.Context, name string) (io.ReadCloser, string, error)
+
+	// @POST(/avatar)
+	UploadAvatar(
+		ctx context.Context,
+		// @FILE(required)
+		avatar *multipart.FileHeader,
+	) error
+
+	// @POST(/docs)
+	UploadDocs(
+		ctx context.Context,
+		req UploadReq,
+		// @FILE
+		docs []*multipart.FileHeader,
+		// @FILE(required=false)
+		cover *multipart.FileHeader,
+	) (int, error)
+}
+
+// @SwagBackend(nethttp)
+type IBadSeqAPI interface {
+	// @GET(/ticks)
+	Ticks(ctx context.Context) iter.Seq[Tick]
+}
+
+// @SwagBackend(nethttp)
+type IBadFileAPI interface {
+	// @GET(/avatar)
+	Avatar(
+		ctx context.Context,
+		// @FILE
+		avatar *multipart.FileHeader,
+	) error
+}
+
+// @SwagBackend(nethttp)
+type IBadFileRuleAPI interface {
+	// @POST(/avatar)
+	Avatar(
+		ctx context.Context,
+		// @FILE(max=10)
+		avatar *multipart.FileHeader,
+	) error
+}
@@ -0,0 +1,117 @@
+package typescript
+
+import (
+	"context"
+	"io"
+	"iter"
+	"mime/multipart"
+	"time"
+
+	"github.com/donutnomad/gogen/swaggen/testdata/typescript/models"
+)
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusDisabled Status = "disabled"
+)
+
+type BaseResponse[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+	Data T      `json:"data"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type User struct {
+	Timestamps
+	// 用户 ID
+	ID      int64             `json:"id,string"`
+	Name    string            `json:"name"`
+	Status  Status            `json:"status"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]any    `json:"attrs,omitempty"`
+	Manager *User             `json:"manager"`
+	Account models.User       `json:"account"`
+	Avatar  []byte            `json:"avatar"`
+	Secret  string            `json:"-"`
+	Extra   struct{ N int }   `json:"extra"`
+	Labels  map[string]Status `json:"labels"`
+}
+
+type ListReq struct {
+	Page    int      `form:"page" binding:"required"`
+	Keyword string   `form:"keyword"`
+	Status  []Status `form:"status"`
+}
+
+type CreateReq struct {
+	Name string `json:"name"`
+}
+
+type FormReq struct {
+	Title string `form:"title"`
+}
+
+type Event struct {
+	N int `json:"n"`
+}
+
+// @TS(api.ts)
+// @PREFIX(/api)
+type IUserAPI interface {
+	// 获取用户
+	// @GET(/users/{id})
+	GetUser(
+		ctx context.Context,
+		// @PARAM
+		id int64,
+		// @HEADER(X-Tenant)
+		tenant string,
+		// @QUERY(with)
+		with string,
+	) (BaseResponse[User], error)
+
+	// @GET(/users)
+	ListUsers(ctx context.Context, req ListReq) (BaseResponse[[]User], error)
+
+	// @POST(/users)
+	CreateUser(ctx context.Context, req CreateReq) (*User, error)
+
+	// @PUT(/users/{id}/form)
+	// @FORM-REQ
+	UpdateForm(ctx context.Context, id int64, req FormReq) error
+
+	// @POST(/users/{id}/avatar)
+	UploadAvatar(
+		ctx context.Context,
+		id int64,
+		req FormReq,
+		// @FILE
+		file *multipart.FileHeader,
+		// @FILE(required=false)
+		extra []*multipart.FileHeader,
+	) error
+
+	// @GET(/users/events)
+	Events(ctx context.Context) (iter.Seq[Event], error)
+
+	// @GET(/users/logs)
+	Logs(ctx context.Context) (<-chan string, error)
+
+	// @GET(/users/{id}/export)
+	Export(ctx context.Context, id int64) (io.ReadCloser, string, error)
+
+	// @GET(/users/{id}/name)
+	// @MIME(plain)
+	Name(ctx context.Context, id int64) (string, error)
+
+	// @DELETE(/users/{id})
+	// @Removed
+	DeleteUser(ctx context.Context, id int64) error
+}
@@ -0,0 +1,6 @@
+package models
+
+// User 与 typescript 包中的 User 同名
+type User struct {
+	Email string `json:"email"`
+}
@@ -0,0 +1,58 @@
+package validation
+
+import "context"
+
+type Order struct {
+	ID int64 `json:"id"`
+}
+
+type CreateOrderReq struct {
+	Name     string   `json:"name" binding:"required,min=2,max=8"`
+	Quantity int      `json:"quantity" validate:"min=1,max=100"`
+	Status   string   `json:"status" validate:"omitempty,oneof=draft paid"`
+	Tags     []string `json:"tags" validate:"max=2,dive,min=1"`
+	Note     *string  `json:"note" validate:"required"`
+}
+
+type ListReq struct {
+	Page int `form:"page" binding:"min=1"`
+}
+
+// @OPENAPI(openapi.json)
+type IOrderAPI interface {
+	// @GET(/orders/{id})
+	GetOrder(ctx context.Context,
+		// @PARAM(min=1; max=1000)
+		id int64,
+		// @QUERY(expand; enum=items,customer)
+		expand string,
+		// @QUERY(required; min=1)
+		limit uint,
+		// @HEADER(X-Tenant; pattern=^[a-z]{2,8}$)
+		tenant string,
+	) (Order, error)
+
+	// @POST(/orders)
+	// @JSON-REQ
+	CreateOrder(ctx context.Context, req CreateOrderReq) (Order, error)
+
+	// @GET(/orders)
+	ListOrders(ctx context.Context, req ListReq) ([]Order, error)
+}
+
+// @SwagBackend(nethttp)
+type IPlainAPI interface {
+	// @GET(/plain/{id})
+	GetPlain(ctx context.Context, id int64,
+		// @QUERY
+		q string,
+	) (Order, error)
+}
+
+type IBadRuleAPI interface {
+	// @GET(/bad/{id})
+	GetBad(ctx context.Context,
+		// @PARAM(pattern=^[0-9]+$)
+		id int64,
+	) (Order, error)
+}
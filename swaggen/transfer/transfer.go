@@ -0,0 +1,303 @@
+// Package transfer 为 swaggen 生成的处理器与客户端提供流式响应与文件传输
+//
+// 服务端：返回 iter.Seq[T] / <-chan T 的方法经 SSE / SSEChan 以 Server-Sent Events 输出，
+// 返回 (io.Reader, 文件名, error) 的方法经 Download 以附件输出，@FILE 参数经 FormFile / FormFiles 读取；
+// 客户端：ReadSSE / ReadSSEChan 解析事件流，Filename 解析附件文件名，EncodeMultipart 上传文件。
+// 各函数只依赖 net/http，gin、chi、echo 均通过其底层的 http.ResponseWriter / *http.Request 调用。
+//
+// 生成代码不导入本包：swaggen 将本文件改写为包内声明（SSE -> transferSSE），每个包生成一次。
+// 本文件因此只能依赖标准库，包级标识符在改写时统一加前缀；直接使用本包（如 NewFileHeader）是可选的。
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+)
+
+// MaxMemory 解析 multipart 表单时保存在内存中的上限，超出部分写入临时文件
+var MaxMemory int64 = 32 << 20
+
+// ============================================================================
+// Server-Sent Events
+// ============================================================================
+
+// SSE 将 seq 中的值逐条写为 Server-Sent Events 并立即 Flush；
+// string / []byte 原样作为 data，其余值编码为 JSON。客户端断开或写入失败时停止迭代，
+// 生产者阻塞等待数据时应同时监听请求的 context（即传给方法的 ctx）
+func SSE[T any](w http.ResponseWriter, r *http.Request, seq iter.Seq[T]) {
+	flush := startSSE(w)
+	for v := range seq {
+		if r.Context().Err() != nil || writeEvent(w, v) != nil {
+			return
+		}
+		flush()
+	}
+}
+
+// SSEChan 同 SSE，ch 关闭或客户端断开时结束
+func SSEChan[T any](w http.ResponseWriter, r *http.Request, ch <-chan T) {
+	flush := startSSE(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case v, ok := <-ch:
+			if !ok || writeEvent(w, v) != nil {
+				return
+			}
+			flush()
+		}
+	}
+}
+
+// startSSE 写入事件流响应头，返回 Flush 函数
+func startSSE(w http.ResponseWriter) func() {
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	flush := func() { _ = rc.Flush() }
+	flush()
+	return flush
+}
+
+// writeEvent 写入一条事件，多行数据拆分为多个 data 字段
+func writeEvent(w io.Writer, v any) error {
+	var data []byte
+	switch v := v.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadSSE 将事件流响应体解析为 iter.Seq[T]，只能迭代一次；
+// 迭代结束、提前停止或解码失败时关闭 body
+func ReadSSE[T any](body io.ReadCloser) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		defer body.Close()
+		for data := range readEvents(body) {
+			v, err := decodeEvent[T](data)
+			if err != nil || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ReadSSEChan 同 ReadSSE，在后台读取事件并写入返回的通道；流结束或 ctx 结束时关闭通道
+func ReadSSEChan[T any](ctx context.Context, body io.ReadCloser) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for v := range ReadSSE[T](body) {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// readEvents 按行读取事件流，返回每条事件的 data（多个 data 字段以换行连接），忽略注释与其他字段
+func readEvents(r io.Reader) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		var data [][]byte
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				if data != nil && !yield(bytes.Join(data, []byte("\n"))) {
+					return
+				}
+				data = nil
+				continue
+			}
+			if value, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+				value, _ = bytes.CutPrefix(value, []byte(" "))
+				data = append(data, bytes.Clone(value))
+			}
+		}
+		if data != nil {
+			yield(bytes.Join(data, []byte("\n")))
+		}
+	}
+}
+
+// decodeEvent 按 writeEvent 的规则解码 data
+func decodeEvent[T any](data []byte) (T, error) {
+	var v T
+	switch p := any(&v).(type) {
+	case *string:
+		*p = string(data)
+	case *[]byte:
+		*p = data
+	default:
+		return v, json.Unmarshal(data, &v)
+	}
+	return v, nil
+}
+
+// ============================================================================
+// 文件下载
+// ============================================================================
+
+// Download 以附件形式输出 body：filename 非空时写入 Content-Disposition，contentType 为空时为
+// application/octet-stream；body 实现 io.Closer 时输出后关闭
+func Download(w http.ResponseWriter, body io.Reader, filename, contentType string) {
+	if c, ok := body.(io.Closer); ok {
+		defer c.Close()
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	disposition := "attachment"
+	if filename != "" {
+		disposition = mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	}
+	w.Header().Set("Content-Disposition", disposition)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, body)
+}
+
+// Filename 解析响应头 Content-Disposition 中的文件名，没有时返回空字符串
+func Filename(h http.Header) string {
+	_, params, err := mime.ParseMediaType(h.Get("Content-Disposition"))
+	if err != nil {
+		return ""
+	}
+	return params["filename"]
+}
+
+// ============================================================================
+// 文件上传
+// ============================================================================
+
+// FormFile 读取 multipart 表单中名为 name 的第一个文件；未上传时返回 nil, nil，请求体无法解析时返回错误
+func FormFile(r *http.Request, name string) (*multipart.FileHeader, error) {
+	files, err := FormFiles(r, name)
+	if len(files) == 0 {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// FormFiles 读取 multipart 表单中名为 name 的全部文件；未上传时返回空切片，请求体无法解析时返回错误
+func FormFiles(r *http.Request, name string) ([]*multipart.FileHeader, error) {
+	if r.MultipartForm == nil {
+		if err := r.ParseMultipartForm(MaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, err
+		}
+	}
+	if r.MultipartForm == nil {
+		return nil, nil
+	}
+	return r.MultipartForm.File[name], nil
+}
+
+// NewFileHeader 由内存中的内容构造 *multipart.FileHeader，用于通过生成的客户端上传文件
+func NewFileHeader(filename string, content []byte, contentType string) (*multipart.FileHeader, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreatePart(fileHeader("file", filename, contentType))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(MaxMemory)
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}
+
+// EncodeMultipart 将表单字段与文件编码为 multipart/form-data，返回请求体与 Content-Type，
+// 文件内容经 FileHeader.Open 读取，保留原有的文件名与 Content-Type
+func EncodeMultipart(form url.Values, files map[string][]*multipart.FileHeader) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for key, values := range form {
+		for _, value := range values {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for key, list := range files {
+		for _, fh := range list {
+			if fh == nil {
+				continue
+			}
+			if err := writeFile(writer, key, fh); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// writeFile 写入一个文件部分
+func writeFile(writer *multipart.Writer, field string, fh *multipart.FileHeader) error {
+	part, err := writer.CreatePart(fileHeader(field, fh.Filename, fh.Header.Get("Content-Type")))
+	if err != nil {
+		return err
+	}
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(part, src)
+	return err
+}
+
+// fileHeader multipart 文件部分的头
+func fileHeader(field, filename, contentType string) textproto.MIMEHeader {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": field, "filename": filename}))
+	h.Set("Content-Type", contentType)
+	return h
+}
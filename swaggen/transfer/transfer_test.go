@@ -0,0 +1,168 @@
+package transfer
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type event struct {
+	N    int    `json:"n"`
+	Text string `json:"text"`
+}
+
+func TestSSERoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SSE(w, r, slices.Values([]event{{1, "a"}, {2, "b\nc"}}))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	got := slices.Collect(ReadSSE[event](resp.Body))
+	want := []event{{1, "a"}, {2, "b\nc"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestSSEStringData(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SSE(rec, httptest.NewRequest("GET", "/", nil), slices.Values([]string{"hello", "a\nb"}))
+	if want := "data: hello\n\ndata: a\ndata: b\n\n"; rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+	if !rec.Flushed {
+		t.Error("events were not flushed")
+	}
+	got := slices.Collect(ReadSSE[string](io.NopCloser(rec.Body)))
+	if !slices.Equal(got, []string{"hello", "a\nb"}) {
+		t.Errorf("events = %q", got)
+	}
+}
+
+func TestSSEChanStopsOnDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	ch := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		SSEChan(httptest.NewRecorder(), req, ch)
+		close(done)
+	}()
+	ch <- 1
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SSEChan did not return after the client disconnected")
+	}
+}
+
+func TestSSEStopsSequence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	var produced int
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			produced++
+			if i == 1 {
+				cancel()
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	SSE(httptest.NewRecorder(), req, seq)
+	if produced != 2 {
+		t.Errorf("produced %d values, want 2", produced)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Download(rec, io.NopCloser(strings.NewReader("a,b")), "报表.csv", "text/csv")
+	if got := rec.Header().Get("Content-Type"); got != "text/csv" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := Filename(rec.Header()); got != "报表.csv" {
+		t.Errorf("Filename = %q, header %q", got, rec.Header().Get("Content-Disposition"))
+	}
+	if rec.Body.String() != "a,b" {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	Download(rec, strings.NewReader(""), "", "")
+	if got := rec.Header().Get("Content-Disposition"); got != "attachment" {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestUploadRoundTrip(t *testing.T) {
+	avatar, err := NewFileHeader("a.png", []byte("png"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewFileHeader("b.txt", []byte("text"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, contentType, err := EncodeMultipart(url.Values{"title": {"hi"}}, map[string][]*multipart.FileHeader{
+		"avatar": {avatar},
+		"docs":   {doc, doc},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", contentType)
+
+	fh, err := FormFile(req, "avatar")
+	if err != nil || fh == nil {
+		t.Fatalf("FormFile = %v, %v", fh, err)
+	}
+	if fh.Filename != "a.png" || fh.Header.Get("Content-Type") != "image/png" || fh.Size != 3 {
+		t.Errorf("avatar = %q %q %d", fh.Filename, fh.Header.Get("Content-Type"), fh.Size)
+	}
+	docs, err := FormFiles(req, "docs")
+	if err != nil || len(docs) != 2 {
+		t.Fatalf("FormFiles = %v, %v", docs, err)
+	}
+	if req.FormValue("title") != "hi" {
+		t.Errorf("title = %q", req.FormValue("title"))
+	}
+	if fh, err := FormFile(req, "missing"); fh != nil || err != nil {
+		t.Errorf("missing file = %v, %v", fh, err)
+	}
+}
+
+func TestFormFileNotMultipart(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	if fh, err := FormFile(req, "avatar"); fh != nil || err != nil {
+		t.Errorf("FormFile = %v, %v", fh, err)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("garbage"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	if _, err := FormFile(req, "avatar"); err == nil {
+		t.Error("expected an error for a malformed multipart body")
+	}
+}
@@ -3,6 +3,7 @@ package swaggen
 import (
 	"fmt"
 	"go/token"
+	"strings"
 
 	"github.com/donutnomad/gogen/internal/xast"
 	parsers "github.com/donutnomad/gogen/swaggen/parser"
@@ -14,6 +15,7 @@ import (
 
 const (
 	ParamSourcePath = "path"
+	ParamSourceFile = "file"
 )
 
 const (
@@ -66,6 +68,18 @@ type Parameter struct {
 	Source   string   // path,header,query
 	Required bool     // 是否必需
 	Comment  string   // 参数注释
+	Rules    Rules    // 参数注解声明的校验规则
+}
+
+// ExternalName 请求中的参数名：路径参数名、@QUERY / @HEADER 指定的名称，默认为参数名
+func (p Parameter) ExternalName() string {
+	if p.PathName != "" {
+		return p.PathName
+	}
+	if p.Alias != "" {
+		return p.Alias
+	}
+	return p.Name
 }
 
 // SwaggerMethod 表示 Swagger 方法
@@ -77,6 +91,20 @@ type SwaggerMethod struct {
 	Description  string      // 描述
 	RawComments  []string    // 方法原始注释行
 	Def          DefSlice
+	Response     ResponseMode // 成功响应的输出方式，SSE 时 ResponseType 为事件元素类型
+
+	// FieldRules 请求体 / 查询结构体中声明了 binding / validate 规则的字段；生成代码前解析
+	FieldRules []FieldRule
+}
+
+// BodyIndex 请求体（GET 为查询结构体）参数的位置：最后一个非 @FILE 参数，没有时为 -1
+func (s SwaggerMethod) BodyIndex() int {
+	for i := len(s.Parameters) - 1; i >= 0; i-- {
+		if s.Parameters[i].Source != ParamSourceFile {
+			return i
+		}
+	}
+	return -1
 }
 
 func (s SwaggerMethod) GetPaths() []string {
@@ -132,6 +160,41 @@ func (s DefSlice) GetPrefix() string {
 	return ""
 }
 
+// GetOpenAPI 返回 @OPENAPI 定义，未定义时为 nil
+func (s DefSlice) GetOpenAPI() *parsers.OpenAPI {
+	for _, item := range s {
+		if v, ok := item.(*parsers.OpenAPI); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetTS 返回 @TS 定义，未定义时为 nil
+func (s DefSlice) GetTS() *parsers.TS {
+	for _, item := range s {
+		if v, ok := item.(*parsers.TS); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetBackend 返回 @SwagBackend 指定的后端名称，未指定时为空
+func (s DefSlice) GetBackend() string {
+	for _, item := range s {
+		if v, ok := item.(*parsers.SwagBackend); ok {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// HasClient 是否声明了 @CLIENT
+func (s DefSlice) HasClient() bool {
+	return FindDef[*parsers.Client](s)
+}
+
 func (s DefSlice) IsRemoved() bool {
 	return FindDef[*parsers.Removed](s)
 }
@@ -203,7 +266,11 @@ type SwaggerInterface struct {
 	Comments    []string             // 接口注释
 	RawComments []string             // 接口原始注释行
 	Imports     xast.ImportInfoSlice // 导入信息
+	FilePath    string               // 接口所在源文件
 	CommonDef   DefSlice
+
+	// ErrorRegistries @ERRORS 引用的错误所在包（codegen @Code 注册表），与接口同包时 Path 为空；生成代码前解析
+	ErrorRegistries []xast.ImportInfo
 }
 
 func (w SwaggerInterface) GetWrapperName() string {
@@ -214,6 +281,36 @@ func (w SwaggerInterface) GetWrapperName() string {
 	return n
 }
 
+// GetClientName 返回接口对应的客户端名称，如 IUserAPI -> UserAPIClient
+func (w SwaggerInterface) GetClientName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "Client"
+}
+
+// GetFieldErrorName 返回参数校验失败字段的类型名称，如 IUserAPI -> UserAPIFieldError
+func (w SwaggerInterface) GetFieldErrorName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "FieldError"
+}
+
+// GetRouteName 返回路由表条目的类型名称，如 IUserAPI -> UserAPIRoute
+func (w SwaggerInterface) GetRouteName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "Route"
+}
+
+// GetAuthorizerName 返回权限检查接口的名称，如 IUserAPI -> UserAPIAuthorizer
+func (w SwaggerInterface) GetAuthorizerName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "Authorizer"
+}
+
+// GetLimiterName 返回限流接口的名称，如 IUserAPI -> UserAPILimiter
+func (w SwaggerInterface) GetLimiterName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "Limiter"
+}
+
+// GetPermissionName 返回权限目录条目的类型名称，如 IUserAPI -> UserAPIPermission
+func (w SwaggerInterface) GetPermissionName() string {
+	return strings.TrimSuffix(w.GetWrapperName(), "Wrap") + "Permission"
+}
+
 // InterfaceCollection 表示接口集合
 type InterfaceCollection struct {
 	Interfaces []SwaggerInterface // 接口列表
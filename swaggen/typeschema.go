@@ -0,0 +1,510 @@
+package swaggen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/tools/go/packages"
+)
+
+// ============================================================================
+// 类型加载
+// ============================================================================
+
+// TypeLoader 按目录加载接口所在包的类型信息（go/types），同一目录只加载一次
+type TypeLoader struct {
+	pkgs map[string]*packages.Package
+}
+
+// NewTypeLoader 创建类型加载器
+func NewTypeLoader() *TypeLoader {
+	return &TypeLoader{pkgs: make(map[string]*packages.Package)}
+}
+
+// Load 加载源文件所在目录的包；包内存在类型错误时仍返回已检查出的类型信息
+func (l *TypeLoader) Load(filePath string) (*packages.Package, error) {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+	if pkg, ok := l.pkgs[dir]; ok {
+		return pkg, nil
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("加载包 %s 失败: %w", dir, err)
+	}
+	if len(pkgs) == 0 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("加载包 %s 失败: 未找到包", dir)
+	}
+	l.pkgs[dir] = pkgs[0]
+	return pkgs[0], nil
+}
+
+// Signature 返回接口方法的类型签名
+func (l *TypeLoader) Signature(iface SwaggerInterface, method string) (*types.Signature, error) {
+	pkg, err := l.Load(iface.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	obj := pkg.Types.Scope().Lookup(iface.Name)
+	if obj == nil {
+		return nil, fmt.Errorf("包 %s 中未找到接口 %s", pkg.PkgPath, iface.Name)
+	}
+	it, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s 不是接口", iface.Name)
+	}
+	for i := 0; i < it.NumMethods(); i++ {
+		if fn := it.Method(i); fn.Name() == method {
+			return fn.Type().(*types.Signature), nil
+		}
+	}
+	return nil, fmt.Errorf("接口 %s 中未找到方法 %s", iface.Name, method)
+}
+
+// fieldDocs 收集已加载包中结构体字段的注释，key 为字段名标识符的位置
+func (l *TypeLoader) fieldDocs() map[token.Pos]string {
+	docs := make(map[token.Pos]string)
+	for _, pkg := range l.pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				st, ok := n.(*ast.StructType)
+				if !ok {
+					return true
+				}
+				for _, field := range st.Fields.List {
+					text := ""
+					if field.Doc != nil {
+						text = field.Doc.Text()
+					} else if field.Comment != nil {
+						text = field.Comment.Text()
+					}
+					if text = strings.TrimSpace(text); text == "" {
+						continue
+					}
+					for _, name := range field.Names {
+						docs[name.Pos()] = text
+					}
+				}
+				return true
+			})
+		}
+	}
+	return docs
+}
+
+// ============================================================================
+// JSON Schema
+// ============================================================================
+
+// Schema JSON Schema（OpenAPI 3.1 方言）
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	ContentMediaType     string             `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum                 []any              `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// order 属性的定义顺序（JSON/YAML 输出按键名排序，生成其他语言的类型时按此顺序）
+	order []string
+}
+
+// PropertyNames 按定义顺序返回属性名
+func (s *Schema) PropertyNames() []string {
+	return s.order
+}
+
+// setProperty 添加属性，同名属性由 structFields 预先消解
+func (s *Schema) setProperty(name string, prop *Schema, required bool) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+	s.Properties[name] = prop
+	s.order = append(s.order, name)
+	if required {
+		s.Required = append(s.Required, name)
+	}
+}
+
+// SchemaBuilder 从 Go 类型推导 JSON Schema，具名结构体收集为可复用的组件
+type SchemaBuilder struct {
+	Components map[string]*Schema // 组件名 -> Schema
+	docs       map[token.Pos]string
+	building   map[string]bool
+}
+
+// NewSchemaBuilder 创建 Schema 构建器，docs 为字段注释（可为 nil）
+func NewSchemaBuilder(docs map[token.Pos]string) *SchemaBuilder {
+	return &SchemaBuilder{
+		Components: make(map[string]*Schema),
+		docs:       docs,
+		building:   make(map[string]bool),
+	}
+}
+
+// Schema 返回类型的 Schema；具名结构体返回对组件的 $ref
+func (b *SchemaBuilder) Schema(t types.Type) *Schema {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return b.Schema(t.Elem())
+	case *types.Alias:
+		return b.Schema(types.Unalias(t))
+	case *types.Named:
+		return b.namedSchema(t)
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.Schema(t.Elem())}
+	case *types.Array:
+		return &Schema{Type: "array", Items: b.Schema(t.Elem())}
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.Schema(t.Elem())}
+	case *types.Struct:
+		return b.structSchema(t)
+	}
+	// interface{}、函数、通道等：任意值
+	return &Schema{}
+}
+
+// namedSchema 处理具名类型：特殊类型、枚举与结构体组件
+func (b *SchemaBuilder) namedSchema(t *types.Named) *Schema {
+	obj := t.Obj()
+	if obj.Pkg() != nil {
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &Schema{Type: "integer", Format: "int64"}
+		case "encoding/json.RawMessage":
+			return &Schema{}
+		case "mime/multipart.FileHeader":
+			return binarySchema()
+		}
+	}
+	if hasMethod(t, "MarshalText") {
+		return &Schema{Type: "string"}
+	}
+	if hasMethod(t, "MarshalJSON") {
+		return &Schema{}
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		schema := b.Schema(t.Underlying())
+		if enum := enumValues(t); len(enum) > 0 {
+			copied := *schema
+			copied.Enum = enum
+			return &copied
+		}
+		return schema
+	}
+
+	name := componentName(t)
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := b.Components[name]; ok || b.building[name] {
+		return ref
+	}
+	b.building[name] = true
+	b.Components[name] = b.structSchema(st)
+	delete(b.building, name)
+	return ref
+}
+
+// structSchema 按 encoding/json 的规则展开结构体字段
+func (b *SchemaBuilder) structSchema(st *types.Struct) *Schema {
+	schema := &Schema{Type: "object"}
+	b.addFields(schema, st, "json")
+	return schema
+}
+
+// addFields 将结构体字段加入 schema；tagKey 为字段名所用的标签（json 或 form）
+// 必填由 binding / validate 标签中的 required 决定，嵌入且未命名的结构体字段被展开
+func (b *SchemaBuilder) addFields(schema *Schema, st *types.Struct, tagKey string) {
+	for _, f := range structFields(st, tagKey, embeddedStruct) {
+		prop := b.Schema(f.field.Type())
+		if slices.Contains(f.opts, "string") && isScalar(prop) {
+			prop = &Schema{Type: "string"}
+		}
+		if doc := b.docs[f.field.Pos()]; doc != "" {
+			if prop.Ref != "" {
+				prop = &Schema{Ref: prop.Ref}
+			} else {
+				copied := *prop
+				prop = &copied
+			}
+			prop.Description = doc
+		}
+		rules, _ := tagRules(f.tag)
+		prop = applyRules(prop, rules)
+		schema.setProperty(f.name, prop, tagRequired(f.tag))
+	}
+}
+
+// structField 结构体展开后的一个可见字段
+type structField struct {
+	field  *types.Var
+	tag    reflect.StructTag
+	name   string   // 标签中的名称，未指定时为字段名
+	opts   []string // 标签选项
+	path   string   // 从外层结构体开始的字段选择路径，如 Base.ID
+	depth  int      // 嵌入层级，外层结构体的字段为 0
+	tagged bool     // 名称是否来自标签
+}
+
+// structFields 按 encoding/json 的规则展开结构体字段，tagKey 为字段名所用的标签（json 或 form）：
+// 标签为 "-" 与未导出的字段跳过，expand 返回 true 的字段被展开；同名字段取嵌入层级最浅的一个，
+// 同一层级有多个时取带标签的一个，仍无法区分则全部忽略
+func structFields(st *types.Struct, tagKey string, expand func(field *types.Var, name string) bool) []structField {
+	var all []structField
+	walkFields(st, tagKey, expand, "", 0, map[*types.Struct]bool{}, &all)
+
+	byName := make(map[string][]structField)
+	for _, f := range all {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	var out []structField
+	for _, f := range all {
+		if dominant, ok := dominantField(byName[f.name]); ok && dominant.path == f.path {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// walkFields 深度优先收集字段，visiting 防止嵌入结构体循环展开
+func walkFields(st *types.Struct, tagKey string, expand func(field *types.Var, name string) bool, prefix string, depth int, visiting map[*types.Struct]bool, out *[]structField) {
+	if visiting[st] {
+		return
+	}
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, opts := parseTagName(tag.Get(tagKey))
+		if name == "-" && len(opts) == 0 || !field.Exported() && !field.Embedded() {
+			continue
+		}
+		if expand(field, name) {
+			inner := derefType(field.Type()).Underlying().(*types.Struct)
+			walkFields(inner, tagKey, expand, prefix+field.Name()+".", depth+1, visiting, out)
+			continue
+		}
+		if !field.Exported() {
+			continue
+		}
+		*out = append(*out, structField{
+			field:  field,
+			tag:    tag,
+			name:   lo.Ternary(name == "", field.Name(), name),
+			opts:   opts,
+			path:   prefix + field.Name(),
+			depth:  depth,
+			tagged: name != "",
+		})
+	}
+}
+
+// dominantField 同名字段中生效的一个：层级最浅者，同层级多个时取唯一带标签的一个
+func dominantField(fields []structField) (structField, bool) {
+	minDepth := slices.MinFunc(fields, func(a, b structField) int { return a.depth - b.depth }).depth
+	fields = lo.Filter(fields, func(f structField, _ int) bool { return f.depth == minDepth })
+	if len(fields) > 1 {
+		fields = lo.Filter(fields, func(f structField, _ int) bool { return f.tagged })
+	}
+	if len(fields) != 1 {
+		return structField{}, false
+	}
+	return fields[0], true
+}
+
+// embeddedStruct 嵌入且标签未命名的结构体字段按 encoding/json 的规则展开
+func embeddedStruct(field *types.Var, name string) bool {
+	_, ok := derefType(field.Type()).Underlying().(*types.Struct)
+	return ok && field.Embedded() && name == ""
+}
+
+// FormSchema 表单请求体的 Schema：字段名取自 form 标签，不生成组件
+func (b *SchemaBuilder) FormSchema(t types.Type) *Schema {
+	st, ok := derefType(t).Underlying().(*types.Struct)
+	if !ok {
+		return b.Schema(t)
+	}
+	schema := &Schema{Type: "object"}
+	b.addFields(schema, st, "form")
+	return schema
+}
+
+// QueryFields 将查询参数结构体展开为字段列表（字段名取自 form 标签，与 gin 的 ShouldBindQuery 一致）
+func (b *SchemaBuilder) QueryFields(t types.Type) (names []string, schemas []*Schema, required []bool) {
+	schema := b.FormSchema(t)
+	for _, name := range schema.PropertyNames() {
+		names = append(names, name)
+		schemas = append(schemas, schema.Properties[name])
+		required = append(required, slices.Contains(schema.Required, name))
+	}
+	return names, schemas, required
+}
+
+// basicSchema 基础类型
+func basicSchema(t *types.Basic) *Schema {
+	switch t.Kind() {
+	case types.Bool, types.UntypedBool:
+		return &Schema{Type: "boolean"}
+	case types.Int, types.Int64, types.Uint, types.Uint64, types.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.Uint32, types.UntypedInt, types.UntypedRune:
+		return &Schema{Type: "integer", Format: "int32"}
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case types.Float64, types.UntypedFloat:
+		return &Schema{Type: "number", Format: "double"}
+	case types.String, types.UntypedString:
+		return &Schema{Type: "string"}
+	}
+	return &Schema{}
+}
+
+// componentName 组件名：包名.类型名，泛型实参以 - 连接，如 example.BaseResponse-example_UserInfo
+func componentName(t *types.Named) string {
+	obj := t.Obj()
+	name := obj.Name()
+	if obj.Pkg() != nil {
+		name = obj.Pkg().Name() + "." + name
+	}
+	if args := t.TypeArgs(); args != nil && args.Len() > 0 {
+		var parts []string
+		for i := 0; i < args.Len(); i++ {
+			parts = append(parts, typeArgName(args.At(i)))
+		}
+		name += "-" + strings.Join(parts, "-")
+	}
+	return name
+}
+
+// typeArgName 泛型实参在组件名中的写法
+func typeArgName(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return typeArgName(t.Elem())
+	case *types.Slice:
+		return "array_" + typeArgName(t.Elem())
+	case *types.Map:
+		return "map_" + typeArgName(t.Key()) + "_" + typeArgName(t.Elem())
+	case *types.Named:
+		return strings.ReplaceAll(componentName(t), ".", "_")
+	case *types.Alias:
+		return typeArgName(types.Unalias(t))
+	}
+	return strings.ReplaceAll(t.String(), " ", "")
+}
+
+// enumValues 收集与具名类型同包、同类型的常量作为枚举值（按声明位置排序）
+func enumValues(t *types.Named) []any {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		return nil
+	}
+	scope := obj.Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && c.Exported() && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(a, b *types.Const) int { return int(a.Pos()) - int(b.Pos()) })
+
+	var values []any
+	for _, c := range consts {
+		switch v := c.Val(); v.Kind() {
+		case constant.String:
+			values = append(values, constant.StringVal(v))
+		case constant.Int:
+			if n, ok := constant.Int64Val(v); ok {
+				values = append(values, n)
+			}
+		case constant.Float:
+			f, _ := constant.Float64Val(v)
+			values = append(values, f)
+		case constant.Bool:
+			values = append(values, constant.BoolVal(v))
+		}
+	}
+	return values
+}
+
+// parseTagName 解析 json/form 标签的名称与选项
+func parseTagName(tag string) (string, []string) {
+	if tag == "" {
+		return "", nil
+	}
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// tagRequired binding / validate 标签是否包含 required
+func tagRequired(tag reflect.StructTag) bool {
+	for _, key := range []string{"binding", "validate"} {
+		if slices.Contains(strings.Split(tag.Get(key), ","), "required") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMethod 类型（或其指针）是否有指定方法
+func hasMethod(t types.Type, name string) bool {
+	for _, typ := range []types.Type{t, types.NewPointer(t)} {
+		if sel := types.NewMethodSet(typ).Lookup(nil, name); sel != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+func isByte(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+func isScalar(s *Schema) bool {
+	return s.Type == "integer" || s.Type == "number" || s.Type == "boolean"
+}
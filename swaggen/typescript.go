@@ -0,0 +1,794 @@
+package swaggen
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// TypeScript 类型定义与 fetch 客户端生成器
+// ============================================================================
+
+// TSGenerator 为声明了 @TS 的接口生成 TypeScript 类型定义与基于 fetch 的客户端
+// 类型由 go/types 推导：字段名取自 json 标签，指针与 omitempty 字段为可选，泛型保留为 TypeScript 泛型，
+// time.Time 为 string；参数的位置（路径 / 查询 / 请求头 / 请求体）与 Gin 绑定代码一致
+type TSGenerator struct {
+	loader *TypeLoader
+	docs   map[token.Pos]string
+	names  map[*types.TypeName]string // Go 类型 -> TypeScript 名称
+	forms  map[*types.TypeName]string // 查询 / 表单结构体 -> TypeScript 名称
+	taken  map[string]bool
+	decls  []string // 类型声明，按首次引用的顺序
+
+	// 客户端用到的辅助函数
+	json, form, multipart, sse, download bool
+}
+
+// NewTSGenerator 创建 TypeScript 生成器
+func NewTSGenerator(loader *TypeLoader) *TSGenerator {
+	return &TSGenerator{
+		loader: loader,
+		names:  make(map[*types.TypeName]string),
+		forms:  make(map[*types.TypeName]string),
+		taken:  lo.SliceToMap(tsRuntimeNames, func(name string) (string, bool) { return name, true }),
+	}
+}
+
+// tsRuntimeNames 生成文件中运行时辅助代码声明的名称，Go 类型重名时加包名前缀
+var tsRuntimeNames = []string{"ClientOptions", "RequestOptions", "ApiError", "ApiRequest", "DownloadResult", "Values"}
+
+// tsReservedNames TypeScript 保留字及生成代码使用的局部变量，参数重名时追加 Param 后缀
+var tsReservedNames = []string{
+	"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum",
+	"export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null",
+	"return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with", "yield",
+	"let", "static", "implements", "interface", "package", "private", "protected", "public", "await", "arguments",
+	"eval", "init", "request", "res", "options",
+}
+
+// Generate 生成 ifaces 的类型定义与客户端，合并为一个 TypeScript 文件
+func (g *TSGenerator) Generate(ifaces []SwaggerInterface) (string, error) {
+	for _, iface := range ifaces {
+		if _, err := g.loader.Load(iface.FilePath); err != nil {
+			return "", err
+		}
+		g.taken[tsAPIName(iface)] = true
+	}
+	g.docs = g.loader.fieldDocs()
+
+	var clients []string
+	for _, iface := range ifaces {
+		code, err := g.generateClient(iface)
+		if err != nil {
+			return "", err
+		}
+		clients = append(clients, code)
+	}
+
+	data := map[string]any{
+		"JSON":      g.json,
+		"Form":      g.form,
+		"Multipart": g.multipart,
+		"SSE":       g.sse,
+		"Download":  g.download,
+	}
+	parts := []string{strings.TrimSpace(utils.MustExecuteTemplate(data, tsRuntimeTemplate))}
+	parts = append(parts, g.decls...)
+	parts = append(parts, clients...)
+	return "// Code generated by swagGen. DO NOT EDIT.\n/* eslint-disable */\n\n" + strings.Join(parts, "\n\n") + "\n", nil
+}
+
+// tsAPIName 接口在 TypeScript 中的名称，如 IUserAPI -> UserAPI
+func tsAPIName(iface SwaggerInterface) string {
+	return strings.TrimSuffix(iface.GetClientName(), "Client")
+}
+
+// ----------------------------------------------------------------------------
+// 类型
+// ----------------------------------------------------------------------------
+
+// tsType 返回类型在 TypeScript 中的写法，具名结构体与具名基础类型生成对应的声明
+func (g *TSGenerator) tsType(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return g.tsType(t.Elem())
+	case *types.Alias:
+		return g.tsType(types.Unalias(t))
+	case *types.TypeParam:
+		return t.Obj().Name()
+	case *types.Named:
+		return g.namedType(t)
+	case *types.Basic:
+		return basicTSType(t)
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return "string" // encoding/json 以 base64 编码 []byte
+		}
+		return tsArray(g.tsType(t.Elem()))
+	case *types.Array:
+		return tsArray(g.tsType(t.Elem()))
+	case *types.Map:
+		return fmt.Sprintf("Record<string, %s>", g.tsType(t.Elem()))
+	case *types.Struct:
+		return tsObject(g.jsonFields(t))
+	}
+	// interface{}、函数、通道等：任意值
+	return "unknown"
+}
+
+// namedType 处理具名类型：特殊类型、具名基础类型（枚举）与结构体
+func (g *TSGenerator) namedType(t *types.Named) string {
+	obj := t.Obj()
+	if obj.Pkg() != nil {
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return "string"
+		case "time.Duration":
+			return "number"
+		case "encoding/json.RawMessage":
+			return "unknown"
+		case "mime/multipart.FileHeader":
+			return "Blob"
+		}
+	}
+	if hasMethod(t, "MarshalText") {
+		return "string"
+	}
+	if hasMethod(t, "MarshalJSON") {
+		return "unknown"
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		name := g.declareInterface(t.Origin())
+		if args := t.TypeArgs(); args != nil && args.Len() > 0 {
+			var list []string
+			for i := 0; i < args.Len(); i++ {
+				list = append(list, g.tsType(args.At(i)))
+			}
+			name += "<" + strings.Join(list, ", ") + ">"
+		}
+		return name
+	case *types.Basic:
+		if obj.Pkg() != nil && t.TypeParams().Len() == 0 {
+			return g.declareAlias(t, u)
+		}
+	}
+	return g.tsType(t.Underlying())
+}
+
+// declareInterface 为具名结构体（泛型时为其原始定义）生成 interface 声明
+func (g *TSGenerator) declareInterface(origin *types.Named) string {
+	obj := origin.Obj()
+	if name, ok := g.names[obj]; ok {
+		return name
+	}
+	name := g.reserve(obj, "")
+	g.names[obj] = name
+	// 先占位再展开字段，递归引用自身时直接使用名称，声明顺序与首次引用的顺序一致
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var params []string
+	for i := 0; i < origin.TypeParams().Len(); i++ {
+		params = append(params, origin.TypeParams().At(i).Obj().Name())
+	}
+	head := "export interface " + name
+	if len(params) > 0 {
+		head += "<" + strings.Join(params, ", ") + ">"
+	}
+	g.decls[index] = head + " " + tsBlock(g.jsonFields(origin.Underlying().(*types.Struct)))
+	return name
+}
+
+// declareAlias 为具名基础类型生成 type 声明，同包的同类型常量作为字面量联合
+func (g *TSGenerator) declareAlias(t *types.Named, basic *types.Basic) string {
+	obj := t.Obj()
+	if name, ok := g.names[obj]; ok {
+		return name
+	}
+	name := g.reserve(obj, "")
+	g.names[obj] = name
+
+	typ := basicTSType(basic)
+	if values := lo.Uniq(enumValues(t)); len(values) > 0 {
+		typ = strings.Join(lo.Map(values, func(v any, _ int) string {
+			if s, ok := v.(string); ok {
+				return strconv.Quote(s)
+			}
+			return fmt.Sprint(v)
+		}), " | ")
+	}
+	g.decls = append(g.decls, fmt.Sprintf("export type %s = %s;", name, typ))
+	return name
+}
+
+// formType 查询参数结构体与表单请求体的类型：字段名取自 form 标签，未打标签的结构体字段被展开（与客户端的编码一致）
+func (g *TSGenerator) formType(t types.Type) string {
+	named, ok := types.Unalias(derefType(t)).(*types.Named)
+	if !ok {
+		if st, ok := derefType(t).Underlying().(*types.Struct); ok {
+			return tsObject(g.formFields(st))
+		}
+		return g.tsType(t)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return g.tsType(t)
+	}
+	obj := named.Obj()
+	if name, ok := g.forms[obj]; ok {
+		return name
+	}
+	name := g.reserve(obj, "Form")
+	g.forms[obj] = name
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+	g.decls[index] = "export interface " + name + " " + tsBlock(g.formFields(st))
+	return name
+}
+
+// reserve 分配 TypeScript 名称：默认为类型名加后缀，重名时加包名前缀，仍重名时追加序号
+func (g *TSGenerator) reserve(obj *types.TypeName, suffix string) string {
+	name := obj.Name() + suffix
+	if g.taken[name] && obj.Pkg() != nil {
+		name = upperFirst(obj.Pkg().Name()) + name
+	}
+	for i, base := 2, name; g.taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.taken[name] = true
+	return name
+}
+
+// tsField interface 中的一个属性
+type tsField struct {
+	name     string
+	typ      string
+	optional bool
+	doc      string
+}
+
+// jsonFields 按 encoding/json 的规则展开结构体字段（见 structFields）：json:"-" 跳过，嵌入且未命名的结构体字段被展开，
+// 同名字段取嵌入层级最浅的一个；指针与 omitempty / omitzero 字段为可选
+func (g *TSGenerator) jsonFields(st *types.Struct) []tsField {
+	var out []tsField
+	for _, f := range structFields(st, "json", embeddedStruct) {
+		typ := g.tsType(f.field.Type())
+		if slices.Contains(f.opts, "string") && (typ == "number" || typ == "boolean") {
+			typ = "string"
+		}
+		_, isPointer := f.field.Type().(*types.Pointer)
+		out = append(out, tsField{
+			name:     f.name,
+			typ:      typ,
+			optional: isPointer || slices.Contains(f.opts, "omitempty") || slices.Contains(f.opts, "omitzero"),
+			doc:      g.docs[f.field.Pos()],
+		})
+	}
+	return out
+}
+
+// formFields 按 form 标签展开结构体字段，未命名的结构体字段被展开，未声明 binding / validate required 的字段为可选
+func (g *TSGenerator) formFields(st *types.Struct) []tsField {
+	var out []tsField
+	for _, f := range structFields(st, "form", func(field *types.Var, name string) bool {
+		return name == "" && isPlainStruct(field.Type())
+	}) {
+		out = append(out, tsField{
+			name:     f.name,
+			typ:      g.tsType(f.field.Type()),
+			optional: !tagRequired(f.tag),
+			doc:      g.docs[f.field.Pos()],
+		})
+	}
+	return out
+}
+
+// tsBlock interface 的多行属性块
+func tsBlock(fields []tsField) string {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, f := range fields {
+		if f.doc != "" {
+			sb.WriteString("  /** " + strings.ReplaceAll(strings.ReplaceAll(f.doc, "*/", "* /"), "\n", " ") + " */\n")
+		}
+		sb.WriteString("  " + f.property() + ";\n")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// tsObject 匿名结构体的单行对象类型
+func tsObject(fields []tsField) string {
+	if len(fields) == 0 {
+		return "Record<string, never>"
+	}
+	return "{ " + strings.Join(lo.Map(fields, func(f tsField, _ int) string { return f.property() }), "; ") + " }"
+}
+
+func (f tsField) property() string {
+	return tsPropertyName(f.name) + lo.Ternary(f.optional, "?: ", ": ") + f.typ
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsPropertyName 属性名不是合法标识符时加引号
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsArray 数组类型，联合类型加括号
+func tsArray(elem string) string {
+	if strings.Contains(elem, "|") {
+		return "(" + elem + ")[]"
+	}
+	return elem + "[]"
+}
+
+// basicTSType 基础类型：数值为 number（int64 / uint64 同样以 JSON 数字传输）
+func basicTSType(t *types.Basic) string {
+	switch info := t.Info(); {
+	case info&types.IsBoolean != 0:
+		return "boolean"
+	case info&types.IsNumeric != 0:
+		return "number"
+	case info&types.IsString != 0:
+		return "string"
+	}
+	return "unknown"
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// ----------------------------------------------------------------------------
+// 客户端
+// ----------------------------------------------------------------------------
+
+// tsMethod 单个方法生成时的上下文
+type tsMethod struct {
+	params  []string // 带类型的参数列表
+	request []string // ApiRequest 的属性
+	query   []string
+	headers []string
+	files   []string
+	values  string // multipart 请求体中的表单结构体参数
+	result  string // Promise 的类型参数
+	decode  string // 由 res 得到返回值的表达式
+}
+
+// generateClient 生成接口的 TypeScript interface 与创建客户端的函数
+func (g *TSGenerator) generateClient(iface SwaggerInterface) (string, error) {
+	var decls, impls []string
+	for _, method := range iface.Methods {
+		if method.Def.IsRemoved() {
+			continue
+		}
+		sig, err := g.loader.Signature(iface, method.Name)
+		if err != nil {
+			return "", err
+		}
+		m, err := g.generateMethod(iface, method, sig)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", iface.Name, method.Name, err)
+		}
+
+		doc := fmt.Sprintf("%s %s", method.GetHTTPMethod(), clientFullPath(iface, method))
+		if method.Summary != "" {
+			doc = method.Summary + " " + doc
+		}
+		signature := fmt.Sprintf("%s(%s): Promise<%s>", lowerFirst(method.Name), strings.Join(m.params, ", "), m.result)
+		decls = append(decls, fmt.Sprintf("  /** %s */\n  %s;", strings.ReplaceAll(doc, "*/", "* /"), signature))
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "    async %s {\n", signature)
+		sb.WriteString("      const res = await request(\n        {\n")
+		for _, prop := range m.request {
+			sb.WriteString("          " + prop + ",\n")
+		}
+		sb.WriteString("        },\n        init,\n      );\n")
+		if m.decode == "" {
+			sb.WriteString("      await res.body?.cancel();\n")
+		} else {
+			sb.WriteString("      return " + m.decode + ";\n")
+		}
+		sb.WriteString("    },")
+		impls = append(impls, sb.String())
+	}
+
+	name := tsAPIName(iface)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "/** %s 的客户端接口 */\nexport interface %s {\n%s\n}\n\n", iface.Name, name, strings.Join(decls, "\n"))
+	fmt.Fprintf(&sb, "/** 创建基于 fetch 的 %s 客户端 */\nexport function create%sClient(options: ClientOptions = {}): %s {\n", name, name, name)
+	sb.WriteString("  const request = newRequest(options);\n  return {\n")
+	sb.WriteString(strings.Join(impls, "\n"))
+	sb.WriteString("\n  };\n}")
+	return sb.String(), nil
+}
+
+// generateMethod 按 Gin 绑定代码的规则放置参数：路径参数拼入路径，@QUERY / @HEADER 写入查询与请求头，
+// 最后一个非 @FILE 参数在 GET 中展开为查询参数、其他方法中按请求类型编码为请求体
+func (g *TSGenerator) generateMethod(iface SwaggerInterface, method SwaggerMethod, sig *types.Signature) (*tsMethod, error) {
+	if sig.Params().Len() != len(method.Parameters) {
+		return nil, fmt.Errorf("参数数量与类型信息不一致")
+	}
+	m := &tsMethod{}
+	names := tsParamNames(sig)
+	accept := acceptType(iface, method)
+	body, contentType := "", ""
+
+	for i, param := range method.Parameters {
+		typ := sig.Params().At(i).Type()
+		name := names[i]
+		tsTyp := ""
+		switch {
+		case isContextType(typ) || isGinContextType(typ):
+			continue
+		case param.Source == ParamSourceFile:
+			tsTyp = lo.Ternary(strings.HasPrefix(param.Type.FullName, "[]"), "Blob[]", "Blob")
+			m.files = append(m.files, fmt.Sprintf("%s: %s", tsPropertyName(param.ExternalName()), name))
+		case param.Source == "path":
+			tsTyp = g.tsType(typ)
+		case param.Source == "header":
+			tsTyp = g.tsType(typ)
+			m.headers = append(m.headers, fmt.Sprintf("%s: %s", tsPropertyName(param.ExternalName()), name))
+		case param.Source == "query":
+			tsTyp = g.tsType(typ)
+			m.query = append(m.query, fmt.Sprintf("%s: %s", tsPropertyName(param.ExternalName()), name))
+		case i != method.BodyIndex():
+			tsTyp = g.tsType(typ)
+		case method.GetHTTPMethod() == "GET":
+			if _, ok := derefType(typ).Underlying().(*types.Struct); ok {
+				tsTyp = g.formType(typ)
+				m.query = append(m.query, "..."+name)
+			} else {
+				tsTyp = g.tsType(typ)
+				m.query = append(m.query, fmt.Sprintf("%s: %s", tsPropertyName(param.Name), name))
+			}
+		default:
+			tsTyp, body, contentType = g.encodeBody(m, accept, name, typ)
+		}
+		if !param.Required && (param.Source == "query" || param.Source == ParamSourceFile) {
+			tsTyp += " | undefined"
+		}
+		m.params = append(m.params, name+": "+tsTyp)
+	}
+	if m.values != "" || len(m.files) > 0 {
+		// multipart 请求体由 fetch 设置带 boundary 的 Content-Type
+		g.multipart = true
+		body = fmt.Sprintf("encodeMultipart(%s, { %s })", lo.Ternary(m.values == "", "undefined", m.values), strings.Join(m.files, ", "))
+		contentType = ""
+	}
+	m.params = append(m.params, "init?: RequestOptions")
+
+	m.request = []string{
+		fmt.Sprintf("method: %q", method.GetHTTPMethod()),
+		"path: " + tsPath(iface, method, names),
+	}
+	if len(m.query) > 0 {
+		m.request = append(m.request, "query: { "+strings.Join(m.query, ", ")+" }")
+	}
+	if len(m.headers) > 0 {
+		m.request = append(m.request, "headers: { "+strings.Join(m.headers, ", ")+" }")
+	}
+	if body != "" {
+		m.request = append(m.request, "body: "+body)
+	}
+	if contentType != "" {
+		m.request = append(m.request, fmt.Sprintf("contentType: %q", contentType))
+	}
+	m.request = append(m.request, fmt.Sprintf("accept: %q", mimeType(produceType(iface, method))))
+
+	return m, g.decodeResult(m, method, sig)
+}
+
+// encodeBody 请求体的参数类型、编码表达式与 Content-Type；multipart 表单结构体记录在 m.values 中，与文件一并编码
+func (g *TSGenerator) encodeBody(m *tsMethod, accept, name string, typ types.Type) (string, string, string) {
+	contentType := mimeType(accept)
+	switch accept {
+	case "json", "json-api":
+		return g.tsType(typ), "JSON.stringify(" + name + ")", contentType
+	case "x-www-form-urlencoded", "mpfd":
+		if _, ok := derefType(typ).Underlying().(*types.Struct); !ok {
+			break
+		}
+		if accept == "mpfd" {
+			m.values = name
+			return g.formType(typ), "", ""
+		}
+		g.form = true
+		return g.formType(typ), "encodeForm(" + name + ")", contentType
+	}
+
+	// 其他 MIME 类型：字符串、字节切片与 io.Reader 原样发送，其余按 JSON 编码
+	switch {
+	case isStringType(typ):
+		return "string", name, contentType
+	case isByteSlice(typ) || implementsReader(typ):
+		return "Blob", name, contentType
+	}
+	return g.tsType(typ), "JSON.stringify(" + name + ")", contentType
+}
+
+// decodeResult 按返回值与响应方式确定 Promise 的类型与解码表达式
+func (g *TSGenerator) decodeResult(m *tsMethod, method SwaggerMethod, sig *types.Signature) error {
+	results := sig.Results()
+	switch {
+	case method.Response.IsSSE():
+		g.sse = true
+		elem := streamElemType(results.At(0).Type())
+		m.result = fmt.Sprintf("AsyncGenerator<%s>", g.tsType(elem))
+		m.decode = fmt.Sprintf("readSSE<%s>(res, %t)", g.tsType(elem), isStringType(elem) || isByteSlice(elem))
+	case method.Response == ResponseDownload:
+		g.download = true
+		m.result, m.decode = "DownloadResult", "readDownload(res)"
+	case results.Len() == 1 && isErrorType(results.At(0).Type()):
+		m.result = "void"
+	case results.Len() == 2 && isErrorType(results.At(1).Type()):
+		// 与 Go 客户端一致：string 读取文本，[]byte 读取原始内容，其余按 JSON 解码
+		switch typ := results.At(0).Type(); {
+		case isStringType(typ):
+			m.result, m.decode = "string", "res.text()"
+		case isByteSlice(typ):
+			m.result, m.decode = "Blob", "res.blob()"
+		default:
+			g.json = true
+			m.result = g.tsType(typ)
+			m.decode = fmt.Sprintf("decodeJSON<%s>(res)", m.result)
+		}
+	default:
+		return fmt.Errorf("TypeScript 客户端要求方法返回 error 或 (T, error)")
+	}
+	return nil
+}
+
+// tsPath 请求路径表达式：路径参数经 encodeURIComponent 拼入，使用方法的第一个路由
+func tsPath(iface SwaggerInterface, method SwaggerMethod, names []string) string {
+	fullPath := clientFullPath(iface, method)
+	var parts []string
+	for {
+		open := strings.Index(fullPath, "{")
+		if open == -1 {
+			break
+		}
+		end := strings.Index(fullPath[open:], "}")
+		if end == -1 {
+			break
+		}
+		end += open
+		if open > 0 {
+			parts = append(parts, strconv.Quote(fullPath[:open]))
+		}
+		name := fullPath[open+1 : end]
+		expr := strconv.Quote("{" + name + "}")
+		for i, param := range method.Parameters {
+			if param.Source == "path" && (param.PathName == name || param.Alias == name || param.Name == name) {
+				expr = fmt.Sprintf("encodeURIComponent(String(%s))", names[i])
+				break
+			}
+		}
+		parts = append(parts, expr)
+		fullPath = fullPath[end+1:]
+	}
+	if fullPath != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(fullPath))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// tsParamNames 生成代码中的参数名
+func tsParamNames(sig *types.Signature) []string {
+	names := make([]string, sig.Params().Len())
+	for i := range names {
+		name := sig.Params().At(i).Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		if slices.Contains(tsReservedNames, name) {
+			name += "Param"
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// tsRuntimeTemplate 客户端共用的类型与辅助函数，按需包含
+const tsRuntimeTemplate = `
+export interface ClientOptions {
+  /** 服务地址，如 http://localhost:8080 */
+  baseURL?: string;
+  /** 自定义 fetch 实现，默认使用全局 fetch */
+  fetch?: typeof fetch;
+  /** 每个请求附加的请求头，可为函数以便动态设置认证信息 */
+  headers?: HeadersInit | (() => HeadersInit | Promise<HeadersInit>);
+}
+
+/** 单次请求的选项 */
+export interface RequestOptions {
+  /** 用于取消请求 */
+  signal?: AbortSignal;
+  /** 附加的请求头 */
+  headers?: HeadersInit;
+}
+
+/** 服务端返回的非 2xx 响应 */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: string;
+
+  constructor(status: number, body: string) {
+    super(status + " " + body);
+    this.name = "ApiError";
+    this.status = status;
+    this.body = body;
+  }
+}
+{{- if .Download}}
+
+/** 文件下载的结果 */
+export interface DownloadResult {
+  data: Blob;
+  /** Content-Disposition 中的文件名，没有时为空字符串 */
+  filename: string;
+}
+{{- end}}
+
+type Values = Record<string, unknown>;
+
+interface ApiRequest {
+  method: string;
+  path: string;
+  query?: Values;
+  headers?: Values;
+  body?: BodyInit;
+  contentType?: string;
+  accept: string;
+}
+
+/** 写入非空的值，数组逐个写入 */
+function appendValues(append: (key: string, value: string) => void, values: object | undefined): void {
+  for (const [key, value] of Object.entries(values ?? {})) {
+    for (const v of Array.isArray(value) ? value : [value]) {
+      if (v !== undefined && v !== null) {
+        append(key, String(v));
+      }
+    }
+  }
+}
+
+function newRequest(options: ClientOptions) {
+  return async (req: ApiRequest, init?: RequestOptions): Promise<Response> => {
+    const query = new URLSearchParams();
+    appendValues((key, value) => query.append(key, value), req.query);
+    const search = query.toString();
+    const url = (options.baseURL ?? "").replace(/\/+$/, "") + req.path + (search ? "?" + search : "");
+
+    const headers = new Headers(typeof options.headers === "function" ? await options.headers() : options.headers);
+    new Headers(init?.headers).forEach((value, key) => headers.set(key, value));
+    appendValues((key, value) => headers.set(key, value), req.headers);
+    if (req.contentType) {
+      headers.set("Content-Type", req.contentType);
+    }
+    headers.set("Accept", req.accept);
+
+    const res = await (options.fetch ?? fetch)(url, { method: req.method, headers, body: req.body ?? null, signal: init?.signal ?? null });
+    if (!res.ok) {
+      throw new ApiError(res.status, await res.text());
+    }
+    return res;
+  };
+}
+{{- if .JSON}}
+
+/** 解码 JSON 响应体，响应体为空时返回 undefined */
+async function decodeJSON<T>(res: Response): Promise<T> {
+  const text = await res.text();
+  return (text ? JSON.parse(text) : undefined) as T;
+}
+{{- end}}
+{{- if .Form}}
+
+function encodeForm(values: object | undefined): URLSearchParams {
+  const form = new URLSearchParams();
+  appendValues((key, value) => form.append(key, value), values);
+  return form;
+}
+{{- end}}
+{{- if .Multipart}}
+
+function encodeMultipart(values: object | undefined, files: Record<string, Blob | Blob[] | undefined>): FormData {
+  const form = new FormData();
+  appendValues((key, value) => form.append(key, value), values);
+  for (const [key, value] of Object.entries(files)) {
+    for (const file of Array.isArray(value) ? value : value ? [value] : []) {
+      form.append(key, file);
+    }
+  }
+  return form;
+}
+{{- end}}
+{{- if .SSE}}
+
+/** 逐条读取 Server-Sent Events；raw 为 true 时 data 原样返回，否则按 JSON 解码 */
+async function* readSSE<T>(res: Response, raw: boolean): AsyncGenerator<T> {
+  const reader = res.body!.pipeThrough(new TextDecoderStream()).getReader();
+  let buffer = "";
+  let data: string[] = [];
+  const event = (): T => {
+    const text = data.join("\n");
+    data = [];
+    return (raw ? text : JSON.parse(text)) as T;
+  };
+  try {
+    for (;;) {
+      const { value, done } = await reader.read();
+      if (done) {
+        break;
+      }
+      buffer += value;
+      let end: number;
+      while ((end = buffer.indexOf("\n")) >= 0) {
+        const line = buffer.slice(0, end).replace(/\r$/, "");
+        buffer = buffer.slice(end + 1);
+        if (line === "") {
+          if (data.length > 0) {
+            yield event();
+          }
+        } else if (line.startsWith("data:")) {
+          data.push(line.slice(line.startsWith("data: ") ? 6 : 5));
+        }
+      }
+    }
+    if (data.length > 0) {
+      yield event();
+    }
+  } finally {
+    await reader.cancel();
+  }
+}
+{{- end}}
+{{- if .Download}}
+
+async function readDownload(res: Response): Promise<DownloadResult> {
+  const disposition = res.headers.get("Content-Disposition") ?? "";
+  const extended = /filename\*\s*=\s*[^']*'[^']*'([^;]+)/i.exec(disposition);
+  const plain = /filename\s*=\s*("(?:[^"\\]|\\.)*"|[^;]*)/i.exec(disposition);
+  let filename = "";
+  if (extended) {
+    filename = decodeURIComponent(extended[1].trim());
+  } else if (plain) {
+    const value = plain[1].trim();
+    filename = value.startsWith('"') ? value.slice(1, -1).replace(/\\(.)/g, "$1") : value;
+  }
+  return { data: await res.blob(), filename };
+}
+{{- end}}
+`
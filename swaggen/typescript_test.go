@@ -0,0 +1,136 @@
+package swaggen
+
+import (
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateTSCode(t *testing.T) string {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/typescript/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{
+		Targets: []*plugin.AnnotatedTarget{interfaceTarget(t, filePath, "IUserAPI")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	data, ok := result.FileOutputs[filepath.Join(filepath.Dir(filePath), "api.ts")]
+	if !ok {
+		t.Fatalf("api.ts not generated, outputs: %v", keys(result.FileOutputs))
+	}
+	return string(data)
+}
+
+func TestTSTypes(t *testing.T) {
+	code := generateTSCode(t)
+
+	wants := []string{
+		// 泛型结构体
+		"export interface BaseResponse<T> {\n  code: number;\n  msg?: string;\n  data: T;\n}",
+		// 嵌入字段展开、指针可选、time.Time 为字符串、,string 选项
+		"  created_at: string;\n  deleted_at?: string;\n  /** 用户 ID */\n  id: string;",
+		"  tags: string[];\n  attrs?: Record<string, unknown>;\n  manager?: User;",
+		// 其他包的同名类型加包名前缀
+		"  account: ModelsUser;",
+		"export interface ModelsUser {\n  email: string;\n}",
+		"  avatar: string;\n  extra: { N: number };\n  labels: Record<string, Status>;",
+		// 枚举常量生成字面量联合类型
+		`export type Status = "active" | "disabled";`,
+		// GET 请求体按 form 标签生成查询参数类型
+		"export interface ListReqForm {\n  page: number;\n  keyword?: string;\n  status?: Status[];\n}",
+		"export interface FormReqForm {\n  title?: string;\n}",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing:\n%s\n\ngot:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"Secret", "secret", "deleteUser"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("unexpected %q in output", unwanted)
+		}
+	}
+}
+
+func TestTSClient(t *testing.T) {
+	code := generateTSCode(t)
+
+	wants := []string{
+		"export function createUserAPIClient(options: ClientOptions = {}): UserAPI {",
+		// 路径、头部、查询参数；保留字参数名改写
+		"getUser(id: number, tenant: string, withParam: string | undefined, init?: RequestOptions): Promise<BaseResponse<User>>;",
+		`path: "/api/users/" + encodeURIComponent(String(id)),
+          query: { with: withParam },
+          headers: { "X-Tenant": tenant },`,
+		// GET 请求体展开为查询参数
+		"query: { ...req },",
+		// JSON / 表单 / multipart 请求体
+		"body: JSON.stringify(req),\n          contentType: \"application/json\",",
+		"body: encodeForm(req),\n          contentType: \"application/x-www-form-urlencoded\",",
+		"uploadAvatar(id: number, req: FormReqForm, file: Blob, extra: Blob[] | undefined, init?: RequestOptions): Promise<void>;",
+		"body: encodeMultipart(req, { file: file, extra: extra }),",
+		// 各类响应的解码方式
+		"return decodeJSON<User>(res);",
+		"await res.body?.cancel();",
+		"events(init?: RequestOptions): Promise<AsyncGenerator<Event>>;",
+		"logs(init?: RequestOptions): Promise<AsyncGenerator<string>>;",
+		"export(id: number, init?: RequestOptions): Promise<DownloadResult>;",
+		"return readDownload(res);",
+		"name(id: number, init?: RequestOptions): Promise<string>;",
+		"return res.text();",
+		// 运行时辅助代码按需输出
+		"export class ApiError extends Error {",
+		"async function* readSSE<T>(",
+		"function encodeMultipart(",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing:\n%s\n\ngot:\n%s", want, code)
+		}
+	}
+}
+
+func TestTSRuntimeOnDemand(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/stream/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := interfaceTarget(t, filePath, "IStreamAPI")
+	iface, err := NewSwagGenerator().parseInterface(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := NewTSGenerator(NewTypeLoader()).Generate([]SwaggerInterface{*iface})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, "export function createStreamAPIClient(") {
+		t.Errorf("client missing:\n%s", code)
+	}
+	if strings.Contains(code, "function encodeForm(") {
+		t.Errorf("unused encodeForm helper emitted:\n%s", code)
+	}
+}
+
+func TestTSFieldPrecedence(t *testing.T) {
+	st := checkType(t, fieldPrecedenceSrc, "Req").Underlying().(*types.Struct)
+	g := NewTSGenerator(NewTypeLoader())
+
+	// 外层 id 覆盖先声明的 Base.ID；同层级的 Dup 无法区分被忽略，JSON 中带标签的 Tagged 生效
+	if got, want := tsObject(g.jsonFields(st)), "{ Note: string; Tagged: number; id: number }"; got != want {
+		t.Errorf("jsonFields = %s, want %s", got, want)
+	}
+	if got, want := tsObject(g.formFields(st)), "{ Note?: string; Kept?: number; Tagged?: string; id?: number }"; got != want {
+		t.Errorf("formFields = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,638 @@
+package swaggen
+
+import (
+	"fmt"
+	"go/types"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/donutnomad/gogen/internal/utils"
+	"github.com/samber/lo"
+)
+
+// ============================================================================
+// 声明式校验：参数注解与 binding / validate 标签
+// ============================================================================
+
+// validationComponent OpenAPI 中参数校验失败响应的组件名
+const validationComponent = "ValidationError"
+
+// 待校验值的类别
+const (
+	kindInt    = "int"
+	kindUint   = "uint"
+	kindFloat  = "float"
+	kindString = "string"
+	kindBool   = "bool"
+	kindLength = "length" // 切片 / 数组 / map，min / max 限制元素个数
+)
+
+// Rules 校验规则。min / max 对数值限制取值范围，对字符串（按字符数）与切片限制长度，与 validator 的语义一致
+type Rules struct {
+	Required bool
+	Min      *float64
+	Max      *float64
+	Pattern  string   // 正则，仅参数注解支持
+	Enum     []string // 可选值
+}
+
+// IsZero 是否未声明任何规则
+func (r Rules) IsZero() bool {
+	return !r.Required && !r.hasConstraints()
+}
+
+// hasConstraints 是否声明了 required 以外的约束
+func (r Rules) hasConstraints() bool {
+	return r.Min != nil || r.Max != nil || r.Pattern != "" || len(r.Enum) > 0
+}
+
+// FieldRule 请求结构体中声明了校验规则的字段
+type FieldRule struct {
+	Path      string // 从请求结构体开始的字段选择路径，嵌入结构体的字段带上嵌入字段名，如 Base.ID
+	Name      string // 响应中的字段名（json / form 标签名）
+	Kind      string // 值类别，为空时只支持指针字段的 required
+	Pointer   bool
+	OmitEmpty bool
+	Rules     Rules
+}
+
+// parseRules 解析参数注解括号内的内容，以分号分隔：第一个不含 = 的值为名称（路径参数别名 / 查询参数名 / 请求头名），
+// 其余为规则：required、required=false、min=1、max=100、len=6、pattern=^[a-z]+$、enum=a,b,c
+func parseRules(content string) (value string, rules Rules, required *bool, err error) {
+	for i, part := range strings.Split(content, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			switch {
+			case part == "required":
+				required = lo.ToPtr(true)
+			case i == 0:
+				value = part
+			default:
+				return "", rules, nil, fmt.Errorf("无法识别的规则 %q", part)
+			}
+			continue
+		}
+		key, val = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(val)
+		switch key {
+		case "required":
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return "", rules, nil, fmt.Errorf("无效的 required=%s", val)
+			}
+			required = &b
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return "", rules, nil, fmt.Errorf("无效的 %s=%s", key, val)
+			}
+			if key != "max" {
+				rules.Min = &n
+			}
+			if key != "min" {
+				rules.Max = &n
+			}
+		case "pattern":
+			if _, err := regexp.Compile(val); err != nil {
+				return "", rules, nil, fmt.Errorf("无效的 pattern: %w", err)
+			}
+			rules.Pattern = val
+		case "enum":
+			rules.Enum = lo.Map(strings.Split(val, ","), func(item string, _ int) string { return strings.TrimSpace(item) })
+		default:
+			return "", rules, nil, fmt.Errorf("无法识别的规则 %q", key)
+		}
+	}
+	return value, rules, required, nil
+}
+
+// tagRules 解析 binding / validate 标签中可静态校验的规则：required、omitempty、min、max、len、gte、lte、oneof；
+// dive 之后的规则作用于元素，其余规则（email、url 等）不生成校验代码
+func tagRules(tag reflect.StructTag) (rules Rules, omitEmpty bool) {
+	for _, key := range []string{"binding", "validate"} {
+	items:
+		for _, item := range strings.Split(tag.Get(key), ",") {
+			name, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+			n, numErr := strconv.ParseFloat(val, 64)
+			switch name {
+			case "dive", "keys":
+				break items
+			case "required":
+				rules.Required = true
+			case "omitempty":
+				omitEmpty = true
+			case "min", "gte":
+				if numErr == nil {
+					rules.Min = &n
+				}
+			case "max", "lte":
+				if numErr == nil {
+					rules.Max = &n
+				}
+			case "len":
+				if numErr == nil {
+					rules.Min, rules.Max = &n, &n
+				}
+			case "oneof":
+				rules.Enum = strings.Fields(val)
+			}
+		}
+	}
+	return rules, omitEmpty
+}
+
+// paramKind 参数注解所在参数的值类别，非基础类型返回空
+func paramKind(param Parameter) string {
+	if param.Type.IsPointer || param.Type.IsSlice || param.Type.Package != "" {
+		return ""
+	}
+	switch param.Type.TypeName {
+	case "int", "int8", "int16", "int32", "int64":
+		return kindInt
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return kindUint
+	case "float32", "float64":
+		return kindFloat
+	case "string":
+		return kindString
+	case "bool":
+		return kindBool
+	}
+	return ""
+}
+
+// typeKind 结构体字段的值类别
+func typeKind(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsUnsigned != 0:
+			return kindUint
+		case u.Info()&types.IsInteger != 0:
+			return kindInt
+		case u.Info()&types.IsFloat != 0:
+			return kindFloat
+		case u.Info()&types.IsString != 0:
+			return kindString
+		case u.Info()&types.IsBoolean != 0:
+			return kindBool
+		}
+	case *types.Slice, *types.Array, *types.Map:
+		return kindLength
+	}
+	return ""
+}
+
+// checkParamRules 校验参数注解中的规则能否用于参数类型
+func checkParamRules(param Parameter) error {
+	if param.Rules.IsZero() {
+		return nil
+	}
+	kind := paramKind(param)
+	if kind == "" {
+		return fmt.Errorf("参数 %s 的类型 %s 不支持校验规则，仅支持基础类型", param.Name, param.Type.FullName)
+	}
+	r := param.Rules
+	if r.Pattern != "" && kind != kindString {
+		return fmt.Errorf("参数 %s: pattern 仅适用于 string 类型", param.Name)
+	}
+	for _, bound := range []*float64{r.Min, r.Max} {
+		if bound == nil {
+			continue
+		}
+		if kind == kindBool {
+			return fmt.Errorf("参数 %s: min / max 不适用于 bool 类型", param.Name)
+		}
+		if kind != kindFloat && *bound != math.Trunc(*bound) {
+			return fmt.Errorf("参数 %s: %s 类型的 min / max 必须为整数", param.Name, param.Type.TypeName)
+		}
+		if kind == kindUint && *bound < 0 {
+			return fmt.Errorf("参数 %s: %s 类型的 min / max 不能为负数", param.Name, param.Type.TypeName)
+		}
+	}
+	for _, v := range r.Enum {
+		if _, ok := enumLiteral(kind, v); !ok {
+			return fmt.Errorf("参数 %s: enum 值 %q 不是有效的 %s", param.Name, v, param.Type.TypeName)
+		}
+	}
+	return nil
+}
+
+// enumLiteral 可选值在 Go 代码中的字面量
+func enumLiteral(kind, v string) (string, bool) {
+	switch kind {
+	case kindString:
+		return strconv.Quote(v), true
+	case kindInt:
+		_, err := strconv.ParseInt(v, 10, 64)
+		return v, err == nil
+	case kindUint:
+		_, err := strconv.ParseUint(v, 10, 64)
+		return v, err == nil
+	case kindFloat:
+		_, err := strconv.ParseFloat(v, 64)
+		return v, err == nil
+	}
+	return "", false
+}
+
+// FieldRules 收集方法请求结构体（最后一个非 @FILE 参数）中声明了 binding / validate 规则的字段，字段名与绑定时一致：JSON 请求体取 json 标签，其余取 form 标签
+func (l *TypeLoader) FieldRules(iface SwaggerInterface, method SwaggerMethod) ([]FieldRule, error) {
+	last := method.BodyIndex()
+	if last < 0 {
+		return nil, nil
+	}
+	param := method.Parameters[last]
+	if param.Source != "" || paramKind(param) != "" || param.Type.FullName == GinContextType || param.Type.TypeName == "Context" {
+		return nil, nil
+	}
+	sig, err := l.Signature(iface, method.Name)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := derefType(sig.Params().At(last).Type()).Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+	tagKey := "form"
+	if method.GetHTTPMethod() != "GET" && acceptType(iface, method) == "json" {
+		tagKey = "json"
+	}
+	var out []FieldRule
+	collectFieldRules(st, tagKey, &out)
+	return out, nil
+}
+
+// collectFieldRules 按 addFields 的字段命名规则收集带校验规则的字段，嵌入且未命名的结构体字段被展开，被外层覆盖的字段不收集
+func collectFieldRules(st *types.Struct, tagKey string, out *[]FieldRule) {
+	for _, f := range structFields(st, tagKey, embeddedStruct) {
+		rules, omitEmpty := tagRules(f.tag)
+		if rules.IsZero() {
+			continue
+		}
+		_, pointer := f.field.Type().(*types.Pointer)
+		*out = append(*out, FieldRule{
+			Path:      f.path,
+			Name:      f.name,
+			Kind:      typeKind(derefType(f.field.Type())),
+			Pointer:   pointer,
+			OmitEmpty: omitEmpty,
+			Rules:     rules,
+		})
+	}
+}
+
+// hasValidation 接口中是否有方法需要生成校验代码
+func hasValidation(iface SwaggerInterface) bool {
+	return slices.ContainsFunc(iface.Methods, methodHasValidation)
+}
+
+// methodHasValidation 方法是否声明了参数校验规则、请求结构体校验规则或 @FILE 参数
+func methodHasValidation(method SwaggerMethod) bool {
+	return len(method.FieldRules) > 0 || slices.ContainsFunc(method.Parameters, func(p Parameter) bool {
+		return !p.Rules.IsZero() || p.Source == ParamSourceFile
+	})
+}
+
+// patternVar 参数 pattern 规则对应的包级正则变量名
+func patternVar(iface SwaggerInterface, method SwaggerMethod, param Parameter) string {
+	return utils.EString(iface.GetWrapperName()).LowerCamelCase().String() + method.Name + utils.UpperCamelCase(param.Name) + "Pattern"
+}
+
+// generatePatternVars 生成接口中 pattern 规则的包级正则变量
+func generatePatternVars(iface SwaggerInterface) string {
+	var lines []string
+	for _, method := range iface.Methods {
+		for _, param := range method.Parameters {
+			if param.Rules.Pattern != "" {
+				lines = append(lines, fmt.Sprintf("    %s = regexp.MustCompile(%s)", patternVar(iface, method, param), strconv.Quote(param.Rules.Pattern)))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "var (\n" + strings.Join(lines, "\n") + "\n)"
+}
+
+// generateFieldErrorType 生成校验失败字段的类型与 invalidResponse 方法
+func generateFieldErrorType(iface SwaggerInterface) string {
+	template := `
+// {{.TypeName}} 未通过校验的请求字段
+type {{.TypeName}} struct {
+    Field   string ` + "`json:\"field\"`" + `
+    In      string ` + "`json:\"in\"`" + `
+    Rule    string ` + "`json:\"rule\"`" + `
+    Message string ` + "`json:\"message\"`" + `
+}
+
+// invalidResponse 参数校验失败时的 400 响应，列出每个未通过校验的字段
+func (a *{{.WrapperName}}) invalidResponse(fields []{{.TypeName}}) (int, any) {
+    return 400, struct {
+        Code    int    ` + "`json:\"code\"`" + `
+        Message string ` + "`json:\"message\"`" + `
+        Errors  []{{.TypeName}} ` + "`json:\"errors\"`" + `
+    }{Code: 400, Message: "validation failed", Errors: fields}
+}
+`
+	data := map[string]any{
+		"TypeName":    iface.GetFieldErrorName(),
+		"WrapperName": iface.GetWrapperName(),
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// needsBindingInvalid 是否有方法使用框架自带校验的绑定（见 Backend.ValidatingBind），此时需要 bindingInvalid 方法
+func needsBindingInvalid(iface SwaggerInterface) bool {
+	return backendOf(iface).ValidatingBind("req", "JSON") != "" && slices.ContainsFunc(iface.Methods, func(m SwaggerMethod) bool {
+		return len(m.FieldRules) > 0
+	})
+}
+
+// generateBindingInvalid 生成 bindingInvalid 方法：将 gin 绑定请求结构体的错误转换为校验失败字段
+func generateBindingInvalid(iface SwaggerInterface) string {
+	template := `
+// bindingInvalid 将绑定请求结构体的错误转换为校验失败字段：binding 标签校验失败时每个字段一条，
+// fields 为字段路径（如 Base.ID）到字段名的映射；其他错误（如请求体格式错误）记为一条 type 失败
+func (a *{{.WrapperName}}) bindingInvalid(err error, in string, fields map[string]string) []{{.TypeName}} {
+    var errs validator.ValidationErrors
+    if !errors.As(err, &errs) {
+        return []{{.TypeName}}{{"{{"}}Field: "", In: in, Rule: "type", Message: err.Error()}}
+    }
+    out := make([]{{.TypeName}}, 0, len(errs))
+    for _, e := range errs {
+        path := e.StructNamespace()
+        if i := strings.IndexByte(path, '.'); i >= 0 {
+            path = path[i+1:]
+        }
+        field, ok := fields[path]
+        if !ok {
+            field = path
+        }
+        message := "is required"
+        if e.Tag() != "required" {
+            message = "must satisfy " + e.Tag()
+            if e.Param() != "" {
+                message += "=" + e.Param()
+            }
+        }
+        out = append(out, {{.TypeName}}{Field: field, In: in, Rule: e.Tag(), Message: message})
+    }
+    return out
+}
+`
+	data := map[string]any{
+		"TypeName":    iface.GetFieldErrorName(),
+		"WrapperName": iface.GetWrapperName(),
+	}
+	return strings.TrimSpace(utils.MustExecuteTemplate(data, template))
+}
+
+// validationImports 返回校验代码需要的导入行
+func validationImports(interfaces []SwaggerInterface) []string {
+	var out []string
+	for _, iface := range interfaces {
+		if !hasValidation(iface) {
+			continue
+		}
+		out = append(out, backendOf(iface).ErrorImports()...)
+		if needsBindingInvalid(iface) {
+			out = append(out, `"errors"`, `"github.com/go-playground/validator/v10"`)
+		}
+		for _, method := range iface.Methods {
+			for _, param := range method.Parameters {
+				if param.Rules.IsZero() {
+					continue
+				}
+				if param.Rules.Pattern != "" {
+					out = append(out, `"regexp"`)
+				}
+				if kind := paramKind(param); kind != kindString {
+					out = append(out, `"github.com/spf13/cast"`)
+				} else if param.Rules.Min != nil || param.Rules.Max != nil {
+					out = append(out, `"unicode/utf8"`)
+				}
+			}
+			if slices.ContainsFunc(method.FieldRules, func(f FieldRule) bool {
+				return f.Kind == kindString && (f.Rules.Min != nil || f.Rules.Max != nil)
+			}) {
+				out = append(out, `"unicode/utf8"`)
+			}
+		}
+	}
+	return lo.Uniq(out)
+}
+
+// validator 生成处理器中的校验语句，未通过的字段追加到 invalid 切片
+type validator struct {
+	typeName string // 校验失败字段的类型名
+}
+
+// fail 追加一条校验失败记录的语句
+func (v validator) fail(field, in, rule, message string) string {
+	return fmt.Sprintf("invalid = append(invalid, %s{Field: %q, In: %q, Rule: %q, Message: %q})", v.typeName, field, in, rule, message)
+}
+
+// binding 框架绑定失败（err）时追加校验失败字段的语句，rules 提供字段路径到字段名的映射
+func (v validator) binding(in string, rules []FieldRule) string {
+	pairs := lo.Map(rules, func(f FieldRule, _ int) string { return fmt.Sprintf("%q: %q", f.Path, f.Name) })
+	return fmt.Sprintf("invalid = append(invalid, a.bindingInvalid(err, %q, map[string]string{%s})...)", in, strings.Join(pairs, ", "))
+}
+
+// ifBlock 生成 if 语句，els 为空时不生成 else 分支
+func ifBlock(cond string, body, els []string) string {
+	code := "if " + cond + " {\n" + strings.Join(body, "\n") + "\n}"
+	if len(els) > 0 {
+		code += " else {\n" + strings.Join(els, "\n") + "\n}"
+	}
+	return code
+}
+
+// checks 生成值 expr 的约束检查语句（不含 required）；patternVar 为 pattern 规则的正则变量名
+func (v validator) checks(field, in, expr, kind string, r Rules, patternVar string) []string {
+	var out []string
+	add := func(rule, cond, message string) {
+		out = append(out, ifBlock(cond, []string{v.fail(field, in, rule, message)}, nil))
+	}
+	bound := func(kind string, n *float64) (string, bool) {
+		if n == nil || (kind != kindFloat && *n != math.Trunc(*n)) || (kind == kindUint && *n < 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(*n, 'f', -1, 64), true
+	}
+
+	switch kind {
+	case kindInt, kindUint, kindFloat:
+		if n, ok := bound(kind, r.Min); ok {
+			add("min", expr+" < "+n, "must be >= "+n)
+		}
+		if n, ok := bound(kind, r.Max); ok {
+			add("max", expr+" > "+n, "must be <= "+n)
+		}
+	case kindString, kindLength:
+		length := "len(" + expr + ")"
+		if kind == kindString {
+			length = "utf8.RuneCountInString(" + expr + ")"
+		}
+		if n, ok := bound(kindUint, r.Min); ok {
+			add("min", length+" < "+n, "length must be >= "+n)
+		}
+		if n, ok := bound(kindUint, r.Max); ok {
+			add("max", length+" > "+n, "length must be <= "+n)
+		}
+		if r.Pattern != "" && kind == kindString {
+			add("pattern", "!"+patternVar+".MatchString("+expr+")", "must match pattern "+r.Pattern)
+		}
+	}
+
+	var conds []string
+	for _, e := range r.Enum {
+		if lit, ok := enumLiteral(kind, e); ok {
+			conds = append(conds, expr+" != "+lit)
+		}
+	}
+	if len(conds) > 0 {
+		add("enum", strings.Join(conds, " && "), "must be one of "+strings.Join(r.Enum, ", "))
+	}
+	return out
+}
+
+// zeroCond 值为零值（negate 时为非零值）的条件
+func zeroCond(expr, kind string, negate bool) string {
+	op := lo.Ternary(negate, " != ", " == ")
+	switch kind {
+	case kindString:
+		return expr + op + `""`
+	case kindBool:
+		return lo.Ternary(negate, expr, "!"+expr)
+	case kindLength:
+		return "len(" + expr + ")" + op + "0"
+	}
+	return expr + op + "0"
+}
+
+// field 生成结构体字段的校验语句，与 validator 一致：required 拒绝零值，omitempty 跳过零值
+func (v validator) field(varName, in string, f FieldRule) string {
+	expr := varName + "." + f.Path
+	value := lo.Ternary(f.Pointer, "*"+expr, expr)
+	var checks []string
+	if f.Kind != "" {
+		checks = v.checks(f.Name, in, value, f.Kind, f.Rules, "")
+	}
+	required := []string{v.fail(f.Name, in, "required", "is required")}
+
+	switch {
+	case f.Pointer && f.Rules.Required:
+		return ifBlock(expr+" == nil", required, checks)
+	case f.Pointer && len(checks) > 0:
+		return ifBlock(expr+" != nil", checks, nil)
+	case f.Kind == "":
+		return ""
+	case f.Rules.Required:
+		return ifBlock(zeroCond(value, f.Kind, false), required, checks)
+	case f.OmitEmpty && len(checks) > 0:
+		return ifBlock(zeroCond(value, f.Kind, true), checks, nil)
+	}
+	return strings.Join(checks, "\n")
+}
+
+// param 生成带校验的标量参数绑定：raw 为读取原始字符串的表达式，缺失、类型转换失败与约束不满足均记录为失败字段
+func (v validator) param(param Parameter, raw, patternVar string) string {
+	field, in := param.ExternalName(), param.Source
+	kind := paramKind(param)
+	required := []string{v.fail(field, in, "required", "is required")}
+	checks := v.checks(field, in, param.Name, kind, param.Rules, patternVar)
+
+	if kind == kindString {
+		code := fmt.Sprintf("%s := %s\n", param.Name, raw)
+		if param.Rules.Required {
+			return code + ifBlock(param.Name+` == ""`, required, checks)
+		}
+		if len(checks) == 0 {
+			return strings.TrimSpace(code)
+		}
+		return code + ifBlock(param.Name+` != ""`, checks, nil)
+	}
+
+	rawName := param.Name + "Raw"
+	convert := fmt.Sprintf("if v, err := cast.To%sE(%s); err != nil {\n%s\n} else {\n%s\n}",
+		utils.UpperCamelCase(param.Type.TypeName), rawName,
+		v.fail(field, in, "type", "must be a valid "+param.Type.TypeName),
+		strings.Join(append([]string{param.Name + " = v"}, checks...), "\n"))
+	code := fmt.Sprintf("%s := %s\nvar %s %s\n", rawName, raw, param.Name, param.Type.TypeName)
+	if param.Rules.Required {
+		return code + fmt.Sprintf("if %s == \"\" {\n%s\n} else %s", rawName, required[0], convert)
+	}
+	return code + ifBlock(rawName+` != ""`, []string{convert}, nil)
+}
+
+// applyRules 将规则写入 Schema 的 minimum / maxLength / pattern / enum 等约束，返回副本；引用类型的 Schema 不修改
+func applyRules(s *Schema, r Rules) *Schema {
+	if s.Ref != "" || !r.hasConstraints() {
+		return s
+	}
+	copied := *s
+	toInt := func(n *float64) *int {
+		if n == nil {
+			return nil
+		}
+		return lo.ToPtr(int(*n))
+	}
+	switch copied.Type {
+	case "integer", "number":
+		copied.Minimum, copied.Maximum = r.Min, r.Max
+	case "string":
+		copied.MinLength, copied.MaxLength = toInt(r.Min), toInt(r.Max)
+		copied.Pattern = r.Pattern
+	case "array":
+		copied.MinItems, copied.MaxItems = toInt(r.Min), toInt(r.Max)
+	}
+	if len(r.Enum) > 0 {
+		copied.Enum = nil
+		for _, e := range r.Enum {
+			copied.Enum = append(copied.Enum, enumSchemaValue(copied.Type, e))
+		}
+	}
+	return &copied
+}
+
+// enumSchemaValue 按 Schema 类型转换可选值，无法转换时保留字符串
+func enumSchemaValue(typ, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// validationErrorSchema 参数校验失败响应的 Schema
+func validationErrorSchema() *Schema {
+	item := &Schema{Type: "object"}
+	item.setProperty("field", &Schema{Type: "string", Description: "字段名"}, true)
+	item.setProperty("in", &Schema{Type: "string", Description: "字段位置：path / query / header / body / formData"}, true)
+	item.setProperty("rule", &Schema{Type: "string", Description: "未通过的规则"}, true)
+	item.setProperty("message", &Schema{Type: "string", Description: "错误信息"}, true)
+
+	s := &Schema{Type: "object"}
+	s.setProperty("code", &Schema{Type: "integer", Description: "业务错误码"}, true)
+	s.setProperty("message", &Schema{Type: "string", Description: "错误信息"}, true)
+	s.setProperty("errors", &Schema{Type: "array", Items: item, Description: "未通过校验的字段"}, false)
+	return s
+}
@@ -0,0 +1,171 @@
+package swaggen
+
+import (
+	"go/format"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/donutnomad/gogen/plugin"
+)
+
+func generateValidationCode(t *testing.T, names ...string) (string, *plugin.GenerateResult) {
+	t.Helper()
+	filePath, err := filepath.Abs("testdata/validation/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var targets []*plugin.AnnotatedTarget
+	for _, name := range names {
+		targets = append(targets, interfaceTarget(t, filePath, name))
+	}
+	result, err := NewSwagGenerator().Generate(&plugin.GenerateContext{Targets: targets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(result.RawOutputs[filepath.Join(filepath.Dir(filePath), "api_swagger.go")])
+	if code != "" {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, code)
+		}
+	}
+	return code, result
+}
+
+func TestValidationHandler(t *testing.T) {
+	code, result := generateValidationCode(t, "IOrderAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	wants := []string{
+		"type OrderAPIFieldError struct {",
+		"func (a *OrderAPIWrap) invalidResponse(fields []OrderAPIFieldError) (int, any) {",
+		`}{Code: 400, Message: "validation failed", Errors: fields}`,
+		"func (a *OrderAPIWrap) onInvalid(ctx *gin.Context, fields []OrderAPIFieldError) {",
+		`orderAPIWrapGetOrderTenantPattern = regexp.MustCompile("^[a-z]{2,8}$")`,
+		// 路径参数：类型转换失败与取值范围
+		`} else if v, err := cast.ToInt64E(idRaw); err != nil {`,
+		`invalid = append(invalid, OrderAPIFieldError{Field: "id", In: "path", Rule: "min", Message: "must be >= 1"})`,
+		// 查询参数：可选参数缺失时跳过，必填参数缺失时报错
+		`expand := ctx.Query("expand")`,
+		`if expand != "items" && expand != "customer" {`,
+		`invalid = append(invalid, OrderAPIFieldError{Field: "limit", In: "query", Rule: "required", Message: "is required"})`,
+		// 请求头：名称取自注解
+		`tenant := ctx.GetHeader("X-Tenant")`,
+		`if !orderAPIWrapGetOrderTenantPattern.MatchString(tenant) {`,
+		// 请求体：binding / validate 标签
+		`if utf8.RuneCountInString(req.Name) < 2 {`,
+		`if req.Quantity > 100 {`,
+		`if req.Status != "" {`,
+		`if len(req.Tags) > 2 {`,
+		`invalid = append(invalid, OrderAPIFieldError{Field: "note", In: "body", Rule: "required", Message: "is required"})`,
+		`invalid = append(invalid, OrderAPIFieldError{Field: "page", In: "query", Rule: "min", Message: "must be >= 1"})`,
+		// gin 的 binding 校验失败同样转换为校验失败字段，绑定成功后再执行生成的校验
+		`"github.com/go-playground/validator/v10"`,
+		"func (a *OrderAPIWrap) bindingInvalid(err error, in string, fields map[string]string) []OrderAPIFieldError {",
+		"if err := ctx.ShouldBindJSON(&req); err != nil {\ninvalid = append(invalid, a.bindingInvalid(err, \"body\", map[string]string{\"Name\": \"name\", \"Quantity\": \"quantity\", \"Status\": \"status\", \"Tags\": \"tags\", \"Note\": \"note\"})...)\n} else {",
+		`if err := ctx.ShouldBindQuery(&req); err != nil {`,
+		// swag 注释
+		`// @Param id path integer true "id" minimum(1) maximum(1000)`,
+		`// @Param expand query string false "expand" Enums(items,customer)`,
+		`// @Param X-Tenant header string true "tenant"`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	if got := strings.Count(code, "a.onInvalid(ctx, invalid)"); got != 3 {
+		t.Errorf("onInvalid calls = %d, want 3", got)
+	}
+	if strings.Contains(code, "if !onGinBind(ctx, &req") {
+		t.Error("request structs with rules should not be bound by onGinBind")
+	}
+	// dive 之后的规则作用于元素，不生成校验
+	if strings.Contains(code, "len(req.Tags) < 1") {
+		t.Error("rules after dive should be ignored")
+	}
+}
+
+func TestValidationOptional(t *testing.T) {
+	code, result := generateValidationCode(t, "IPlainAPI")
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	// 未声明规则的参数保持原有绑定方式
+	for _, want := range []string{`id := cast.ToInt64(r.PathValue("id"))`, `q := r.URL.Query().Get("q")`} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	if strings.Contains(code, "invalid") {
+		t.Errorf("no validation code expected:\n%s", code)
+	}
+}
+
+func TestValidationRejectsInvalidRule(t *testing.T) {
+	_, result := generateValidationCode(t, "IBadRuleAPI")
+	if !result.HasErrors() || !strings.Contains(result.Errors[0].Error(), "pattern 仅适用于 string 类型") {
+		t.Fatalf("expected pattern error, got %v", result.Errors)
+	}
+}
+
+func TestValidationOpenAPI(t *testing.T) {
+	filePath, err := filepath.Abs("testdata/validation/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := NewSwagGenerator().parseInterface(interfaceTarget(t, filePath, "IOrderAPI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := BuildOpenAPI(NewTypeLoader(), []SwaggerInterface{*iface}, OpenAPIInfo{Title: "t", Version: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := doc.Paths["/orders/{id}"]["get"]
+	if p := findParam(get, "path", "id"); p == nil || *p.Schema.Minimum != 1 || *p.Schema.Maximum != 1000 {
+		t.Errorf("id parameter = %+v", p)
+	}
+	if p := findParam(get, "query", "expand"); p == nil || p.Required || len(p.Schema.Enum) != 2 {
+		t.Errorf("expand parameter = %+v", p)
+	}
+	if p := findParam(get, "query", "limit"); p == nil || !p.Required {
+		t.Errorf("limit parameter = %+v", p)
+	}
+	if p := findParam(get, "header", "X-Tenant"); p == nil || p.Schema.Pattern != "^[a-z]{2,8}$" {
+		t.Errorf("X-Tenant parameter = %+v", p)
+	}
+	if got := get.Responses["400"].Content["application/json"].Schema.Ref; got != "#/components/schemas/ValidationError" {
+		t.Errorf("400 schema ref = %q", got)
+	}
+
+	req := doc.Components.Schemas["validation.CreateOrderReq"]
+	if name := req.Properties["name"]; *name.MinLength != 2 || *name.MaxLength != 8 {
+		t.Errorf("name schema = %+v", name)
+	}
+	if tags := req.Properties["tags"]; tags.MaxItems == nil || *tags.MaxItems != 2 {
+		t.Errorf("tags schema = %+v", tags)
+	}
+	if status := req.Properties["status"]; len(status.Enum) != 2 {
+		t.Errorf("status schema = %+v", status)
+	}
+	if p := findParam(doc.Paths["/orders"]["get"], "query", "page"); p == nil || *p.Schema.Minimum != 1 {
+		t.Errorf("page parameter = %+v", p)
+	}
+}
+
+func TestCollectFieldRulesPrecedence(t *testing.T) {
+	st := checkType(t, fieldPrecedenceSrc, "Req").Underlying().(*types.Struct)
+	for _, tagKey := range []string{"json", "form"} {
+		var rules []FieldRule
+		collectFieldRules(st, tagKey, &rules)
+		// 被外层 ID 覆盖的 Base.ID 的规则不生成
+		if len(rules) != 1 || rules[0].Path != "ID" || rules[0].Name != "id" || rules[0].Rules.Required || rules[0].Rules.Min == nil {
+			t.Errorf("%s rules = %+v", tagKey, rules)
+		}
+	}
+}